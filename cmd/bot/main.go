@@ -6,13 +6,14 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/sirupsen/logrus"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/bot"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/config"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
 func main() {
-	// Initialize logger
+	// Bootstrap logger used only until configuration is loaded
 	log := logger.New(logger.Config{
 		Level:  "info",
 		Format: "text",
@@ -26,6 +27,18 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Re-initialize with the configured level and stamp every line with the
+	// running version and git SHA (if set), so logs from different deploys
+	// aren't ambiguous
+	log = logger.New(logger.Config{
+		Level:  cfg.LogLevel,
+		Format: "text",
+		Fields: logrus.Fields{
+			"version": cfg.Version,
+			"git_sha": os.Getenv("GIT_SHA"),
+		},
+	})
+
 	log.Infof("Bot Name: %s", cfg.BotName)
 	log.Infof("Stay Connected 24/7: %v", cfg.StayConnected247)
 
@@ -0,0 +1,90 @@
+// Command migrate-playlists is a one-time tool that reads legacy on-disk
+// playlist JSON files and writes them into Postgres, so deployments
+// switching USE_DATABASE on don't lose existing playlists.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+func main() {
+	playlistDir := flag.String("playlist-dir", envOrDefault("PLAYLIST_DIR", "./playlist"), "directory containing legacy playlist JSON files")
+	databaseURL := flag.String("database-url", os.Getenv("DATABASE_URL"), "Postgres connection string to migrate into")
+	flag.Parse()
+
+	log := logger.New(logger.Config{Level: "info", Format: "text"})
+
+	_ = godotenv.Load()
+	if *databaseURL == "" {
+		log.Fatal("no database URL provided; pass -database-url or set DATABASE_URL")
+	}
+
+	fileRepo := repositories.NewPlaylistRepository(*playlistDir)
+	names, err := fileRepo.List()
+	if err != nil {
+		log.Fatalf("failed to list legacy playlists in %s: %v", *playlistDir, err)
+	}
+	if len(names) == 0 {
+		log.Infof("no legacy playlists found in %s, nothing to do", *playlistDir)
+		return
+	}
+
+	ctx := context.Background()
+	dbCfg := database.DefaultConfig(*databaseURL)
+	db, err := database.Connect(ctx, dbCfg)
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.RunMigrations(ctx); err != nil {
+		log.Fatalf("failed to run database migrations: %v", err)
+	}
+
+	dbRepo := repositories.NewDatabasePlaylistRepository(db)
+
+	migrated := 0
+	for _, name := range names {
+		playlist, err := fileRepo.Load(name)
+		if err != nil {
+			log.WithError(err).WithField("playlist", name).Error("Failed to read legacy playlist, skipping")
+			continue
+		}
+		if playlist == nil {
+			continue
+		}
+
+		// Legacy on-disk playlists have no guild, so they migrate in as
+		// global (guildID "") playlists, matching PlaylistService's
+		// existing file-backed behavior.
+		if err := dbRepo.Save("", playlist); err != nil {
+			log.WithError(err).WithField("playlist", name).Error("Failed to migrate playlist, skipping")
+			continue
+		}
+
+		if playlist.ExternalInfo != nil {
+			if err := dbRepo.SaveExternalInfo("", name, playlist.ExternalInfo); err != nil {
+				log.WithError(err).WithField("playlist", name).Warn("Migrated playlist but failed to carry over external sync info")
+			}
+		}
+
+		migrated++
+		log.WithField("playlist", name).Info("Migrated playlist to Postgres")
+	}
+
+	log.Infof("Migration complete: %d/%d playlists migrated", migrated, len(names))
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
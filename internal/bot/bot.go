@@ -3,16 +3,25 @@ package bot
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/commands"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/config"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/metrics"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/cache"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/externalplaylist"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/sources"
+	sourcesspotify "github.com/vuongmanhnghia/discord-music-bot/internal/sources/spotify"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger/hooks"
 )
 
 // MusicBot represents the Discord music bot
@@ -23,11 +32,18 @@ type MusicBot struct {
 	db                *database.DB
 	ytService         *youtube.Service
 	spotifyService    *spotify.Service
+	spotifyCallback   *spotify.CallbackServer
 	audioService      *audio.AudioService
 	processingService *services.ProcessingService
 	playbackService   *services.PlaybackService
 	playlistService   *services.PlaylistService
+	scrobbleService   *services.ScrobbleService
+	externalImporters *externalplaylist.Registry
+	songCache         *cache.Manager
+	songAudioCache    *cache.SongCache
 	cmdHandler        *commands.Handler
+	syncScheduler     *services.PlaylistSyncScheduler
+	metrics           metrics.Recorder
 }
 
 // New creates a new MusicBot instance
@@ -73,40 +89,227 @@ func New(cfg *config.Config, log *logger.Logger) (*MusicBot, error) {
 		return nil, fmt.Errorf("failed to create YouTube service: %w", err)
 	}
 
-	// Initialize Spotify service (optional)
+	// Initialize Spotify service. Works even without SpotifyClientID/Secret -
+	// newService falls back to an anonymous web-player token - so this only
+	// fails on a genuine error (e.g. the scrape itself failing).
 	var spotifyService *spotify.Service
-	if cfg.SpotifyClientID != "" && cfg.SpotifyClientSecret != "" {
+	if cfg.UseDatabase && db != nil {
+		spotifyService, err = spotify.NewServiceWithDB(db, cfg.SpotifyClientID, cfg.SpotifyClientSecret, log)
+	} else {
 		spotifyService, err = spotify.NewService(cfg.SpotifyClientID, cfg.SpotifyClientSecret, log)
+	}
+	if err != nil {
+		log.WithError(err).Warn("Failed to initialize Spotify service - Spotify links will not work")
+		spotifyService = nil
+	}
+
+	// Enable /spotify connect + /spotify export, if a developer app and a
+	// callback URL were configured - these need real credentials, unlike
+	// the anonymous fallback above. Tokens are stored under cfg.CacheDir,
+	// same base directory the resolution/song audio caches use.
+	var spotifyCallbackServer *spotify.CallbackServer
+	if spotifyService != nil && cfg.SpotifyClientID != "" && cfg.SpotifyClientSecret != "" && cfg.SpotifyRedirectURL != "" {
+		spotifyCredRepo, err := repositories.NewSpotifyCredentialRepository(cfg.CacheDir, cfg.SpotifyClientSecret)
 		if err != nil {
-			log.WithError(err).Warn("Failed to initialize Spotify service - Spotify links will not work")
+			log.WithError(err).Warn("Failed to initialize Spotify credential store - /spotify connect will not work")
 		} else {
-			log.Info("Spotify service initialized")
+			spotifyService.EnableUserAuth(cfg.SpotifyClientID, cfg.SpotifyClientSecret, cfg.SpotifyRedirectURL, spotifyCredRepo)
+
+			spotifyCallbackServer, err = spotify.NewCallbackServer(cfg.SpotifyOAuthAddr, spotifyService, log)
+			if err != nil {
+				log.WithError(err).Warn("Failed to start Spotify OAuth callback server - /spotify connect will not work")
+			}
 		}
-	} else {
-		log.Info("Spotify credentials not provided - Spotify links will not work")
 	}
 
-	// Initialize audio service
-	audioService := audio.NewAudioService(session, log)
+	// Initialize audio service. The idle watcher it starts per connected
+	// guild is wired up below, once playbackService exists to answer
+	// "is this guild idle?" and to post the disconnect notice.
+	audioService := audio.NewAudioService(session, cfg.IdleTimeout, cfg.AloneTimeout, !cfg.StayConnected247, log)
+	audioService.SetVoteConfig(cfg.VoteThresholdRatio, cfg.VoteMinVotes, cfg.VoteTTL)
+
+	// If Lavalink nodes are configured, hand every guild's playback off to
+	// them instead of this process's own yt-dlp/FFmpeg pipeline
+	if cfg.LavalinkEnabled {
+		nodes := make([]audio.LavalinkNodeConfig, len(cfg.LavalinkNodes))
+		for i, addr := range cfg.LavalinkNodes {
+			nodes[i] = audio.LavalinkNodeConfig{
+				Name:     addr,
+				Host:     addr,
+				Password: cfg.LavalinkPassword,
+				Secure:   cfg.LavalinkSecure,
+			}
+		}
+		audioService.SetLavalinkBackend(audio.NewLavalinkBackend(nodes, session, log))
+		log.WithField("nodes", len(nodes)).Info("Lavalink backend enabled")
+	}
+
+	// Initialize search service for "did you mean" fuzzy matching over
+	// playlist names/entries and processed song titles
+	searchService := services.NewSearchService(log)
 
 	// Initialize processing service with config values
-	processingService := services.NewProcessingService(ytService, cfg.WorkerCount, cfg.MaxQueueSize, log)
+	processingService := services.NewProcessingService(ytService, searchService, cfg.WorkerCount, cfg.MaxQueueSize, log)
 
-	// Initialize playback service
-	playbackService := services.NewPlaybackService(session, audioService, processingService, log)
+	// Initialize scrobble service (ListenBrainz always available, Last.fm only
+	// if API credentials were configured)
+	var scrobbleService *services.ScrobbleService
+	if cfg.UseDatabase && db != nil {
+		scrobbleService = services.NewScrobbleServiceWithDB(db, cfg.CacheDir, cfg.LastFMAPIKey, cfg.LastFMAPISecret, log)
+	} else {
+		scrobbleService = services.NewScrobbleService(cfg.CacheDir, cfg.LastFMAPIKey, cfg.LastFMAPISecret, log)
+	}
+
+	// Initialize pluggable audio source providers. SoundCloud works even
+	// without SoundCloudClientID configured: SoundCloudProvider falls back
+	// to scraping the web player's client_id from soundcloud.com itself.
+	if cfg.SoundCloudClientID == "" {
+		log.Info("SoundCloud client_id not configured - will scrape one from soundcloud.com on first use")
+	}
+	soundCloudProvider := sources.NewSoundCloudProvider(cfg.SoundCloudClientID)
+	sourceRegistry := sources.NewRegistry(
+		sources.NewYouTubeProvider(ytService),
+		soundCloudProvider,
+	)
+
+	// Initialize external playlist importers (YouTube/SoundCloud always available
+	// via yt-dlp, Spotify only if credentials were configured)
+	var spotifyImporter externalplaylist.PlaylistImporter
+	if spotifyService != nil {
+		spotifyBridge := sourcesspotify.NewBridge(spotifyService, ytService, log)
+		spotifyImporter = externalplaylist.NewSpotifyImporter(spotifyService, spotifyBridge)
+	}
+	externalImporters := externalplaylist.NewRegistry(
+		externalplaylist.NewYouTubeImporter(ytService),
+		externalplaylist.NewSoundCloudImporter(ytService),
+		spotifyImporter,
+	)
+
+	// Initialize metrics recorder (behind cfg.MetricsEnabled / cfg.MetricsBackend)
+	metricsRecorder, err := newMetricsRecorder(cfg, log)
+	if err != nil {
+		if db != nil {
+			db.Close()
+		}
+		return nil, fmt.Errorf("failed to initialize metrics: %w", err)
+	}
+	ytService.SetMetricsRecorder(metricsRecorder)
+	audioService.SetMetricsRecorder(metricsRecorder)
+
+	// Initialize playback service, backed by a snapshot of each guild's
+	// queue/position/channels so playback can resume after a restart - one
+	// row per guild in Postgres if configured, otherwise one JSON file per
+	// guild under cfg.StateDir
+	var stateRepo repositories.StateRepositoryInterface
+	if cfg.UseDatabase && db != nil {
+		stateRepo = repositories.NewDatabaseStateRepository(db)
+		log.Info("Using database for playback state storage")
+	} else {
+		stateRepo = repositories.NewStateRepository(cfg.StateDir)
+	}
+	// playHistoryRepo is shared with the playlist service below so smart
+	// playlists' play_count criteria see every play this service records
+	playHistoryRepo := repositories.NewPlayHistoryRepository(cfg.PlayHistoryDir)
+	playbackService := services.NewPlaybackService(session, audioService, processingService, scrobbleService, externalImporters, cfg.SkipRatio, cfg.MaxPlaylistURLSize, metricsRecorder, stateRepo, playHistoryRepo, log)
+
+	// Wire AudioService's activity/cleanup hooks to the playback service now
+	// that it exists, so ConnectToChannel/PlaySong/DisconnectFromGuild can
+	// trigger state snapshots without AudioService depending on
+	// PlaybackService directly
+	audioService.SetActivityHook(playbackService.ScheduleStateSave)
+	audioService.SetCleanupHook(playbackService.DeleteState)
+	audioService.SetIdleCheckHook(playbackService.IsIdle)
+	audioService.SetAutoDisconnectHook(playbackService.NotifyAutoDisconnect)
 
 	// Initialize playlist service (with or without database)
 	var playlistService *services.PlaylistService
 	if cfg.UseDatabase && db != nil {
-		playlistService = services.NewPlaylistServiceWithDB(db, log)
+		playlistService = services.NewPlaylistServiceWithDB(db, playHistoryRepo, searchService, log)
 		log.Info("Using database for playlist storage")
 	} else {
-		playlistService = services.NewPlaylistService(cfg.PlaylistDir, log)
+		playlistService, err = services.NewPlaylistService(cfg.PlaylistDir, playHistoryRepo, searchService, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create playlist service: %w", err)
+		}
 		log.Info("Using file-based playlist storage")
 	}
 
+	// Initialize the persistent song resolution cache (Spotify track ->
+	// YouTube video, and YouTube URL -> metadata), protecting whatever's
+	// currently playing in any guild from its age/size-based eviction
+	songCache, err := cache.NewManager(
+		filepath.Join(cfg.CacheDir, "resolved"),
+		time.Duration(cfg.CacheDurationMinutes)*time.Minute,
+		int64(cfg.CacheSizeMB)*1024*1024,
+		func(key string) bool {
+			for _, url := range playbackService.CurrentlyPlayingURLs() {
+				if url == key {
+					return true
+				}
+			}
+			return false
+		},
+		log,
+	)
+	if err != nil {
+		if db != nil {
+			db.Close()
+		}
+		return nil, fmt.Errorf("failed to create song resolution cache: %w", err)
+	}
+
+	// Initialize the on-disk song audio cache: after a track finishes
+	// playing successfully, its Opus audio is downloaded here so replaying
+	// it skips yt-dlp's network fetch entirely. Protects whatever's
+	// currently playing in any guild the same way the resolution cache does.
+	songAudioCache, err := cache.NewSongCache(
+		filepath.Join(cfg.CacheDir, "songs"),
+		"yt-dlp",
+		time.Duration(cfg.SongCacheExpireHours)*time.Hour,
+		int64(cfg.SongCacheSizeMB)*1024*1024,
+		func(videoID string) bool {
+			for _, url := range playbackService.CurrentlyPlayingURLs() {
+				if youtube.VideoIDFromURL(url) == videoID {
+					return true
+				}
+			}
+			return false
+		},
+		log,
+	)
+	if err != nil {
+		if db != nil {
+			db.Close()
+		}
+		return nil, fmt.Errorf("failed to create song audio cache: %w", err)
+	}
+	ytService.SetSongCache(songAudioCache)
+
+	// Once a track finishes playing successfully, cache its audio in the
+	// background so the next play of the same song is instant
+	audioService.AddGlobalHandler(audio.TrackEventEnd, func(trackCtx audio.TrackContext) {
+		if trackCtx.Song == nil {
+			return
+		}
+		videoID := youtube.VideoIDFromURL(trackCtx.Song.OriginalInput)
+		if videoID == "" {
+			return
+		}
+		go ytService.DownloadToSongCache(videoID, trackCtx.Song.OriginalInput)
+	})
+
+	// Tap the logger with a ring buffer so /logs can dump recent structured
+	// entries without a log aggregator
+	logBuffer := hooks.NewRingBuffer(cfg.LogBufferSize)
+	log.AddHook(logBuffer)
+
 	// Initialize command handler
-	cmdHandler := commands.NewHandler(session, playbackService, playlistService, ytService, spotifyService, log, cfg)
+	cmdHandler := commands.NewHandler(session, playbackService, playlistService, scrobbleService, searchService, ytService, spotifyService, sourceRegistry, externalImporters, songCache, songAudioCache, logBuffer, log, cfg)
+
+	// Initialize the playlist sync scheduler: a per-playlist cron override
+	// (entities.ExternalInfo.SyncCron) takes priority, falling back to the
+	// configured global cron, and finally to a fixed interval
+	syncScheduler := services.NewPlaylistSyncScheduler(session, playlistService, externalImporters, cfg.PlaylistSyncCron, cfg.PlaylistSyncInterval, cfg.PlaylistSyncDryRun, log)
 
 	bot := &MusicBot{
 		config:            cfg,
@@ -114,21 +317,83 @@ func New(cfg *config.Config, log *logger.Logger) (*MusicBot, error) {
 		session:           session,
 		db:                db,
 		ytService:         ytService,
+		spotifyCallback:   spotifyCallbackServer,
 		audioService:      audioService,
 		processingService: processingService,
 		playbackService:   playbackService,
 		playlistService:   playlistService,
+		scrobbleService:   scrobbleService,
+		externalImporters: externalImporters,
+		songCache:         songCache,
+		songAudioCache:    songAudioCache,
 		cmdHandler:        cmdHandler,
+		syncScheduler:     syncScheduler,
+		metrics:           metricsRecorder,
 	}
 
 	// Register event handlers
 	session.AddHandler(bot.onReady)
 	session.AddHandler(cmdHandler.HandleInteraction)
 	session.AddHandler(bot.onVoiceStateUpdate)
+	session.AddHandler(cmdHandler.HandleVoiceStateUpdate)
+	session.AddHandler(bot.onVoiceServerUpdate)
 
 	return bot, nil
 }
 
+// newMetricsRecorder builds the Recorder selected by cfg.MetricsBackend, or
+// a NoopRecorder if metrics are disabled
+func newMetricsRecorder(cfg *config.Config, log *logger.Logger) (metrics.Recorder, error) {
+	if !cfg.MetricsEnabled {
+		return metrics.NoopRecorder{}, nil
+	}
+
+	switch cfg.MetricsBackend {
+	case "redis":
+		return metrics.NewRedisRecorder(cfg.MetricsRedisAddr, log), nil
+	case "prometheus":
+		return metrics.NewPrometheusRecorder(cfg.MetricsAddr, log)
+	default:
+		return nil, fmt.Errorf("unknown metrics backend: %q", cfg.MetricsBackend)
+	}
+}
+
+// pollQueueDepth periodically reports the processing queue size to the
+// metrics recorder, since ProcessingService doesn't push its own state
+func (b *MusicBot) pollQueueDepth(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			depth := b.processingService.QueueSize()
+			b.metrics.SetQueueDepth(depth)
+			b.metrics.ObserveQueueLength(depth)
+		}
+	}
+}
+
+// pollCacheStats periodically scrapes ytService's info/stream cache stats
+// into the metrics recorder, mirroring pollQueueDepth - youtube.Service's
+// caches have no metrics hook of their own to push live events through.
+func (b *MusicBot) pollCacheStats(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hits, misses, evictions, size := b.ytService.CacheStats()
+			b.metrics.ObserveCacheStats("youtube", hits, misses, evictions, size)
+		}
+	}
+}
+
 // Start starts the bot
 func (b *MusicBot) Start(ctx context.Context) error {
 	b.logger.Info("Starting services...")
@@ -136,6 +401,18 @@ func (b *MusicBot) Start(ctx context.Context) error {
 	// Start processing service
 	b.processingService.Start()
 
+	go b.pollQueueDepth(ctx)
+	go b.pollCacheStats(ctx)
+
+	// Start scrobble submission queue
+	b.scrobbleService.Start()
+
+	// Start the song resolution cache's periodic sweep
+	b.songCache.Start()
+
+	// Start the song audio cache's periodic sweep
+	b.songAudioCache.Start()
+
 	b.logger.Info("Opening Discord connection...")
 	if err := b.session.Open(); err != nil {
 		return fmt.Errorf("failed to open Discord connection: %w", err)
@@ -147,6 +424,8 @@ func (b *MusicBot) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to register commands: %w", err)
 	}
 
+	b.syncScheduler.Start()
+
 	return nil
 }
 
@@ -154,9 +433,21 @@ func (b *MusicBot) Start(ctx context.Context) error {
 func (b *MusicBot) Stop() {
 	b.logger.Info("Shutting down services...")
 
+	// Stop the playlist sync scheduler
+	b.syncScheduler.Stop()
+
 	// Stop processing service
 	b.processingService.Stop()
 
+	// Stop scrobble submission queue
+	b.scrobbleService.Stop()
+
+	// Stop the song resolution cache's periodic sweep
+	b.songCache.Stop()
+
+	// Stop the song audio cache's periodic sweep
+	b.songAudioCache.Stop()
+
 	// Cleanup all audio resources
 	b.audioService.CleanupAll()
 
@@ -170,6 +461,16 @@ func (b *MusicBot) Stop() {
 	if err := b.session.Close(); err != nil {
 		b.logger.WithError(err).Error("Failed to close Discord session")
 	}
+
+	if err := b.metrics.Close(); err != nil {
+		b.logger.WithError(err).Warn("Failed to close metrics recorder")
+	}
+
+	if b.spotifyCallback != nil {
+		if err := b.spotifyCallback.Close(); err != nil {
+			b.logger.WithError(err).Warn("Failed to close Spotify OAuth callback server")
+		}
+	}
 }
 
 // onReady is called when the bot is ready
@@ -182,17 +483,22 @@ func (b *MusicBot) onReady(s *discordgo.Session, event *discordgo.Ready) {
 	if err := s.UpdateGameStatus(0, "🎵 Music Bot - /help"); err != nil {
 		b.logger.WithError(err).Warn("Failed to update status")
 	}
+
+	// Reconnect and resume any guild that was playing when the bot last
+	// shut down. Runs in the background since rejoining voice channels and
+	// resubmitting songs for processing can take a while on a guild with a
+	// large queue, and shouldn't block the ready handler.
+	go b.playbackService.RestoreAll(context.Background())
 }
 
 // onVoiceStateUpdate handles voice state updates (user joins/leaves voice channels)
 func (b *MusicBot) onVoiceStateUpdate(s *discordgo.Session, event *discordgo.VoiceStateUpdate) {
-	// Skip if 24/7 mode is enabled - never auto-disconnect
-	if b.config.StayConnected247 {
-		return
-	}
-
-	// Skip if the event is about the bot itself
+	// Skip if the event is about the bot itself, except to relay it to the
+	// Lavalink backend (if configured) - it needs our own session id to
+	// finish its voice handshake with Discord. ForwardVoiceStateUpdate is a
+	// no-op when no Lavalink backend is set.
 	if event.UserID == s.State.User.ID {
+		b.audioService.ForwardVoiceStateUpdate(event.GuildID, event.SessionID, event.ChannelID)
 		return
 	}
 
@@ -205,15 +511,26 @@ func (b *MusicBot) onVoiceStateUpdate(s *discordgo.Session, event *discordgo.Voi
 		return
 	}
 
-	// Check if the user left the bot's channel
-	// event.BeforeUpdate contains the previous voice state
-	if event.BeforeUpdate == nil {
-		// User joined a channel, not left
+	joinedBotChannel := event.ChannelID == botChannelID
+	leftBotChannel := event.BeforeUpdate != nil && event.BeforeUpdate.ChannelID == botChannelID && event.ChannelID != botChannelID
+
+	// Track presence in the bot's channel regardless of 24/7 mode, so
+	// session-owner promotion (see PlaybackService.HandleListenerLeft)
+	// still works even when auto-disconnect is disabled
+	if joinedBotChannel {
+		b.playbackService.RecordListenerJoin(guildID, event.UserID)
+	}
+	if leftBotChannel {
+		b.playbackService.HandleListenerLeft(guildID, event.UserID)
+	}
+
+	// Skip if 24/7 mode is enabled - never auto-disconnect
+	if b.config.StayConnected247 {
 		return
 	}
 
-	// Only care if user was in the bot's channel before
-	if event.BeforeUpdate.ChannelID != botChannelID {
+	// Only the empty-channel disconnect check below cares about leaves
+	if !leftBotChannel {
 		return
 	}
 
@@ -225,26 +542,12 @@ func (b *MusicBot) onVoiceStateUpdate(s *discordgo.Session, event *discordgo.Voi
 	}
 
 	// Count users in the voice channel (excluding bots)
-	userCount := 0
-	guild, err := s.State.Guild(guildID)
+	userCount, err := b.audioService.CountNonBotListeners(guildID)
 	if err != nil {
 		b.logger.WithError(err).Warn("Failed to get guild state")
 		return
 	}
 
-	for _, vs := range guild.VoiceStates {
-		if vs.ChannelID == botChannelID && vs.UserID != s.State.User.ID {
-			// Check if user is not a bot
-			member, err := s.GuildMember(guildID, vs.UserID)
-			if err != nil {
-				continue
-			}
-			if member.User != nil && !member.User.Bot {
-				userCount++
-			}
-		}
-	}
-
 	b.logger.WithFields(map[string]interface{}{
 		"guild":     guildID,
 		"channel":   voiceChannel.Name,
@@ -260,6 +563,18 @@ func (b *MusicBot) onVoiceStateUpdate(s *discordgo.Session, event *discordgo.Voi
 
 		if err := b.audioService.DisconnectFromGuild(guildID); err != nil {
 			b.logger.WithError(err).Warn("Failed to disconnect from guild")
+		} else {
+			b.metrics.IncVoiceIdleDisconnects()
 		}
 	}
 }
+
+// onVoiceServerUpdate relays Discord's VOICE_SERVER_UPDATE to the Lavalink
+// backend, if one is configured (a no-op otherwise). The local backend
+// doesn't need this: discordgo establishes its own voice UDP connection
+// directly from ChannelVoiceJoin.
+func (b *MusicBot) onVoiceServerUpdate(s *discordgo.Session, event *discordgo.VoiceServerUpdate) {
+	if err := b.audioService.ForwardVoiceServerUpdate(event.GuildID, event.Token, event.Endpoint); err != nil {
+		b.logger.WithError(err).WithField("guild", event.GuildID).Warn("Failed to forward voice server update to Lavalink")
+	}
+}
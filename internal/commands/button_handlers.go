@@ -25,6 +25,52 @@ func (h *Handler) handleButtonInteraction(s *discordgo.Session, i *discordgo.Int
 		h.handleQueuePagination(s, i, parts)
 	case "playlist":
 		h.handlePlaylistPagination(s, i, parts)
+	case "npctl":
+		h.handleNowPlayingControl(s, i, parts)
+	case "search":
+		h.handleSearchSelection(s, i)
+	}
+}
+
+// handleNowPlayingControl handles the live now-playing message's ⏯️/⏭️/🔁
+// buttons, gated by the same session-owner/admin check as the /pause,
+// /skip, and /repeat commands.
+func (h *Handler) handleNowPlayingControl(s *discordgo.Session, i *discordgo.InteractionCreate, parts []string) {
+	if len(parts) < 2 {
+		return
+	}
+
+	if !h.isAuthorizedController(i) {
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: h.ownerDeniedMessage(i.GuildID),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	switch parts[1] {
+	case "pause":
+		if _, err := h.playbackService.TogglePause(h.contextFor(i), i.GuildID); err != nil {
+			h.logger.WithError(err).Warn("Now-playing pause/resume toggle failed")
+		}
+	case "skip":
+		if err := h.playbackService.ForceSkip(h.contextFor(i), i.GuildID); err != nil {
+			h.logger.WithError(err).Warn("Now-playing skip failed")
+		}
+	case "repeat":
+		h.playbackService.CycleRepeatMode(i.GuildID)
+	}
+
+	// The live message's own update loop will pick up the new state on its
+	// next tick; just acknowledge the click so Discord doesn't show a
+	// "This interaction failed" toast.
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		h.logger.WithError(err).Error("Failed to acknowledge now-playing control")
 	}
 }
 
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleCacheSubcommand dispatches /cache's subcommands
+func (h *Handler) handleCacheSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !isGuildAdmin(i) {
+		return respondError(s, i, "🔒 /cache requires server Administrator permission")
+	}
+
+	if h.songCache == nil {
+		return respondError(s, i, "Song resolution cache is not enabled")
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return respondError(s, i, "Invalid subcommand")
+	}
+
+	switch options[0].Name {
+	case "stats":
+		return h.handleCacheStats(s, i)
+	case "clear":
+		return h.handleCacheClear(s, i)
+	default:
+		return respondError(s, i, "Unknown subcommand")
+	}
+}
+
+// handleCacheStats reports the song resolution cache's and song audio
+// cache's entry counts and on-disk sizes, plus the in-memory yt-dlp
+// result cache's hit rate as a footer
+func (h *Handler) handleCacheStats(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	stats, err := h.songCache.Stats()
+	if err != nil {
+		return respondError(s, i, "Failed to read cache stats: "+err.Error())
+	}
+
+	builder := NewEmbed().
+		Title("🗄️ Song Resolution Cache").
+		Color(ColorInfo).
+		Field("Entries", fmt.Sprintf("%d", stats.Entries), true).
+		Field("Size", fmt.Sprintf("%.2f MB", float64(stats.Bytes)/(1024*1024)), true)
+
+	if h.songAudioCache != nil {
+		if audioStats, err := h.songAudioCache.Stats(); err == nil {
+			builder.Field("Cached Songs", fmt.Sprintf("%d", audioStats.Entries), true).
+				Field("Audio Size", fmt.Sprintf("%.2f MB", float64(audioStats.Bytes)/(1024*1024)), true)
+		}
+	}
+
+	hits, misses, evictions, size := h.ytService.CacheStats()
+	embed := builder.
+		Footer(fmt.Sprintf("In-memory: %d entries • %d hits • %d misses • %d evictions", size, hits, misses, evictions)).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// handleCacheClear empties the song resolution cache and the song audio cache
+func (h *Handler) handleCacheClear(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := h.songCache.Clear(); err != nil {
+		return respondError(s, i, "Failed to clear cache: "+err.Error())
+	}
+	if h.songAudioCache != nil {
+		if err := h.songAudioCache.Clear(); err != nil {
+			return respondError(s, i, "Failed to clear song audio cache: "+err.Error())
+		}
+	}
+
+	embed := NewEmbed().
+		Title("🧹 Cache Cleared").
+		Description("All cached song resolutions and downloaded audio have been removed").
+		Color(ColorSuccess).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
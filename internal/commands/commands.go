@@ -18,6 +18,30 @@ func GetCommands() []*discordgo.ApplicationCommand {
 				},
 			},
 		},
+		{
+			Name:        "addnext",
+			Description: "[Admin] Queue a song to play immediately after the current one",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "URL (YouTube/Spotify/SoundCloud) or search query",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "playnow",
+			Description: "[Admin] Interrupt the current song and play this immediately",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "URL (YouTube/Spotify/SoundCloud) or search query",
+					Required:    true,
+				},
+			},
+		},
 		{
 			Name:        "pause",
 			Description: "Pause the current playback",
@@ -43,6 +67,26 @@ func GetCommands() []*discordgo.ApplicationCommand {
 			Name:        "stop",
 			Description: "Stop playback and clear the queue",
 		},
+		{
+			Name:        "voteskip",
+			Description: "Start or join a vote to skip the current song",
+		},
+		{
+			Name:        "votestop",
+			Description: "Start or join a vote to stop playback and clear the queue",
+		},
+		{
+			Name:        "seek",
+			Description: "Jump to a position in the current song",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "position",
+					Description: "Position as seconds or hh:mm:ss / mm:ss (e.g. 90, 1:30, 1:02:03)",
+					Required:    true,
+				},
+			},
+		},
 		{
 			Name:        "volume",
 			Description: "Adjust playback volume (0-100%)",
@@ -57,6 +101,127 @@ func GetCommands() []*discordgo.ApplicationCommand {
 				},
 			},
 		},
+		{
+			Name:        "crossfade",
+			Description: "Set the fade-out/fade-in transition between tracks, in seconds (0 disables)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Name:        "seconds",
+					Description: "Transition length in seconds (0-10, default 0/off)",
+					Required:    true,
+					MinValue:    func() *float64 { v := 0.0; return &v }(),
+					MaxValue:    10,
+				},
+			},
+		},
+		{
+			Name:        "transfer",
+			Description: "Hand control of playback to another user in the voice channel",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "User to transfer control to",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "filter",
+			Description: "Apply DSP audio effects to playback",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "toggle",
+					Description: "Toggle an audio effect on or off",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "effect",
+							Description: "Effect to toggle",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Bass Boost", Value: "bassboost"},
+								{Name: "Nightcore", Value: "nightcore"},
+								{Name: "Vaporwave", Value: "vaporwave"},
+								{Name: "8D Audio", Value: "8d"},
+								{Name: "Karaoke", Value: "karaoke"},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "eq",
+					Description: "Set a graphic equalizer band's gain",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "band",
+							Description: "Band number (1-15, low to high frequency)",
+							Required:    true,
+							MinValue:    func() *float64 { v := 1.0; return &v }(),
+							MaxValue:    15,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionNumber,
+							Name:        "gain",
+							Description: "Gain in dB (-12 to 12)",
+							Required:    true,
+							MinValue:    func() *float64 { v := -12.0; return &v }(),
+							MaxValue:    12,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "reset",
+					Description: "Turn off every effect and flatten the equalizer",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "status",
+					Description: "Show the current filter chain",
+				},
+			},
+		},
+		{
+			Name:        "normalize",
+			Description: "Normalize track loudness so volume stays consistent across the queue",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "on",
+					Description: "Turn on loudness normalization",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "off",
+					Description: "Turn off loudness normalization",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "target",
+					Description: "Set the target integrated loudness and turn normalization on",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionNumber,
+							Name:        "lufs",
+							Description: "Target loudness in LUFS (-40 to 0, default -14)",
+							Required:    true,
+							MinValue:    func() *float64 { v := -40.0; return &v }(),
+							MaxValue:    0,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "status",
+					Description: "Show the current normalization setting",
+				},
+			},
+		},
 
 		// Queue commands
 		{
@@ -71,6 +236,27 @@ func GetCommands() []*discordgo.ApplicationCommand {
 			Name:        "shuffle",
 			Description: "Shuffle the songs in queue",
 		},
+		{
+			Name:        "smartshuffle",
+			Description: "Shuffle the queue while spacing out songs by the same artist",
+		},
+		{
+			Name:        "voteremove",
+			Description: "Start or join a vote to remove a song from the queue",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "position",
+					Description: "Queue position of the song to remove (1-based)",
+					Required:    true,
+					MinValue:    func() *float64 { v := 1.0; return &v }(),
+				},
+			},
+		},
+		{
+			Name:        "voteshuffle",
+			Description: "Start or join a vote to smart-shuffle the queue",
+		},
 		{
 			Name:        "clear",
 			Description: "Clear the queue and reset playback state",
@@ -92,6 +278,10 @@ func GetCommands() []*discordgo.ApplicationCommand {
 				},
 			},
 		},
+		{
+			Name:        "queuemode",
+			Description: "Toggle between FIFO and round-robin (one song per DJ) queueing",
+		},
 
 		// Playlist commands
 		{
@@ -209,6 +399,125 @@ func GetCommands() []*discordgo.ApplicationCommand {
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "import",
+					Description: "Import a playlist from a YouTube, Spotify, or SoundCloud URL",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "url",
+							Description: "YouTube/Spotify/SoundCloud playlist URL",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Name for the imported playlist",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "sync",
+					Description: "Re-sync an imported playlist against its remote source",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Playlist name to sync",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "dry_run",
+							Description: "Preview the diff without saving it",
+							Required:    false,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "import-file",
+					Description: "Import a playlist from an uploaded M3U, PLS, or XSPF file",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionAttachment,
+							Name:        "file",
+							Description: "Playlist file (.m3u, .m3u8, .pls, or .xspf)",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Name for the imported playlist",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "Download a playlist as an M3U, PLS, or XSPF file",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Playlist name to export",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "format",
+							Description: "Output file format",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "M3U", Value: "m3u"},
+								{Name: "PLS", Value: "pls"},
+								{Name: "XSPF", Value: "xspf"},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "smart-create",
+					Description: "Create a smart playlist whose songs are computed from rules",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Name for the new smart playlist",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "criteria",
+							Description: `Criteria as JSON, e.g. {"all":[{"field":"source_type","op":"eq","value":"youtube"}]}`,
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "smart-edit",
+					Description: "Replace an existing smart playlist's rules",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Smart playlist name to edit",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "criteria",
+							Description: `New criteria as JSON, e.g. {"all":[{"field":"play_count","op":"gt","value":5}]}`,
+							Required:    true,
+						},
+					},
+				},
 			},
 		},
 
@@ -221,6 +530,10 @@ func GetCommands() []*discordgo.ApplicationCommand {
 			Name:        "leave",
 			Description: "Leave voice channel and clear all state",
 		},
+		{
+			Name:        "autoleave",
+			Description: "Toggle automatically leaving when idle or alone in the voice channel",
+		},
 		{
 			Name:        "stats",
 			Description: "Display bot statistics and status",
@@ -233,5 +546,146 @@ func GetCommands() []*discordgo.ApplicationCommand {
 			Name:        "sync",
 			Description: "[Admin] Force synchronize slash commands with Discord",
 		},
+		{
+			Name:        "find",
+			Description: "Fuzzy-search playlist names, playlist songs, and recently played songs",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "What you're looking for, misspellings OK",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "search",
+			Description: "Search YouTube, SoundCloud, or Spotify and pick a result to queue",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "service",
+					Description: "Service to search",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "YouTube", Value: "yt"},
+						{Name: "SoundCloud", Value: "sc"},
+						{Name: "Spotify", Value: "sp"},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "Search terms",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "cache",
+			Description: "[Admin] Manage the persistent song resolution cache",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "stats",
+					Description: "Show cache entry count and size on disk",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "clear",
+					Description: "Clear all cached song resolutions",
+				},
+			},
+		},
+		{
+			Name:        "logs",
+			Description: "[Admin] Dump the most recent structured log entries",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "count",
+					Description: "Number of entries to show (default 20, max 50)",
+					Required:    false,
+				},
+			},
+		},
+
+		// Scrobbling commands
+		{
+			Name:        "scrobble",
+			Description: "Manage ListenBrainz/Last.fm scrobbling",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "link",
+					Description: "Link your ListenBrainz or Last.fm account",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "service",
+							Description: "Scrobbling service",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "ListenBrainz", Value: "listenbrainz"},
+								{Name: "Last.fm", Value: "lastfm"},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "token",
+							Description: "ListenBrainz user token, or Last.fm session key",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "unlink",
+					Description: "Unlink a previously linked scrobbling account",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "service",
+							Description: "Scrobbling service",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "ListenBrainz", Value: "listenbrainz"},
+								{Name: "Last.fm", Value: "lastfm"},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "enable",
+					Description: "Opt this server in to scrobbling played tracks",
+				},
+			},
+		},
+
+		{
+			Name:        "spotify",
+			Description: "Link your Spotify account and export the queue as a playlist",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "connect",
+					Description: "Link your Spotify account so /spotify export can create playlists for you",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "Save the current queue as a playlist on your Spotify account",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Playlist name",
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
 	}
 }
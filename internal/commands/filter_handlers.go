@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleFilterSubcommand dispatches /filter's subcommands
+func (h *Handler) handleFilterSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return respondError(s, i, "Invalid subcommand")
+	}
+
+	subCmd := options[0]
+	switch subCmd.Name {
+	case "toggle":
+		return h.handleFilterToggle(s, i, subCmd)
+	case "eq":
+		return h.handleFilterEQ(s, i, subCmd)
+	case "reset":
+		return h.handleFilterReset(s, i)
+	case "status":
+		return h.handleFilterStatus(s, i)
+	default:
+		return respondError(s, i, "Unknown subcommand")
+	}
+}
+
+func (h *Handler) handleFilterToggle(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	effect := subCmd.Options[0].StringValue()
+
+	enabled, err := h.playbackService.ToggleFilter(i.GuildID, effect)
+	if err != nil {
+		return respondError(s, i, "Failed to toggle filter: "+err.Error())
+	}
+
+	status := "disabled"
+	if enabled {
+		status = "enabled"
+	}
+
+	embed := NewEmbed().
+		Title("🎛️ Filter Toggled").
+		Description(fmt.Sprintf("**%s** is now %s", filterDisplayName(effect), status)).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleFilterEQ(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	band := int(subCmd.Options[0].IntValue()) - 1 // 1-based in the command, 0-based internally
+	gain := subCmd.Options[1].FloatValue()
+
+	if err := h.playbackService.SetEqualizerBand(i.GuildID, band, gain); err != nil {
+		return respondError(s, i, "Failed to set equalizer band: "+err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("🎛️ Equalizer Updated").
+		Description(fmt.Sprintf("Band %d set to **%.1f dB**", band+1, gain)).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleFilterReset(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	if err := h.playbackService.ResetFilters(i.GuildID); err != nil {
+		return respondError(s, i, "Failed to reset filters: "+err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("🎛️ Filters Reset").
+		Description("Every effect is off and the equalizer is flat").
+		Color(ColorWarning).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleFilterStatus(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	spec := h.playbackService.GetFilters(i.GuildID)
+
+	var effects []string
+	if spec.BassBoost {
+		effects = append(effects, "Bass Boost")
+	}
+	if spec.Nightcore {
+		effects = append(effects, "Nightcore")
+	}
+	if spec.Vaporwave {
+		effects = append(effects, "Vaporwave")
+	}
+	if spec.EightD {
+		effects = append(effects, "8D Audio")
+	}
+	if spec.Karaoke {
+		effects = append(effects, "Karaoke")
+	}
+
+	effectsValue := "None"
+	if len(effects) > 0 {
+		effectsValue = strings.Join(effects, ", ")
+	}
+
+	var bands []string
+	for band, gain := range spec.EqualizerGains {
+		if gain != 0 {
+			bands = append(bands, fmt.Sprintf("Band %d: %.1f dB", band+1, gain))
+		}
+	}
+	bandsValue := "Flat"
+	if len(bands) > 0 {
+		bandsValue = strings.Join(bands, ", ")
+	}
+
+	embed := NewEmbed().
+		Title("🎛️ Current Filter Chain").
+		Color(ColorPrimary).
+		Field("Effects", effectsValue, false).
+		Field("Equalizer", bandsValue, false).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// filterDisplayName maps a /filter toggle effect value to its human-readable
+// command-choice label
+func filterDisplayName(effect string) string {
+	switch effect {
+	case "bassboost":
+		return "Bass Boost"
+	case "nightcore":
+		return "Nightcore"
+	case "vaporwave":
+		return "Vaporwave"
+	case "8d":
+		return "8D Audio"
+	case "karaoke":
+		return "Karaoke"
+	default:
+		return effect
+	}
+}
@@ -7,22 +7,66 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/config"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/cache"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/externalplaylist"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/sources"
+	sourcesspotify "github.com/vuongmanhnghia/discord-music-bot/internal/sources/spotify"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger/hooks"
 )
 
 // Handler manages all bot commands
 type Handler struct {
-	session         *discordgo.Session
-	playbackService *services.PlaybackService
-	playlistService *services.PlaylistService
-	ytService       *youtube.Service
-	logger          *logger.Logger
-	config          *config.Config
+	session           *discordgo.Session
+	playbackService   *services.PlaybackService
+	playlistService   *services.PlaylistService
+	scrobbleService   *services.ScrobbleService
+	searchService     *services.SearchService
+	ytService         *youtube.Service
+	spotifyService    *spotify.Service
+	spotifyBridge     *sourcesspotify.Bridge
+	sourceRegistry    *sources.Registry
+	externalImporters *externalplaylist.Registry
+	songCache         *cache.Manager
+	songAudioCache    *cache.SongCache
+	logger            *logger.Logger
+	logBuffer         *hooks.RingBuffer
+	config            *config.Config
 
 	// Track active playlist per guild
 	activePlaylist   map[string]string
 	activePlaylistMu sync.RWMutex
+
+	// searchResults maps a /search reply's message ID to the resolvable
+	// query (URL) behind each of its select-menu options, so the component
+	// handler can queue the chosen result without re-running the search.
+	// See search_handlers.go.
+	searchResults   map[string][]string
+	searchResultsMu sync.Mutex
+
+	// interactionContexts maps a discordgo interaction ID to the logging
+	// context built for it by requestContext, so nested service calls can
+	// recover it via contextFor. See middleware.go.
+	interactionContexts sync.Map
+
+	// playlistJobs tracks each guild's in-progress handleYouTubePlaylistPlay
+	// extraction or handleSpotifyPlaylistPlay fetch, so a /stop while a
+	// large playlist is still loading can cancel its remaining work
+	// instead of letting it run to completion in the background. See
+	// setPlaylistJob/cancelPlaylistJob.
+	playlistJobs   map[string]playlistLoadJob
+	playlistJobsMu sync.Mutex
+}
+
+// playlistLoadJob is implemented by both youtube.PlaylistJob and
+// spotify.PlaylistTracksJob, so a guild's in-progress large-playlist load -
+// whichever service it came from - can be tracked and cancelled uniformly.
+type playlistLoadJob interface {
+	Loaded() int
+	TotalCount() int
+	Cancel()
 }
 
 // NewHandler creates a new command handler
@@ -30,18 +74,83 @@ func NewHandler(
 	session *discordgo.Session,
 	playbackSvc *services.PlaybackService,
 	playlistSvc *services.PlaylistService,
+	scrobbleSvc *services.ScrobbleService,
+	searchSvc *services.SearchService,
 	ytSvc *youtube.Service,
+	spotifySvc *spotify.Service,
+	sourceRegistry *sources.Registry,
+	importers *externalplaylist.Registry,
+	songCache *cache.Manager,
+	songAudioCache *cache.SongCache,
+	logBuffer *hooks.RingBuffer,
 	log *logger.Logger,
 	config *config.Config,
 ) *Handler {
+	var spotifyBridge *sourcesspotify.Bridge
+	if spotifySvc != nil {
+		spotifyBridge = sourcesspotify.NewBridge(spotifySvc, ytSvc, log)
+	}
+
 	return &Handler{
-		session:         session,
-		playbackService: playbackSvc,
-		playlistService: playlistSvc,
-		ytService:       ytSvc,
-		logger:          log,
-		config:          config,
-		activePlaylist:  make(map[string]string),
+		session:           session,
+		playbackService:   playbackSvc,
+		playlistService:   playlistSvc,
+		scrobbleService:   scrobbleSvc,
+		searchService:     searchSvc,
+		ytService:         ytSvc,
+		spotifyService:    spotifySvc,
+		spotifyBridge:     spotifyBridge,
+		sourceRegistry:    sourceRegistry,
+		externalImporters: importers,
+		songCache:         songCache,
+		songAudioCache:    songAudioCache,
+		logger:            log,
+		logBuffer:         logBuffer,
+		config:            config,
+		activePlaylist:    make(map[string]string),
+		searchResults:     make(map[string][]string),
+		playlistJobs:      make(map[string]playlistLoadJob),
+	}
+}
+
+// setPlaylistJob records job as guildID's in-progress playlist load, so a
+// /stop can find and cancel it. See handleYouTubePlaylistPlay and
+// handleSpotifyPlaylistPlay.
+func (h *Handler) setPlaylistJob(guildID string, job playlistLoadJob) {
+	h.playlistJobsMu.Lock()
+	h.playlistJobs[guildID] = job
+	h.playlistJobsMu.Unlock()
+}
+
+// clearPlaylistJob removes job from guildID's tracked playlist load, but
+// only if it's still the current one - a newer /play on the same guild may
+// have already replaced it.
+func (h *Handler) clearPlaylistJob(guildID string, job playlistLoadJob) {
+	h.playlistJobsMu.Lock()
+	if h.playlistJobs[guildID] == job {
+		delete(h.playlistJobs, guildID)
+	}
+	h.playlistJobsMu.Unlock()
+}
+
+// getPlaylistJob returns guildID's in-progress playlist load, or nil if
+// none is running. See handleQueue's loading-progress footer.
+func (h *Handler) getPlaylistJob(guildID string) playlistLoadJob {
+	h.playlistJobsMu.Lock()
+	defer h.playlistJobsMu.Unlock()
+	return h.playlistJobs[guildID]
+}
+
+// cancelPlaylistJob stops guildID's in-progress playlist extraction, if any,
+// so its remaining workers exit instead of continuing to load songs after
+// the user has asked playback to stop.
+func (h *Handler) cancelPlaylistJob(guildID string) {
+	h.playlistJobsMu.Lock()
+	job := h.playlistJobs[guildID]
+	h.playlistJobsMu.Unlock()
+
+	if job != nil {
+		job.Cancel()
 	}
 }
 
@@ -80,19 +189,20 @@ func (h *Handler) HandleInteraction(s *discordgo.Session, i *discordgo.Interacti
 
 	data := i.ApplicationCommandData()
 
-	h.logger.WithFields(map[string]interface{}{
-		"command": data.Name,
-		"guild":   i.GuildID,
-		"user":    i.Member.User.Username,
-	}).Info("Command received")
+	ctx := requestContext(i, data.Name)
+	defer h.trackInteraction(i, ctx)()
+
+	h.logger.FromContext(ctx).Info("Command received")
 
 	var err error
 	switch data.Name {
 	// Playback commands
 	case "play":
 		err = h.handlePlay(s, i)
-	case "aplay":
-		err = h.handleAPlay(s, i)
+	case "addnext":
+		err = h.handleAddNext(s, i)
+	case "playnow":
+		err = h.handlePlayNow(s, i)
 	case "pause":
 		err = h.handlePause(s, i)
 	case "resume":
@@ -101,8 +211,23 @@ func (h *Handler) HandleInteraction(s *discordgo.Session, i *discordgo.Interacti
 		err = h.handleSkip(s, i)
 	case "stop":
 		err = h.handleStop(s, i)
+	case "voteskip":
+		err = h.handleVoteSkip(s, i)
+	case "votestop":
+		err = h.handleVoteStop(s, i)
+	case "seek":
+		err = h.handleSeek(s, i)
 	case "volume":
 		err = h.handleVolume(s, i)
+	case "crossfade":
+		err = h.handleCrossfade(s, i)
+	case "transfer":
+		err = h.handleTransfer(s, i)
+	case "filter":
+		err = h.handleFilterSubcommand(s, i)
+
+	case "normalize":
+		err = h.handleNormalizeSubcommand(s, i)
 
 	// Queue commands
 	case "queue":
@@ -111,10 +236,18 @@ func (h *Handler) HandleInteraction(s *discordgo.Session, i *discordgo.Interacti
 		err = h.handleNowPlaying(s, i)
 	case "shuffle":
 		err = h.handleShuffle(s, i)
+	case "smartshuffle":
+		err = h.handleSmartShuffle(s, i)
+	case "voteremove":
+		err = h.handleVoteRemove(s, i)
+	case "voteshuffle":
+		err = h.handleVoteShuffle(s, i)
 	case "clear":
 		err = h.handleClear(s, i)
 	case "repeat":
 		err = h.handleRepeat(s, i)
+	case "queuemode":
+		err = h.handleQueueMode(s, i)
 
 	// Playlist commands
 	case "playlists":
@@ -133,19 +266,33 @@ func (h *Handler) HandleInteraction(s *discordgo.Session, i *discordgo.Interacti
 		err = h.handleJoin(s, i)
 	case "leave":
 		err = h.handleLeave(s, i)
+	case "autoleave":
+		err = h.handleAutoLeave(s, i)
 	case "stats":
 		err = h.handleStats(s, i)
 	case "help":
 		err = h.handleHelp(s, i)
 	case "sync":
 		err = h.handleSync(s, i)
+	case "find":
+		err = h.handleFind(s, i)
+	case "search":
+		err = h.handleSearch(s, i)
+	case "cache":
+		err = h.handleCacheSubcommand(s, i)
+	case "scrobble":
+		err = h.handleScrobbleSubcommand(s, i)
+	case "spotify":
+		err = h.handleSpotifySubcommand(s, i)
+	case "logs":
+		err = h.handleLogs(s, i)
 
 	default:
 		err = respondError(s, i, "Unknown command")
 	}
 
 	if err != nil {
-		h.logger.WithError(err).WithField("command", data.Name).Error("Command handler failed")
+		h.logger.FromContext(ctx).WithError(err).Error("Command handler failed")
 	}
 }
 
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxLogLines caps how many buffered entries /logs will render, regardless
+// of the requested count, so a single embed description can't blow past
+// Discord's 4096-character limit.
+const maxLogLines = 50
+
+// handleLogs dumps the most recent structured log entries captured by the
+// logger's ring buffer hook (see pkg/logger/hooks.RingBuffer), so an admin
+// can grep for a guild_id/correlation_id without shelling into the host.
+func (h *Handler) handleLogs(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !isGuildAdmin(i) {
+		return respondError(s, i, "🔒 /logs requires server Administrator permission")
+	}
+
+	if h.logBuffer == nil {
+		return respondError(s, i, "Log buffer is not enabled")
+	}
+
+	count := 20
+	if opts := i.ApplicationCommandData().Options; len(opts) > 0 {
+		count = int(opts[0].IntValue())
+	}
+	if count > maxLogLines {
+		count = maxLogLines
+	}
+
+	entries := h.logBuffer.Last(count)
+	if len(entries) == 0 {
+		return respondInfo(s, i, "No log entries captured yet")
+	}
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "[%s] %-5s %s", e.Time.Format("15:04:05"), strings.ToUpper(e.Level), e.Message)
+		for k, v := range e.Fields {
+			fmt.Fprintf(&b, " %s=%v", k, v)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("```")
+
+	embed := NewEmbed().
+		Title(fmt.Sprintf("📜 Last %d Log Entries", len(entries))).
+		Description(b.String()).
+		Color(ColorInfo).
+		Build()
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
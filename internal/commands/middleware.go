@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// requestContext builds the logging context for an interaction: a fresh
+// correlation ID plus guild/user/command fields, so every log line emitted
+// via logger.FromContext for this interaction - no matter how deep the call
+// chain - can be traced back to the request that caused it.
+func requestContext(i *discordgo.InteractionCreate, command string) context.Context {
+	fields := logrus.Fields{
+		"correlation_id": uuid.New().String(),
+		"command":        command,
+		"guild":          i.GuildID,
+		"channel":        i.ChannelID,
+	}
+	if i.Member != nil && i.Member.User != nil {
+		fields["user"] = i.Member.User.Username
+	}
+	return logger.WithContext(context.Background(), fields)
+}
+
+// trackInteraction records ctx for the lifetime of an interaction so nested
+// service calls can recover it via contextFor without every handler
+// signature in the package needing an explicit ctx parameter. The returned
+// func must be deferred to release the entry once the interaction is done.
+func (h *Handler) trackInteraction(i *discordgo.InteractionCreate, ctx context.Context) func() {
+	h.interactionContexts.Store(i.Interaction.ID, ctx)
+	return func() { h.interactionContexts.Delete(i.Interaction.ID) }
+}
+
+// contextFor recovers the logging context built for an in-flight
+// interaction by trackInteraction, falling back to a bare background context
+// if none was recorded (e.g. a call originating outside HandleInteraction)
+func (h *Handler) contextFor(i *discordgo.InteractionCreate) context.Context {
+	if ctx, ok := h.interactionContexts.Load(i.Interaction.ID); ok {
+		return ctx.(context.Context)
+	}
+	return context.Background()
+}
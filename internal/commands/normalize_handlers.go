@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleNormalizeSubcommand dispatches /normalize's subcommands
+func (h *Handler) handleNormalizeSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return respondError(s, i, "Invalid subcommand")
+	}
+
+	subCmd := options[0]
+	switch subCmd.Name {
+	case "on":
+		return h.handleNormalizeOn(s, i)
+	case "off":
+		return h.handleNormalizeOff(s, i)
+	case "target":
+		return h.handleNormalizeTarget(s, i, subCmd)
+	case "status":
+		return h.handleNormalizeStatus(s, i)
+	default:
+		return respondError(s, i, "Unknown subcommand")
+	}
+}
+
+func (h *Handler) handleNormalizeOn(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	target := h.playbackService.GetNormalization(i.GuildID).TargetLUFS
+	if err := h.playbackService.SetNormalization(i.GuildID, true, target); err != nil {
+		return respondError(s, i, "Failed to enable normalization: "+err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("🔊 Normalization Enabled").
+		Description(fmt.Sprintf("Tracks will be leveled to **%.0f LUFS**", target)).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleNormalizeOff(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	target := h.playbackService.GetNormalization(i.GuildID).TargetLUFS
+	if err := h.playbackService.SetNormalization(i.GuildID, false, target); err != nil {
+		return respondError(s, i, "Failed to disable normalization: "+err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("🔊 Normalization Disabled").
+		Description("Tracks will play at their original loudness").
+		Color(ColorWarning).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleNormalizeTarget(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	target := subCmd.Options[0].FloatValue()
+
+	if err := h.playbackService.SetNormalization(i.GuildID, true, target); err != nil {
+		return respondError(s, i, "Failed to set normalization target: "+err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("🔊 Normalization Target Updated").
+		Description(fmt.Sprintf("Tracks will be leveled to **%.0f LUFS**", target)).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleNormalizeStatus(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	config := h.playbackService.GetNormalization(i.GuildID)
+
+	status := "Off"
+	if config.Enabled {
+		status = "On"
+	}
+
+	embed := NewEmbed().
+		Title("🔊 Normalization Status").
+		Color(ColorPrimary).
+		Field("State", status, true).
+		Field("Target", fmt.Sprintf("%.0f LUFS", config.TargetLUFS), true).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
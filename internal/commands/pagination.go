@@ -121,6 +121,9 @@ func buildQueuePage(tracklist *entities.Tracklist, page int) (*discordgo.Message
 				title = title[:47] + "..."
 			}
 			duration := meta.DurationFormatted()
+			if meta.StartOffset > 0 {
+				duration = fmt.Sprintf("%s @%s", duration, meta.StartOffsetFormatted())
+			}
 			sb.WriteString(fmt.Sprintf("%s **%s** `[%s]`\n", indicator, title, duration))
 		} else {
 			songName := song.DisplayName()
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// isAuthorizedController reports whether i's invoker may run a control
+// command (pause, stop, skip override, volume, queue clear) in this guild:
+// a server admin, the current session owner (see PlaybackService.Owner), or
+// anyone once cfg.OwnerlessMode disables the gate or nobody has claimed
+// ownership yet.
+func (h *Handler) isAuthorizedController(i *discordgo.InteractionCreate) bool {
+	if h.config.OwnerlessMode {
+		return true
+	}
+	if i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator != 0 {
+		return true
+	}
+	return h.playbackService.IsOwner(i.GuildID, i.Member.User.ID)
+}
+
+// ownerDeniedMessage is the friendly error shown when isAuthorizedController
+// fails, naming whoever currently controls playback
+func (h *Handler) ownerDeniedMessage(guildID string) string {
+	return fmt.Sprintf("🔒 Playback is controlled by <@%s>. Ask them, or have them run `/transfer` to hand it to you.", h.playbackService.Owner(guildID))
+}
+
+// isGuildAdmin reports whether i's invoker has server Administrator
+// permission. Unlike isAuthorizedController, this never opens up to the
+// session owner or OwnerlessMode - it gates commands like /cache that
+// affect the bot globally rather than one guild's playback session.
+func isGuildAdmin(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator != 0
+}
+
+// isAdmin reports whether i's invoker is a server Administrator or holds one
+// of the roles listed in config.AdminRoleIDs. Gates /addnext and /playnow,
+// which jump the queue in a way that's disruptive if anyone could do it.
+func (h *Handler) isAdmin(i *discordgo.InteractionCreate) bool {
+	if isGuildAdmin(i) {
+		return true
+	}
+	if i.Member == nil || len(h.config.AdminRoleIDs) == 0 {
+		return false
+	}
+	for _, roleID := range i.Member.Roles {
+		for _, adminRole := range h.config.AdminRoleIDs {
+			if roleID == adminRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDJ reports whether i's invoker holds one of config.DJRoleIDs. DJs bypass
+// /voteremove and /voteshuffle's democratic vote entirely - the same
+// privilege isAuthorizedController already grants /skip and /stop's voting
+// paths, but scoped to its own role list rather than reusing admin/owner.
+func (h *Handler) isDJ(i *discordgo.InteractionCreate) bool {
+	if i.Member == nil || len(h.config.DJRoleIDs) == 0 {
+		return false
+	}
+	for _, roleID := range i.Member.Roles {
+		for _, djRole := range h.config.DJRoleIDs {
+			if roleID == djRole {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,15 +1,22 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/soundcloud"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/sources"
 )
 
 // handlePlay handles the play command
@@ -34,31 +41,54 @@ func (h *Handler) handlePlay(s *discordgo.Session, i *discordgo.InteractionCreat
 		}
 	}
 
+	// Check for a YouTube playlist and use progressive loading, so a large
+	// one starts playing on its first track instead of stalling /play until
+	// every entry has been extracted
+	if youtube.IsPlaylistURL(query) {
+		return h.handleYouTubePlaylistPlay(s, i, query, channelID)
+	}
+
 	// Resolve query to song URLs (handles single video, playlist, or search)
-	songs, isPlaylist, err := h.ResolveSongURLs(query)
+	songs, kind, err := h.ResolveSongURLs(h.contextFor(i), query)
 	if err != nil {
 		return followUpError(s, i, err.Error())
 	}
 
 	// For single URL, extract metadata before adding to show proper title
 	var extractedTitle string
-	if !isPlaylist && len(songs) == 1 {
+	if !kind.IsPlaylist() && len(songs) == 1 {
 		songURL := songs[0].URL
 		if strings.HasPrefix(songURL, "http://") || strings.HasPrefix(songURL, "https://") {
 			h.logger.WithField("url", songURL).Debug("Extracting metadata for single URL")
-			if info, err := h.ytService.ExtractInfo(songURL); err == nil {
+			info, err := h.extractMetadataCached(h.contextFor(i), songURL)
+			if err != nil {
+				if errors.Is(err, youtube.ErrEmptyMetadata) {
+					return followUpError(s, i, "This video is unavailable")
+				}
+				// Any other extraction failure (network hiccup, etc.) just
+				// falls back to the query as a display title below
+			} else {
 				extractedTitle = info.Title
 				songs[0].Title = info.Title // Update title for display
 			}
 		}
 	}
 
+	// Cap playlist fan-out so a single URL can't flood the queue
+	skipped := 0
+	if kind.IsPlaylist() && len(songs) > h.config.MaxPlaylistURLSize {
+		skipped = len(songs) - h.config.MaxPlaylistURLSize
+		songs = songs[:h.config.MaxPlaylistURLSize]
+	}
+
 	// Add all songs to queue
 	addedCount := 0
+	failedCount := 0
 	for _, songInfo := range songs {
 		song := entities.NewSong(songInfo.URL, songInfo.SourceType, i.Member.User.ID, i.GuildID)
-		if err := h.playbackService.AddSong(i.GuildID, song); err != nil {
+		if err := h.playbackService.AddSong(h.contextFor(i), i.GuildID, song); err != nil {
 			h.logger.WithError(err).Warn("Failed to add song")
+			failedCount++
 			continue
 		}
 		addedCount++
@@ -68,23 +98,28 @@ func (h *Handler) handlePlay(s *discordgo.Session, i *discordgo.InteractionCreat
 		return followUpError(s, i, "Failed to add any songs")
 	}
 
+	h.playbackService.BindNowPlayingChannel(i.GuildID, i.ChannelID)
+
 	// Start playback if not already playing
 	if !h.playbackService.IsPlaying(i.GuildID) {
-		if err := h.playbackService.Play(i.GuildID, channelID); err != nil {
+		if err := h.playbackService.Play(h.contextFor(i), i.GuildID, channelID, i.Member.User.ID); err != nil {
 			return followUpError(s, i, fmt.Sprintf("Failed to start playback: %v", err))
 		}
 	}
 
 	// Build appropriate response
 	var embed *discordgo.MessageEmbed
-	if isPlaylist {
-		embed = NewEmbed().
+	if kind.IsPlaylist() {
+		builder := NewEmbed().
 			Title("📻 Playlist Added").
 			Description(fmt.Sprintf("Successfully added **%d** songs to the queue", addedCount)).
 			Color(ColorSuccess).
-			Field("Songs Added", fmt.Sprintf("%d", addedCount), true).
-			Footer("Use /queue to view the queue").
-			Build()
+			Field("Added", fmt.Sprintf("%d", addedCount), true).
+			Field("Failed", fmt.Sprintf("%d", failedCount), true)
+		if skipped > 0 {
+			builder.Field("Skipped (cap reached)", fmt.Sprintf("%d", skipped), true)
+		}
+		embed = builder.Footer("Use /queue to view the queue").Build()
 	} else {
 		displayTitle := extractedTitle
 		if displayTitle == "" {
@@ -104,31 +139,188 @@ func (h *Handler) handlePlay(s *discordgo.Session, i *discordgo.InteractionCreat
 	return followUpEmbed(s, i, embed)
 }
 
-// handleSpotifyPlaylistPlay handles Spotify playlist/album with progressive loading
+// handleAddNext resolves a query the same way /play does, but inserts the
+// result(s) immediately after the currently playing song instead of
+// appending to the end of the queue
+func (h *Handler) handleAddNext(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAdmin(i) {
+		return respondError(s, i, "🔒 /addnext requires an admin role")
+	}
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	options := i.ApplicationCommandData().Options
+	query := options[0].StringValue()
+
+	if _, err := h.getUserVoiceChannel(s, i.GuildID, i.Member.User.ID); err != nil {
+		return followUpError(s, i, "You must be in a voice channel to play music")
+	}
+
+	songs, kind, err := h.ResolveSongURLs(h.contextFor(i), query)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	skipped := 0
+	if kind.IsPlaylist() && len(songs) > h.config.MaxPlaylistURLSize {
+		skipped = len(songs) - h.config.MaxPlaylistURLSize
+		songs = songs[:h.config.MaxPlaylistURLSize]
+	}
+
+	addedCount := h.insertSongsAfterCurrent(h.contextFor(i), i.GuildID, i.Member.User.ID, songs)
+	if addedCount == 0 {
+		return followUpError(s, i, "Failed to add any songs")
+	}
+
+	h.playbackService.BindNowPlayingChannel(i.GuildID, i.ChannelID)
+
+	description := fmt.Sprintf("Queued **%d** song(s) to play next", addedCount)
+	if skipped > 0 {
+		description += fmt.Sprintf(" (%d skipped, cap reached)", skipped)
+	}
+	embed := NewEmbed().
+		Title("⏭️ Added Next").
+		Description(description).
+		Color(ColorSuccess).
+		Build()
+
+	return followUpEmbed(s, i, embed)
+}
+
+// handlePlayNow resolves a query the same way /addnext does, then force-skips
+// the currently playing song so the result starts immediately. The replaced
+// song isn't lost - it's simply next up again, right after whatever was
+// inserted.
+func (h *Handler) handlePlayNow(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAdmin(i) {
+		return respondError(s, i, "🔒 /playnow requires an admin role")
+	}
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	options := i.ApplicationCommandData().Options
+	query := options[0].StringValue()
+
+	channelID, err := h.getUserVoiceChannel(s, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		return followUpError(s, i, "You must be in a voice channel to play music")
+	}
+
+	songs, kind, err := h.ResolveSongURLs(h.contextFor(i), query)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	skipped := 0
+	if kind.IsPlaylist() && len(songs) > h.config.MaxPlaylistURLSize {
+		skipped = len(songs) - h.config.MaxPlaylistURLSize
+		songs = songs[:h.config.MaxPlaylistURLSize]
+	}
+
+	wasPlaying := h.playbackService.IsPlaying(i.GuildID)
+
+	addedCount := h.insertSongsAfterCurrent(h.contextFor(i), i.GuildID, i.Member.User.ID, songs)
+	if addedCount == 0 {
+		return followUpError(s, i, "Failed to add any songs")
+	}
+
+	h.playbackService.BindNowPlayingChannel(i.GuildID, i.ChannelID)
+
+	if !wasPlaying {
+		if err := h.playbackService.Play(h.contextFor(i), i.GuildID, channelID, i.Member.User.ID); err != nil {
+			return followUpError(s, i, fmt.Sprintf("Failed to start playback: %v", err))
+		}
+	} else if err := h.playbackService.ForceSkip(h.contextFor(i), i.GuildID); err != nil {
+		return followUpError(s, i, fmt.Sprintf("Failed to skip to inserted song: %v", err))
+	}
+
+	description := fmt.Sprintf("Now playing **%d** inserted song(s)", addedCount)
+	if skipped > 0 {
+		description += fmt.Sprintf(" (%d skipped, cap reached)", skipped)
+	}
+	embed := NewEmbed().
+		Title("⏯️ Playing Now").
+		Description(description).
+		Color(ColorSuccess).
+		Build()
+
+	return followUpEmbed(s, i, embed)
+}
+
+// insertSongsAfterCurrent inserts songs in order immediately after the
+// guild's currently playing song (or at the front of an empty/nonexistent
+// queue), returning how many were successfully inserted
+func (h *Handler) insertSongsAfterCurrent(ctx context.Context, guildID, userID string, songs []SongInfo) int {
+	current := 0
+	if tracklist := h.playbackService.GetTracklist(guildID); tracklist != nil {
+		current, _ = tracklist.Position()
+	}
+
+	addedCount := 0
+	for idx, songInfo := range songs {
+		song := entities.NewSong(songInfo.URL, songInfo.SourceType, userID, guildID)
+		if err := h.playbackService.InsertAt(ctx, guildID, current+1+idx, song); err != nil {
+			h.logger.WithError(err).Warn("Failed to insert song")
+			continue
+		}
+		addedCount++
+	}
+	return addedCount
+}
+
+// handleSpotifyPlaylistPlay handles Spotify playlist/album with progressive
+// loading: GetPlaylistTracksAsync/GetAlbumTracksAsync fetch pages
+// concurrently and stream them back as they complete, so a huge
+// playlist/album doesn't block on every page before the first song can be
+// queued. Registered via setPlaylistJob so /stop can cancel any pages still
+// in flight, the same as handleYouTubePlaylistPlay's extraction job.
 func (h *Handler) handleSpotifyPlaylistPlay(s *discordgo.Session, i *discordgo.InteractionCreate, urlType, id, channelID string) error {
 	h.logger.WithFields(map[string]interface{}{
 		"type": urlType,
 		"id":   id,
 	}).Info("Using progressive loading for Spotify playlist/album")
 
-	// Get all tracks from Spotify
-	var tracks []spotify.Track
+	ctx := h.contextFor(i)
+
+	var job *spotify.PlaylistTracksJob
 	var err error
 	if urlType == "playlist" {
-		tracks, err = h.spotifyService.GetPlaylistTracks(id)
+		job, err = h.spotifyService.GetPlaylistTracksAsync(ctx, id)
 	} else {
-		tracks, err = h.spotifyService.GetAlbumTracks(id)
+		job, err = h.spotifyService.GetAlbumTracksAsync(ctx, id)
 	}
 	if err != nil {
 		return followUpError(s, i, fmt.Sprintf("Failed to get Spotify %s: %v", urlType, err))
 	}
+	if job.Total == 0 {
+		return followUpError(s, i, fmt.Sprintf("Spotify %s is empty", urlType))
+	}
+
+	h.setPlaylistJob(i.GuildID, job)
+	defer h.clearPlaylistJob(i.GuildID, job)
+
+	tracks := make([]spotify.Track, 0, job.Total)
+	for track := range job.Results {
+		tracks = append(tracks, track)
+	}
 
 	if len(tracks) == 0 {
 		return followUpError(s, i, fmt.Sprintf("Spotify %s is empty", urlType))
 	}
 
+	// Cap playlist fan-out so a single URL can't flood the queue
+	skipped := 0
+	if len(tracks) > h.config.MaxPlaylistURLSize {
+		skipped = len(tracks) - h.config.MaxPlaylistURLSize
+		tracks = tracks[:h.config.MaxPlaylistURLSize]
+	}
+
 	// Resolve tracks progressively
-	initialSongs, totalCount := h.addSpotifyTracksProgressively(i.GuildID, i.Member.User.ID, tracks, h.config.InitialLoadSize)
+	initialSongs, totalCount := h.addSpotifyTracksProgressively(h.contextFor(i), i.GuildID, i.Member.User.ID, tracks, h.config.InitialLoadSize)
 
 	if len(initialSongs) == 0 {
 		return followUpError(s, i, "Failed to resolve any songs from Spotify playlist")
@@ -138,7 +330,7 @@ func (h *Handler) handleSpotifyPlaylistPlay(s *discordgo.Session, i *discordgo.I
 	addedCount := 0
 	for _, songInfo := range initialSongs {
 		song := entities.NewSong(songInfo.URL, songInfo.SourceType, i.Member.User.ID, i.GuildID)
-		if err := h.playbackService.AddSong(i.GuildID, song); err != nil {
+		if err := h.playbackService.AddSong(h.contextFor(i), i.GuildID, song); err != nil {
 			h.logger.WithError(err).Warn("Failed to add song")
 			continue
 		}
@@ -149,9 +341,11 @@ func (h *Handler) handleSpotifyPlaylistPlay(s *discordgo.Session, i *discordgo.I
 		return followUpError(s, i, "Failed to add any songs to queue")
 	}
 
+	h.playbackService.BindNowPlayingChannel(i.GuildID, i.ChannelID)
+
 	// Start playback if not already playing
 	if !h.playbackService.IsPlaying(i.GuildID) {
-		if err := h.playbackService.Play(i.GuildID, channelID); err != nil {
+		if err := h.playbackService.Play(h.contextFor(i), i.GuildID, channelID, i.Member.User.ID); err != nil {
 			return followUpError(s, i, fmt.Sprintf("Failed to start playback: %v", err))
 		}
 	}
@@ -162,18 +356,129 @@ func (h *Handler) handleSpotifyPlaylistPlay(s *discordgo.Session, i *discordgo.I
 		description = fmt.Sprintf("✅ Successfully added **%d** songs to the queue", addedCount)
 	}
 
-	embed := NewEmbed().
+	builder := NewEmbed().
 		Title("📻 Spotify Playlist Added").
 		Description(description).
 		Color(ColorSuccess).
 		Field("Total Tracks", fmt.Sprintf("%d", totalCount), true).
-		Field("Playing Now", fmt.Sprintf("%d", addedCount), true).
-		Footer("Use /queue to view the queue").
-		Build()
+		Field("Playing Now", fmt.Sprintf("%d", addedCount), true)
+	if skipped > 0 {
+		builder.Field("Skipped (cap reached)", fmt.Sprintf("%d", skipped), true)
+	}
+	embed := builder.Footer("Use /queue to view the queue").Build()
 
 	return followUpEmbed(s, i, embed)
 }
 
+// playlistProgressUpdateEvery is how many resolved entries pass between
+// edits of handleYouTubePlaylistPlay's loading-progress embed
+const playlistProgressUpdateEvery = 10
+
+// handleYouTubePlaylistPlay handles a YouTube playlist URL with progressive
+// loading: ExtractPlaylistAsync's worker pool resolves entries concurrently
+// and streams them back as they complete, so playback starts on the first
+// one instead of /play blocking until the whole playlist has been extracted.
+// A loading embed is edited every playlistProgressUpdateEvery entries, and
+// /stop cancels any workers still in flight (see cancelPlaylistJob).
+func (h *Handler) handleYouTubePlaylistPlay(s *discordgo.Session, i *discordgo.InteractionCreate, query, channelID string) error {
+	ctx := h.contextFor(i)
+
+	job, err := h.ytService.ExtractPlaylistAsync(ctx, query, h.config.PlaylistExtractWorkers)
+	if err != nil {
+		return followUpError(s, i, fmt.Sprintf("failed to extract playlist: %v", err))
+	}
+	if job.Total == 0 {
+		return followUpError(s, i, "playlist is empty or invalid")
+	}
+
+	h.setPlaylistJob(i.GuildID, job)
+	defer h.clearPlaylistJob(i.GuildID, job)
+
+	// Cap playlist fan-out so a single URL can't flood the queue
+	limit := job.Total
+	if h.config.MaxPlaylistURLSize > 0 && limit > h.config.MaxPlaylistURLSize {
+		limit = h.config.MaxPlaylistURLSize
+	}
+
+	msg, err := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{playlistProgressEmbed(0, limit)},
+	})
+	if err != nil {
+		return err
+	}
+
+	started := false
+	addedCount := 0
+	skipped := 0
+	nextProgressUpdate := playlistProgressUpdateEvery
+
+	for info := range job.Results {
+		if addedCount >= limit {
+			skipped++
+			job.Cancel() // stop workers still in flight once the cap is hit
+			continue
+		}
+
+		song := entities.NewSong(fmt.Sprintf("https://www.youtube.com/watch?v=%s", info.ID), valueobjects.SourceTypeYouTube, i.Member.User.ID, i.GuildID)
+		if err := h.playbackService.AddSong(ctx, i.GuildID, song); err != nil {
+			h.logger.WithError(err).Warn("Failed to add playlist song")
+			continue
+		}
+		addedCount++
+
+		if !started {
+			started = true
+			h.playbackService.BindNowPlayingChannel(i.GuildID, i.ChannelID)
+			if !h.playbackService.IsPlaying(i.GuildID) {
+				if err := h.playbackService.Play(h.contextFor(i), i.GuildID, channelID, i.Member.User.ID); err != nil {
+					h.logger.WithError(err).Warn("Failed to start playback on first playlist entry")
+				}
+			}
+		}
+
+		if loaded := job.Loaded(); loaded >= nextProgressUpdate || loaded == job.Total {
+			nextProgressUpdate = loaded + playlistProgressUpdateEvery
+			embeds := []*discordgo.MessageEmbed{playlistProgressEmbed(loaded, limit)}
+			if _, err := s.FollowupMessageEdit(i.Interaction, msg.ID, &discordgo.WebhookEdit{Embeds: &embeds}); err != nil {
+				h.logger.WithError(err).Debug("Failed to update playlist load progress")
+			}
+		}
+	}
+
+	if addedCount == 0 {
+		embeds := []*discordgo.MessageEmbed{NewEmbed().
+			Title("📻 Playlist Load Failed").
+			Description("Failed to add any songs").
+			Color(ColorError).
+			Build()}
+		_, _ = s.FollowupMessageEdit(i.Interaction, msg.ID, &discordgo.WebhookEdit{Embeds: &embeds})
+		return nil
+	}
+
+	description := fmt.Sprintf("✅ Added **%d** songs to the queue", addedCount)
+	if skipped > 0 {
+		description += fmt.Sprintf(" (%d skipped, cap reached)", skipped)
+	}
+	embeds := []*discordgo.MessageEmbed{NewEmbed().
+		Title("📻 Playlist Added").
+		Description(description).
+		Color(ColorSuccess).
+		Footer("Use /queue to view the queue").
+		Build()}
+	_, err = s.FollowupMessageEdit(i.Interaction, msg.ID, &discordgo.WebhookEdit{Embeds: &embeds})
+	return err
+}
+
+// playlistProgressEmbed renders handleYouTubePlaylistPlay's "Loaded N/total…"
+// loading message
+func playlistProgressEmbed(loaded, total int) *discordgo.MessageEmbed {
+	return NewEmbed().
+		Title("📻 Loading YouTube Playlist").
+		Description(fmt.Sprintf("Loaded %d/%d…", loaded, total)).
+		Color(ColorInfo).
+		Build()
+}
+
 // SongInfo represents a resolved song with URL, title, and source type
 type SongInfo struct {
 	URL        string
@@ -181,18 +486,42 @@ type SongInfo struct {
 	SourceType valueobjects.SourceType
 }
 
+// songsFromMediaInfo converts a sources.MediaInfo into the SongInfo/kind
+// pair ResolveSongURLs returns. SourceType is always SourceTypeYouTube
+// regardless of the originating provider since every source is ultimately
+// played by piping its URL through yt-dlp (see internal/services/audio).
+func songsFromMediaInfo(media *sources.MediaInfo) ([]SongInfo, valueobjects.SourceKind) {
+	if media.IsPlaylist {
+		songs := make([]SongInfo, 0, len(media.Entries))
+		for _, e := range media.Entries {
+			songs = append(songs, SongInfo{
+				URL:        e.URL,
+				Title:      e.Title,
+				SourceType: valueobjects.SourceTypeYouTube,
+			})
+		}
+		return songs, valueobjects.SourceKindPlaylistURL
+	}
+
+	return []SongInfo{{
+		URL:        media.URL,
+		Title:      media.Title,
+		SourceType: valueobjects.SourceTypeYouTube,
+	}}, valueobjects.SourceKindTrackURL
+}
+
 // ResolveSongURLs resolves a query (URL/search) into a list of song URLs and titles
-// Returns: list of (URL, title) pairs and whether it was a playlist
-func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
+// Returns: list of (URL, title) pairs and whether it resolved to a track or a playlist
+func (h *Handler) ResolveSongURLs(ctx context.Context, query string) ([]SongInfo, valueobjects.SourceKind, error) {
 	// Check if query is a Spotify URL
 	if spotify.IsSpotifyURL(query) {
 		if h.spotifyService == nil {
-			return nil, false, fmt.Errorf("Spotify support is not enabled. Please contact the bot owner to add Spotify credentials")
+			return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("Spotify support is not enabled. Please contact the bot owner to add Spotify credentials")
 		}
 
 		urlType, id, err := spotify.ParseSpotifyURL(query)
 		if err != nil {
-			return nil, false, fmt.Errorf("invalid Spotify URL: %w", err)
+			return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("invalid Spotify URL: %w", err)
 		}
 
 		h.logger.WithFields(map[string]interface{}{
@@ -201,146 +530,114 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 		}).Info("Detected Spotify URL")
 
 		var tracks []spotify.Track
-		isPlaylist := false
+		kind := valueobjects.SourceKindTrackURL
 
 		switch urlType {
 		case "track":
 			track, err := h.spotifyService.GetTrack(id)
 			if err != nil {
-				return nil, false, fmt.Errorf("failed to get Spotify track: %w", err)
+				return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("failed to get Spotify track: %w", err)
 			}
 			tracks = []spotify.Track{*track}
 
 		case "playlist":
 			var err error
-			tracks, err = h.spotifyService.GetPlaylistTracks(id)
+			tracks, err = h.spotifyService.GetPlaylistTracks(ctx, id)
 			if err != nil {
-				return nil, false, fmt.Errorf("failed to get Spotify playlist: %w", err)
+				return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("failed to get Spotify playlist: %w", err)
 			}
-			isPlaylist = true
+			kind = valueobjects.SourceKindPlaylistURL
 
 		case "album":
 			var err error
-			tracks, err = h.spotifyService.GetAlbumTracks(id)
+			tracks, err = h.spotifyService.GetAlbumTracks(ctx, id)
+			if err != nil {
+				return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("failed to get Spotify album: %w", err)
+			}
+			kind = valueobjects.SourceKindPlaylistURL
+
+		case "artist":
+			var err error
+			tracks, err = h.spotifyService.GetArtistTopTracks(id, "")
+			if err != nil {
+				return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("failed to get Spotify artist top tracks: %w", err)
+			}
+			kind = valueobjects.SourceKindPlaylistURL
+
+		case "show":
+			var err error
+			tracks, err = h.spotifyService.GetShowEpisodes(id)
+			if err != nil {
+				return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("failed to get Spotify show: %w", err)
+			}
+			kind = valueobjects.SourceKindPlaylistURL
+
+		case "episode":
+			track, err := h.spotifyService.GetEpisode(id)
 			if err != nil {
-				return nil, false, fmt.Errorf("failed to get Spotify album: %w", err)
+				return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("failed to get Spotify episode: %w", err)
 			}
-			isPlaylist = true
+			tracks = []spotify.Track{*track}
 
 		default:
-			return nil, false, fmt.Errorf("unsupported Spotify URL type: %s", urlType)
+			return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("unsupported Spotify URL type: %s", urlType)
 		}
 
 		if len(tracks) == 0 {
-			return nil, false, fmt.Errorf("no tracks found in Spotify content")
+			return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("no tracks found in Spotify content")
 		}
 
 		// Search YouTube for each Spotify track
 		songs := make([]SongInfo, 0, len(tracks))
 		for _, track := range tracks {
-			var videoID string
-			var found bool
-			spotifyDuration := track.GetDurationSeconds()
-
-			// Strategy 1: Try ISRC search first (most accurate)
-			if isrc := track.GetISRC(); isrc != "" {
-				h.logger.WithFields(map[string]interface{}{
-					"track": track.Name,
-					"isrc":  isrc,
-				}).Debug("Trying ISRC search")
-
-				if info, err := h.ytService.SearchByISRC(isrc); err == nil {
-					// Verify duration (±5 seconds tolerance)
-					if absFloat(info.Duration-float64(spotifyDuration)) <= 5 {
-						videoID = info.ID
-						found = true
-						h.logger.WithField("track", track.Name).Info("✅ Found by ISRC with duration match")
-					} else {
-						h.logger.WithFields(map[string]interface{}{
-							"track":            track.Name,
-							"spotify_duration": spotifyDuration,
-							"youtube_duration": info.Duration,
-						}).Warn("ISRC match but duration mismatch, trying other methods")
-					}
-				}
+			ytURL := h.resolveSpotifyTrackToYouTube(track)
+			if ytURL == "" {
+				continue
 			}
 
-			// Strategy 2: Try detailed search with album info
-			if !found {
-				detailedQuery := track.ToDetailedSearchQuery()
-				h.logger.WithField("query", detailedQuery).Debug("Trying detailed search")
-
-				results, err := h.ytService.Search(detailedQuery, 3) // Get top 3 results
-				if err == nil && len(results) > 0 {
-					// Find best match by duration
-					bestMatch := findBestDurationMatch(results, spotifyDuration)
-					if bestMatch != nil {
-						videoID = bestMatch.ID
-						found = true
-						h.logger.WithField("track", track.Name).Info("✅ Found by detailed search")
-					}
-				}
-			}
-
-			// Strategy 3: Fall back to simple search
-			if !found {
-				simpleQuery := track.ToSearchQuery()
-				h.logger.WithField("query", simpleQuery).Debug("Trying simple search")
-
-				results, err := h.ytService.Search(simpleQuery, 3)
-				if err != nil {
-					h.logger.WithError(err).WithField("track", track.Name).Warn("All search methods failed")
-					continue
-				}
-
-				if len(results) == 0 {
-					h.logger.WithField("track", track.Name).Warn("No YouTube results found")
-					continue
-				}
-
-				// Find best match by duration
-				bestMatch := findBestDurationMatch(results, spotifyDuration)
-				if bestMatch != nil {
-					videoID = bestMatch.ID
-					found = true
-					h.logger.WithField("track", track.Name).Info("✅ Found by simple search")
-				} else {
-					// Last resort: use first result
-					videoID = results[0].ID
-					h.logger.WithField("track", track.Name).Warn("⚠️ Using first result (no duration match)")
-				}
-			}
-
-			if found || videoID != "" {
-				songs = append(songs, SongInfo{
-					URL:        fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID),
-					Title:      track.ToSearchQuery(),
-					SourceType: valueobjects.SourceTypeYouTube,
-				})
-			}
+			songs = append(songs, SongInfo{
+				URL:        ytURL,
+				Title:      track.ToSearchQuery(),
+				SourceType: valueobjects.SourceTypeYouTube,
+			})
 		}
 
 		if len(songs) == 0 {
-			return nil, false, fmt.Errorf("could not find any YouTube videos for Spotify tracks")
+			return nil, valueobjects.SourceKindTrackURL, fmt.Errorf("could not find any YouTube videos for Spotify tracks")
 		}
 
-		return songs, isPlaylist, nil
+		return songs, kind, nil
 	}
 
 	// Check if query is a SoundCloud URL
 	if soundcloud.IsSoundCloudURL(query) {
 		h.logger.WithField("url", query).Info("Detected SoundCloud URL")
 
+		// Prefer the SoundCloud API provider (real metadata, no yt-dlp
+		// subprocess) when a client_id is configured
+		if provider := h.sourceRegistry.ByName("soundcloud"); provider != nil {
+			media, err := provider.Extract(context.Background(), query)
+			if err != nil {
+				h.logger.WithError(err).Warn("SoundCloud provider extraction failed, falling back to yt-dlp")
+			} else {
+				songs, kind := songsFromMediaInfo(media)
+				if len(songs) == 0 {
+					return nil, kind, fmt.Errorf("no tracks found in SoundCloud content")
+				}
+				return songs, kind, nil
+			}
+		}
+
 		// SoundCloud playlists/sets
 		if soundcloud.IsPlaylistURL(query) {
 			h.logger.Info("Extracting SoundCloud playlist")
 			videos, err := h.ytService.ExtractPlaylist(query)
 			if err != nil {
-				return nil, false, fmt.Errorf("failed to extract SoundCloud playlist: %w", err)
+				return nil, valueobjects.SourceKindPlaylistURL, fmt.Errorf("failed to extract SoundCloud playlist: %w", err)
 			}
 
 			if len(videos) == 0 {
-				return nil, false, fmt.Errorf("SoundCloud playlist is empty or invalid")
+				return nil, valueobjects.SourceKindPlaylistURL, fmt.Errorf("SoundCloud playlist is empty or invalid")
 			}
 
 			songs := make([]SongInfo, 0, len(videos))
@@ -359,7 +656,7 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 				})
 			}
 
-			return songs, true, nil
+			return songs, valueobjects.SourceKindPlaylistURL, nil
 		}
 
 		// Single SoundCloud track - return the URL directly (yt-dlp will handle it)
@@ -367,7 +664,7 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 			URL:        query,
 			Title:      query, // Will be updated after extraction
 			SourceType: valueobjects.SourceTypeYouTube,
-		}}, false, nil
+		}}, valueobjects.SourceKindTrackURL, nil
 	}
 
 	// Check if query is a YouTube playlist URL
@@ -376,11 +673,11 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 
 		videos, err := h.ytService.ExtractPlaylist(query)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to extract playlist: %w", err)
+			return nil, valueobjects.SourceKindPlaylistURL, fmt.Errorf("failed to extract playlist: %w", err)
 		}
 
 		if len(videos) == 0 {
-			return nil, false, fmt.Errorf("playlist is empty or invalid")
+			return nil, valueobjects.SourceKindPlaylistURL, fmt.Errorf("playlist is empty or invalid")
 		}
 
 		songs := make([]SongInfo, 0, len(videos))
@@ -392,7 +689,7 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 			})
 		}
 
-		return songs, true, nil
+		return songs, valueobjects.SourceKindPlaylistURL, nil
 	}
 
 	// If query is not a YouTube URL, search for it
@@ -400,17 +697,17 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 		h.logger.WithField("query", query).Info("Searching YouTube...")
 		results, err := h.ytService.Search(query, 1)
 		if err != nil {
-			return nil, false, fmt.Errorf("search failed: %w", err)
+			return nil, valueobjects.SourceKindSearch, fmt.Errorf("search failed: %w", err)
 		}
 		if len(results) == 0 {
-			return nil, false, fmt.Errorf("no results found for: %s", query)
+			return nil, valueobjects.SourceKindSearch, fmt.Errorf("no results found for: %s", query)
 		}
 
 		return []SongInfo{{
 			URL:        fmt.Sprintf("https://www.youtube.com/watch?v=%s", results[0].ID),
 			Title:      results[0].Title,
 			SourceType: valueobjects.SourceTypeYouTube,
-		}}, false, nil
+		}}, valueobjects.SourceKindSearch, nil
 	}
 
 	// Regular YouTube video URL
@@ -418,12 +715,16 @@ func (h *Handler) ResolveSongURLs(query string) ([]SongInfo, bool, error) {
 		URL:        query,
 		Title:      query, // Will be updated after extraction
 		SourceType: valueobjects.SourceTypeYouTube,
-	}}, false, nil
+	}}, valueobjects.SourceKindTrackURL, nil
 }
 
 // handlePause handles the pause command
 func (h *Handler) handlePause(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	if err := h.playbackService.Pause(i.GuildID); err != nil {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	if err := h.playbackService.Pause(h.contextFor(i), i.GuildID); err != nil {
 		return respondError(s, i, "No active playback to pause")
 	}
 
@@ -438,7 +739,7 @@ func (h *Handler) handlePause(s *discordgo.Session, i *discordgo.InteractionCrea
 
 // handleResume handles the resume command
 func (h *Handler) handleResume(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	if err := h.playbackService.Resume(i.GuildID); err != nil {
+	if err := h.playbackService.Resume(h.contextFor(i), i.GuildID); err != nil {
 		return respondError(s, i, "No paused playback to resume")
 	}
 
@@ -485,18 +786,120 @@ func (h *Handler) handleSkip(s *discordgo.Session, i *discordgo.InteractionCreat
 		return respondEmbed(s, i, embed)
 	}
 
-	// Regular skip to next song
-	if err := h.playbackService.Skip(i.GuildID); err != nil {
+	// Admins and the session owner bypass voting and skip immediately
+	if h.isAuthorizedController(i) {
+		if err := h.playbackService.ForceSkip(h.contextFor(i), i.GuildID); err != nil {
+			return respondError(s, i, "No song to skip")
+		}
+
+		embed := h.buildSkipEmbed(tracklist.CurrentSong(), "⏭️ Skipped to Next")
+		return respondEmbed(s, i, embed)
+	}
+
+	// Everyone else votes; the song advances once enough listeners agree
+	current, required, skipped, err := h.playbackService.VoteSkip(h.contextFor(i), i.GuildID, i.Member.User.ID)
+	if err != nil {
 		return respondError(s, i, "No song to skip")
 	}
 
-	// Get the next song that will play
-	nextSong := tracklist.CurrentSong()
+	if skipped {
+		embed := h.buildSkipEmbed(tracklist.CurrentSong(), "⏭️ Skipped to Next")
+		return respondEmbed(s, i, embed)
+	}
 
-	embed := h.buildSkipEmbed(nextSong, "⏭️ Skipped to Next")
+	embed := h.buildVoteSkipEmbed(current, required)
 	return respondEmbed(s, i, embed)
 }
 
+// buildVoteSkipEmbed reports vote-skip progress when a skip vote doesn't yet
+// meet the required threshold
+func (h *Handler) buildVoteSkipEmbed(current, required int) *discordgo.MessageEmbed {
+	return NewEmbed().
+		Title("🗳️ Vote to Skip").
+		Description(fmt.Sprintf("%d/%d votes needed to skip this song", current, required)).
+		Color(ColorWarning).
+		Build()
+}
+
+// handleVoteSkip handles the voteskip command. Unlike /skip, this always
+// goes through the configurable-threshold vote subsystem in the audio
+// package, even for admins/the session owner, so it's a way to start or
+// join a skip vote without forcing it.
+func (h *Handler) handleVoteSkip(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	tracklist := h.playbackService.GetTracklist(i.GuildID)
+	if tracklist == nil || tracklist.Size() == 0 {
+		return respondError(s, i, "No songs in queue")
+	}
+
+	current, required, passed, err := h.playbackService.VoteAction(i.GuildID, i.Member.User.ID, audio.VoteKindSkip)
+	if err != nil {
+		return respondError(s, i, "No song to skip")
+	}
+
+	if passed {
+		if err := h.playbackService.ForceSkip(h.contextFor(i), i.GuildID); err != nil {
+			return respondError(s, i, "No song to skip")
+		}
+		return respondEmbed(s, i, h.buildSkipEmbed(tracklist.CurrentSong(), "⏭️ Vote Passed - Skipped to Next"))
+	}
+
+	return respondEmbed(s, i, h.buildVoteActionEmbed("⏭️ Vote to Skip", current, required))
+}
+
+// handleStop handles the stop command. Admins and the session owner stop
+// playback immediately; everyone else has to go through /votestop.
+func (h *Handler) handleStop(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	h.cancelPlaylistJob(i.GuildID)
+
+	if err := h.playbackService.Stop(h.contextFor(i), i.GuildID); err != nil {
+		return respondError(s, i, "No active playback to stop")
+	}
+
+	embed := NewEmbed().
+		Title("⏹️ Playback Stopped").
+		Description("Queue cleared").
+		Color(ColorError).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// handleVoteStop handles the votestop command, the vote-gated counterpart
+// to /stop.
+func (h *Handler) handleVoteStop(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	current, required, passed, err := h.playbackService.VoteAction(i.GuildID, i.Member.User.ID, audio.VoteKindStop)
+	if err != nil {
+		return respondError(s, i, "No active playback to stop")
+	}
+
+	if passed {
+		if err := h.playbackService.Stop(h.contextFor(i), i.GuildID); err != nil {
+			return respondError(s, i, "No active playback to stop")
+		}
+		return respondEmbed(s, i, NewEmbed().
+			Title("⏹️ Vote Passed - Playback Stopped").
+			Description("Queue cleared").
+			Color(ColorError).
+			Build())
+	}
+
+	return respondEmbed(s, i, h.buildVoteActionEmbed("⏹️ Vote to Stop", current, required))
+}
+
+// buildVoteActionEmbed reports vote progress for any in-progress vote
+// session (skip, stop, ...) started through PlaybackService.VoteAction.
+func (h *Handler) buildVoteActionEmbed(title string, current, required int) *discordgo.MessageEmbed {
+	return NewEmbed().
+		Title(title).
+		Description(fmt.Sprintf("%d/%d votes needed", current, required)).
+		Color(ColorWarning).
+		Build()
+}
+
 // buildSkipEmbed creates an embed for skip response
 func (h *Handler) buildSkipEmbed(nextSong *entities.Song, title string) *discordgo.MessageEmbed {
 	builder := NewEmbed().
@@ -524,6 +927,10 @@ func (h *Handler) buildSkipEmbed(nextSong *entities.Song, title string) *discord
 
 // handleVolume handles the volume command
 func (h *Handler) handleVolume(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
 	options := i.ApplicationCommandData().Options
 	level := int(options[0].IntValue())
 
@@ -551,148 +958,206 @@ func (h *Handler) handleVolume(s *discordgo.Session, i *discordgo.InteractionCre
 	return respondEmbed(s, i, embed)
 }
 
-// findBestDurationMatch finds the YouTube video with closest duration to target
-// Returns nil if no match within acceptable tolerance (±10 seconds)
-func findBestDurationMatch(results []youtube.YouTubeInfo, targetDuration int) *youtube.YouTubeInfo {
-	if len(results) == 0 {
-		return nil
+// handleCrossfade sets how long tracks fade out/in at the transition
+// between songs. See audio.AudioPlayer.SetCrossfade for what "crossfade"
+// means on this player.
+func (h *Handler) handleCrossfade(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
 	}
 
-	const maxDifference = 10.0 // ±10 seconds tolerance
-
-	var bestMatch *youtube.YouTubeInfo
-	minDifference := 999999.0 // Large number
+	options := i.ApplicationCommandData().Options
+	seconds := options[0].FloatValue()
+	d := time.Duration(seconds * float64(time.Second))
 
-	for i := range results {
-		diff := absFloat(results[i].Duration - float64(targetDuration))
-		if diff < minDifference {
-			minDifference = diff
-			bestMatch = &results[i]
-		}
+	if err := h.playbackService.SetCrossfade(i.GuildID, d); err != nil {
+		return respondError(s, i, "Failed to set crossfade: "+err.Error())
 	}
 
-	// Only return match if within acceptable tolerance
-	if minDifference <= maxDifference {
-		return bestMatch
+	desc := fmt.Sprintf("Tracks now fade out/in over **%.1fs** at each transition", seconds)
+	if d <= 0 {
+		desc = "Crossfade disabled - tracks switch without a fade"
 	}
 
-	return nil
+	embed := NewEmbed().
+		Title("🔀 Crossfade Updated").
+		Description(desc).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
 }
 
-// abs returns the absolute value of an integer
-func abs(x int) int {
-	if x < 0 {
-		return -x
+// handleSeek handles the seek command
+func (h *Handler) handleSeek(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
 	}
-	return x
+
+	options := i.ApplicationCommandData().Options
+	position, err := parseSeekPosition(options[0].StringValue())
+	if err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	if err := h.playbackService.Seek(i.GuildID, position); err != nil {
+		if errors.Is(err, audio.ErrSeekPastEnd) {
+			return respondError(s, i, "That position is past the end of this song")
+		}
+		return respondError(s, i, "No song to seek")
+	}
+
+	embed := NewEmbed().
+		Title("⏩ Seeked").
+		Description(fmt.Sprintf("Jumped to **%s**", formatSeconds(int(position.Seconds())))).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
 }
 
-// absFloat returns the absolute value of a float64
-func absFloat(x float64) float64 {
-	if x < 0 {
-		return -x
+// parseSeekPosition parses a /seek position argument given as a plain
+// integer number of seconds, "mm:ss", or "hh:mm:ss".
+func parseSeekPosition(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, ":") {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds < 0 {
+			return 0, fmt.Errorf("invalid position %q: expected seconds or hh:mm:ss", raw)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	parts := strings.Split(raw, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid position %q: expected seconds or hh:mm:ss", raw)
 	}
-	return x
+
+	total := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid position %q: expected seconds or hh:mm:ss", raw)
+		}
+		total = total*60 + n
+	}
+
+	return time.Duration(total) * time.Second, nil
 }
 
-// resolveSpotifyTrackToYouTube searches YouTube for a Spotify track
-// Returns YouTube URL or empty string if not found
-func (h *Handler) resolveSpotifyTrackToYouTube(track spotify.Track) string {
-	var videoID string
-	var found bool
-	spotifyDuration := track.GetDurationSeconds()
+// handleTransfer hands session-owner control of playback to another user
+func (h *Handler) handleTransfer(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	options := i.ApplicationCommandData().Options
+	target := options[0].UserValue(s)
 
-	// Strategy 1: Try ISRC search first (most accurate)
-	if isrc := track.GetISRC(); isrc != "" {
-		h.logger.WithFields(map[string]interface{}{
-			"track": track.Name,
-			"isrc":  isrc,
-		}).Debug("Trying ISRC search")
-
-		if info, err := h.ytService.SearchByISRC(isrc); err == nil {
-			// Verify duration (±5 seconds tolerance)
-			if absFloat(info.Duration-float64(spotifyDuration)) <= 5 {
-				videoID = info.ID
-				found = true
-				h.logger.WithField("track", track.Name).Info("✅ Found by ISRC with duration match")
-			} else {
-				h.logger.WithFields(map[string]interface{}{
-					"track":            track.Name,
-					"spotify_duration": spotifyDuration,
-					"youtube_duration": info.Duration,
-				}).Warn("ISRC match but duration mismatch, trying other methods")
-			}
+	if err := h.playbackService.TransferOwner(i.GuildID, i.Member.User.ID, target.ID); err != nil {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	embed := NewEmbed().
+		Title("🔑 Control Transferred").
+		Description(fmt.Sprintf("<@%s> now controls playback", target.ID)).
+		Color(ColorSuccess).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// extractMetadataCached wraps ytService.ExtractInfo with the persistent
+// song resolution cache, so repeated /play or /playlist add calls for the
+// same URL (e.g. a popular song shared in chat) skip the yt-dlp subprocess.
+func (h *Handler) extractMetadataCached(ctx context.Context, url string) (*youtube.YouTubeInfo, error) {
+	if h.songCache != nil {
+		var cached youtube.YouTubeInfo
+		if hit, err := h.songCache.YouTubeMetadata.Get(url, &cached); err == nil && hit {
+			return &cached, nil
 		}
 	}
 
-	// Strategy 2: Try detailed search with album info
-	if !found {
-		detailedQuery := track.ToDetailedSearchQuery()
-		h.logger.WithField("query", detailedQuery).Debug("Trying detailed search")
+	info, err := h.ytService.ExtractInfo(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-		results, err := h.ytService.Search(detailedQuery, 3)
-		if err == nil && len(results) > 0 {
-			bestMatch := findBestDurationMatch(results, spotifyDuration)
-			if bestMatch != nil {
-				videoID = bestMatch.ID
-				found = true
-				h.logger.WithField("track", track.Name).Info("✅ Found by detailed search")
-			}
+	if h.songCache != nil {
+		if err := h.songCache.YouTubeMetadata.Set(url, info); err != nil {
+			h.logger.WithError(err).Debug("Failed to cache YouTube metadata")
 		}
 	}
 
-	// Strategy 3: Fall back to simple search
-	if !found {
-		simpleQuery := track.ToSearchQuery()
-		h.logger.WithField("query", simpleQuery).Debug("Trying simple search")
+	return info, nil
+}
 
-		results, err := h.ytService.Search(simpleQuery, 3)
-		if err != nil {
-			h.logger.WithError(err).WithField("track", track.Name).Warn("All search methods failed")
-			return ""
+// resolveSpotifyTrackToYouTube searches YouTube for a Spotify track,
+// memoizing the result in the persistent song resolution cache so the same
+// track isn't re-searched on every /play of a shared playlist. Tracks that
+// expose an ISRC are looked up there first: unlike a Spotify track ID, an
+// ISRC identifies the recording itself, so it's worth checking even when
+// this is the first time we've seen this particular Spotify track (e.g. a
+// regional re-release with a different ID for the same recording).
+// Returns YouTube URL or empty string if not found
+func (h *Handler) resolveSpotifyTrackToYouTube(track spotify.Track) string {
+	isrc := track.GetISRC()
+
+	if h.songCache != nil {
+		if isrc != "" {
+			var cachedURL string
+			if hit, err := h.songCache.ISRCToYouTube.Get(isrc, &cachedURL); err == nil && hit {
+				return cachedURL
+			}
 		}
 
-		if len(results) == 0 {
-			h.logger.WithField("track", track.Name).Warn("No YouTube results found")
-			return ""
+		var cachedURL string
+		if hit, err := h.songCache.SpotifyToYouTube.Get(track.ID, &cachedURL); err == nil && hit {
+			return cachedURL
 		}
+	}
 
-		bestMatch := findBestDurationMatch(results, spotifyDuration)
-		if bestMatch != nil {
-			videoID = bestMatch.ID
-			found = true
-			h.logger.WithField("track", track.Name).Info("✅ Found by simple search")
-		} else {
-			// Last resort: use first result
-			videoID = results[0].ID
-			h.logger.WithField("track", track.Name).Warn("⚠️ Using first result (no duration match)")
+	ytURL := h.resolveSpotifyTrackToYouTubeUncached(track)
+
+	if ytURL != "" && h.songCache != nil {
+		if isrc != "" {
+			if err := h.songCache.ISRCToYouTube.Set(isrc, ytURL); err != nil {
+				h.logger.WithError(err).Debug("Failed to cache ISRC->YouTube resolution")
+			}
+		}
+		if err := h.songCache.SpotifyToYouTube.Set(track.ID, ytURL); err != nil {
+			h.logger.WithError(err).Debug("Failed to cache Spotify->YouTube resolution")
 		}
 	}
 
+	return ytURL
+}
+
+// resolveSpotifyTrackToYouTubeUncached runs the ISRC-first, scored-search
+// Spotify->YouTube bridge for a single track. See sourcesspotify.Bridge.
+func (h *Handler) resolveSpotifyTrackToYouTubeUncached(track spotify.Track) string {
+	videoID := h.spotifyBridge.ResolveTrack(track)
 	if videoID == "" {
 		return ""
 	}
-
 	return fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
 }
 
 // addSpotifyTracksProgressively resolves Spotify tracks to YouTube progressively
-// Resolves initialCount tracks immediately, then resolves remaining in background
-// Returns: initial songs resolved and total track count
-func (h *Handler) addSpotifyTracksProgressively(guildID, userID string, tracks []spotify.Track, initialCount int) ([]SongInfo, int) {
+// Resolves initialCount tracks immediately, then resolves remaining in background.
+// ctx carries the originating request's logging fields into the background
+// goroutine, which outlives the interaction that started it.
+func (h *Handler) addSpotifyTracksProgressively(ctx context.Context, guildID, userID string, tracks []spotify.Track, initialCount int) ([]SongInfo, int) {
 	totalTracks := len(tracks)
 	if initialCount <= 0 || initialCount > totalTracks {
 		initialCount = totalTracks
 	}
 
-	// Resolve initial batch immediately
+	// Resolve initial batch immediately, spread across the worker pool
+	initial := tracks[:initialCount]
+	initialURLs := h.resolveTracksConcurrently(initial)
 	initialSongs := make([]SongInfo, 0, initialCount)
-	for i := 0; i < initialCount && i < totalTracks; i++ {
-		ytURL := h.resolveSpotifyTrackToYouTube(tracks[i])
+	for idx, ytURL := range initialURLs {
 		if ytURL != "" {
 			initialSongs = append(initialSongs, SongInfo{
 				URL:        ytURL,
-				Title:      tracks[i].Name,
+				Title:      initial[idx].Name,
 				SourceType: valueobjects.SourceTypeYouTube,
 			})
 		}
@@ -707,36 +1172,114 @@ func (h *Handler) addSpotifyTracksProgressively(guildID, userID string, tracks [
 			"total":            totalTracks,
 		}).Info("Resolving remaining Spotify tracks in background...")
 
+		go h.resolveAndQueueRemaining(ctx, guildID, userID, remaining)
+	}
+
+	return initialSongs, totalTracks
+}
+
+// resolveTracksConcurrently resolves tracks to YouTube URLs across
+// config.SpotifyResolveWorkers workers, returning results in the same order
+// as tracks (empty string for a track that couldn't be resolved). Blocks
+// until every track is resolved.
+func (h *Handler) resolveTracksConcurrently(tracks []spotify.Track) []string {
+	results := make([]string, len(tracks))
+
+	workers := h.config.SpotifyResolveWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(tracks) {
+		workers = len(tracks)
+	}
+	if workers == 0 {
+		return results
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
 		go func() {
-			addedCount := 0
-			for _, track := range remaining {
-				// Check if playback is still active before adding more songs
-				if !h.playbackService.IsPlaying(guildID) {
-					h.logger.WithField("added", addedCount).Info("⏹️ Playback stopped, halting background Spotify track loading")
-					return
-				}
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = h.resolveSpotifyTrackToYouTube(tracks[idx])
+			}
+		}()
+	}
 
-				// Check if tracklist still exists
-				if h.playbackService.GetTracklist(guildID) == nil {
-					h.logger.WithField("added", addedCount).Info("⏹️ Tracklist cleared, halting background Spotify track loading")
-					return
-				}
+	for idx := range tracks {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
 
-				ytURL := h.resolveSpotifyTrackToYouTube(track)
-				if ytURL == "" {
-					continue
-				}
+	return results
+}
 
-				song := entities.NewSong(ytURL, valueobjects.SourceTypeYouTube, userID, guildID)
-				if err := h.playbackService.AddSong(guildID, song); err != nil {
-					h.logger.WithError(err).Debug("Failed to add background Spotify song")
-					continue
-				}
-				addedCount++
+// resolveAndQueueRemaining resolves remaining across the worker pool, then
+// drains the results in original track order onto the queue - preserving
+// playback order even though resolution itself finishes out of order. Halts
+// if playback stops or the tracklist is cleared partway through, same as the
+// previous strictly sequential version.
+func (h *Handler) resolveAndQueueRemaining(ctx context.Context, guildID, userID string, remaining []spotify.Track) {
+	results := make([]string, len(remaining))
+	done := make([]chan struct{}, len(remaining))
+	for idx := range done {
+		done[idx] = make(chan struct{})
+	}
+
+	workers := h.config.SpotifyResolveWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(remaining) {
+		workers = len(remaining)
+	}
+
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		go func() {
+			for idx := range jobs {
+				results[idx] = h.resolveSpotifyTrackToYouTube(remaining[idx])
+				close(done[idx])
 			}
-			h.logger.WithField("count", addedCount).Info("✅ Finished resolving background Spotify tracks")
 		}()
 	}
+	go func() {
+		for idx := range remaining {
+			jobs <- idx
+		}
+		close(jobs)
+	}()
 
-	return initialSongs, totalTracks
+	addedCount := 0
+	for idx := range remaining {
+		<-done[idx] // slots are drained in order, regardless of resolution order
+
+		// Check if playback is still active before adding more songs
+		if !h.playbackService.IsPlaying(guildID) {
+			h.logger.WithField("added", addedCount).Info("⏹️ Playback stopped, halting background Spotify track loading")
+			return
+		}
+
+		// Check if tracklist still exists
+		if h.playbackService.GetTracklist(guildID) == nil {
+			h.logger.WithField("added", addedCount).Info("⏹️ Tracklist cleared, halting background Spotify track loading")
+			return
+		}
+
+		ytURL := results[idx]
+		if ytURL == "" {
+			continue
+		}
+
+		song := entities.NewSong(ytURL, valueobjects.SourceTypeYouTube, userID, guildID)
+		if err := h.playbackService.AddSong(ctx, guildID, song); err != nil {
+			h.logger.WithError(err).Debug("Failed to add background Spotify song")
+			continue
+		}
+		addedCount++
+	}
+	h.logger.WithField("count", addedCount).Info("✅ Finished resolving background Spotify tracks")
 }
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/playlistformat"
 )
 
 // handlePlaylists shows all available playlists
@@ -97,14 +102,15 @@ func (h *Handler) handleUsePlaylist(s *discordgo.Session, i *discordgo.Interacti
 	}
 
 	// Stop and clear
-	h.playbackService.Stop(i.GuildID)
+	h.playbackService.Stop(h.contextFor(i), i.GuildID)
+	h.cancelPlaylistJob(i.GuildID)
 	if tracklist := h.playbackService.GetTracklist(i.GuildID); tracklist != nil {
 		tracklist.Clear()
 	}
 
 	// Add songs
 	for _, song := range songs {
-		if err := h.playbackService.AddSong(i.GuildID, song); err != nil {
+		if err := h.playbackService.AddSong(h.contextFor(i), i.GuildID, song); err != nil {
 			h.logger.WithError(err).Warn("Failed to add song to queue")
 		}
 	}
@@ -117,7 +123,7 @@ func (h *Handler) handleUsePlaylist(s *discordgo.Session, i *discordgo.Interacti
 	}
 
 	// Start playback
-	if err := h.playbackService.Play(i.GuildID, channelID); err != nil {
+	if err := h.playbackService.Play(h.contextFor(i), i.GuildID, channelID, i.Member.User.ID); err != nil {
 		return followUpError(s, i, "Failed to start playback")
 	}
 
@@ -157,26 +163,34 @@ func (h *Handler) handleQuickAdd(s *discordgo.Session, i *discordgo.InteractionC
 	songQuery := options[0].StringValue()
 
 	// Resolve query to song URLs (handles single video, playlist, or search)
-	songs, isPlaylist, err := h.ResolveSongURLs(songQuery)
+	songs, kind, err := h.ResolveSongURLs(h.contextFor(i), songQuery)
 	if err != nil {
 		return followUpError(s, i, err.Error())
 	}
 
 	// For single URL, extract metadata before adding to show proper title
 	var extractedTitle string
-	if !isPlaylist && len(songs) == 1 {
+	if !kind.IsPlaylist() && len(songs) == 1 {
 		songURL := songs[0].URL
 		if strings.HasPrefix(songURL, "http://") || strings.HasPrefix(songURL, "https://") {
 			h.logger.WithField("url", songURL).Debug("Extracting metadata for single URL")
-			if info, err := h.ytService.ExtractInfo(songURL); err == nil {
+			if info, err := h.extractMetadataCached(h.contextFor(i), songURL); err == nil {
 				extractedTitle = info.Title
 				songs[0].Title = info.Title // Update title for saving and display
 			}
 		}
 	}
 
+	// Cap playlist fan-out so a single URL can't flood the playlist
+	skipped := 0
+	if kind.IsPlaylist() && len(songs) > h.config.MaxPlaylistURLSize {
+		skipped = len(songs) - h.config.MaxPlaylistURLSize
+		songs = songs[:h.config.MaxPlaylistURLSize]
+	}
+
 	// Add all songs to playlist
 	addedCount := 0
+	duplicateCount := 0
 	for _, songInfo := range songs {
 		err := h.playlistService.AddToPlaylistForGuild(
 			i.GuildID,
@@ -186,26 +200,34 @@ func (h *Handler) handleQuickAdd(s *discordgo.Session, i *discordgo.InteractionC
 			songInfo.Title,
 		)
 		if err != nil {
+			if strings.Contains(err.Error(), "already exists") {
+				duplicateCount++
+				continue
+			}
 			h.logger.WithError(err).Warn("Failed to add song to playlist")
 			continue
 		}
 		addedCount++
 	}
 
-	if addedCount == 0 {
+	if addedCount == 0 && duplicateCount == 0 {
 		return followUpError(s, i, "Failed to add any songs to playlist")
 	}
 
 	// Build appropriate response
 	var embed *discordgo.MessageEmbed
-	if isPlaylist {
-		embed = NewEmbed().
+	if kind.IsPlaylist() {
+		builder := NewEmbed().
 			Title("✅ Playlist Added to Playlist").
 			Description(fmt.Sprintf("Added **%d** songs to **%s**", addedCount, playlistName)).
 			Color(ColorSuccess).
-			Field("Songs Added", fmt.Sprintf("%d", addedCount), true).
-			Field("Playlist", playlistName, true).
-			Build()
+			Field("Added", fmt.Sprintf("%d", addedCount), true).
+			Field("Duplicates Skipped", fmt.Sprintf("%d", duplicateCount), true).
+			Field("Playlist", playlistName, true)
+		if skipped > 0 {
+			builder.Field("Skipped (cap reached)", fmt.Sprintf("%d", skipped), true)
+		}
+		embed = builder.Build()
 	} else {
 		displayTitle := extractedTitle
 		if displayTitle == "" {
@@ -341,6 +363,18 @@ func (h *Handler) handlePlaylistSubcommand(s *discordgo.Session, i *discordgo.In
 		return h.handlePlaylistAdd(s, i, subCmd)
 	case "rename":
 		return h.handlePlaylistRename(s, i, subCmd)
+	case "import":
+		return h.handlePlaylistImport(s, i, subCmd)
+	case "sync":
+		return h.handlePlaylistSync(s, i, subCmd)
+	case "import-file":
+		return h.handlePlaylistImportFile(s, i, subCmd)
+	case "export":
+		return h.handlePlaylistExport(s, i, subCmd)
+	case "smart-create":
+		return h.handlePlaylistSmartCreate(s, i, subCmd)
+	case "smart-edit":
+		return h.handlePlaylistSmartEdit(s, i, subCmd)
 	default:
 		return respondError(s, i, "Unknown subcommand")
 	}
@@ -427,26 +461,34 @@ func (h *Handler) handlePlaylistAdd(s *discordgo.Session, i *discordgo.Interacti
 		}
 
 		// Resolve query to song URLs (handles single video, playlist, or search)
-		songs, isPlaylist, err := h.ResolveSongURLs(songQuery)
+		songs, kind, err := h.ResolveSongURLs(h.contextFor(i), songQuery)
 		if err != nil {
 			return followUpError(s, i, err.Error())
 		}
 
 		// For single URL, extract metadata before adding to show proper title
 		var extractedTitle string
-		if !isPlaylist && len(songs) == 1 {
+		if !kind.IsPlaylist() && len(songs) == 1 {
 			songURL := songs[0].URL
 			if strings.HasPrefix(songURL, "http://") || strings.HasPrefix(songURL, "https://") {
 				h.logger.WithField("url", songURL).Debug("Extracting metadata for single URL")
-				if info, err := h.ytService.ExtractInfo(songURL); err == nil {
+				if info, err := h.extractMetadataCached(h.contextFor(i), songURL); err == nil {
 					extractedTitle = info.Title
 					songs[0].Title = info.Title // Update title for saving and display
 				}
 			}
 		}
 
+		// Cap playlist fan-out so a single URL can't flood the playlist
+		skipped := 0
+		if kind.IsPlaylist() && len(songs) > h.config.MaxPlaylistURLSize {
+			skipped = len(songs) - h.config.MaxPlaylistURLSize
+			songs = songs[:h.config.MaxPlaylistURLSize]
+		}
+
 		// Add all songs to playlist
 		addedCount := 0
+		duplicateCount := 0
 		for _, songInfo := range songs {
 			err := h.playlistService.AddToPlaylistForGuild(
 				guildID,
@@ -456,25 +498,33 @@ func (h *Handler) handlePlaylistAdd(s *discordgo.Session, i *discordgo.Interacti
 				songInfo.Title,
 			)
 			if err != nil {
+				if strings.Contains(err.Error(), "already exists") {
+					duplicateCount++
+					continue
+				}
 				h.logger.WithError(err).Warn("Failed to add song to playlist")
 				continue
 			}
 			addedCount++
 		}
 
-		if addedCount == 0 {
+		if addedCount == 0 && duplicateCount == 0 {
 			return followUpError(s, i, "Failed to add any songs to playlist")
 		}
 
 		// Build appropriate response
 		var embed *discordgo.MessageEmbed
-		if isPlaylist {
-			embed = NewEmbed().
+		if kind.IsPlaylist() {
+			builder := NewEmbed().
 				Title("✅ Playlist Added").
 				Description(fmt.Sprintf("Added **%d** songs to playlist **%s**", addedCount, name)).
 				Color(ColorSuccess).
-				Field("Songs Added", fmt.Sprintf("%d", addedCount), true).
-				Build()
+				Field("Added", fmt.Sprintf("%d", addedCount), true).
+				Field("Duplicates Skipped", fmt.Sprintf("%d", duplicateCount), true)
+			if skipped > 0 {
+				builder.Field("Skipped (cap reached)", fmt.Sprintf("%d", skipped), true)
+			}
+			embed = builder.Build()
 		} else {
 			displayTitle := extractedTitle
 			if displayTitle == "" {
@@ -561,6 +611,208 @@ func (h *Handler) handlePlaylistRename(s *discordgo.Session, i *discordgo.Intera
 	return respondEmbed(s, i, embed)
 }
 
+func (h *Handler) handlePlaylistImport(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	guildID := i.GuildID
+	url := subCmd.Options[0].StringValue()
+	name := subCmd.Options[1].StringValue()
+
+	importer, err := h.externalImporters.For(url)
+	if err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	count, err := h.playlistService.ImportExternalForGuild(guildID, name, importer, url)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("✅ Playlist Imported").
+		Description(fmt.Sprintf("Imported **%d** songs into **%s**", count, name)).
+		Color(ColorSuccess).
+		Field("Next Steps", fmt.Sprintf("Use `/playlist sync %s` to pull in upstream changes later", name), false).
+		Build()
+
+	return followUpEmbed(s, i, embed)
+}
+
+func (h *Handler) handlePlaylistSync(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	guildID := i.GuildID
+	name := subCmd.Options[0].StringValue()
+	dryRun := false
+	if len(subCmd.Options) > 1 {
+		dryRun = subCmd.Options[1].BoolValue()
+	}
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	info, err := h.playlistService.GetExternalInfoForGuild(guildID, name)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+	if info == nil {
+		return followUpError(s, i, fmt.Sprintf("Playlist '%s' was not imported from an external source", name))
+	}
+
+	importer, err := h.externalImporters.For(info.URL)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	var added, removed int
+	if dryRun {
+		added, removed, err = h.playlistService.PreviewSyncExternalForGuild(guildID, name, importer)
+	} else {
+		added, removed, err = h.playlistService.SyncExternalForGuild(guildID, name, importer)
+	}
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	title := "✅ Playlist Synced"
+	description := fmt.Sprintf("**%s** is up to date with its remote source", name)
+	if dryRun {
+		title = "🔍 Playlist Sync Preview"
+		description = fmt.Sprintf("Dry run only - **%s** was not modified", name)
+	}
+
+	embed := NewEmbed().
+		Title(title).
+		Description(description).
+		Color(ColorSuccess).
+		Field("Added", fmt.Sprintf("%d", added), true).
+		Field("Removed", fmt.Sprintf("%d", removed), true).
+		Build()
+
+	return followUpEmbed(s, i, embed)
+}
+
+func (h *Handler) handlePlaylistImportFile(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	guildID := i.GuildID
+	attachmentID := subCmd.Options[0].Value.(string)
+	name := subCmd.Options[1].StringValue()
+
+	attachment, ok := i.ApplicationCommandData().Resolved.Attachments[attachmentID]
+	if !ok {
+		return respondError(s, i, "Could not resolve the uploaded file")
+	}
+
+	format, err := playlistformat.SniffFormat(attachment.Filename, nil)
+	if err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	resp, err := http.Get(attachment.URL)
+	if err != nil {
+		return followUpError(s, i, "Failed to download the uploaded file")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(h.config.MaxPlaylistFileSize)))
+	if err != nil {
+		return followUpError(s, i, "Failed to read the uploaded file")
+	}
+
+	count, err := h.playlistService.ImportFileForGuild(guildID, name, format, data)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("✅ Playlist Imported").
+		Description(fmt.Sprintf("Imported **%d** songs into **%s** from %s", count, name, attachment.Filename)).
+		Color(ColorSuccess).
+		Build()
+
+	return followUpEmbed(s, i, embed)
+}
+
+func (h *Handler) handlePlaylistExport(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	guildID := i.GuildID
+	name := subCmd.Options[0].StringValue()
+	format := playlistformat.Format(subCmd.Options[1].StringValue())
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	data, err := h.playlistService.ExportFileForGuild(guildID, name, format)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	file := &discordgo.File{
+		Name:        fmt.Sprintf("%s.%s", name, format.Extension()),
+		ContentType: "text/plain",
+		Reader:      strings.NewReader(string(data)),
+	}
+
+	return followUpFile(s, i, fmt.Sprintf("Exported **%s**", name), file)
+}
+
+func (h *Handler) handlePlaylistSmartCreate(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	guildID := i.GuildID
+	name := subCmd.Options[0].StringValue()
+	criteria, err := parseCriteriaJSON(subCmd.Options[1].StringValue())
+	if err != nil {
+		return respondError(s, i, fmt.Sprintf("Invalid criteria: %s", err.Error()))
+	}
+
+	if err := h.playlistService.CreateSmartPlaylist(guildID, name, criteria); err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("Smart Playlist Created").
+		Description(fmt.Sprintf("Successfully created smart playlist **%s**", name)).
+		Color(ColorSuccess).
+		Field("Next Steps", fmt.Sprintf("> • Use `/use %s` to play the songs matching its rules", name), false).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handlePlaylistSmartEdit(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	guildID := i.GuildID
+	name := subCmd.Options[0].StringValue()
+	criteria, err := parseCriteriaJSON(subCmd.Options[1].StringValue())
+	if err != nil {
+		return respondError(s, i, fmt.Sprintf("Invalid criteria: %s", err.Error()))
+	}
+
+	if err := h.playlistService.UpdateSmartPlaylistCriteria(guildID, name, criteria); err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("Smart Playlist Updated").
+		Description(fmt.Sprintf("Updated the rules for **%s**", name)).
+		Color(ColorSuccess).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// parseCriteriaJSON parses a /playlist smart-create|smart-edit criteria
+// option into a Criteria tree
+func parseCriteriaJSON(raw string) (entities.Criteria, error) {
+	var criteria entities.Criteria
+	if err := json.Unmarshal([]byte(raw), &criteria); err != nil {
+		return entities.Criteria{}, err
+	}
+	return criteria, nil
+}
+
 // parseIndexes parses a string containing song indexes and returns a sorted slice of unique indexes
 // Supports formats:
 // - "2-5" (range)
@@ -14,6 +14,13 @@ func (h *Handler) handleQueue(s *discordgo.Session, i *discordgo.InteractionCrea
 	// Build first page
 	embed, components := buildQueuePage(tracklist, 0)
 
+	// Note a still-loading playlist extraction, if any, in the footer
+	if job := h.getPlaylistJob(i.GuildID); job != nil {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("⏳ Loading playlist: %d/%d songs resolved…", job.Loaded(), job.TotalCount()),
+		}
+	}
+
 	// Send response with pagination buttons
 	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -49,6 +56,10 @@ func (h *Handler) handleNowPlaying(s *discordgo.Session, i *discordgo.Interactio
 		builder.Field("Artist", metadata.Uploader, true)
 	}
 
+	if offset := metadata.StartOffsetFormatted(); offset != "" {
+		builder.Field("Starts At", offset, true)
+	}
+
 	// Add progress indicator
 	builder.Field("Status", "Playing", true)
 
@@ -76,12 +87,113 @@ func (h *Handler) handleShuffle(s *discordgo.Session, i *discordgo.InteractionCr
 	return respondEmbed(s, i, embed)
 }
 
+// handleSmartShuffle handles the smartshuffle command
+func (h *Handler) handleSmartShuffle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	tracklist := h.playbackService.GetTracklist(i.GuildID)
+	if tracklist == nil || tracklist.Size() == 0 {
+		return respondError(s, i, "Queue is empty - nothing to shuffle")
+	}
+
+	count := tracklist.Size()
+	tracklist.SmartShuffle()
+
+	embed := NewEmbed().
+		Title("Queue Smart Shuffled").
+		Description(fmt.Sprintf("Successfully shuffled **%d** songs in the queue, spacing out same-artist tracks", count)).
+		Color(ColorSuccess).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// handleVoteRemove handles the voteremove command. DJs and admins/the
+// session owner bypass voting and remove the song immediately; everyone
+// else votes, and the song is removed once enough listeners agree.
+func (h *Handler) handleVoteRemove(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	tracklist := h.playbackService.GetTracklist(i.GuildID)
+	if tracklist == nil || tracklist.Size() == 0 {
+		return respondError(s, i, "Queue is empty - nothing to remove")
+	}
+
+	position := int(i.ApplicationCommandData().Options[0].IntValue())
+	if position < 1 || position > tracklist.Size() {
+		return respondError(s, i, fmt.Sprintf("Invalid position. Queue has %d songs (use 1-%d)", tracklist.Size(), tracklist.Size()))
+	}
+
+	if h.isAuthorizedController(i) || h.isDJ(i) {
+		if !h.playbackService.ForceRemoveSong(i.GuildID, position) {
+			return respondError(s, i, "Failed to remove song")
+		}
+		return respondEmbed(s, i, NewEmbed().
+			Title("🗑️ Song Removed").
+			Description(fmt.Sprintf("Removed song at position #%d", position)).
+			Color(ColorSuccess).
+			Build())
+	}
+
+	current, required, removed, err := h.playbackService.VoteRemoveSong(i.GuildID, position, i.Member.User.ID)
+	if err != nil {
+		return respondError(s, i, "Failed to remove song")
+	}
+
+	if removed {
+		return respondEmbed(s, i, NewEmbed().
+			Title("🗑️ Vote Passed - Song Removed").
+			Description(fmt.Sprintf("Removed song at position #%d", position)).
+			Color(ColorSuccess).
+			Build())
+	}
+
+	return respondEmbed(s, i, h.buildVoteActionEmbed("🗑️ Vote to Remove", current, required))
+}
+
+// handleVoteShuffle handles the voteshuffle command. DJs and admins/the
+// session owner bypass voting and smart-shuffle immediately; everyone else
+// votes, and the queue is shuffled once enough listeners agree.
+func (h *Handler) handleVoteShuffle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	tracklist := h.playbackService.GetTracklist(i.GuildID)
+	if tracklist == nil || tracklist.Size() == 0 {
+		return respondError(s, i, "Queue is empty - nothing to shuffle")
+	}
+
+	if h.isAuthorizedController(i) || h.isDJ(i) {
+		if !h.playbackService.ForceSmartShuffle(i.GuildID) {
+			return respondError(s, i, "Failed to shuffle queue")
+		}
+		return respondEmbed(s, i, NewEmbed().
+			Title("🔀 Queue Smart Shuffled").
+			Description("Successfully shuffled the queue, spacing out same-artist tracks").
+			Color(ColorSuccess).
+			Build())
+	}
+
+	current, required, shuffled, err := h.playbackService.VoteShuffleQueue(i.GuildID, i.Member.User.ID)
+	if err != nil {
+		return respondError(s, i, "Failed to shuffle queue")
+	}
+
+	if shuffled {
+		return respondEmbed(s, i, NewEmbed().
+			Title("🔀 Vote Passed - Queue Smart Shuffled").
+			Description("Successfully shuffled the queue, spacing out same-artist tracks").
+			Color(ColorSuccess).
+			Build())
+	}
+
+	return respondEmbed(s, i, h.buildVoteActionEmbed("🔀 Vote to Shuffle", current, required))
+}
+
 // handleClear handles the clear command
 func (h *Handler) handleClear(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
 	h.logger.WithField("guild", i.GuildID).Info("🔄 Performing full reset...")
 
 	// 1. Stop playback immediately
-	h.playbackService.Stop(i.GuildID)
+	h.playbackService.Stop(h.contextFor(i), i.GuildID)
+	h.cancelPlaylistJob(i.GuildID)
 
 	// 2. Clear queue
 	if tracklist := h.playbackService.GetTracklist(i.GuildID); tracklist != nil {
@@ -156,3 +268,34 @@ func (h *Handler) handleRepeat(s *discordgo.Session, i *discordgo.InteractionCre
 
 	return respondEmbed(s, i, embed)
 }
+
+// handleQueueMode handles the queuemode command, toggling between FIFO and
+// round-robin per-DJ queueing
+func (h *Handler) handleQueueMode(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	mode, err := h.playbackService.SwitchQueueType(i.GuildID)
+	if err != nil {
+		return respondError(s, i, "Failed to switch queue mode")
+	}
+
+	var modeDisplay, modeIcon string
+	switch mode {
+	case entities.QueueModeRoundRobin:
+		modeDisplay = "Round Robin (one song per DJ per rotation)"
+		modeIcon = "🔄"
+	default:
+		modeDisplay = "FIFO (first come, first served)"
+		modeIcon = "➡️"
+	}
+
+	embed := NewEmbed().
+		Title(fmt.Sprintf("%s Queue Mode Updated", modeIcon)).
+		Description(fmt.Sprintf("Queue mode set to: **%s**", modeDisplay)).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
@@ -111,6 +111,15 @@ func followUpSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, messa
 	return followUpEmbed(s, i, embed)
 }
 
+// followUpFile sends a follow-up message with a file attachment
+func followUpFile(s *discordgo.Session, i *discordgo.InteractionCreate, message string, file *discordgo.File) error {
+	_, err := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Content: message,
+		Files:   []*discordgo.File{file},
+	})
+	return err
+}
+
 // EmbedBuilder helps build consistent embeds
 type EmbedBuilder struct {
 	embed *discordgo.MessageEmbed
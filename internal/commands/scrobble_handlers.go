@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleScrobbleSubcommand handles scrobbling management subcommands
+func (h *Handler) handleScrobbleSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return respondError(s, i, "Invalid subcommand")
+	}
+
+	subCmd := options[0]
+	switch subCmd.Name {
+	case "link":
+		return h.handleScrobbleLink(s, i, subCmd)
+	case "unlink":
+		return h.handleScrobbleUnlink(s, i, subCmd)
+	case "enable":
+		return h.handleScrobbleEnable(s, i)
+	default:
+		return respondError(s, i, "Unknown subcommand")
+	}
+}
+
+func (h *Handler) handleScrobbleLink(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	service := subCmd.Options[0].StringValue()
+	token := subCmd.Options[1].StringValue()
+
+	if err := h.scrobbleService.Link(i.Member.User.ID, service, token); err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	return respondSuccess(s, i, fmt.Sprintf("Linked your %s account. Use `/scrobble enable` to start scrobbling in this server.", service))
+}
+
+func (h *Handler) handleScrobbleUnlink(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	service := subCmd.Options[0].StringValue()
+
+	if err := h.scrobbleService.Unlink(i.Member.User.ID, service); err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	return respondSuccess(s, i, fmt.Sprintf("Unlinked your %s account", service))
+}
+
+func (h *Handler) handleScrobbleEnable(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := h.scrobbleService.SetGuildEnabled(i.GuildID, true); err != nil {
+		return respondError(s, i, "Failed to enable scrobbling for this server")
+	}
+
+	return respondSuccess(s, i, "Scrobbling enabled for this server. Linked users' plays will be sent to ListenBrainz/Last.fm.")
+}
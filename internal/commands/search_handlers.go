@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// searchResultLimit caps how many options a /search select menu offers
+const searchResultLimit = 10
+
+// searchCandidate is one result of a /search lookup: a label to show in the
+// select menu and the query ResolveSongURLs should resolve if it's picked
+type searchCandidate struct {
+	Label string
+	Query string
+}
+
+// handleSearch handles the search command: it runs the query against the
+// chosen service and replies with a select menu of results, letting the
+// requester disambiguate instead of always queueing the first hit.
+func (h *Handler) handleSearch(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	options := i.ApplicationCommandData().Options
+	service := options[0].StringValue()
+	query := options[1].StringValue()
+
+	candidates, err := h.runServiceSearch(service, query)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+	if len(candidates) == 0 {
+		return followUpError(s, i, fmt.Sprintf("No results found for %q", query))
+	}
+
+	selectOptions := make([]discordgo.SelectMenuOption, len(candidates))
+	queries := make([]string, len(candidates))
+	for idx, c := range candidates {
+		selectOptions[idx] = discordgo.SelectMenuOption{
+			Label: truncate(c.Label, 100),
+			Value: strconv.Itoa(idx),
+		}
+		queries[idx] = c.Query
+	}
+
+	embed := NewEmbed().
+		Title("🔍 Search Results").
+		Description(fmt.Sprintf("Results for **%s** — pick one to add to the queue", query)).
+		Color(ColorInfo).
+		Build()
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					CustomID:    "search:pick",
+					Placeholder: "Choose a result to queue",
+					Options:     selectOptions,
+				},
+			},
+		},
+	}
+
+	msg, err := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.storeSearchResults(msg.ID, queries)
+	return nil
+}
+
+// runServiceSearch runs a /search query against the requested service and
+// normalizes its results into searchCandidates
+func (h *Handler) runServiceSearch(service, query string) ([]searchCandidate, error) {
+	switch service {
+	case "yt":
+		results, err := h.ytService.Search(query, searchResultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("YouTube search failed: %w", err)
+		}
+		candidates := make([]searchCandidate, 0, len(results))
+		for _, r := range results {
+			candidates = append(candidates, searchCandidate{
+				Label: fmt.Sprintf("%s [%s]", r.Title, formatSeconds(r.Duration)),
+				Query: fmt.Sprintf("https://www.youtube.com/watch?v=%s", r.ID),
+			})
+		}
+		return candidates, nil
+
+	case "sc":
+		// Prefer the SoundCloud API provider (real metadata, no yt-dlp
+		// subprocess) when a client_id is configured
+		if provider := h.sourceRegistry.ByName("soundcloud"); provider != nil {
+			media, err := provider.Search(query, searchResultLimit)
+			if err != nil {
+				return nil, fmt.Errorf("SoundCloud search failed: %w", err)
+			}
+			candidates := make([]searchCandidate, 0, len(media))
+			for _, m := range media {
+				candidates = append(candidates, searchCandidate{
+					Label: fmt.Sprintf("%s [%s]", m.Title, formatSeconds(m.Duration)),
+					Query: m.URL,
+				})
+			}
+			return candidates, nil
+		}
+
+		results, err := h.ytService.SearchSoundCloud(query, searchResultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("SoundCloud search failed: %w", err)
+		}
+		candidates := make([]searchCandidate, 0, len(results))
+		for _, r := range results {
+			trackURL := r.WebpageURL
+			if trackURL == "" {
+				trackURL = r.ID
+			}
+			candidates = append(candidates, searchCandidate{
+				Label: fmt.Sprintf("%s [%s]", r.Title, formatSeconds(r.Duration)),
+				Query: trackURL,
+			})
+		}
+		return candidates, nil
+
+	case "sp":
+		if h.spotifyService == nil {
+			return nil, fmt.Errorf("Spotify support is not enabled. Please contact the bot owner to add Spotify credentials")
+		}
+		tracks, err := h.spotifyService.SearchTracks(query, searchResultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("Spotify search failed: %w", err)
+		}
+		candidates := make([]searchCandidate, 0, len(tracks))
+		for _, t := range tracks {
+			candidates = append(candidates, searchCandidate{
+				Label: t.ToSearchQuery(),
+				Query: fmt.Sprintf("https://open.spotify.com/track/%s", t.ID),
+			})
+		}
+		return candidates, nil
+
+	default:
+		return nil, fmt.Errorf("unknown search service: %s", service)
+	}
+}
+
+// handleSearchSelection resolves the option picked from a /search select
+// menu through the existing ResolveSongURLs -> AddSong path, the same one
+// /play and /add use.
+func (h *Handler) handleSearchSelection(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+	if len(data.Values) == 0 {
+		return
+	}
+
+	idx, err := strconv.Atoi(data.Values[0])
+	if err != nil {
+		return
+	}
+
+	query, ok := h.takeSearchQuery(i.Message.ID, idx)
+	if !ok {
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    "⚠️ This search result has expired. Run `/search` again.",
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+		return
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		h.logger.WithError(err).Error("Failed to acknowledge search selection")
+		return
+	}
+
+	channelID, err := h.getUserVoiceChannel(s, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		h.editSearchMessage(s, i, "❌ You must be in a voice channel to queue a song")
+		return
+	}
+
+	songs, _, err := h.ResolveSongURLs(h.contextFor(i), query)
+	if err != nil {
+		h.editSearchMessage(s, i, fmt.Sprintf("❌ Failed to resolve selection: %v", err))
+		return
+	}
+	if len(songs) == 0 {
+		h.editSearchMessage(s, i, "❌ Could not resolve the selected result")
+		return
+	}
+
+	songInfo := songs[0]
+	song := entities.NewSong(songInfo.URL, songInfo.SourceType, i.Member.User.ID, i.GuildID)
+	if err := h.playbackService.AddSong(h.contextFor(i), i.GuildID, song); err != nil {
+		h.editSearchMessage(s, i, fmt.Sprintf("❌ Failed to queue song: %v", err))
+		return
+	}
+
+	h.playbackService.BindNowPlayingChannel(i.GuildID, i.ChannelID)
+
+	if !h.playbackService.IsPlaying(i.GuildID) {
+		if err := h.playbackService.Play(h.contextFor(i), i.GuildID, channelID, i.Member.User.ID); err != nil {
+			h.editSearchMessage(s, i, fmt.Sprintf("❌ Failed to start playback: %v", err))
+			return
+		}
+	}
+
+	title := songInfo.Title
+	if title == "" {
+		title = query
+	}
+	h.editSearchMessage(s, i, fmt.Sprintf("🎵 Added **%s** to the queue", title))
+}
+
+// editSearchMessage replaces a /search reply's content with the outcome of
+// the requester's pick and removes its now-stale select menu
+func (h *Handler) editSearchMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	components := []discordgo.MessageComponent{}
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to edit search result message")
+	}
+}
+
+// storeSearchResults records the resolvable query behind each option of a
+// /search reply's select menu, keyed by the reply's message ID
+func (h *Handler) storeSearchResults(messageID string, queries []string) {
+	h.searchResultsMu.Lock()
+	defer h.searchResultsMu.Unlock()
+	h.searchResults[messageID] = queries
+}
+
+// takeSearchQuery looks up and consumes the stored queries for a /search
+// reply, returning the one at index. Consuming them on first pick keeps
+// searchResults from growing unbounded.
+func (h *Handler) takeSearchQuery(messageID string, index int) (string, bool) {
+	h.searchResultsMu.Lock()
+	defer h.searchResultsMu.Unlock()
+
+	queries, ok := h.searchResults[messageID]
+	if !ok || index < 0 || index >= len(queries) {
+		return "", false
+	}
+	delete(h.searchResults, messageID)
+	return queries[index], true
+}
+
+// formatSeconds renders a duration in seconds as MM:SS, matching
+// SongMetadata.DurationFormatted
+func formatSeconds(seconds int) string {
+	if seconds <= 0 {
+		return "00:00"
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// truncate shortens s to at most n runes, appending an ellipsis if it was cut
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
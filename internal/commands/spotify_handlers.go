@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
+)
+
+// handleSpotifySubcommand handles Spotify account linking and playlist
+// export subcommands
+func (h *Handler) handleSpotifySubcommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if h.spotifyService == nil {
+		return respondError(s, i, "Spotify integration is not configured on this bot")
+	}
+	if !h.spotifyService.UserAuthEnabled() {
+		return respondError(s, i, "Spotify account linking is not configured on this bot - ask the bot operator to set SPOTIFY_REDIRECT_URL")
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return respondError(s, i, "Invalid subcommand")
+	}
+
+	subCmd := options[0]
+	switch subCmd.Name {
+	case "connect":
+		return h.handleSpotifyConnect(s, i)
+	case "export":
+		return h.handleSpotifyExport(s, i, subCmd)
+	default:
+		return respondError(s, i, "Unknown subcommand")
+	}
+}
+
+func (h *Handler) handleSpotifyConnect(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	url, err := h.spotifyService.GenerateConnectURL(i.Member.User.ID)
+	if err != nil {
+		return respondError(s, i, err.Error())
+	}
+
+	embed := NewEmbed().
+		Title("🔗 Connect your Spotify account").
+		Description(fmt.Sprintf("[Click here to authorize](%s)\nThis link expires in 10 minutes.", url)).
+		Color(ColorInfo).
+		Build()
+	return respondEmbed(s, i, embed)
+}
+
+func (h *Handler) handleSpotifyExport(s *discordgo.Session, i *discordgo.InteractionCreate, subCmd *discordgo.ApplicationCommandInteractionDataOption) error {
+	name := subCmd.Options[0].StringValue()
+
+	tracklist := h.playbackService.GetTracklist(i.GuildID)
+	songs := tracklist.GetAllSongs()
+	if len(songs) == 0 {
+		return respondError(s, i, "The queue is empty - add some songs first")
+	}
+
+	if err := deferResponse(s, i); err != nil {
+		return err
+	}
+
+	trackIDs := h.resolveSpotifyTrackIDs(songs)
+	if len(trackIDs) == 0 {
+		return followUpError(s, i, "Couldn't match any queued songs to Spotify tracks")
+	}
+
+	url, err := h.spotifyService.CreatePlaylistFromTracks(requestContext(i, "spotify export"), i.Member.User.ID, name, trackIDs)
+	if err != nil {
+		return followUpError(s, i, err.Error())
+	}
+
+	return followUpSuccess(s, i, fmt.Sprintf("Created [%s](%s) with %d track(s)", name, url, len(trackIDs)))
+}
+
+// resolveSpotifyTrackIDs maps songs to Spotify track IDs, resolving
+// SourceTypeSpotify songs directly from their original URL and falling back
+// to a title search for everything else. Songs that can't be matched are
+// skipped rather than failing the whole export.
+func (h *Handler) resolveSpotifyTrackIDs(songs []*entities.Song) []string {
+	var trackIDs []string
+	for _, song := range songs {
+		if song.SourceType == valueobjects.SourceTypeSpotify {
+			if urlType, id, err := spotify.ParseSpotifyURL(song.OriginalInput); err == nil && urlType == "track" {
+				trackIDs = append(trackIDs, id)
+				continue
+			}
+		}
+
+		results, err := h.spotifyService.SearchTracks(song.DisplayName(), 1)
+		if err != nil || len(results) == 0 {
+			continue
+		}
+		trackIDs = append(trackIDs, results[0].ID)
+	}
+	return trackIDs
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services"
 )
 
 // handleJoin handles the join command
@@ -32,7 +33,8 @@ func (h *Handler) handleJoin(s *discordgo.Session, i *discordgo.InteractionCreat
 // handleLeave handles the leave command
 func (h *Handler) handleLeave(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	// Stop playback
-	h.playbackService.Stop(i.GuildID)
+	h.playbackService.Stop(h.contextFor(i), i.GuildID)
+	h.cancelPlaylistJob(i.GuildID)
 
 	// Clear queue
 	if tracklist := h.playbackService.GetTracklist(i.GuildID); tracklist != nil {
@@ -64,6 +66,44 @@ func (h *Handler) handleLeave(s *discordgo.Session, i *discordgo.InteractionCrea
 	return respondError(s, i, "I'm not currently in a voice channel")
 }
 
+// handleAutoLeave toggles the idle/alone auto-disconnect watcher for this
+// guild, overriding the server-wide STAY_CONNECTED_24_7 default until the
+// bot restarts.
+func (h *Handler) handleAutoLeave(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if !h.isAuthorizedController(i) {
+		return respondError(s, i, h.ownerDeniedMessage(i.GuildID))
+	}
+
+	enabled := !h.playbackService.AutoLeaveEnabled(i.GuildID)
+	h.playbackService.SetAutoLeave(i.GuildID, enabled)
+
+	icon := "🔁"
+	status := "disabled"
+	description := "I'll stay connected even when idle or alone."
+	if enabled {
+		icon = "💤"
+		status = "enabled"
+		description = fmt.Sprintf("I'll leave after %s alone, or %s idle with an empty queue.", h.config.AloneTimeout, h.config.IdleTimeout)
+	}
+
+	embed := NewEmbed().
+		Title(fmt.Sprintf("%s Auto-Leave %s", icon, status)).
+		Description(description).
+		Color(ColorInfo).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// HandleVoiceStateUpdate nudges a guild's idle/alone auto-disconnect watcher
+// to re-check immediately on any voice-state change, instead of waiting for
+// its next periodic poll. Registered alongside MusicBot's own
+// onVoiceStateUpdate (session-owner tracking) - discordgo calls every
+// handler registered for an event.
+func (h *Handler) HandleVoiceStateUpdate(s *discordgo.Session, event *discordgo.VoiceStateUpdate) {
+	h.playbackService.NotifyVoiceStateChange(event.GuildID)
+}
+
 // handleStats handles the stats command
 func (h *Handler) handleStats(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	voiceCount := len(s.VoiceConnections)
@@ -99,10 +139,13 @@ func (h *Handler) handleHelp(s *discordgo.Session, i *discordgo.InteractionCreat
 		Color(ColorPrimary).
 		Field("Basic",
 			"> **`/join` - Join voice channel**\n"+
-				"> **`/leave` - Leave and clear state**\n",
+				"> **`/leave` - Leave and clear state**\n"+
+				"> **`/autoleave` - Toggle idle/alone auto-disconnect**\n",
 			false).
 		Field("Playback",
 			"> **`/play <query>` - Play a song**\n"+
+				"> **`/addnext <query>` - [Admin] Play next**\n"+
+				"> **`/playnow <query>` - [Admin] Interrupt and play now**\n"+
 				"> **`/pause` - Pause playback**\n"+
 				"> **`/resume` - Resume playback**\n"+
 				"> **`/skip` - Skip current song**\n"+
@@ -125,8 +168,11 @@ func (h *Handler) handleHelp(s *discordgo.Session, i *discordgo.InteractionCreat
 			false).
 		Field("Utility",
 			"> **`/stats` - Bot statistics**\n"+
+				"> **`/find <query>` - Fuzzy search playlists & songs**\n"+
+				"> **`/search <service> <query>` - Search & pick a result to queue**\n"+
 				"> **`/help` - Show this help**\n"+
-				"> **`/sync` - [Admin] Sync commands**",
+				"> **`/sync` - [Admin] Sync commands**\n"+
+				"> **`/cache stats/clear` - [Admin] Manage song resolution cache**",
 			false).
 		Footer("Discord Music Bot v2.0.0 • Built with Go").
 		Build()
@@ -134,6 +180,45 @@ func (h *Handler) handleHelp(s *discordgo.Session, i *discordgo.InteractionCreat
 	return respondEmbed(s, i, embed)
 }
 
+// handleFind handles the find command, a fuzzy "did you mean" search over
+// playlist names, playlist songs, and recently played songs
+func (h *Handler) handleFind(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	query := i.ApplicationCommandData().Options[0].StringValue()
+
+	results := h.searchService.Search(query, 10)
+	if len(results) == 0 {
+		return respondError(s, i, fmt.Sprintf("No matches found for %q", query))
+	}
+
+	var lines string
+	for _, r := range results {
+		lines += fmt.Sprintf("> **%s** `%s` — %.0f%% match\n", r.Text, findKindLabel(r.Kind), r.Score*100)
+	}
+
+	embed := NewEmbed().
+		Title("🔍 Search Results").
+		Description(lines).
+		Color(ColorInfo).
+		Footer(fmt.Sprintf("Query: %s", query)).
+		Build()
+
+	return respondEmbed(s, i, embed)
+}
+
+// findKindLabel renders a SearchKind for display in /find results
+func findKindLabel(kind services.SearchKind) string {
+	switch kind {
+	case services.SearchKindPlaylist:
+		return "playlist"
+	case services.SearchKindPlaylistEntry:
+		return "playlist song"
+	case services.SearchKindSong:
+		return "recent song"
+	default:
+		return string(kind)
+	}
+}
+
 // handleSync handles the sync command
 func (h *Handler) handleSync(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	if err := deferEphemeral(s, i); err != nil {
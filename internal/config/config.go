@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +19,14 @@ type Config struct {
 	Version          string
 	StayConnected247 bool
 
+	// IdleTimeout is how long a guild can sit stopped with an empty queue
+	// before AudioService's idle watcher disconnects it. AloneTimeout is how
+	// long the bot can be alone (no non-bot listeners) in a voice channel
+	// before the same watcher disconnects it. Both are ignored for a guild
+	// with StayConnected247 true, unless /autoleave overrides it.
+	IdleTimeout  time.Duration
+	AloneTimeout time.Duration
+
 	// Database
 	DatabaseURL string
 	UseDatabase bool
@@ -25,20 +35,146 @@ type Config struct {
 	SpotifyClientID     string
 	SpotifyClientSecret string
 
+	// SpotifyRedirectURL is the OAuth2 Authorization Code callback this bot
+	// is registered with in the Spotify developer dashboard (must match
+	// exactly). Empty disables /spotify connect - SpotifyClientID/Secret
+	// alone only support the read-only client-credentials grant.
+	SpotifyRedirectURL string
+	// SpotifyOAuthAddr is the listen address for the callback HTTP server
+	// SpotifyRedirectURL points at, e.g. ":8181"
+	SpotifyOAuthAddr string
+
+	// SoundCloudClientID authenticates requests to SoundCloud's public
+	// api-v2 (used by internal/sources.SoundCloudProvider). Leave empty to
+	// fall back to yt-dlp for SoundCloud URLs/search
+	SoundCloudClientID string
+
+	// Last.fm (scrobbling)
+	LastFMAPIKey    string
+	LastFMAPISecret string
+
 	// Directories (fallback for file-based storage)
 	PlaylistDir string
 	CacheDir    string
 
+	// StateDir holds the per-guild playback snapshots used to resume queues
+	// and voice connections after a restart; see
+	// repositories.StateRepositoryInterface.
+	StateDir string
+
+	// PlayHistoryDir holds per-guild play counts backing smart playlists'
+	// play_count criteria; see repositories.PlayHistoryRepositoryInterface.
+	PlayHistoryDir string
+
 	// Logging
 	LogLevel string
 	LogFile  string
 
+	// LogBufferSize is how many recent structured log entries the in-Discord
+	// /logs admin command can dump; see pkg/logger/hooks.RingBuffer.
+	LogBufferSize int
+
 	// Performance
 	WorkerCount          int
 	MaxQueueSize         int
 	CacheSizeMB          int
 	CacheDurationMinutes int
 	InitialLoadSize      int
+
+	// SongCacheSizeMB caps the on-disk size of the downloaded-audio song
+	// cache (internal/services/cache.SongCache); SongCacheExpireHours bounds
+	// how long an unreplayed song survives before Sweep evicts it regardless
+	// of size pressure
+	SongCacheSizeMB      int
+	SongCacheExpireHours int
+
+	// SpotifyResolveWorkers caps how many Spotify tracks
+	// addSpotifyTracksProgressively resolves to YouTube concurrently, so a
+	// large playlist/album finishes in roughly 1/N the wall-clock time of a
+	// strictly sequential resolution
+	SpotifyResolveWorkers int
+
+	// PlaylistExtractWorkers caps how many entries youtube.Service.ExtractPlaylistAsync
+	// resolves to full metadata concurrently, so a large YouTube/SoundCloud
+	// playlist can start playback on its first track instead of stalling
+	// /play until every entry has been extracted
+	PlaylistExtractWorkers int
+
+	// Playlist sync
+	PlaylistSyncInterval time.Duration
+
+	// PlaylistSyncCron is the default cron expression the scheduler checks
+	// playlists against; a playlist's own ExternalInfo.SyncCron overrides it.
+	// Empty falls back to the fixed PlaylistSyncInterval ticker.
+	PlaylistSyncCron string
+
+	// PlaylistSyncDryRun logs the changes a sync would make without saving
+	// them, for auditing a new cron schedule before trusting it
+	PlaylistSyncDryRun bool
+
+	// MaxPlaylistURLSize caps how many tracks a single playlist URL (YouTube
+	// list=, Spotify playlist/album, SoundCloud set) can fan out into per /play
+	// or /add, so a 5,000-song playlist can't flood the queue
+	MaxPlaylistURLSize int
+
+	// MaxPlaylistFileSize caps the size in bytes of an uploaded M3U/PLS/XSPF
+	// file accepted by /playlist import-file
+	MaxPlaylistFileSize int
+
+	// SkipRatio is the fraction of non-bot listeners in the voice channel
+	// required to approve a /skip vote (rounded up, minimum 1 vote)
+	SkipRatio float64
+
+	// VoteThresholdRatio is the fraction of non-bot voice-channel listeners
+	// required to pass a /voteskip or /votestop vote started via
+	// audio.AudioPlayer.VoteStart (rounded up). VoteMinVotes, if > 0, raises
+	// that requirement to a fixed minimum regardless of ratio - useful on a
+	// large server where a low ratio would otherwise pass with just one or
+	// two votes. VoteTTL is how long a vote session stays open before it
+	// must be restarted.
+	VoteThresholdRatio float64
+	VoteMinVotes       int
+	VoteTTL            time.Duration
+
+	// OwnerlessMode disables the session-owner control gate entirely, so
+	// any user can pause/stop/skip/adjust volume. Intended for small,
+	// trusted private servers
+	OwnerlessMode bool
+
+	// AdminRoleIDs are Discord role IDs treated as admin for commands that
+	// gate on h.isAdmin (e.g. /addnext, /playnow), in addition to anyone
+	// with the guild's actual Administrator permission
+	AdminRoleIDs []string
+
+	// DJRoleIDs are Discord role IDs that bypass the /voteremove and
+	// /voteshuffle democratic vote entirely, same idea as AdminRoleIDs but
+	// for the "DJ" concept the round-robin queue mode already names
+	DJRoleIDs []string
+
+	// Lavalink offloads audio encoding to a pool of remote nodes instead of
+	// running yt-dlp/FFmpeg in this process; see audio.LavalinkBackend.
+	LavalinkEnabled bool
+	// LavalinkNodes is the pool's host:port addresses, in no particular
+	// order - AudioService hashes each guild onto one of them
+	LavalinkNodes []string
+	// LavalinkPassword authenticates both the REST and WebSocket calls to
+	// every node in the pool; Lavalink only supports one password per node,
+	// so this assumes all configured nodes share it
+	LavalinkPassword string
+	// LavalinkSecure selects wss/https instead of ws/http for node connections
+	LavalinkSecure bool
+
+	// Metrics
+	MetricsEnabled bool
+	// MetricsBackend selects the Recorder implementation: "prometheus" or
+	// "redis". Ignored when MetricsEnabled is false.
+	MetricsBackend string
+	// MetricsAddr is the listen address for the Prometheus /metrics endpoint
+	// (e.g. ":9090")
+	MetricsAddr string
+	// MetricsRedisAddr is the host:port of the Redis instance metrics are
+	// pushed to when MetricsBackend is "redis"
+	MetricsRedisAddr string
 }
 
 // Load reads configuration from environment variables
@@ -78,6 +214,8 @@ func Load() (*Config, error) {
 		BotName:          getEnvOrDefault("BOT_NAME", "Discord Music Bot"),
 		Version:          getEnvOrDefault("VERSION", "2.0.0"),
 		StayConnected247: getEnvBool("STAY_CONNECTED_24_7", true),
+		IdleTimeout:      getEnvDuration("IDLE_TIMEOUT", 5*time.Minute),
+		AloneTimeout:     getEnvDuration("ALONE_TIMEOUT", 2*time.Minute),
 
 		// Database
 		DatabaseURL: databaseURL,
@@ -86,21 +224,69 @@ func Load() (*Config, error) {
 		// Spotify
 		SpotifyClientID:     os.Getenv("SPOTIFY_CLIENT_ID"),
 		SpotifyClientSecret: os.Getenv("SPOTIFY_CLIENT_SECRET"),
+		SpotifyRedirectURL:  os.Getenv("SPOTIFY_REDIRECT_URL"),
+		SpotifyOAuthAddr:    getEnvOrDefault("SPOTIFY_OAUTH_ADDR", ":8181"),
+
+		// SoundCloud
+		SoundCloudClientID: os.Getenv("SOUNDCLOUD_CLIENT_ID"),
+
+		// Last.fm
+		LastFMAPIKey:    os.Getenv("LASTFM_API_KEY"),
+		LastFMAPISecret: os.Getenv("LASTFM_API_SECRET"),
 
 		// Directories
-		PlaylistDir: getEnvOrDefault("PLAYLIST_DIR", "./playlist"),
-		CacheDir:    getEnvOrDefault("CACHE_DIR", "./cache"),
+		PlaylistDir:    getEnvOrDefault("PLAYLIST_DIR", "./playlist"),
+		CacheDir:       getEnvOrDefault("CACHE_DIR", "./cache"),
+		StateDir:       getEnvOrDefault("STATE_DIR", "./state"),
+		PlayHistoryDir: getEnvOrDefault("PLAY_HISTORY_DIR", "./play_history"),
 
 		// Logging
-		LogLevel: getEnvOrDefault("LOG_LEVEL", "INFO"),
-		LogFile:  getEnvOrDefault("LOG_FILE", ""),
+		LogLevel:      getEnvOrDefault("LOG_LEVEL", "INFO"),
+		LogFile:       getEnvOrDefault("LOG_FILE", ""),
+		LogBufferSize: getEnvInt("LOG_BUFFER_SIZE", 500),
 
 		// Performance
-		WorkerCount:          getEnvInt("WORKER_COUNT", 3),
-		MaxQueueSize:         getEnvInt("MAX_QUEUE_SIZE", 100),
-		CacheSizeMB:          getEnvInt("CACHE_SIZE_MB", 100),
-		CacheDurationMinutes: getEnvInt("CACHE_DURATION_MINUTES", 360),
-		InitialLoadSize:      getEnvInt("INITIAL_LOAD_SIZE", 5),
+		WorkerCount:           getEnvInt("WORKER_COUNT", 3),
+		MaxQueueSize:          getEnvInt("MAX_QUEUE_SIZE", 100),
+		CacheSizeMB:           getEnvInt("CACHE_SIZE_MB", 100),
+		CacheDurationMinutes:  getEnvInt("CACHE_DURATION_MINUTES", 360),
+		InitialLoadSize:       getEnvInt("INITIAL_LOAD_SIZE", 5),
+		SpotifyResolveWorkers: getEnvInt("SPOTIFY_RESOLVE_WORKERS", 4),
+
+		PlaylistExtractWorkers: getEnvInt("PLAYLIST_EXTRACT_WORKERS", 8),
+
+		SongCacheSizeMB:      getEnvInt("SONG_CACHE_SIZE_MB", 1024),
+		SongCacheExpireHours: getEnvInt("SONG_CACHE_EXPIRE_HOURS", 168),
+
+		// Playlist sync
+		PlaylistSyncInterval: getEnvDuration("PLAYLIST_SYNC_INTERVAL", 6*time.Hour),
+		PlaylistSyncCron:     getEnvOrDefault("PLAYLIST_SYNC_CRON", ""),
+		PlaylistSyncDryRun:   getEnvBool("PLAYLIST_SYNC_DRY_RUN", false),
+
+		MaxPlaylistURLSize: getEnvInt("MAX_PLAYLIST_URL_SIZE", 500),
+
+		MaxPlaylistFileSize: getEnvInt("MAX_PLAYLIST_FILE_SIZE", 2*1024*1024),
+
+		SkipRatio: getEnvFloat("SKIP_RATIO", 0.5),
+
+		VoteThresholdRatio: getEnvFloat("VOTE_THRESHOLD_RATIO", 0.5),
+		VoteMinVotes:       getEnvInt("VOTE_MIN_VOTES", 0),
+		VoteTTL:            getEnvDuration("VOTE_TTL", 60*time.Second),
+
+		OwnerlessMode: getEnvBool("OWNERLESS_MODE", false),
+
+		AdminRoleIDs: getEnvStringSlice("ADMIN_ROLE_IDS", nil),
+		DJRoleIDs:    getEnvStringSlice("DJ_ROLE_IDS", nil),
+
+		LavalinkEnabled:  getEnvBool("LAVALINK_ENABLED", false),
+		LavalinkNodes:    getEnvStringSlice("LAVALINK_NODES", nil),
+		LavalinkPassword: getEnvOrDefault("LAVALINK_PASSWORD", "youshallnotpass"),
+		LavalinkSecure:   getEnvBool("LAVALINK_SECURE", false),
+
+		MetricsEnabled:   getEnvBool("METRICS_ENABLED", false),
+		MetricsBackend:   getEnvOrDefault("METRICS_BACKEND", "prometheus"),
+		MetricsAddr:      getEnvOrDefault("METRICS_ADDR", ":9090"),
+		MetricsRedisAddr: getEnvOrDefault("METRICS_REDIS_ADDR", "localhost:6379"),
 	}
 
 	// Create directories if needed (for file-based fallback)
@@ -114,6 +300,14 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
+	if err := os.MkdirAll(cfg.StateDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	if err := os.MkdirAll(cfg.PlayHistoryDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create play history directory: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -143,6 +337,41 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvStringSlice splits a comma-separated env var into a trimmed,
+// non-empty slice, or returns defaultValue if it's unset.
+func getEnvStringSlice(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		switch value {
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Guild mirrors a row in the guilds table (migration 00004).
+type Guild struct {
+	ID   string
+	Name *string
+}
+
+// Playlist mirrors a row in the playlists table (migrations 00001, 00004).
+// GuildID is nil for global (not guild-scoped) playlists.
+type Playlist struct {
+	ID        uuid.UUID
+	GuildID   *string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PlaylistEntry mirrors a row in the playlist_entries table (migration 00004).
+type PlaylistEntry struct {
+	ID            uuid.UUID
+	PlaylistID    uuid.UUID
+	Position      int32
+	OriginalInput string
+	Title         *string
+	SourceType    string
+	AddedBy       *string
+	AddedAt       time.Time
+}
+
+// PlaylistExternalInfo mirrors a row in the playlist_external_info table
+// (migrations 00001, 00005). LastSync, Etag and SyncCron are nullable in
+// the schema but surfaced as their zero value rather than a pointer, since
+// that's how entities.ExternalInfo models them.
+type PlaylistExternalInfo struct {
+	PlaylistID uuid.UUID
+	Source     string
+	ExternalID string
+	Url        string
+	LastSync   time.Time
+	Etag       string
+	SyncCron   string
+}
+
+// UserScrobbleCredential mirrors a row in the user_scrobble_credentials
+// table (migration 00002).
+type UserScrobbleCredential struct {
+	UserID   string
+	Service  string
+	Token    string
+	LinkedAt time.Time
+}
+
+// SpotifyTrackCache mirrors a row in the spotify_track_cache table
+// (migration 00003).
+type SpotifyTrackCache struct {
+	SpotifyID string
+	Data      []byte
+	ExpiresAt time.Time
+}
+
+// SmartPlaylist mirrors a row in the smart_playlists table (migration 00006).
+type SmartPlaylist struct {
+	ID        uuid.UUID
+	GuildID   *string
+	Name      string
+	Criteria  []byte
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GuildPlaybackState mirrors a row in the guild_playback_state table
+// (migration 00007).
+type GuildPlaybackState struct {
+	GuildID        string
+	VoiceChannelID string
+	TextChannelID  string
+	Songs          []byte
+	CurrentIndex   int32
+	History        []byte
+	PositionMs     int64
+	Volume         int32
+	RepeatMode     string
+	QueueMode      string
+	UpdatedAt      time.Time
+}
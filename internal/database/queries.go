@@ -0,0 +1,489 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is satisfied by both *pgxpool.Pool and pgx.Tx, so a Queries can run
+// against the pool directly or against a transaction started by a caller
+// (see WithTx).
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Queries is the hand-written equivalent of sqlc-generated output: one
+// method per query, backed by the raw SQL against the schema in
+// internal/database/migrations.
+type Queries struct {
+	db DBTX
+}
+
+// New returns a Queries that runs against pool.
+func New(pool *pgxpool.Pool) *Queries {
+	return &Queries{db: pool}
+}
+
+// WithTx returns a Queries that runs against tx instead of the pool, so a
+// caller can compose several queries into one transaction.
+func (q *Queries) WithTx(tx pgx.Tx) *Queries {
+	return &Queries{db: tx}
+}
+
+// --- guilds ---
+
+type UpsertGuildParams struct {
+	ID   string
+	Name *string
+}
+
+func (q *Queries) UpsertGuild(ctx context.Context, arg UpsertGuildParams) (Guild, error) {
+	row := q.db.QueryRow(ctx, `
+		INSERT INTO guilds (id, name) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET name = COALESCE(EXCLUDED.name, guilds.name)
+		RETURNING id, name
+	`, arg.ID, arg.Name)
+
+	var g Guild
+	if err := row.Scan(&g.ID, &g.Name); err != nil {
+		return Guild{}, err
+	}
+	return g, nil
+}
+
+// --- playlists ---
+
+func (q *Queries) ListPlaylistsByGuild(ctx context.Context, guildID *string) ([]Playlist, error) {
+	rows, err := q.db.Query(ctx, `
+		SELECT id, guild_id, name, created_at, updated_at
+		FROM playlists
+		WHERE guild_id IS NOT DISTINCT FROM $1
+		ORDER BY name
+	`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var playlists []Playlist
+	for rows.Next() {
+		var p Playlist
+		if err := rows.Scan(&p.ID, &p.GuildID, &p.Name, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+	return playlists, rows.Err()
+}
+
+type GetPlaylistByNameAndGuildParams struct {
+	Name    string
+	GuildID *string
+}
+
+// GetPlaylistByNameAndGuild returns pgx.ErrNoRows if no playlist matches, so
+// callers that already branch on errors.Is(err, pgx.ErrNoRows) elsewhere in
+// this package can handle a missing playlist the same way.
+func (q *Queries) GetPlaylistByNameAndGuild(ctx context.Context, arg GetPlaylistByNameAndGuildParams) (*Playlist, error) {
+	row := q.db.QueryRow(ctx, `
+		SELECT id, guild_id, name, created_at, updated_at
+		FROM playlists
+		WHERE name = $1 AND guild_id IS NOT DISTINCT FROM $2
+	`, arg.Name, arg.GuildID)
+
+	var p Playlist
+	if err := row.Scan(&p.ID, &p.GuildID, &p.Name, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+type CreatePlaylistParams struct {
+	Name    string
+	GuildID *string
+}
+
+func (q *Queries) CreatePlaylist(ctx context.Context, arg CreatePlaylistParams) (Playlist, error) {
+	row := q.db.QueryRow(ctx, `
+		INSERT INTO playlists (guild_id, name) VALUES ($1, $2)
+		RETURNING id, guild_id, name, created_at, updated_at
+	`, arg.GuildID, arg.Name)
+
+	var p Playlist
+	if err := row.Scan(&p.ID, &p.GuildID, &p.Name, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return Playlist{}, err
+	}
+	return p, nil
+}
+
+type UpdatePlaylistNameParams struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func (q *Queries) UpdatePlaylistName(ctx context.Context, arg UpdatePlaylistNameParams) error {
+	_, err := q.db.Exec(ctx, `UPDATE playlists SET name = $2, updated_at = now() WHERE id = $1`, arg.ID, arg.Name)
+	return err
+}
+
+type DeletePlaylistByNameParams struct {
+	Name    string
+	GuildID *string
+}
+
+func (q *Queries) DeletePlaylistByName(ctx context.Context, arg DeletePlaylistByNameParams) error {
+	_, err := q.db.Exec(ctx, `
+		DELETE FROM playlists WHERE name = $1 AND guild_id IS NOT DISTINCT FROM $2
+	`, arg.Name, arg.GuildID)
+	return err
+}
+
+type PlaylistExistsParams struct {
+	Name    string
+	GuildID *string
+}
+
+func (q *Queries) PlaylistExists(ctx context.Context, arg PlaylistExistsParams) (bool, error) {
+	var exists bool
+	err := q.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM playlists WHERE name = $1 AND guild_id IS NOT DISTINCT FROM $2)
+	`, arg.Name, arg.GuildID).Scan(&exists)
+	return exists, err
+}
+
+// --- playlist entries ---
+
+func (q *Queries) ListPlaylistEntries(ctx context.Context, playlistID uuid.UUID) ([]PlaylistEntry, error) {
+	rows, err := q.db.Query(ctx, `
+		SELECT id, playlist_id, position, original_input, title, source_type, added_by, added_at
+		FROM playlist_entries
+		WHERE playlist_id = $1
+		ORDER BY position
+	`, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []PlaylistEntry
+	for rows.Next() {
+		var e PlaylistEntry
+		if err := rows.Scan(&e.ID, &e.PlaylistID, &e.Position, &e.OriginalInput, &e.Title, &e.SourceType, &e.AddedBy, &e.AddedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (q *Queries) DeletePlaylistEntriesByPlaylistID(ctx context.Context, playlistID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, `DELETE FROM playlist_entries WHERE playlist_id = $1`, playlistID)
+	return err
+}
+
+type AddPlaylistEntryParams struct {
+	PlaylistID    uuid.UUID
+	Position      int32
+	OriginalInput string
+	SourceType    string
+	Title         *string
+}
+
+func (q *Queries) AddPlaylistEntry(ctx context.Context, arg AddPlaylistEntryParams) (PlaylistEntry, error) {
+	row := q.db.QueryRow(ctx, `
+		INSERT INTO playlist_entries (playlist_id, position, original_input, source_type, title)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, playlist_id, position, original_input, title, source_type, added_by, added_at
+	`, arg.PlaylistID, arg.Position, arg.OriginalInput, arg.SourceType, arg.Title)
+
+	var e PlaylistEntry
+	if err := row.Scan(&e.ID, &e.PlaylistID, &e.Position, &e.OriginalInput, &e.Title, &e.SourceType, &e.AddedBy, &e.AddedAt); err != nil {
+		return PlaylistEntry{}, err
+	}
+	return e, nil
+}
+
+// --- playlist external info ---
+
+func (q *Queries) DeletePlaylistExternalInfo(ctx context.Context, playlistID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, `DELETE FROM playlist_external_info WHERE playlist_id = $1`, playlistID)
+	return err
+}
+
+type UpsertPlaylistExternalInfoParams struct {
+	PlaylistID uuid.UUID
+	Source     string
+	ExternalID string
+	Url        string
+	Etag       string
+	SyncCron   string
+}
+
+func (q *Queries) UpsertPlaylistExternalInfo(ctx context.Context, arg UpsertPlaylistExternalInfoParams) error {
+	_, err := q.db.Exec(ctx, `
+		INSERT INTO playlist_external_info (playlist_id, source, external_id, url, etag, sync_cron, last_sync)
+		VALUES ($1, $2, $3, $4, nullif($5, ''), nullif($6, ''), now())
+		ON CONFLICT (playlist_id) DO UPDATE SET
+			source = EXCLUDED.source,
+			external_id = EXCLUDED.external_id,
+			url = EXCLUDED.url,
+			etag = EXCLUDED.etag,
+			sync_cron = EXCLUDED.sync_cron,
+			last_sync = now()
+	`, arg.PlaylistID, arg.Source, arg.ExternalID, arg.Url, arg.Etag, arg.SyncCron)
+	return err
+}
+
+func (q *Queries) GetPlaylistExternalInfo(ctx context.Context, playlistID uuid.UUID) (PlaylistExternalInfo, error) {
+	row := q.db.QueryRow(ctx, `
+		SELECT playlist_id, source, external_id, url, last_sync, etag, sync_cron
+		FROM playlist_external_info
+		WHERE playlist_id = $1
+	`, playlistID)
+
+	var info PlaylistExternalInfo
+	var lastSync *time.Time
+	var etag, syncCron *string
+	if err := row.Scan(&info.PlaylistID, &info.Source, &info.ExternalID, &info.Url, &lastSync, &etag, &syncCron); err != nil {
+		return PlaylistExternalInfo{}, err
+	}
+	if lastSync != nil {
+		info.LastSync = *lastSync
+	}
+	if etag != nil {
+		info.Etag = *etag
+	}
+	if syncCron != nil {
+		info.SyncCron = *syncCron
+	}
+	return info, nil
+}
+
+// --- smart playlists ---
+
+type UpsertSmartPlaylistParams struct {
+	GuildID  *string
+	Name     string
+	Criteria []byte
+}
+
+func (q *Queries) UpsertSmartPlaylist(ctx context.Context, arg UpsertSmartPlaylistParams) error {
+	_, err := q.db.Exec(ctx, `
+		INSERT INTO smart_playlists (guild_id, name, criteria) VALUES ($1, $2, $3)
+		ON CONFLICT (guild_id, name) DO UPDATE SET criteria = EXCLUDED.criteria, updated_at = now()
+	`, arg.GuildID, arg.Name, arg.Criteria)
+	return err
+}
+
+type GetSmartPlaylistByNameAndGuildParams struct {
+	Name    string
+	GuildID *string
+}
+
+func (q *Queries) GetSmartPlaylistByNameAndGuild(ctx context.Context, arg GetSmartPlaylistByNameAndGuildParams) (SmartPlaylist, error) {
+	row := q.db.QueryRow(ctx, `
+		SELECT id, guild_id, name, criteria, created_at, updated_at
+		FROM smart_playlists
+		WHERE name = $1 AND guild_id IS NOT DISTINCT FROM $2
+	`, arg.Name, arg.GuildID)
+
+	var sp SmartPlaylist
+	if err := row.Scan(&sp.ID, &sp.GuildID, &sp.Name, &sp.Criteria, &sp.CreatedAt, &sp.UpdatedAt); err != nil {
+		return SmartPlaylist{}, err
+	}
+	return sp, nil
+}
+
+type SmartPlaylistExistsParams struct {
+	Name    string
+	GuildID *string
+}
+
+func (q *Queries) SmartPlaylistExists(ctx context.Context, arg SmartPlaylistExistsParams) (bool, error) {
+	var exists bool
+	err := q.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM smart_playlists WHERE name = $1 AND guild_id IS NOT DISTINCT FROM $2)
+	`, arg.Name, arg.GuildID).Scan(&exists)
+	return exists, err
+}
+
+// --- scrobble credentials ---
+
+type UpsertScrobbleCredentialParams struct {
+	UserID  string
+	Service string
+	Token   string
+}
+
+func (q *Queries) UpsertScrobbleCredential(ctx context.Context, arg UpsertScrobbleCredentialParams) error {
+	_, err := q.db.Exec(ctx, `
+		INSERT INTO user_scrobble_credentials (user_id, service, token, linked_at) VALUES ($1, $2, $3, now())
+		ON CONFLICT (user_id, service) DO UPDATE SET token = EXCLUDED.token, linked_at = now()
+	`, arg.UserID, arg.Service, arg.Token)
+	return err
+}
+
+type DeleteScrobbleCredentialParams struct {
+	UserID  string
+	Service string
+}
+
+func (q *Queries) DeleteScrobbleCredential(ctx context.Context, arg DeleteScrobbleCredentialParams) error {
+	_, err := q.db.Exec(ctx, `
+		DELETE FROM user_scrobble_credentials WHERE user_id = $1 AND service = $2
+	`, arg.UserID, arg.Service)
+	return err
+}
+
+type GetScrobbleCredentialParams struct {
+	UserID  string
+	Service string
+}
+
+func (q *Queries) GetScrobbleCredential(ctx context.Context, arg GetScrobbleCredentialParams) (UserScrobbleCredential, error) {
+	row := q.db.QueryRow(ctx, `
+		SELECT user_id, service, token, linked_at
+		FROM user_scrobble_credentials
+		WHERE user_id = $1 AND service = $2
+	`, arg.UserID, arg.Service)
+
+	var c UserScrobbleCredential
+	if err := row.Scan(&c.UserID, &c.Service, &c.Token, &c.LinkedAt); err != nil {
+		return UserScrobbleCredential{}, err
+	}
+	return c, nil
+}
+
+func (q *Queries) ListScrobbleCredentialsByUser(ctx context.Context, userID string) ([]UserScrobbleCredential, error) {
+	rows, err := q.db.Query(ctx, `
+		SELECT user_id, service, token, linked_at
+		FROM user_scrobble_credentials
+		WHERE user_id = $1
+		ORDER BY service
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var creds []UserScrobbleCredential
+	for rows.Next() {
+		var c UserScrobbleCredential
+		if err := rows.Scan(&c.UserID, &c.Service, &c.Token, &c.LinkedAt); err != nil {
+			return nil, err
+		}
+		creds = append(creds, c)
+	}
+	return creds, rows.Err()
+}
+
+// --- spotify track cache ---
+
+func (q *Queries) GetSpotifyTrackCache(ctx context.Context, spotifyID string) (SpotifyTrackCache, error) {
+	row := q.db.QueryRow(ctx, `
+		SELECT spotify_id, data, expires_at FROM spotify_track_cache WHERE spotify_id = $1
+	`, spotifyID)
+
+	var c SpotifyTrackCache
+	if err := row.Scan(&c.SpotifyID, &c.Data, &c.ExpiresAt); err != nil {
+		return SpotifyTrackCache{}, err
+	}
+	return c, nil
+}
+
+type UpsertSpotifyTrackCacheParams struct {
+	SpotifyID string
+	Data      []byte
+	ExpiresAt time.Time
+}
+
+func (q *Queries) UpsertSpotifyTrackCache(ctx context.Context, arg UpsertSpotifyTrackCacheParams) error {
+	_, err := q.db.Exec(ctx, `
+		INSERT INTO spotify_track_cache (spotify_id, data, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (spotify_id) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at
+	`, arg.SpotifyID, arg.Data, arg.ExpiresAt)
+	return err
+}
+
+// --- guild playback state ---
+
+type UpsertGuildPlaybackStateParams struct {
+	GuildID        string
+	VoiceChannelID string
+	TextChannelID  string
+	Songs          []byte
+	CurrentIndex   int32
+	History        []byte
+	PositionMs     int64
+	Volume         int32
+	RepeatMode     string
+	QueueMode      string
+}
+
+func (q *Queries) UpsertGuildPlaybackState(ctx context.Context, arg UpsertGuildPlaybackStateParams) error {
+	_, err := q.db.Exec(ctx, `
+		INSERT INTO guild_playback_state (
+			guild_id, voice_channel_id, text_channel_id, songs, current_index,
+			history, position_ms, volume, repeat_mode, queue_mode, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, now())
+		ON CONFLICT (guild_id) DO UPDATE SET
+			voice_channel_id = EXCLUDED.voice_channel_id,
+			text_channel_id = EXCLUDED.text_channel_id,
+			songs = EXCLUDED.songs,
+			current_index = EXCLUDED.current_index,
+			history = EXCLUDED.history,
+			position_ms = EXCLUDED.position_ms,
+			volume = EXCLUDED.volume,
+			repeat_mode = EXCLUDED.repeat_mode,
+			queue_mode = EXCLUDED.queue_mode,
+			updated_at = now()
+	`, arg.GuildID, arg.VoiceChannelID, arg.TextChannelID, arg.Songs, arg.CurrentIndex,
+		arg.History, arg.PositionMs, arg.Volume, arg.RepeatMode, arg.QueueMode)
+	return err
+}
+
+func (q *Queries) GetGuildPlaybackState(ctx context.Context, guildID string) (GuildPlaybackState, error) {
+	row := q.db.QueryRow(ctx, `
+		SELECT guild_id, voice_channel_id, text_channel_id, songs, current_index,
+			history, position_ms, volume, repeat_mode, queue_mode, updated_at
+		FROM guild_playback_state
+		WHERE guild_id = $1
+	`, guildID)
+
+	var s GuildPlaybackState
+	if err := row.Scan(&s.GuildID, &s.VoiceChannelID, &s.TextChannelID, &s.Songs, &s.CurrentIndex,
+		&s.History, &s.PositionMs, &s.Volume, &s.RepeatMode, &s.QueueMode, &s.UpdatedAt); err != nil {
+		return GuildPlaybackState{}, err
+	}
+	return s, nil
+}
+
+func (q *Queries) DeleteGuildPlaybackState(ctx context.Context, guildID string) error {
+	_, err := q.db.Exec(ctx, `DELETE FROM guild_playback_state WHERE guild_id = $1`, guildID)
+	return err
+}
+
+func (q *Queries) ListGuildPlaybackStateIDs(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, `SELECT guild_id FROM guild_playback_state ORDER BY guild_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
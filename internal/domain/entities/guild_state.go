@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+)
+
+// PersistedSong is the lean, re-resolvable form of a Song that GuildState
+// stores: just enough to reconstruct and resubmit it for processing, not
+// the resolved metadata/stream URL a full Song carries, since those are
+// short-lived and would bloat every snapshot write for no benefit. See
+// Song.ToPersisted and RestoreSong.
+type PersistedSong struct {
+	ID            string                  `json:"id"`
+	OriginalInput string                  `json:"original_input"`
+	SourceType    valueobjects.SourceType `json:"source_type"`
+	RequestedBy   string                  `json:"requested_by,omitempty"`
+}
+
+// ToPersisted returns the lean snapshot of s stored in a GuildState.
+func (s *Song) ToPersisted() PersistedSong {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return PersistedSong{
+		ID:            s.ID,
+		OriginalInput: s.OriginalInput,
+		SourceType:    s.SourceType,
+		RequestedBy:   s.RequestedBy,
+	}
+}
+
+// RestoreSong rebuilds a fresh, PENDING Song from a PersistedSong,
+// preserving its original ID so GuildState.History (and any other
+// by-ID reference) still resolves after restore. Callers are expected to
+// resubmit the result for processing, the same as any newly-queued song.
+func RestoreSong(ref PersistedSong, guildID string) *Song {
+	song := NewSong(ref.OriginalInput, ref.SourceType, ref.RequestedBy, guildID)
+	song.ID = ref.ID
+	return song
+}
+
+// GuildState is a point-in-time snapshot of everything needed to resume
+// playback in a guild after the bot restarts: where it was connected, what
+// was queued, and how far into the current song it had gotten. It's written
+// by a StateRepositoryInterface implementation on every state-changing
+// playback operation and read back once, at startup reconciliation.
+type GuildState struct {
+	GuildID string `json:"guild_id"`
+
+	// VoiceChannelID and TextChannelID are where the bot should rejoin and
+	// where it should keep posting the live now-playing message. Empty
+	// VoiceChannelID means the guild wasn't connected and is skipped on
+	// restore.
+	VoiceChannelID string `json:"voice_channel_id"`
+	TextChannelID  string `json:"text_channel_id,omitempty"`
+
+	// Songs is the full tracklist in order, CurrentIndex the 0-indexed
+	// position of the song that was playing (or about to play). History is
+	// the song IDs that had already been played, oldest first, mirroring
+	// Tracklist's own history buffer.
+	Songs        []PersistedSong `json:"songs"`
+	CurrentIndex int             `json:"current_index"`
+	History      []string        `json:"history,omitempty"`
+
+	// Position is how far into the current song playback had progressed,
+	// used to seek back to the same spot on resume.
+	Position time.Duration `json:"position"`
+
+	Volume     int        `json:"volume"`
+	RepeatMode RepeatMode `json:"repeat_mode"`
+	QueueMode  QueueMode  `json:"queue_mode"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -117,11 +117,11 @@ func (l *Library) AddToPlaylist(playlistName, originalInput string, sourceType v
 	}
 
 	// Check for duplicate
-	if playlist.HasEntry(originalInput) {
+	if playlist.HasEntry(originalInput, "") {
 		return true, nil // Not an error, just a duplicate
 	}
 
-	playlist.AddEntry(originalInput, sourceType, title)
+	playlist.AddEntry(originalInput, sourceType, title, "")
 
 	if err := l.SavePlaylist(playlist); err != nil {
 		return false, err
@@ -137,7 +137,7 @@ func (l *Library) RemoveFromPlaylist(playlistName, originalInput string) error {
 		return err
 	}
 
-	if !playlist.RemoveEntry(originalInput) {
+	if !playlist.RemoveEntry(originalInput, "") {
 		return ErrSongNotInPlaylist
 	}
 
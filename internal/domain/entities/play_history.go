@@ -0,0 +1,11 @@
+package entities
+
+// PlayRecord tracks how many times a track has been played in a guild,
+// keyed by its OriginalInput so plays of the same track queued from
+// different playlists still accumulate into one count. Used by smart
+// playlists to evaluate play_count criteria.
+type PlayRecord struct {
+	OriginalInput string   `json:"original_input"`
+	PlayCount     int      `json:"play_count"`
+	LastPlayedAt  FlexTime `json:"last_played_at"`
+}
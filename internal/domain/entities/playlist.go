@@ -52,14 +52,40 @@ type PlaylistEntry struct {
 	SourceType    valueobjects.SourceType `json:"source_type"`
 	Title         string                  `json:"title,omitempty"`
 	AddedAt       FlexTime                `json:"added_at"`
+	// ExternalID is the upstream provider's stable identifier for this entry
+	// (e.g. a YouTube video ID or Spotify track ID). Only set for entries
+	// that came from an externally-synced playlist; used to diff remote
+	// changes by identity rather than by title, which can be renamed.
+	ExternalID string `json:"external_id,omitempty"`
+	// ResolvedID is a cross-platform-normalized identifier (e.g.
+	// "yt:VIDEOID"), populated by a services.MediaIDResolver when the entry
+	// is added. Empty for legacy entries added before this field existed, or
+	// for inputs a resolver doesn't recognize (e.g. a plain search query);
+	// HasEntry/RemoveEntry fall back to OriginalInput in that case.
+	ResolvedID string `json:"resolved_id,omitempty"`
 }
 
 // Playlist represents a saved collection of songs
 type Playlist struct {
-	Name      string           `json:"name"`
-	Entries   []*PlaylistEntry `json:"entries"`
-	CreatedAt FlexTime         `json:"created_at,omitempty"`
-	UpdatedAt FlexTime         `json:"updated_at,omitempty"`
+	Name         string           `json:"name"`
+	Entries      []*PlaylistEntry `json:"entries"`
+	CreatedAt    FlexTime         `json:"created_at,omitempty"`
+	UpdatedAt    FlexTime         `json:"updated_at,omitempty"`
+	ExternalInfo *ExternalInfo    `json:"external_info,omitempty"`
+}
+
+// ExternalInfo tracks the remote source a playlist was imported from so it
+// can be periodically re-synced.
+type ExternalInfo struct {
+	Source     valueobjects.SourceType `json:"source"`
+	ExternalID string                  `json:"external_id"`
+	URL        string                  `json:"url"`
+	LastSync   FlexTime                `json:"last_sync,omitempty"`
+	ETag       string                  `json:"etag,omitempty"`
+	// SyncCron is a standard 5-field cron expression overriding how often
+	// the PlaylistSyncScheduler reconciles this playlist. Empty means use
+	// the bot's global default schedule.
+	SyncCron string `json:"sync_cron,omitempty"`
 }
 
 // NewPlaylist creates a new empty playlist
@@ -73,22 +99,37 @@ func NewPlaylist(name string) *Playlist {
 	}
 }
 
-// AddEntry adds a new entry to the playlist
-func (p *Playlist) AddEntry(originalInput string, sourceType valueobjects.SourceType, title string) {
+// AddEntry adds a new entry to the playlist. resolvedID is the
+// cross-platform ID a services.MediaIDResolver produced for originalInput,
+// or "" if none was resolved (see PlaylistEntry.ResolvedID).
+func (p *Playlist) AddEntry(originalInput string, sourceType valueobjects.SourceType, title, resolvedID string) {
 	entry := &PlaylistEntry{
 		OriginalInput: originalInput,
 		SourceType:    sourceType,
 		Title:         title,
 		AddedAt:       FlexTime{time.Now()},
+		ResolvedID:    resolvedID,
 	}
 	p.Entries = append(p.Entries, entry)
 	p.UpdatedAt = FlexTime{time.Now()}
 }
 
-// RemoveEntry removes an entry by original input
-func (p *Playlist) RemoveEntry(originalInput string) bool {
+// matchesEntry reports whether entry is the same song as (originalInput,
+// resolvedID): matched by ResolvedID when the entry has one and the caller
+// supplied one, falling back to OriginalInput for legacy entries or inputs a
+// MediaIDResolver didn't recognize.
+func matchesEntry(entry *PlaylistEntry, originalInput, resolvedID string) bool {
+	if entry.ResolvedID != "" && resolvedID != "" {
+		return entry.ResolvedID == resolvedID
+	}
+	return entry.OriginalInput == originalInput
+}
+
+// RemoveEntry removes an entry matching originalInput/resolvedID (see
+// matchesEntry)
+func (p *Playlist) RemoveEntry(originalInput, resolvedID string) bool {
 	for i, entry := range p.Entries {
-		if entry.OriginalInput == originalInput {
+		if matchesEntry(entry, originalInput, resolvedID) {
 			p.Entries = append(p.Entries[:i], p.Entries[i+1:]...)
 			p.UpdatedAt = FlexTime{time.Now()}
 			return true
@@ -97,10 +138,11 @@ func (p *Playlist) RemoveEntry(originalInput string) bool {
 	return false
 }
 
-// HasEntry checks if an entry exists
-func (p *Playlist) HasEntry(originalInput string) bool {
+// HasEntry checks if an entry matching originalInput/resolvedID already
+// exists (see matchesEntry)
+func (p *Playlist) HasEntry(originalInput, resolvedID string) bool {
 	for _, entry := range p.Entries {
-		if entry.OriginalInput == originalInput {
+		if matchesEntry(entry, originalInput, resolvedID) {
 			return true
 		}
 	}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+)
+
+// ScrobbleCredential links a Discord user to a scrobbling account. Token
+// holds whatever the provider needs to authenticate a submission: a
+// ListenBrainz user token, or a Last.fm session key.
+type ScrobbleCredential struct {
+	UserID   string                       `json:"user_id"`
+	Service  valueobjects.ScrobbleService `json:"service"`
+	Token    string                       `json:"token"`
+	LinkedAt time.Time                    `json:"linked_at"`
+}
+
+// NewScrobbleCredential creates a new credential linking userID to service
+func NewScrobbleCredential(userID string, service valueobjects.ScrobbleService, token string) *ScrobbleCredential {
+	return &ScrobbleCredential{
+		UserID:   userID,
+		Service:  service,
+		Token:    token,
+		LinkedAt: time.Now(),
+	}
+}
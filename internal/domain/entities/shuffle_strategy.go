@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// artistSpacedLookahead bounds how far ArtistSpacedStrategy scans ahead of a
+// same-artist clash to find a song with a different artist to swap in,
+// before giving up and leaving the clash in place.
+const artistSpacedLookahead = 10
+
+// ShuffleStrategy reorders a tracklist's songs in place. Tracklist.Shuffle
+// and SmartShuffle delegate to one so new shuffle behaviors (e.g. a future
+// "favor songs I haven't heard" mode) can be added without changing
+// Tracklist itself - just implement ShuffleStrategy and wire up a new
+// Tracklist method that calls shuffleWith.
+type ShuffleStrategy interface {
+	// Order reorders songs in place. recentIDs holds the guild's play
+	// history (oldest first, as returned by Tracklist.HistoryIDs) for
+	// strategies that want to avoid resurfacing a recently played song
+	// too soon; strategies that don't care about history can ignore it.
+	Order(songs []*Song, recentIDs []string)
+}
+
+// RandomStrategy is a plain Fisher-Yates shuffle with no regard for artist
+// or play history - the original Tracklist.Shuffle behavior.
+type RandomStrategy struct{}
+
+// Order implements ShuffleStrategy.
+func (RandomStrategy) Order(songs []*Song, recentIDs []string) {
+	fisherYates(songs)
+}
+
+// ArtistSpacedStrategy Fisher-Yates shuffles, then walks the result and for
+// any adjacent pair sharing the same artist, swaps the second song with the
+// nearest later song (within artistSpacedLookahead positions) whose artist
+// differs from both of its would-be neighbors. If no such song exists, the
+// clash is left in place rather than risk creating one further down.
+type ArtistSpacedStrategy struct{}
+
+// Order implements ShuffleStrategy.
+func (ArtistSpacedStrategy) Order(songs []*Song, recentIDs []string) {
+	fisherYates(songs)
+
+	for i := 1; i < len(songs); i++ {
+		if artistOf(songs[i-1]) == "" || artistOf(songs[i]) != artistOf(songs[i-1]) {
+			continue
+		}
+
+		// Prefer the next song within the lookahead window; if none of
+		// those work, fall back to one behind it (still within the
+		// window) - a clash right at the end of the queue would
+		// otherwise have no later song to swap with at all.
+		limit := i + artistSpacedLookahead
+		if limit > len(songs) {
+			limit = len(songs)
+		}
+		swapped := false
+		for j := i + 1; j < limit; j++ {
+			if canSwapToFixClash(songs, i, j) {
+				songs[i], songs[j] = songs[j], songs[i]
+				swapped = true
+				break
+			}
+		}
+		if swapped {
+			continue
+		}
+
+		floor := i - 1 - artistSpacedLookahead
+		if floor < 0 {
+			floor = 0
+		}
+		for j := i - 2; j >= floor; j-- {
+			if canSwapToFixClash(songs, i, j) {
+				songs[i], songs[j] = songs[j], songs[i]
+				break
+			}
+		}
+	}
+}
+
+// canSwapToFixClash reports whether swapping songs[i] and songs[j] (j not
+// adjacent to i) would leave both positions' new neighbors artist-distinct,
+// rather than just moving the clash somewhere else.
+func canSwapToFixClash(songs []*Song, i, j int) bool {
+	curArtist := artistOf(songs[i])
+	candidate := artistOf(songs[j])
+
+	if left := i - 1; left != j && left >= 0 && artistOf(songs[left]) == candidate {
+		return false
+	}
+	if right := i + 1; right != j && right < len(songs) && artistOf(songs[right]) == candidate {
+		return false
+	}
+	if left := j - 1; left != i && left >= 0 && artistOf(songs[left]) == curArtist {
+		return false
+	}
+	if right := j + 1; right != i && right < len(songs) && artistOf(songs[right]) == curArtist {
+		return false
+	}
+	return true
+}
+
+// artistOf returns song's primary artist, or "" if it has no metadata yet
+// (e.g. still resolving) - treated as never clashing with a neighbor.
+func artistOf(song *Song) string {
+	metadata := song.GetMetadata()
+	if metadata == nil {
+		return ""
+	}
+	return metadata.Artist
+}
+
+// RecentlyPlayedDeprioritizedStrategy Fisher-Yates shuffles, then stable-sorts
+// so songs found in recentIDs sink toward the back in the order they were
+// played - most recently played last - while songs with no play history keep
+// their post-shuffle random order at the front.
+type RecentlyPlayedDeprioritizedStrategy struct{}
+
+// Order implements ShuffleStrategy.
+func (RecentlyPlayedDeprioritizedStrategy) Order(songs []*Song, recentIDs []string) {
+	fisherYates(songs)
+
+	if len(recentIDs) == 0 {
+		return
+	}
+
+	// Songs not in recentIDs get rank 0 and stay near the front; songs that
+	// were played keep their relative recency, most recent last.
+	rank := make(map[string]int, len(recentIDs))
+	for i, id := range recentIDs {
+		rank[id] = i + 1
+	}
+
+	sort.SliceStable(songs, func(i, j int) bool {
+		return rank[songs[i].ID] < rank[songs[j].ID]
+	})
+}
+
+// fisherYates shuffles songs in place.
+func fisherYates(songs []*Song) {
+	for i := len(songs) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		songs[i], songs[j] = songs[j], songs[i]
+	}
+}
@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CriteriaField is the entry field a leaf Criteria node evaluates
+type CriteriaField string
+
+const (
+	FieldSourceType CriteriaField = "source_type"
+	FieldTitle      CriteriaField = "title"
+	FieldAddedAt    CriteriaField = "added_at"
+	FieldDuration   CriteriaField = "duration"
+	FieldPlayCount  CriteriaField = "play_count"
+)
+
+// CriteriaOp is the comparison a leaf Criteria node applies to its Field
+type CriteriaOp string
+
+const (
+	OpEquals      CriteriaOp = "eq"
+	OpContains    CriteriaOp = "contains"
+	OpOlderThan   CriteriaOp = "older_than" // Value is a Go duration string, e.g. "720h"
+	OpBetween     CriteriaOp = "between"    // Value is a two-element []interface{}{min, max}
+	OpGreaterThan CriteriaOp = "gt"
+)
+
+// Criteria is a node in a smart playlist's matching rule tree. A node is
+// either a boolean combinator (exactly one of All/Any/Not set) or a leaf
+// predicate (Field/Op/Value set, no combinator). JSON-serializable so it can
+// be persisted alongside a SmartPlaylist and round-tripped through a /playlist
+// smart command.
+type Criteria struct {
+	All []Criteria `json:"all,omitempty"`
+	Any []Criteria `json:"any,omitempty"`
+	Not *Criteria  `json:"not,omitempty"`
+
+	Field CriteriaField `json:"field,omitempty"`
+	Op    CriteriaOp    `json:"op,omitempty"`
+	Value interface{}   `json:"value,omitempty"`
+}
+
+// SmartPlaylist is a saved playlist whose membership is computed by
+// evaluating Criteria against a guild's regular playlist entries and play
+// history, rather than a fixed entry list like Playlist
+type SmartPlaylist struct {
+	Name      string   `json:"name"`
+	Criteria  Criteria `json:"criteria"`
+	CreatedAt FlexTime `json:"created_at,omitempty"`
+	UpdatedAt FlexTime `json:"updated_at,omitempty"`
+}
+
+// NewSmartPlaylist creates a new smart playlist with the given criteria
+func NewSmartPlaylist(name string, criteria Criteria) *SmartPlaylist {
+	now := FlexTime{Time: time.Now()}
+	return &SmartPlaylist{
+		Name:      name,
+		Criteria:  criteria,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Matches reports whether entry satisfies c, given how many times entry has
+// been played (from the play-history store; pass 0 if unknown).
+//
+// Duration is matched against 0 when the entry itself doesn't carry a
+// resolved duration (PlaylistEntry doesn't cache one - that only becomes
+// available once a song is processed for playback), so duration-based
+// criteria only match entries a caller has separately resolved.
+func (c *Criteria) Matches(entry *PlaylistEntry, playCount int) bool {
+	switch {
+	case len(c.All) > 0:
+		for _, child := range c.All {
+			if !child.Matches(entry, playCount) {
+				return false
+			}
+		}
+		return true
+	case len(c.Any) > 0:
+		for _, child := range c.Any {
+			if child.Matches(entry, playCount) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.Matches(entry, playCount)
+	default:
+		return c.matchesLeaf(entry, playCount)
+	}
+}
+
+// matchesLeaf evaluates a single Field/Op/Value predicate
+func (c *Criteria) matchesLeaf(entry *PlaylistEntry, playCount int) bool {
+	switch c.Field {
+	case FieldSourceType:
+		return c.Op == OpEquals && string(entry.SourceType) == fmt.Sprint(c.Value)
+	case FieldTitle:
+		return c.Op == OpContains && strings.Contains(strings.ToLower(entry.Title), strings.ToLower(fmt.Sprint(c.Value)))
+	case FieldAddedAt:
+		return c.Op == OpOlderThan && matchesOlderThan(entry.AddedAt.Time, c.Value)
+	case FieldDuration:
+		return c.Op == OpBetween && matchesBetween(0, c.Value)
+	case FieldPlayCount:
+		return c.Op == OpGreaterThan && matchesGreaterThan(float64(playCount), c.Value)
+	default:
+		return false
+	}
+}
+
+func matchesOlderThan(t time.Time, value interface{}) bool {
+	d, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil {
+		return false
+	}
+	return time.Since(t) > d
+}
+
+func matchesBetween(actual float64, value interface{}) bool {
+	bounds, ok := value.([]interface{})
+	if !ok || len(bounds) != 2 {
+		return false
+	}
+	min, minOK := toFloat(bounds[0])
+	max, maxOK := toFloat(bounds[1])
+	if !minOK || !maxOK {
+		return false
+	}
+	return actual >= min && actual <= max
+}
+
+func matchesGreaterThan(actual float64, value interface{}) bool {
+	threshold, ok := toFloat(value)
+	if !ok {
+		return false
+	}
+	return actual > threshold
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
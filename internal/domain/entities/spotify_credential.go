@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// SpotifyCredential links a Discord user to their own Spotify account via an
+// OAuth2 Authorization Code grant, so playlist-modify commands can act on
+// their behalf. AccessToken expires quickly; RefreshToken is long-lived and
+// used to mint new access tokens transparently. See
+// spotify.Service.UserClient.
+type SpotifyCredential struct {
+	UserID       string    `json:"user_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	LinkedAt     time.Time `json:"linked_at"`
+}
+
+// NewSpotifyCredential creates a new credential linking userID to their
+// Spotify account
+func NewSpotifyCredential(userID, accessToken, refreshToken string, expiresAt time.Time) *SpotifyCredential {
+	return &SpotifyCredential{
+		UserID:       userID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+		LinkedAt:     time.Now(),
+	}
+}
@@ -2,7 +2,7 @@ package entities
 
 import (
 	"container/list"
-	"math/rand"
+	"fmt"
 	"sync"
 )
 
@@ -15,6 +15,18 @@ const (
 	RepeatModeQueue RepeatMode = "queue"
 )
 
+// QueueMode selects how Tracklist picks the next song
+type QueueMode string
+
+const (
+	// QueueModeFIFO plays songs in the order they were added (default)
+	QueueModeFIFO QueueMode = "fifo"
+	// QueueModeRoundRobin gives each requester their own deque and takes
+	// one song per DJ per rotation, so one user queuing a long playlist
+	// doesn't crowd out everyone else
+	QueueModeRoundRobin QueueMode = "round_robin"
+)
+
 // Tracklist manages the song queue with thread-safety
 type Tracklist struct {
 	guildID      string
@@ -26,6 +38,25 @@ type Tracklist struct {
 	shuffleEnabled bool
 	repeatMode     RepeatMode
 
+	// Round-robin DJ queue mode. djQueues/djOrder/djTurn are only populated
+	// while queueMode == QueueModeRoundRobin; see SetQueueMode.
+	queueMode     QueueMode
+	djQueues      map[string][]*Song // requester user ID -> their pending songs
+	djOrder       []string           // rotation order of requesters with pending songs
+	djTurn        int                // index into djOrder of whose turn is next
+	currentRRSong *Song              // song popped by the round-robin rotation that's now "current"
+
+	// Democratic voting (VoteSkip/VoteRemove/VoteShuffle); see vote.go.
+	// votes is guarded by voteMu rather than mu, since a vote completing
+	// calls back into mu-guarded methods like RemoveSong and SmartShuffle.
+	voteMu sync.Mutex
+	votes  map[string]*voteSession
+
+	// EventBus delivers a VoteEvent whenever a vote reaches its threshold
+	// and its action runs, so the Discord layer can post a confirmation
+	// without polling. Buffered and never blocked on - see postVoteEvent.
+	EventBus chan VoteEvent
+
 	mu sync.RWMutex
 }
 
@@ -39,22 +70,71 @@ func NewTracklist(guildID string) *Tracklist {
 		maxHistory:     50,
 		shuffleEnabled: false,
 		repeatMode:     RepeatModeQueue, // Default: auto-repeat queue
+		queueMode:      QueueModeFIFO,
+		votes:          make(map[string]*voteSession),
+		EventBus:       make(chan VoteEvent, voteEventBusSize),
 	}
 }
 
-// AddSong adds a song to the queue and returns its position (1-indexed)
+// AddSong adds a song to the queue and returns its position (1-indexed). In
+// round-robin mode it's also appended to song.RequestedBy's own deque.
 func (t *Tracklist) AddSong(song *Song) int {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	t.songs = append(t.songs, song)
+
+	if t.queueMode == QueueModeRoundRobin {
+		t.enqueueRoundRobinLocked(song)
+	}
+
 	return len(t.songs)
 }
 
+// InsertAt inserts song at position (1-indexed), clamped to the queue's
+// bounds, shifting every song at or after it back by one. If position falls
+// at or before the currently playing song, currentIndex is advanced so it
+// keeps pointing at the same song. Returns the 1-indexed position the song
+// ended up at. Used by /addnext and /playnow to jump a song ahead of the
+// normal append-only AddSong order.
+func (t *Tracklist) InsertAt(position int, song *Song) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	index := position - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(t.songs) {
+		index = len(t.songs)
+	}
+
+	t.songs = append(t.songs, nil)
+	copy(t.songs[index+1:], t.songs[index:])
+	t.songs[index] = song
+
+	if index <= t.currentIndex {
+		t.currentIndex++
+	}
+
+	if t.queueMode == QueueModeRoundRobin {
+		t.enqueueRoundRobinLocked(song)
+	}
+
+	return index + 1
+}
+
 // CurrentSong returns the currently playing song
 func (t *Tracklist) CurrentSong() *Song {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.queueMode == QueueModeRoundRobin {
+		if t.currentRRSong == nil {
+			t.currentRRSong = t.advanceRoundRobinLocked()
+		}
+		return t.currentRRSong
+	}
 
 	if t.currentIndex >= 0 && t.currentIndex < len(t.songs) {
 		return t.songs[t.currentIndex]
@@ -69,12 +149,26 @@ func (t *Tracklist) NextSong() *Song {
 
 	// Track repeat: stay on same song
 	if t.repeatMode == RepeatModeTrack {
+		if t.queueMode == QueueModeRoundRobin {
+			if t.currentRRSong == nil {
+				t.currentRRSong = t.advanceRoundRobinLocked()
+			}
+			return t.currentRRSong
+		}
 		if t.currentIndex >= 0 && t.currentIndex < len(t.songs) {
 			return t.songs[t.currentIndex]
 		}
 		return nil
 	}
 
+	if t.queueMode == QueueModeRoundRobin {
+		if t.currentRRSong != nil {
+			t.addToHistory(t.currentRRSong)
+		}
+		t.currentRRSong = t.advanceRoundRobinLocked()
+		return t.currentRRSong
+	}
+
 	// Add current song to history
 	if t.currentIndex >= 0 && t.currentIndex < len(t.songs) {
 		t.addToHistory(t.songs[t.currentIndex])
@@ -253,6 +347,20 @@ func (t *Tracklist) SetShuffle(enabled bool) {
 
 // Shuffle randomizes the order of songs in the queue (keeping current song in place)
 func (t *Tracklist) Shuffle() {
+	t.shuffleWith(RandomStrategy{})
+}
+
+// SmartShuffle randomizes the queue like Shuffle, but spaces out same-artist
+// tracks so an album or playlist import doesn't play the same artist
+// back-to-back. See ArtistSpacedStrategy.
+func (t *Tracklist) SmartShuffle() {
+	t.shuffleWith(ArtistSpacedStrategy{})
+}
+
+// shuffleWith reorders every song except the currently playing one (kept
+// pinned at the front) according to strategy, so new shuffle behaviors can
+// be added as a ShuffleStrategy without touching Tracklist itself.
+func (t *Tracklist) shuffleWith(strategy ShuffleStrategy) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -271,11 +379,7 @@ func (t *Tracklist) Shuffle() {
 		}
 	}
 
-	// Fisher-Yates shuffle
-	for i := len(remaining) - 1; i > 0; i-- {
-		j := rand.Intn(i + 1)
-		remaining[i], remaining[j] = remaining[j], remaining[i]
-	}
+	strategy.Order(remaining, t.historyIDsLocked())
 
 	// Rebuild songs with current song at start
 	t.songs = append([]*Song{currentSong}, remaining...)
@@ -298,6 +402,43 @@ func (t *Tracklist) addToHistory(song *Song) {
 	t.history.PushBack(song)
 }
 
+// HistoryIDs returns the IDs of songs already played, oldest first, for
+// persisting alongside the queue. See RestoreHistory.
+func (t *Tracklist) HistoryIDs() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.historyIDsLocked()
+}
+
+// historyIDsLocked is HistoryIDs without taking the lock, for callers that
+// already hold it (e.g. shuffleWith, which needs history while holding the
+// write lock).
+func (t *Tracklist) historyIDsLocked() []string {
+	ids := make([]string, 0, t.history.Len())
+	for e := t.history.Front(); e != nil; e = e.Next() {
+		ids = append(ids, e.Value.(*Song).ID)
+	}
+	return ids
+}
+
+// RestoreHistory replaces the history buffer with songs, oldest first,
+// truncated to maxHistory. Intended to be called once, right after a
+// restored queue's songs have been re-added, so skip-back/PreviousSong
+// keeps working the way it would have before a restart.
+func (t *Tracklist) RestoreHistory(songs []*Song) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.history.Init()
+	start := 0
+	if len(songs) > t.maxHistory {
+		start = len(songs) - t.maxHistory
+	}
+	for _, song := range songs[start:] {
+		t.history.PushBack(song)
+	}
+}
+
 // GetAllSongs returns a copy of all songs (for display purposes)
 func (t *Tracklist) GetAllSongs() []*Song {
 	t.mu.RLock()
@@ -307,3 +448,190 @@ func (t *Tracklist) GetAllSongs() []*Song {
 	copy(songs, t.songs)
 	return songs
 }
+
+// QueueMode returns the tracklist's current queue mode
+func (t *Tracklist) QueueMode() QueueMode {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.queueMode
+}
+
+// SetQueueMode switches between FIFO and round-robin DJ queueing. Switching
+// to round-robin splits the upcoming songs into per-requester deques;
+// switching back flattens those deques into a single ordered queue.
+func (t *Tracklist) SetQueueMode(mode QueueMode) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if mode == t.queueMode {
+		return nil
+	}
+
+	switch mode {
+	case QueueModeRoundRobin:
+		t.enterRoundRobinLocked()
+	case QueueModeFIFO:
+		t.exitRoundRobinLocked()
+	default:
+		return fmt.Errorf("unknown queue mode: %q", mode)
+	}
+
+	t.queueMode = mode
+	return nil
+}
+
+// DJQueueLengths returns how many songs remain queued for each DJ. It's
+// empty outside round-robin mode.
+func (t *Tracklist) DJQueueLengths() map[string]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	lengths := make(map[string]int, len(t.djQueues))
+	for djID, queue := range t.djQueues {
+		lengths[djID] = len(queue)
+	}
+	return lengths
+}
+
+// enqueueRoundRobinLocked appends song to its requester's deque, registering
+// the requester in the rotation if this is their first pending song. Must be
+// called with t.mu held.
+func (t *Tracklist) enqueueRoundRobinLocked(song *Song) {
+	requester := song.RequestedBy
+	if _, exists := t.djQueues[requester]; !exists {
+		t.djOrder = append(t.djOrder, requester)
+	}
+	t.djQueues[requester] = append(t.djQueues[requester], song)
+}
+
+// advanceRoundRobinLocked pops the next song from whichever DJ's turn it is,
+// dropping any DJ whose deque is empty from the rotation. Must be called
+// with t.mu held.
+func (t *Tracklist) advanceRoundRobinLocked() *Song {
+	for len(t.djOrder) > 0 {
+		idx := t.djTurn % len(t.djOrder)
+		djID := t.djOrder[idx]
+		queue := t.djQueues[djID]
+
+		if len(queue) == 0 {
+			t.dropDJLocked(idx)
+			continue
+		}
+
+		song := queue[0]
+		t.djQueues[djID] = queue[1:]
+
+		if len(t.djQueues[djID]) == 0 {
+			t.dropDJLocked(idx)
+		} else {
+			t.djTurn = (idx + 1) % len(t.djOrder)
+		}
+
+		return song
+	}
+	return nil
+}
+
+// dropDJLocked removes the DJ at djOrder[idx] from rotation because their
+// deque is empty, leaving djTurn pointing at whoever now occupies that slot.
+// Must be called with t.mu held.
+func (t *Tracklist) dropDJLocked(idx int) {
+	djID := t.djOrder[idx]
+	delete(t.djQueues, djID)
+	t.djOrder = append(t.djOrder[:idx], t.djOrder[idx+1:]...)
+
+	if len(t.djOrder) == 0 {
+		t.djTurn = 0
+		return
+	}
+	t.djTurn = idx % len(t.djOrder)
+}
+
+// enterRoundRobinLocked splits the songs after the current one into
+// per-requester deques, leaving the currently playing song untouched. Must
+// be called with t.mu held.
+func (t *Tracklist) enterRoundRobinLocked() {
+	t.djQueues = make(map[string][]*Song)
+	t.djOrder = nil
+	t.djTurn = 0
+
+	if t.currentIndex >= 0 && t.currentIndex < len(t.songs) {
+		t.currentRRSong = t.songs[t.currentIndex]
+	} else {
+		t.currentRRSong = nil
+	}
+
+	start := t.currentIndex + 1
+	if start < 0 {
+		start = 0
+	}
+	if start > len(t.songs) {
+		start = len(t.songs)
+	}
+	for _, song := range t.songs[start:] {
+		t.enqueueRoundRobinLocked(song)
+	}
+}
+
+// exitRoundRobinLocked flattens the per-requester deques back into a single
+// FIFO queue, placed after the history and the currently playing song. Must
+// be called with t.mu held.
+func (t *Tracklist) exitRoundRobinLocked() {
+	historyEnd := t.currentIndex
+	if historyEnd < 0 {
+		historyEnd = 0
+	}
+	if historyEnd > len(t.songs) {
+		historyEnd = len(t.songs)
+	}
+
+	newSongs := append([]*Song{}, t.songs[:historyEnd]...)
+	newCurrentIndex := len(newSongs)
+
+	if t.currentRRSong != nil {
+		newSongs = append(newSongs, t.currentRRSong)
+	} else if len(newSongs) > 0 {
+		newCurrentIndex = len(newSongs) - 1
+	}
+
+	newSongs = append(newSongs, t.flattenRoundRobinLocked()...)
+
+	t.songs = newSongs
+	t.currentIndex = newCurrentIndex
+	t.djQueues = nil
+	t.djOrder = nil
+	t.djTurn = 0
+	t.currentRRSong = nil
+}
+
+// flattenRoundRobinLocked merges the remaining per-requester deques back
+// into a single slice in rotation order, so switching back to FIFO preserves
+// the fairness the round-robin rotation had established. Must be called
+// with t.mu held.
+func (t *Tracklist) flattenRoundRobinLocked() []*Song {
+	remaining := make(map[string][]*Song, len(t.djQueues))
+	for djID, queue := range t.djQueues {
+		remaining[djID] = append([]*Song{}, queue...)
+	}
+	order := append([]string{}, t.djOrder...)
+
+	var flat []*Song
+	idx := t.djTurn
+	for len(order) > 0 {
+		if idx >= len(order) {
+			idx = 0
+		}
+		djID := order[idx]
+		queue := remaining[djID]
+
+		if len(queue) == 0 {
+			order = append(order[:idx], order[idx+1:]...)
+			continue
+		}
+
+		flat = append(flat, queue[0])
+		remaining[djID] = queue[1:]
+		idx++
+	}
+	return flat
+}
@@ -212,3 +212,133 @@ func TestTracklistThreadSafety(t *testing.T) {
 		t.Error("Tracklist should have at least 10 songs after concurrent operations")
 	}
 }
+
+// songWithArtist builds a ready song with the given artist, for
+// SmartShuffle tests that need Song.GetMetadata() populated.
+func songWithArtist(artist string) *entities.Song {
+	song := entities.NewSong("url", valueobjects.SourceTypeYouTube, "User", "123456789")
+	song.MarkReady(&valueobjects.SongMetadata{Title: "Track", Artist: artist}, "stream-url")
+	return song
+}
+
+func TestTracklistSmartShuffleSpacesOutSameArtist(t *testing.T) {
+	tracklist := entities.NewTracklist("123456789")
+
+	// Current song plus two tracks each from four artists - balanced enough
+	// that a no-same-artist-adjacent arrangement always exists, so a failure
+	// here means SmartShuffle didn't space them out rather than "got unlucky".
+	tracklist.AddSong(songWithArtist("Current"))
+	for _, artist := range []string{"A", "B", "C", "D"} {
+		tracklist.AddSong(songWithArtist(artist))
+		tracklist.AddSong(songWithArtist(artist))
+	}
+
+	tracklist.SmartShuffle()
+
+	songs := tracklist.GetAllSongs()
+	if len(songs) != 9 {
+		t.Fatalf("expected 9 songs, got %d", len(songs))
+	}
+
+	for i := 1; i < len(songs); i++ {
+		prev := songs[i-1].GetMetadata().Artist
+		cur := songs[i].GetMetadata().Artist
+		if prev == cur {
+			t.Errorf("songs %d and %d both have artist %q, SmartShuffle should have spaced them", i-1, i, cur)
+		}
+	}
+}
+
+func TestTracklistVoteSkipPassesAtMajority(t *testing.T) {
+	tracklist := entities.NewTracklist("123456789")
+	tracklist.AddSong(entities.NewSong("url1", valueobjects.SourceTypeYouTube, "User1", "123456789"))
+	tracklist.AddSong(entities.NewSong("url2", valueobjects.SourceTypeYouTube, "User2", "123456789"))
+
+	// 3 eligible listeners -> majority is 2
+	if _, required, skipped := tracklist.VoteSkip("user-a", 3); required != 2 || skipped {
+		t.Fatalf("first vote: required=%d skipped=%v, want required=2 skipped=false", required, skipped)
+	}
+
+	votes, required, skipped := tracklist.VoteSkip("user-b", 3)
+	if votes != 2 || required != 2 || !skipped {
+		t.Fatalf("second vote: votes=%d required=%d skipped=%v, want votes=2 required=2 skipped=true", votes, required, skipped)
+	}
+
+	select {
+	case event := <-tracklist.EventBus:
+		if event.Type != entities.EventSongSkippedByVote {
+			t.Errorf("expected EventSongSkippedByVote, got %v", event.Type)
+		}
+	default:
+		t.Error("expected a VoteEvent on EventBus after the vote passed")
+	}
+}
+
+func TestTracklistVoteSkipDedupesVoter(t *testing.T) {
+	tracklist := entities.NewTracklist("123456789")
+	tracklist.AddSong(entities.NewSong("url1", valueobjects.SourceTypeYouTube, "User1", "123456789"))
+
+	tracklist.VoteSkip("user-a", 5)
+	votes, _, skipped := tracklist.VoteSkip("user-a", 5)
+	if votes != 1 || skipped {
+		t.Errorf("voting twice should not double-count: votes=%d skipped=%v", votes, skipped)
+	}
+}
+
+func TestTracklistVoteRemoveRemovesAtThreshold(t *testing.T) {
+	tracklist := entities.NewTracklist("123456789")
+	tracklist.AddSong(entities.NewSong("url1", valueobjects.SourceTypeYouTube, "User1", "123456789"))
+	tracklist.AddSong(entities.NewSong("url2", valueobjects.SourceTypeYouTube, "User2", "123456789"))
+
+	if tracklist.Size() != 2 {
+		t.Fatalf("expected 2 songs, got %d", tracklist.Size())
+	}
+
+	votes, required, removed := tracklist.VoteRemove(2, "user-a", 1)
+	if votes != 1 || required != 1 || !removed {
+		t.Fatalf("votes=%d required=%d removed=%v, want votes=1 required=1 removed=true", votes, required, removed)
+	}
+
+	if tracklist.Size() != 1 {
+		t.Errorf("expected song to be removed, size=%d", tracklist.Size())
+	}
+}
+
+func TestTracklistVoteShuffleShufflesAtThreshold(t *testing.T) {
+	tracklist := entities.NewTracklist("123456789")
+	tracklist.AddSong(songWithArtist("Current"))
+	for _, artist := range []string{"A", "B", "C", "D"} {
+		tracklist.AddSong(songWithArtist(artist))
+		tracklist.AddSong(songWithArtist(artist))
+	}
+
+	_, _, shuffled := tracklist.VoteShuffle("user-a", 1)
+	if !shuffled {
+		t.Fatal("expected a single voter (required=1) to pass the shuffle vote")
+	}
+
+	select {
+	case event := <-tracklist.EventBus:
+		if event.Type != entities.EventQueueShuffledByVote {
+			t.Errorf("expected EventQueueShuffledByVote, got %v", event.Type)
+		}
+	default:
+		t.Error("expected a VoteEvent on EventBus after the vote passed")
+	}
+}
+
+func TestTracklistSmartShuffleKeepsCurrentSongFirst(t *testing.T) {
+	tracklist := entities.NewTracklist("123456789")
+
+	current := songWithArtist("Current")
+	tracklist.AddSong(current)
+	for i := 0; i < 5; i++ {
+		tracklist.AddSong(songWithArtist("A"))
+	}
+
+	tracklist.SmartShuffle()
+
+	if tracklist.CurrentSong() != current {
+		t.Error("SmartShuffle should keep the currently playing song in place")
+	}
+}
@@ -0,0 +1,200 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// voteSessionTTL bounds how long a democratic vote can sit short of its
+// threshold before the next vote for that action starts a fresh session, so
+// a couple of early voters can't keep a stale ballot alive forever.
+const voteSessionTTL = 60 * time.Second
+
+// voteEventBusSize bounds Tracklist.EventBus so a completed vote never
+// blocks on a Discord layer that's momentarily behind on draining it.
+const voteEventBusSize = 16
+
+// voteSession tracks one in-progress democratic vote, deduplicating voters
+// by user ID. songID (when non-empty) pins the session to the song it was
+// started against, so once that song is no longer the one the vote concerns
+// - it played, got skipped some other way, or got removed - the stale
+// session is discarded instead of carrying over onto an unrelated song.
+type voteSession struct {
+	voters    map[string]struct{}
+	startedAt time.Time
+	songID    string
+}
+
+// stale reports whether session has expired or no longer applies to songID.
+func (s *voteSession) stale(songID string) bool {
+	if s == nil {
+		return true
+	}
+	if time.Since(s.startedAt) > voteSessionTTL {
+		return true
+	}
+	return s.songID != "" && s.songID != songID
+}
+
+// voteKeySkip and voteKeyShuffle key Tracklist.votes for VoteSkip/VoteShuffle
+// sessions; VoteRemove keys its session per queue position via
+// voteKeyRemove, since multiple removal votes can be in progress for
+// different songs at once.
+const (
+	voteKeySkip    = "skip"
+	voteKeyShuffle = "shuffle"
+)
+
+func voteKeyRemove(position int) string {
+	return fmt.Sprintf("remove:%d", position)
+}
+
+// tally registers userID in session, starting a fresh one pinned to songID
+// if session is nil or stale, and returns the resulting yes-vote count.
+func tally(session *voteSession, userID, songID string) (*voteSession, int) {
+	if session.stale(songID) {
+		session = &voteSession{voters: make(map[string]struct{}), startedAt: time.Now(), songID: songID}
+	}
+	session.voters[userID] = struct{}{}
+	return session, len(session.voters)
+}
+
+// requiredVotes computes the democratic pass threshold from the number of
+// eligible (non-bot) voice channel listeners: a simple majority, rounded up,
+// with a floor of 1 so a lone listener can still pass a vote.
+func requiredVotes(eligibleListeners int) int {
+	if eligibleListeners <= 1 {
+		return 1
+	}
+	return (eligibleListeners + 1) / 2
+}
+
+// VoteEventType identifies what a Tracklist vote accomplished, delivered via
+// Tracklist.EventBus so the Discord layer can post a confirmation without
+// polling.
+type VoteEventType string
+
+const (
+	EventSongSkippedByVote   VoteEventType = "song_skipped_by_vote"
+	EventSongRemovedByVote   VoteEventType = "song_removed_by_vote"
+	EventQueueShuffledByVote VoteEventType = "queue_shuffled_by_vote"
+)
+
+// VoteEvent is what Tracklist.EventBus delivers once a vote reaches its
+// threshold and its action runs. Song is the affected song for
+// EventSongSkippedByVote/EventSongRemovedByVote, nil for
+// EventQueueShuffledByVote. Position is the 1-indexed queue position
+// EventSongRemovedByVote acted on, else 0.
+type VoteEvent struct {
+	Type     VoteEventType
+	GuildID  string
+	Song     *Song
+	Position int
+}
+
+// postVoteEvent sends event on EventBus without blocking, so a slow or
+// absent consumer can't stall the vote that's completing.
+func (t *Tracklist) postVoteEvent(event VoteEvent) {
+	select {
+	case t.EventBus <- event:
+	default:
+	}
+}
+
+// VoteSkip registers userID's vote to skip the currently playing song.
+// eligibleListeners is the number of non-bot listeners currently in the
+// voice channel - Tracklist has no visibility into Discord voice state, so
+// the voice layer (PlaybackService) passes it in on every call - and is
+// used to compute required, a simple majority of eligibleListeners. Once
+// votes reaches required, the vote session clears and an
+// EventSongSkippedByVote event is posted to EventBus; the caller is still
+// responsible for actually advancing playback (e.g. PlaybackService.ForceSkip),
+// since stopping the AudioPlayer is outside Tracklist's reach.
+func (t *Tracklist) VoteSkip(userID string, eligibleListeners int) (votes, required int, skipped bool) {
+	current := t.CurrentSong()
+	required = requiredVotes(eligibleListeners)
+	if current == nil {
+		return 0, required, false
+	}
+
+	t.voteMu.Lock()
+	session, count := tally(t.votes[voteKeySkip], userID, current.ID)
+	t.votes[voteKeySkip] = session
+	t.voteMu.Unlock()
+
+	if count < required {
+		return count, required, false
+	}
+
+	t.voteMu.Lock()
+	delete(t.votes, voteKeySkip)
+	t.voteMu.Unlock()
+
+	t.postVoteEvent(VoteEvent{Type: EventSongSkippedByVote, GuildID: t.guildID, Song: current})
+	return count, required, true
+}
+
+// VoteRemove registers userID's vote to remove the song at position
+// (1-indexed). See VoteSkip for eligibleListeners. Once votes reaches
+// required, the song is removed, the vote session clears, and an
+// EventSongRemovedByVote event is posted to EventBus.
+func (t *Tracklist) VoteRemove(position int, userID string, eligibleListeners int) (votes, required int, removed bool) {
+	required = requiredVotes(eligibleListeners)
+
+	t.mu.RLock()
+	index := position - 1
+	var song *Song
+	if index >= 0 && index < len(t.songs) {
+		song = t.songs[index]
+	}
+	t.mu.RUnlock()
+	if song == nil {
+		return 0, required, false
+	}
+
+	key := voteKeyRemove(position)
+	t.voteMu.Lock()
+	session, count := tally(t.votes[key], userID, song.ID)
+	t.votes[key] = session
+	t.voteMu.Unlock()
+
+	if count < required {
+		return count, required, false
+	}
+
+	t.voteMu.Lock()
+	delete(t.votes, key)
+	t.voteMu.Unlock()
+
+	if !t.RemoveSong(position) {
+		return count, required, false
+	}
+
+	t.postVoteEvent(VoteEvent{Type: EventSongRemovedByVote, GuildID: t.guildID, Song: song, Position: position})
+	return count, required, true
+}
+
+// VoteShuffle registers userID's vote to smart-shuffle the queue. See
+// VoteSkip for eligibleListeners. Once votes reaches required, the queue is
+// shuffled via SmartShuffle, the vote session clears, and an
+// EventQueueShuffledByVote event is posted to EventBus.
+func (t *Tracklist) VoteShuffle(userID string, eligibleListeners int) (votes, required int, shuffled bool) {
+	required = requiredVotes(eligibleListeners)
+
+	t.voteMu.Lock()
+	session, count := tally(t.votes[voteKeyShuffle], userID, "")
+	t.votes[voteKeyShuffle] = session
+	t.voteMu.Unlock()
+
+	if count < required {
+		return count, required, false
+	}
+
+	t.voteMu.Lock()
+	delete(t.votes, voteKeyShuffle)
+	t.voteMu.Unlock()
+
+	t.SmartShuffle()
+	t.postVoteEvent(VoteEvent{Type: EventQueueShuffledByVote, GuildID: t.guildID})
+	return count, required, true
+}
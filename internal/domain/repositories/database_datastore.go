@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+)
+
+// DatabaseDataStore is the SQL-backed DataStore implementation.
+// WithTransaction runs fn against a single pgx transaction, committing it
+// if fn returns nil and rolling it back otherwise - the same
+// begin/WithTx/commit pattern DatabasePlaylistRepository.Save already uses
+// for its own multi-statement writes, just made available to callers that
+// need to span more than one repository call.
+type DatabaseDataStore struct {
+	db          *database.DB
+	playHistory PlayHistoryRepositoryInterface
+}
+
+// NewDatabaseDataStore creates a SQL-backed DataStore. playHistory is
+// always file-based (see PlaylistService.playHistory) and does not
+// participate in the SQL transaction; it's returned as-is from
+// PlayHistory().
+func NewDatabaseDataStore(db *database.DB, playHistory PlayHistoryRepositoryInterface) *DatabaseDataStore {
+	return &DatabaseDataStore{db: db, playHistory: playHistory}
+}
+
+// WithTransaction runs fn with a DataStore whose Playlists() handle is
+// scoped to a single SQL transaction, committed only if fn returns nil.
+func (ds *DatabaseDataStore) WithTransaction(fn func(tx DataStore) error) error {
+	ctx := context.Background()
+
+	tx, err := ds.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	txDB := &database.DB{Pool: ds.db.Pool, Queries: ds.db.Queries.WithTx(tx)}
+	txStore := &DatabaseDataStore{db: txDB, playHistory: ds.playHistory}
+
+	if err := fn(txStore); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Playlists returns the playlist repository handle scoped to this
+// DataStore (or transaction)
+func (ds *DatabaseDataStore) Playlists() PlaylistRepositoryInterface {
+	return NewDatabasePlaylistRepository(ds.db)
+}
+
+// PlayHistory returns the (always file-based) play-history repository
+func (ds *DatabaseDataStore) PlayHistory() PlayHistoryRepositoryInterface {
+	return ds.playHistory
+}
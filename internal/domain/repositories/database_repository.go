@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -155,10 +156,11 @@ func (r *DatabasePlaylistRepository) Save(guildID string, playlist *entities.Pla
 	}
 
 	// Add entries
-	for _, entry := range playlist.Entries {
+	for i, entry := range playlist.Entries {
 		title := entry.Title
 		_, err := queries.AddPlaylistEntry(ctx, database.AddPlaylistEntryParams{
 			PlaylistID:    playlistID,
+			Position:      int32(i),
 			OriginalInput: entry.OriginalInput,
 			SourceType:    string(entry.SourceType),
 			Title:         &title,
@@ -206,3 +208,129 @@ func (r *DatabasePlaylistRepository) Exists(guildID, name string) bool {
 
 	return exists
 }
+
+// SaveExternalInfo records the remote source a playlist was imported from
+func (r *DatabasePlaylistRepository) SaveExternalInfo(guildID, name string, info *entities.ExternalInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbPlaylist, err := r.db.Queries.GetPlaylistByNameAndGuild(ctx, database.GetPlaylistByNameAndGuildParams{
+		Name:    name,
+		GuildID: toGuildIDPtr(guildID),
+	})
+	if err != nil {
+		return fmt.Errorf("playlist '%s' not found: %w", name, err)
+	}
+
+	if info == nil {
+		if err := r.db.Queries.DeletePlaylistExternalInfo(ctx, dbPlaylist.ID); err != nil {
+			return fmt.Errorf("failed to clear external info: %w", err)
+		}
+		return nil
+	}
+
+	if err := r.db.Queries.UpsertPlaylistExternalInfo(ctx, database.UpsertPlaylistExternalInfoParams{
+		PlaylistID: dbPlaylist.ID,
+		Source:     string(info.Source),
+		ExternalID: info.ExternalID,
+		Url:        info.URL,
+		Etag:       info.ETag,
+		SyncCron:   info.SyncCron,
+	}); err != nil {
+		return fmt.Errorf("failed to save external info: %w", err)
+	}
+
+	return nil
+}
+
+// GetExternalInfo returns the remote source info for a playlist, or nil if
+// the playlist isn't externally sourced
+func (r *DatabasePlaylistRepository) GetExternalInfo(guildID, name string) (*entities.ExternalInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbPlaylist, err := r.db.Queries.GetPlaylistByNameAndGuild(ctx, database.GetPlaylistByNameAndGuildParams{
+		Name:    name,
+		GuildID: toGuildIDPtr(guildID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("playlist '%s' not found: %w", name, err)
+	}
+
+	row, err := r.db.Queries.GetPlaylistExternalInfo(ctx, dbPlaylist.ID)
+	if err != nil {
+		return nil, nil // No external info recorded
+	}
+
+	return &entities.ExternalInfo{
+		Source:     valueobjects.SourceType(row.Source),
+		ExternalID: row.ExternalID,
+		URL:        row.Url,
+		LastSync:   entities.FlexTime{Time: row.LastSync},
+		ETag:       row.Etag,
+		SyncCron:   row.SyncCron,
+	}, nil
+}
+
+// SaveSmart saves a smart playlist for a guild
+func (r *DatabasePlaylistRepository) SaveSmart(guildID string, playlist *entities.SmartPlaylist) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	criteria, err := json.Marshal(playlist.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal criteria: %w", err)
+	}
+
+	if err := r.db.Queries.UpsertSmartPlaylist(ctx, database.UpsertSmartPlaylistParams{
+		GuildID:  toGuildIDPtr(guildID),
+		Name:     playlist.Name,
+		Criteria: criteria,
+	}); err != nil {
+		return fmt.Errorf("failed to save smart playlist: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSmart loads a smart playlist by name for a guild, or nil if it doesn't exist
+func (r *DatabasePlaylistRepository) LoadSmart(guildID, name string) (*entities.SmartPlaylist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	row, err := r.db.Queries.GetSmartPlaylistByNameAndGuild(ctx, database.GetSmartPlaylistByNameAndGuildParams{
+		Name:    name,
+		GuildID: toGuildIDPtr(guildID),
+	})
+	if err != nil {
+		return nil, nil // Smart playlist doesn't exist
+	}
+
+	var criteria entities.Criteria
+	if err := json.Unmarshal(row.Criteria, &criteria); err != nil {
+		return nil, fmt.Errorf("failed to parse smart playlist criteria: %w", err)
+	}
+
+	return &entities.SmartPlaylist{
+		Name:      row.Name,
+		Criteria:  criteria,
+		CreatedAt: entities.FlexTime{Time: row.CreatedAt},
+		UpdatedAt: entities.FlexTime{Time: row.UpdatedAt},
+	}, nil
+}
+
+// IsSmart reports whether name is a smart playlist for a guild
+func (r *DatabasePlaylistRepository) IsSmart(guildID, name string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	exists, err := r.db.Queries.SmartPlaylistExists(ctx, database.SmartPlaylistExistsParams{
+		Name:    name,
+		GuildID: toGuildIDPtr(guildID),
+	})
+	if err != nil {
+		return false, nil
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+)
+
+// DatabaseScrobbleCredentialRepository implements
+// ScrobbleCredentialRepositoryInterface using PostgreSQL
+type DatabaseScrobbleCredentialRepository struct {
+	db *database.DB
+}
+
+// NewDatabaseScrobbleCredentialRepository creates a new database-backed
+// scrobble credential repository
+func NewDatabaseScrobbleCredentialRepository(db *database.DB) *DatabaseScrobbleCredentialRepository {
+	return &DatabaseScrobbleCredentialRepository{
+		db: db,
+	}
+}
+
+// Link stores (or replaces) a user's credential for a service
+func (r *DatabaseScrobbleCredentialRepository) Link(userID string, service valueobjects.ScrobbleService, token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := r.db.Queries.UpsertScrobbleCredential(ctx, database.UpsertScrobbleCredentialParams{
+		UserID:  userID,
+		Service: service.String(),
+		Token:   token,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to link scrobble credential: %w", err)
+	}
+	return nil
+}
+
+// Unlink removes a user's credential for a service
+func (r *DatabaseScrobbleCredentialRepository) Unlink(userID string, service valueobjects.ScrobbleService) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := r.db.Queries.DeleteScrobbleCredential(ctx, database.DeleteScrobbleCredentialParams{
+		UserID:  userID,
+		Service: service.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unlink scrobble credential: %w", err)
+	}
+	return nil
+}
+
+// Get returns a user's credential for a service, or nil if not linked
+func (r *DatabaseScrobbleCredentialRepository) Get(userID string, service valueobjects.ScrobbleService) (*entities.ScrobbleCredential, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	row, err := r.db.Queries.GetScrobbleCredential(ctx, database.GetScrobbleCredentialParams{
+		UserID:  userID,
+		Service: service.String(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scrobble credential: %w", err)
+	}
+
+	return &entities.ScrobbleCredential{
+		UserID:   row.UserID,
+		Service:  valueobjects.ScrobbleService(row.Service),
+		Token:    row.Token,
+		LinkedAt: row.LinkedAt,
+	}, nil
+}
+
+// ListForUser returns every credential a user has linked
+func (r *DatabaseScrobbleCredentialRepository) ListForUser(userID string) ([]*entities.ScrobbleCredential, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Queries.ListScrobbleCredentialsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scrobble credentials: %w", err)
+	}
+
+	creds := make([]*entities.ScrobbleCredential, 0, len(rows))
+	for _, row := range rows {
+		creds = append(creds, &entities.ScrobbleCredential{
+			UserID:   row.UserID,
+			Service:  valueobjects.ScrobbleService(row.Service),
+			Token:    row.Token,
+			LinkedAt: row.LinkedAt,
+		})
+	}
+	return creds, nil
+}
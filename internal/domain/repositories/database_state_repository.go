@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// DatabaseStateRepository implements StateRepositoryInterface using
+// PostgreSQL, storing each guild's snapshot as a single upserted row (see
+// migration 00007_guild_playback_state.sql) rather than one JSON file per
+// guild like StateRepository.
+type DatabaseStateRepository struct {
+	db *database.DB
+}
+
+// NewDatabaseStateRepository creates a new database-backed state repository
+func NewDatabaseStateRepository(db *database.DB) *DatabaseStateRepository {
+	return &DatabaseStateRepository{db: db}
+}
+
+// Save writes (or overwrites) the snapshot for state.GuildID
+func (r *DatabaseStateRepository) Save(state *entities.GuildState) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	songs, err := json.Marshal(state.Songs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal guild state songs: %w", err)
+	}
+	history, err := json.Marshal(state.History)
+	if err != nil {
+		return fmt.Errorf("failed to marshal guild state history: %w", err)
+	}
+
+	err = r.db.Queries.UpsertGuildPlaybackState(ctx, database.UpsertGuildPlaybackStateParams{
+		GuildID:        state.GuildID,
+		VoiceChannelID: state.VoiceChannelID,
+		TextChannelID:  state.TextChannelID,
+		Songs:          songs,
+		CurrentIndex:   int32(state.CurrentIndex),
+		History:        history,
+		PositionMs:     state.Position.Milliseconds(),
+		Volume:         int32(state.Volume),
+		RepeatMode:     string(state.RepeatMode),
+		QueueMode:      string(state.QueueMode),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save guild playback state: %w", err)
+	}
+	return nil
+}
+
+// Load returns the saved snapshot for guildID, or nil if none exists
+func (r *DatabaseStateRepository) Load(guildID string) (*entities.GuildState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	row, err := r.db.Queries.GetGuildPlaybackState(ctx, guildID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load guild playback state: %w", err)
+	}
+
+	var songs []entities.PersistedSong
+	if err := json.Unmarshal(row.Songs, &songs); err != nil {
+		return nil, fmt.Errorf("failed to parse guild state songs: %w", err)
+	}
+	var history []string
+	if err := json.Unmarshal(row.History, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse guild state history: %w", err)
+	}
+
+	return &entities.GuildState{
+		GuildID:        row.GuildID,
+		VoiceChannelID: row.VoiceChannelID,
+		TextChannelID:  row.TextChannelID,
+		Songs:          songs,
+		CurrentIndex:   int(row.CurrentIndex),
+		History:        history,
+		Position:       time.Duration(row.PositionMs) * time.Millisecond,
+		Volume:         int(row.Volume),
+		RepeatMode:     entities.RepeatMode(row.RepeatMode),
+		QueueMode:      entities.QueueMode(row.QueueMode),
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
+
+// Delete removes any saved snapshot for guildID
+func (r *DatabaseStateRepository) Delete(guildID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := r.db.Queries.DeleteGuildPlaybackState(ctx, guildID); err != nil {
+		return fmt.Errorf("failed to delete guild playback state: %w", err)
+	}
+	return nil
+}
+
+// ListGuildIDs returns the guild IDs with a saved snapshot
+func (r *DatabaseStateRepository) ListGuildIDs() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ids, err := r.db.Queries.ListGuildPlaybackStateIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guild playback states: %w", err)
+	}
+	return ids, nil
+}
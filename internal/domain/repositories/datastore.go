@@ -0,0 +1,23 @@
+package repositories
+
+// DataStore provides transactional access to repository handles, so a
+// caller can group several writes (e.g. save-new-name + delete-old-name for
+// a rename) into a single atomic unit instead of risking a half-applied
+// state if the process crashes partway through.
+type DataStore interface {
+	// WithTransaction runs fn with a DataStore scoped to a single
+	// transaction. fn's writes only become visible to other callers once
+	// WithTransaction returns nil; an error from fn, or a crash before the
+	// transaction finishes committing, leaves the outer DataStore
+	// unchanged.
+	WithTransaction(fn func(tx DataStore) error) error
+
+	// Playlists returns the playlist repository handle scoped to this
+	// DataStore (or to the transaction, if called on the tx passed into
+	// WithTransaction's fn).
+	Playlists() PlaylistRepositoryInterface
+
+	// PlayHistory returns the play-history repository handle scoped to
+	// this DataStore.
+	PlayHistory() PlayHistoryRepositoryInterface
+}
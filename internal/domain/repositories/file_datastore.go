@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"path/filepath"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// txState accumulates a transaction's staged file writes, so repository
+// reads within the same transaction see pending changes before they land on
+// disk, and journal.commit can apply (and durably record) them as one unit.
+type txState struct {
+	ops     []journalOp
+	overlay map[string][]byte // path -> pending content; nil means pending delete
+}
+
+func newTxState() *txState {
+	return &txState{overlay: make(map[string][]byte)}
+}
+
+func (tx *txState) stageWrite(path string, data []byte) {
+	tx.ops = append(tx.ops, journalOp{Path: path, Data: data})
+	tx.overlay[path] = data
+}
+
+func (tx *txState) stageDelete(path string) {
+	tx.ops = append(tx.ops, journalOp{Path: path, Delete: true})
+	tx.overlay[path] = nil
+}
+
+// FileDataStore is the file-based DataStore implementation. WithTransaction
+// stages a callback's writes in memory and, once it returns successfully,
+// hands them to a journal (see journal.go) that fsyncs them as one batch
+// before applying them to baseDir/.journal - so a crash mid-transaction
+// finishes on the next startup (NewFileDataStore calls journal.replay)
+// instead of leaving playlists half-renamed or half-merged.
+type FileDataStore struct {
+	playlists   *PlaylistRepository
+	playHistory PlayHistoryRepositoryInterface
+	journal     *journal
+}
+
+// NewFileDataStore creates a file-based DataStore wrapping the given
+// playlist and play-history repositories (typically the same instances
+// PlaylistService already uses, so both see the same on-disk state),
+// replaying any journal entries left behind by a previous crash before
+// returning. playHistory only participates in a transaction's atomic
+// commit when it's a *PlayHistoryRepository; any other implementation is
+// used as-is (its writes land immediately, same as outside a transaction).
+func NewFileDataStore(playlists *PlaylistRepository, playHistory PlayHistoryRepositoryInterface) (*FileDataStore, error) {
+	j := newJournal(filepath.Join(playlists.baseDir, ".journal"))
+	if err := j.replay(); err != nil {
+		return nil, err
+	}
+
+	return &FileDataStore{
+		playlists:   playlists,
+		playHistory: playHistory,
+		journal:     j,
+	}, nil
+}
+
+// WithTransaction stages fn's playlist/play-history writes in memory, then
+// commits them as a single fsync'd, crash-recoverable batch if fn returns
+// nil. fn's writes are invisible outside the transaction (and simply
+// discarded) if it returns an error.
+func (ds *FileDataStore) WithTransaction(fn func(tx DataStore) error) error {
+	tx := newTxState()
+
+	playHistory := ds.playHistory
+	if ph, ok := ds.playHistory.(*PlayHistoryRepository); ok {
+		playHistory = ph.scopedTo(tx)
+	}
+
+	txStore := &FileDataStore{
+		playlists:   ds.playlists.scopedTo(tx),
+		playHistory: playHistory,
+		journal:     ds.journal,
+	}
+
+	if err := fn(txStore); err != nil {
+		return err
+	}
+
+	return ds.journal.commit(tx.ops)
+}
+
+// Playlists returns the guildID-agnostic file-based playlist repository,
+// adapted to PlaylistRepositoryInterface (file storage predates per-guild
+// playlists and still ignores guildID, same as services.fileRepoAdapter).
+func (ds *FileDataStore) Playlists() PlaylistRepositoryInterface {
+	return &filePlaylistHandle{repo: ds.playlists}
+}
+
+// PlayHistory returns the file-based play-history repository
+func (ds *FileDataStore) PlayHistory() PlayHistoryRepositoryInterface {
+	return ds.playHistory
+}
+
+// filePlaylistHandle adapts PlaylistRepository (no guildID) to
+// PlaylistRepositoryInterface, mirroring services.fileRepoAdapter
+type filePlaylistHandle struct {
+	repo *PlaylistRepository
+}
+
+func (h *filePlaylistHandle) List(guildID string) ([]string, error) {
+	return h.repo.List()
+}
+
+func (h *filePlaylistHandle) Load(guildID, name string) (*entities.Playlist, error) {
+	return h.repo.Load(name)
+}
+
+func (h *filePlaylistHandle) Save(guildID string, playlist *entities.Playlist) error {
+	return h.repo.Save(playlist)
+}
+
+func (h *filePlaylistHandle) Delete(guildID, name string) error {
+	return h.repo.Delete(name)
+}
+
+func (h *filePlaylistHandle) Exists(guildID, name string) bool {
+	return h.repo.Exists(name)
+}
+
+func (h *filePlaylistHandle) SaveExternalInfo(guildID, name string, info *entities.ExternalInfo) error {
+	return h.repo.SaveExternalInfo(name, info)
+}
+
+func (h *filePlaylistHandle) GetExternalInfo(guildID, name string) (*entities.ExternalInfo, error) {
+	return h.repo.GetExternalInfo(name)
+}
+
+func (h *filePlaylistHandle) SaveSmart(guildID string, playlist *entities.SmartPlaylist) error {
+	return h.repo.SaveSmart(playlist)
+}
+
+func (h *filePlaylistHandle) LoadSmart(guildID, name string) (*entities.SmartPlaylist, error) {
+	return h.repo.LoadSmart(name)
+}
+
+func (h *filePlaylistHandle) IsSmart(guildID, name string) (bool, error) {
+	return h.repo.IsSmart(name)
+}
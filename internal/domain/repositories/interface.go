@@ -1,6 +1,9 @@
 package repositories
 
-import "github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+import (
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+)
 
 // PlaylistRepositoryInterface defines the contract for playlist storage
 type PlaylistRepositoryInterface interface {
@@ -18,4 +21,87 @@ type PlaylistRepositoryInterface interface {
 
 	// Exists checks if a playlist exists for a guild
 	Exists(guildID, name string) bool
+
+	// SaveExternalInfo records (or clears, when info is nil) the remote source
+	// a playlist was imported from so it can be periodically re-synced
+	SaveExternalInfo(guildID, name string, info *entities.ExternalInfo) error
+
+	// GetExternalInfo returns the remote source info for a playlist, or nil
+	// if the playlist was not imported from an external source
+	GetExternalInfo(guildID, name string) (*entities.ExternalInfo, error)
+
+	// SaveSmart saves a smart playlist for a guild. Smart playlists are
+	// stored alongside regular ones under the same name, discriminated by
+	// a "type" field in the persisted JSON - see IsSmart.
+	SaveSmart(guildID string, playlist *entities.SmartPlaylist) error
+
+	// LoadSmart loads a smart playlist by name for a guild, or nil if it
+	// doesn't exist
+	LoadSmart(guildID, name string) (*entities.SmartPlaylist, error)
+
+	// IsSmart reports whether name is a smart playlist, so callers that
+	// only have a name can pick the right Load method
+	IsSmart(guildID, name string) (bool, error)
+}
+
+// PlayHistoryRepositoryInterface defines the contract for tracking per-guild
+// play counts, used to evaluate a smart playlist's play_count criteria
+type PlayHistoryRepositoryInterface interface {
+	// RecordPlay increments originalInput's play count for guildID and
+	// updates its last-played timestamp
+	RecordPlay(guildID, originalInput string) error
+
+	// PlayCount returns how many times originalInput has been played in
+	// guildID, or 0 if it has never been recorded
+	PlayCount(guildID, originalInput string) (int, error)
+}
+
+// StateRepositoryInterface defines the contract for persisting per-guild
+// playback state across restarts, so the bot can rejoin voice channels and
+// resume queues on boot instead of starting cold
+type StateRepositoryInterface interface {
+	// Save writes (or overwrites) the snapshot for state.GuildID
+	Save(state *entities.GuildState) error
+
+	// Load returns the saved snapshot for guildID, or nil if none exists
+	Load(guildID string) (*entities.GuildState, error)
+
+	// Delete removes any saved snapshot for guildID. Called once a guild's
+	// session ends normally so a stale queue isn't resumed next boot.
+	Delete(guildID string) error
+
+	// ListGuildIDs returns the guild IDs with a saved snapshot, for startup
+	// reconciliation.
+	ListGuildIDs() ([]string, error)
+}
+
+// ScrobbleCredentialRepositoryInterface defines the contract for storing the
+// scrobbling accounts a user has linked
+type ScrobbleCredentialRepositoryInterface interface {
+	// Link stores (or replaces) a user's credential for a service
+	Link(userID string, service valueobjects.ScrobbleService, token string) error
+
+	// Unlink removes a user's credential for a service
+	Unlink(userID string, service valueobjects.ScrobbleService) error
+
+	// Get returns a user's credential for a service, or nil if not linked
+	Get(userID string, service valueobjects.ScrobbleService) (*entities.ScrobbleCredential, error)
+
+	// ListForUser returns every credential a user has linked
+	ListForUser(userID string) ([]*entities.ScrobbleCredential, error)
+}
+
+// SpotifyCredentialRepositoryInterface defines the contract for storing the
+// per-Discord-user Spotify OAuth tokens behind /spotify connect and /spotify
+// export
+type SpotifyCredentialRepositoryInterface interface {
+	// Save stores (or replaces) userID's credential
+	Save(cred *entities.SpotifyCredential) error
+
+	// Get returns userID's credential, or nil if they haven't connected
+	// their Spotify account
+	Get(userID string) (*entities.SpotifyCredential, error)
+
+	// Delete removes userID's credential
+	Delete(userID string) error
 }
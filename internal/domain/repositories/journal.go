@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// journalOp is one staged file write or delete within a transaction
+type journalOp struct {
+	Path   string `json:"path"`
+	Delete bool   `json:"delete,omitempty"`
+	Data   []byte `json:"data,omitempty"`
+}
+
+// journal is a write-ahead log directory for atomic multi-file writes: a
+// transaction's ops are appended to a single file under dir, fsync'd, then
+// applied to their real paths, then the file is removed. If the process
+// crashes between the fsync and the removal, replay (called once at
+// startup) finds the leftover file and finishes applying it, so a
+// transaction is either fully applied or not applied at all - never half
+// done.
+type journal struct {
+	dir string
+}
+
+func newJournal(dir string) *journal {
+	return &journal{dir: dir}
+}
+
+// commit durably records ops, applies them, then clears the record. Ops are
+// applied in order; once fsync'd below, a crash mid-apply is recovered by
+// replay re-running the same ops (each op is idempotent: writing a file or
+// renaming it to ".deleted" again is harmless).
+func (j *journal) commit(ops []journalOp) error {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(j.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	path := filepath.Join(j.dir, fmt.Sprintf("%d.json", time.Now().UnixNano()))
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to fsync journal entry: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close journal entry: %w", err)
+	}
+
+	if err := applyOps(ops); err != nil {
+		return fmt.Errorf("failed to apply journaled writes: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to clear journal entry: %w", err)
+	}
+	return nil
+}
+
+// replay finishes applying any journal entries left behind by a crash
+// during a previous commit. Safe to call on every startup, including when
+// the journal directory is empty or doesn't exist yet.
+func (j *journal) replay() error {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(j.dir, entry.Name())
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read leftover journal entry %s: %w", entry.Name(), err)
+		}
+
+		var ops []journalOp
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return fmt.Errorf("failed to parse leftover journal entry %s: %w", entry.Name(), err)
+		}
+
+		if err := applyOps(ops); err != nil {
+			return fmt.Errorf("failed to replay journal entry %s: %w", entry.Name(), err)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to clear replayed journal entry %s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// applyOps performs each op against its real path. A delete op mirrors
+// PlaylistRepository.Delete's soft-delete convention (rename to
+// ".deleted") rather than removing the file outright.
+func applyOps(ops []journalOp) error {
+	for _, op := range ops {
+		if op.Delete {
+			if err := os.Rename(op.Path, op.Path+".deleted"); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(op.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(op.Path, op.Data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// PlayHistoryRepository persists per-guild play counts as one JSON file per
+// guild, named by guild ID, mirroring StateRepository's layout. Backs smart
+// playlists' play_count criteria. Outside a transaction it writes straight
+// to disk, same as before; inside one (see FileDataStore.WithTransaction)
+// its writes are staged in tx and only land on disk when the transaction
+// commits.
+type PlayHistoryRepository struct {
+	baseDir string
+	mu      sync.Mutex
+	tx      *txState
+}
+
+// NewPlayHistoryRepository creates a new play-history repository
+func NewPlayHistoryRepository(baseDir string) *PlayHistoryRepository {
+	return &PlayHistoryRepository{baseDir: baseDir}
+}
+
+// scopedTo returns a copy of r staged against tx, used by
+// FileDataStore.WithTransaction to give the callback a transaction-scoped
+// handle without affecting r itself.
+func (r *PlayHistoryRepository) scopedTo(tx *txState) *PlayHistoryRepository {
+	return &PlayHistoryRepository{baseDir: r.baseDir, tx: tx}
+}
+
+func (r *PlayHistoryRepository) getPath(guildID string) string {
+	return filepath.Join(r.baseDir, guildID+".json")
+}
+
+// RecordPlay increments originalInput's play count for guildID and updates
+// its last-played timestamp
+func (r *PlayHistoryRepository) RecordPlay(guildID, originalInput string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records, err := r.load(guildID)
+	if err != nil {
+		return err
+	}
+
+	record, ok := records[originalInput]
+	if !ok {
+		record = entities.PlayRecord{OriginalInput: originalInput}
+	}
+	record.PlayCount++
+	record.LastPlayedAt = entities.FlexTime{Time: time.Now()}
+	records[originalInput] = record
+
+	return r.save(guildID, records)
+}
+
+// PlayCount returns how many times originalInput has been played in
+// guildID, or 0 if it has never been recorded
+func (r *PlayHistoryRepository) PlayCount(guildID, originalInput string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records, err := r.load(guildID)
+	if err != nil {
+		return 0, err
+	}
+	return records[originalInput].PlayCount, nil
+}
+
+func (r *PlayHistoryRepository) load(guildID string) (map[string]entities.PlayRecord, error) {
+	path := r.getPath(guildID)
+
+	var data []byte
+	var err error
+	if r.tx != nil {
+		if staged, ok := r.tx.overlay[path]; ok {
+			data, err = staged, nil
+			if staged == nil {
+				err = os.ErrNotExist
+			}
+		} else {
+			data, err = os.ReadFile(path)
+		}
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]entities.PlayRecord), nil
+		}
+		return nil, fmt.Errorf("failed to read play history file: %w", err)
+	}
+
+	records := make(map[string]entities.PlayRecord)
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse play history JSON: %w", err)
+	}
+	return records, nil
+}
+
+func (r *PlayHistoryRepository) save(guildID string, records map[string]entities.PlayRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal play history: %w", err)
+	}
+
+	path := r.getPath(guildID)
+
+	if r.tx != nil {
+		r.tx.stageWrite(path, data)
+		return nil
+	}
+
+	if err := os.MkdirAll(r.baseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create play history directory: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write play history file: %w", err)
+	}
+	return os.Rename(tempPath, path)
+}
@@ -10,9 +10,13 @@ import (
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
 )
 
-// PlaylistRepository handles persistence of playlists
+// PlaylistRepository handles persistence of playlists. Outside a
+// transaction it writes straight to disk, same as before; inside one
+// (see FileDataStore.WithTransaction) its writes are staged in tx and only
+// land on disk when the transaction commits.
 type PlaylistRepository struct {
 	baseDir string
+	tx      *txState
 }
 
 // NewPlaylistRepository creates a new playlist repository
@@ -22,35 +26,108 @@ func NewPlaylistRepository(baseDir string) *PlaylistRepository {
 	}
 }
 
+// scopedTo returns a copy of r staged against tx, used by
+// FileDataStore.WithTransaction to give the callback a transaction-scoped
+// handle without affecting r itself.
+func (r *PlaylistRepository) scopedTo(tx *txState) *PlaylistRepository {
+	return &PlaylistRepository{baseDir: r.baseDir, tx: tx}
+}
+
+// readFile reads path, preferring a pending write/delete staged by an
+// in-flight transaction over what's actually on disk yet
+func (r *PlaylistRepository) readFile(path string) ([]byte, error) {
+	if r.tx != nil {
+		if data, staged := r.tx.overlay[path]; staged {
+			if data == nil {
+				return nil, os.ErrNotExist
+			}
+			return data, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// statFile reports whether path exists, accounting for a pending
+// write/delete staged by an in-flight transaction
+func (r *PlaylistRepository) statFile(path string) bool {
+	if r.tx != nil {
+		if data, staged := r.tx.overlay[path]; staged {
+			return data != nil
+		}
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// writeFile stages path's write within an in-flight transaction, or applies
+// it immediately outside of one
+func (r *PlaylistRepository) writeFile(path string, data []byte) error {
+	if r.tx != nil {
+		r.tx.stageWrite(path, data)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// deleteFile stages path's (soft) delete within an in-flight transaction, or
+// applies it immediately outside of one
+func (r *PlaylistRepository) deleteFile(path string) error {
+	if r.tx != nil {
+		r.tx.stageDelete(path)
+		return nil
+	}
+
+	return os.Rename(path, path+".deleted")
+}
+
 // List returns all playlist names
 func (r *PlaylistRepository) List() ([]string, error) {
 	entries, err := os.ReadDir(r.baseDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to read playlist directory: %w", err)
 	}
 
-	var names []string
+	names := make(map[string]bool)
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 		name := entry.Name()
 		if strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".backup") && !strings.HasSuffix(name, ".deleted") {
-			names = append(names, strings.TrimSuffix(name, ".json"))
+			names[strings.TrimSuffix(name, ".json")] = true
+		}
+	}
+
+	if r.tx != nil {
+		for path, data := range r.tx.overlay {
+			if filepath.Dir(path) != r.baseDir || !strings.HasSuffix(path, ".json") {
+				continue
+			}
+			name := strings.TrimSuffix(filepath.Base(path), ".json")
+			if data == nil {
+				delete(names, name)
+			} else {
+				names[name] = true
+			}
 		}
 	}
 
-	return names, nil
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	return result, nil
 }
 
 // Load loads a playlist from disk
 func (r *PlaylistRepository) Load(name string) (*entities.Playlist, error) {
 	path := r.getPath(name)
 
-	data, err := os.ReadFile(path)
+	data, err := r.readFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // Playlist doesn't exist
@@ -63,23 +140,45 @@ func (r *PlaylistRepository) Load(name string) (*entities.Playlist, error) {
 		return nil, fmt.Errorf("failed to parse playlist JSON: %w", err)
 	}
 
+	if r.backfillResolvedIDs(&playlist) {
+		if err := r.Save(&playlist); err != nil {
+			return nil, fmt.Errorf("failed to save backfilled playlist: %w", err)
+		}
+	}
+
 	return &playlist, nil
 }
 
-// Save saves a playlist to disk
-func (r *PlaylistRepository) Save(playlist *entities.Playlist) error {
-	// Ensure directory exists
-	if err := os.MkdirAll(r.baseDir, 0755); err != nil {
-		return fmt.Errorf("failed to create playlist directory: %w", err)
+// backfillResolvedIDs sets ResolvedID on any entry saved before that field
+// existed, reporting whether it changed anything so Load knows whether to
+// persist the backfill. One-time per playlist: once every entry has a
+// ResolvedID (or an input no resolver recognizes), this is a no-op.
+func (r *PlaylistRepository) backfillResolvedIDs(playlist *entities.Playlist) bool {
+	changed := false
+	for _, entry := range playlist.Entries {
+		if entry.ResolvedID != "" {
+			continue
+		}
+		if id := resolveMediaID(entry.OriginalInput); id != "" {
+			entry.ResolvedID = id
+			changed = true
+		}
 	}
+	return changed
+}
 
+// Save saves a playlist to disk
+func (r *PlaylistRepository) Save(playlist *entities.Playlist) error {
 	path := r.getPath(playlist.Name)
 
-	// Create backup if file exists
-	if _, err := os.Stat(path); err == nil {
-		backupPath := path + ".backup"
-		if data, err := os.ReadFile(path); err == nil {
-			os.WriteFile(backupPath, data, 0644)
+	// Create backup if file exists (skipped inside a transaction - the
+	// journal itself is the recovery path there)
+	if r.tx == nil {
+		if _, err := os.Stat(path); err == nil {
+			backupPath := path + ".backup"
+			if data, err := os.ReadFile(path); err == nil {
+				os.WriteFile(backupPath, data, 0644)
+			}
 		}
 	}
 
@@ -88,7 +187,7 @@ func (r *PlaylistRepository) Save(playlist *entities.Playlist) error {
 		return fmt.Errorf("failed to marshal playlist: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := r.writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write playlist file: %w", err)
 	}
 
@@ -98,12 +197,12 @@ func (r *PlaylistRepository) Save(playlist *entities.Playlist) error {
 // Delete deletes a playlist (moves to .deleted)
 func (r *PlaylistRepository) Delete(name string) error {
 	path := r.getPath(name)
-	deletedPath := path + ".deleted"
 
-	if err := os.Rename(path, deletedPath); err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("playlist '%s' not found", name)
-		}
+	if !r.statFile(path) {
+		return fmt.Errorf("playlist '%s' not found", name)
+	}
+
+	if err := r.deleteFile(path); err != nil {
 		return fmt.Errorf("failed to delete playlist: %w", err)
 	}
 
@@ -112,9 +211,35 @@ func (r *PlaylistRepository) Delete(name string) error {
 
 // Exists checks if a playlist exists
 func (r *PlaylistRepository) Exists(name string) bool {
-	path := r.getPath(name)
-	_, err := os.Stat(path)
-	return err == nil
+	return r.statFile(r.getPath(name))
+}
+
+// SaveExternalInfo records the remote source a playlist was imported from
+func (r *PlaylistRepository) SaveExternalInfo(name string, info *entities.ExternalInfo) error {
+	playlist, err := r.Load(name)
+	if err != nil {
+		return err
+	}
+	if playlist == nil {
+		return fmt.Errorf("playlist '%s' not found", name)
+	}
+
+	playlist.ExternalInfo = info
+	return r.Save(playlist)
+}
+
+// GetExternalInfo returns the remote source info for a playlist, or nil if
+// the playlist isn't externally sourced
+func (r *PlaylistRepository) GetExternalInfo(name string) (*entities.ExternalInfo, error) {
+	playlist, err := r.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("playlist '%s' not found", name)
+	}
+
+	return playlist.ExternalInfo, nil
 }
 
 // getPath returns the file path for a playlist
@@ -123,3 +248,73 @@ func (r *PlaylistRepository) getPath(name string) string {
 	safeName := strings.ReplaceAll(name, " ", "_")
 	return filepath.Join(r.baseDir, safeName+".json")
 }
+
+// smartPlaylistType is the "type" discriminator a smart playlist's JSON
+// carries, so it can share a directory with regular Playlist files (which
+// have no such field and default to the empty, regular value)
+const smartPlaylistType = "smart"
+
+// smartPlaylistEnvelope wraps a SmartPlaylist with its "type" discriminator
+// for persistence; entities.SmartPlaylist itself has no Type field since
+// that's a storage concern, not a domain one.
+type smartPlaylistEnvelope struct {
+	Type string `json:"type"`
+	entities.SmartPlaylist
+}
+
+// SaveSmart saves a smart playlist to disk
+func (r *PlaylistRepository) SaveSmart(playlist *entities.SmartPlaylist) error {
+	envelope := smartPlaylistEnvelope{Type: smartPlaylistType, SmartPlaylist: *playlist}
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal smart playlist: %w", err)
+	}
+
+	if err := r.writeFile(r.getPath(playlist.Name), data); err != nil {
+		return fmt.Errorf("failed to write smart playlist file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSmart loads a smart playlist from disk, or nil if name doesn't exist
+// or isn't a smart playlist
+func (r *PlaylistRepository) LoadSmart(name string) (*entities.SmartPlaylist, error) {
+	data, err := r.readFile(r.getPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read playlist file: %w", err)
+	}
+
+	var envelope smartPlaylistEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse smart playlist JSON: %w", err)
+	}
+	if envelope.Type != smartPlaylistType {
+		return nil, nil
+	}
+
+	return &envelope.SmartPlaylist, nil
+}
+
+// IsSmart reports whether name is a smart playlist
+func (r *PlaylistRepository) IsSmart(name string) (bool, error) {
+	data, err := r.readFile(r.getPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read playlist file: %w", err)
+	}
+
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return false, fmt.Errorf("failed to parse playlist JSON: %w", err)
+	}
+
+	return envelope.Type == smartPlaylistType, nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"regexp"
+	"strings"
+)
+
+// resolveMediaID computes the same cross-platform ResolvedID a
+// services.MediaIDResolver would for originalInput (e.g. "yt:VIDEOID"),
+// duplicated here rather than imported so the domain/repositories layer
+// doesn't depend on internal/services. Returns "" for inputs it doesn't
+// recognize. Used by PlaylistRepository.Load to backfill ResolvedID on
+// entries saved before this field existed.
+func resolveMediaID(originalInput string) string {
+	if match := youtubeIDPattern.FindStringSubmatch(originalInput); match != nil {
+		return "yt:" + match[1]
+	}
+	if match := spotifyTrackIDPattern.FindStringSubmatch(originalInput); match != nil {
+		return "sp:" + match[1]
+	}
+	if strings.Contains(originalInput, "soundcloud.com/") {
+		return "sc:" + normalizeSoundCloudURL(originalInput)
+	}
+	return ""
+}
+
+var (
+	youtubeIDPattern      = regexp.MustCompile(`(?:v=|youtu\.be/|/embed/|/shorts/)([A-Za-z0-9_-]{6,})`)
+	spotifyTrackIDPattern = regexp.MustCompile(`spotify\.com/track/([a-zA-Z0-9]+)`)
+)
+
+// normalizeSoundCloudURL strips the scheme, query string, and trailing
+// slash from a SoundCloud track URL, collapsing the http/https and
+// www./non-www variants of the same path into the same ResolvedID.
+func normalizeSoundCloudURL(url string) string {
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	url = strings.TrimPrefix(url, "www.")
+	if i := strings.IndexAny(url, "?#"); i != -1 {
+		url = url[:i]
+	}
+	return strings.TrimSuffix(url, "/")
+}
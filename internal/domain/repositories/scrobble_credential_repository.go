@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+)
+
+// ScrobbleCredentialRepository handles persistence of per-user scrobbling
+// credentials to a single JSON file, keyed by "<userID>:<service>"
+type ScrobbleCredentialRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewScrobbleCredentialRepository creates a new file-based credential repository
+func NewScrobbleCredentialRepository(baseDir string) *ScrobbleCredentialRepository {
+	return &ScrobbleCredentialRepository{
+		path: filepath.Join(baseDir, "scrobble_credentials.json"),
+	}
+}
+
+func credentialKey(userID string, service valueobjects.ScrobbleService) string {
+	return userID + ":" + service.String()
+}
+
+// Link stores (or replaces) a user's credential for a service
+func (r *ScrobbleCredentialRepository) Link(userID string, service valueobjects.ScrobbleService, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return err
+	}
+
+	all[credentialKey(userID, service)] = entities.NewScrobbleCredential(userID, service, token)
+	return r.saveAll(all)
+}
+
+// Unlink removes a user's credential for a service
+func (r *ScrobbleCredentialRepository) Unlink(userID string, service valueobjects.ScrobbleService) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return err
+	}
+
+	delete(all, credentialKey(userID, service))
+	return r.saveAll(all)
+}
+
+// Get returns a user's credential for a service, or nil if not linked
+func (r *ScrobbleCredentialRepository) Get(userID string, service valueobjects.ScrobbleService) (*entities.ScrobbleCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return all[credentialKey(userID, service)], nil
+}
+
+// ListForUser returns every credential a user has linked
+func (r *ScrobbleCredentialRepository) ListForUser(userID string) ([]*entities.ScrobbleCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var creds []*entities.ScrobbleCredential
+	for _, c := range all {
+		if c.UserID == userID {
+			creds = append(creds, c)
+		}
+	}
+	return creds, nil
+}
+
+// loadAll reads the credential store from disk
+func (r *ScrobbleCredentialRepository) loadAll() (map[string]*entities.ScrobbleCredential, error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]*entities.ScrobbleCredential), nil
+		}
+		return nil, fmt.Errorf("failed to read scrobble credentials: %w", err)
+	}
+
+	all := make(map[string]*entities.ScrobbleCredential)
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to decode scrobble credentials: %w", err)
+	}
+	return all, nil
+}
+
+// saveAll writes the credential store to disk atomically
+func (r *ScrobbleCredentialRepository) saveAll(all map[string]*entities.ScrobbleCredential) error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scrobble credentials: %w", err)
+	}
+
+	tempPath := r.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write scrobble credentials: %w", err)
+	}
+
+	if err := os.Rename(tempPath, r.path); err != nil {
+		return fmt.Errorf("failed to rename scrobble credentials file: %w", err)
+	}
+	return nil
+}
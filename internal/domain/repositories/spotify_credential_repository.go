@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// unixTime converts a stored Unix timestamp back into a time.Time, treating
+// 0 (unset) as the zero time rather than the Unix epoch.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// SpotifyCredentialRepository persists per-Discord-user Spotify OAuth tokens
+// to a single JSON file, keyed by Discord user ID. AccessToken/RefreshToken
+// are AES-GCM encrypted at rest (unlike ScrobbleCredentialRepository's plain
+// token, a stolen Spotify refresh token gives standing write access to a
+// user's account) using a key derived from the bot's Spotify client secret,
+// so no extra secret needs provisioning just for this.
+type SpotifyCredentialRepository struct {
+	path string
+	gcm  cipher.AEAD
+	mu   sync.Mutex
+}
+
+// storedCredential is SpotifyCredential with its tokens AES-GCM sealed
+// rather than stored in the clear.
+type storedCredential struct {
+	UserID           string `json:"user_id"`
+	EncryptedAccess  []byte `json:"encrypted_access"`
+	EncryptedRefresh []byte `json:"encrypted_refresh"`
+	ExpiresAtUnix    int64  `json:"expires_at_unix"`
+	LinkedAtUnix     int64  `json:"linked_at_unix"`
+}
+
+// NewSpotifyCredentialRepository creates a new file-based credential
+// repository. clientSecret seeds the AES-256 key (via SHA-256) tokens are
+// encrypted under - rotating SPOTIFY_CLIENT_SECRET invalidates every
+// previously linked account, same as it would with Spotify directly.
+func NewSpotifyCredentialRepository(baseDir, clientSecret string) (*SpotifyCredentialRepository, error) {
+	key := sha256.Sum256([]byte(clientSecret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to init spotify credential cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init spotify credential cipher: %w", err)
+	}
+
+	return &SpotifyCredentialRepository{
+		path: filepath.Join(baseDir, "spotify_credentials.json"),
+		gcm:  gcm,
+	}, nil
+}
+
+// Save stores (or replaces) userID's credential
+func (r *SpotifyCredentialRepository) Save(cred *entities.SpotifyCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return err
+	}
+
+	encryptedAccess, err := r.encrypt(cred.AccessToken)
+	if err != nil {
+		return err
+	}
+	encryptedRefresh, err := r.encrypt(cred.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	all[cred.UserID] = storedCredential{
+		UserID:           cred.UserID,
+		EncryptedAccess:  encryptedAccess,
+		EncryptedRefresh: encryptedRefresh,
+		ExpiresAtUnix:    cred.ExpiresAt.Unix(),
+		LinkedAtUnix:     cred.LinkedAt.Unix(),
+	}
+	return r.saveAll(all)
+}
+
+// Get returns userID's credential, or nil if they haven't connected their
+// Spotify account
+func (r *SpotifyCredentialRepository) Get(userID string) (*entities.SpotifyCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	stored, ok := all[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	accessToken, err := r.decrypt(stored.EncryptedAccess)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := r.decrypt(stored.EncryptedRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	cred := entities.NewSpotifyCredential(stored.UserID, accessToken, refreshToken, unixTime(stored.ExpiresAtUnix))
+	cred.LinkedAt = unixTime(stored.LinkedAtUnix)
+	return cred, nil
+}
+
+// Delete removes userID's credential
+func (r *SpotifyCredentialRepository) Delete(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all, err := r.loadAll()
+	if err != nil {
+		return err
+	}
+
+	delete(all, userID)
+	return r.saveAll(all)
+}
+
+func (r *SpotifyCredentialRepository) encrypt(plaintext string) ([]byte, error) {
+	nonce := make([]byte, r.gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return r.gcm.Seal(nonce, nonce, []byte(plaintext), nil), nil
+}
+
+func (r *SpotifyCredentialRepository) decrypt(ciphertext []byte) (string, error) {
+	nonceSize := r.gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return "", fmt.Errorf("malformed spotify credential ciphertext")
+	}
+
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := r.gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt spotify credential: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func (r *SpotifyCredentialRepository) loadAll() (map[string]storedCredential, error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]storedCredential), nil
+		}
+		return nil, fmt.Errorf("failed to read spotify credentials: %w", err)
+	}
+
+	all := make(map[string]storedCredential)
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to decode spotify credentials: %w", err)
+	}
+	return all, nil
+}
+
+func (r *SpotifyCredentialRepository) saveAll(all map[string]storedCredential) error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode spotify credentials: %w", err)
+	}
+
+	tempPath := r.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write spotify credentials: %w", err)
+	}
+	return os.Rename(tempPath, r.path)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// StateRepository persists per-guild playback state as one JSON file per
+// guild, named by guild ID
+type StateRepository struct {
+	baseDir string
+}
+
+// NewStateRepository creates a new state repository
+func NewStateRepository(baseDir string) *StateRepository {
+	return &StateRepository{
+		baseDir: baseDir,
+	}
+}
+
+// Save writes (or overwrites) the snapshot for state.GuildID
+func (r *StateRepository) Save(state *entities.GuildState) error {
+	if err := os.MkdirAll(r.baseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal guild state: %w", err)
+	}
+
+	// Atomic write so a crash mid-write can't leave a truncated file that
+	// fails to parse on the next restart
+	path := r.getPath(state.GuildID)
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write guild state file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to finalize guild state file: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the saved snapshot for guildID, or nil if none exists
+func (r *StateRepository) Load(guildID string) (*entities.GuildState, error) {
+	data, err := os.ReadFile(r.getPath(guildID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read guild state file: %w", err)
+	}
+
+	var state entities.GuildState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse guild state JSON: %w", err)
+	}
+
+	return &state, nil
+}
+
+// Delete removes any saved snapshot for guildID
+func (r *StateRepository) Delete(guildID string) error {
+	if err := os.Remove(r.getPath(guildID)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete guild state file: %w", err)
+	}
+	return nil
+}
+
+// ListGuildIDs returns the guild IDs with a saved snapshot
+func (r *StateRepository) ListGuildIDs() ([]string, error) {
+	entries, err := os.ReadDir(r.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read state directory: %w", err)
+	}
+
+	var guildIDs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if strings.HasSuffix(name, ".json") {
+			guildIDs = append(guildIDs, strings.TrimSuffix(name, ".json"))
+		}
+	}
+
+	return guildIDs, nil
+}
+
+// getPath returns the file path for a guild's state
+func (r *StateRepository) getPath(guildID string) string {
+	return filepath.Join(r.baseDir, guildID+".json")
+}
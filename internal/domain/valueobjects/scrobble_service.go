@@ -0,0 +1,26 @@
+package valueobjects
+
+import "fmt"
+
+// ScrobbleService identifies which scrobbling provider a credential belongs to
+type ScrobbleService string
+
+const (
+	ScrobbleServiceListenBrainz ScrobbleService = "listenbrainz"
+	ScrobbleServiceLastFM       ScrobbleService = "lastfm"
+)
+
+// String returns the string representation
+func (s ScrobbleService) String() string {
+	return string(s)
+}
+
+// ParseScrobbleService validates a raw service name from user input
+func ParseScrobbleService(raw string) (ScrobbleService, error) {
+	switch ScrobbleService(raw) {
+	case ScrobbleServiceListenBrainz, ScrobbleServiceLastFM:
+		return ScrobbleService(raw), nil
+	default:
+		return "", fmt.Errorf("unknown scrobble service %q (expected %q or %q)", raw, ScrobbleServiceListenBrainz, ScrobbleServiceLastFM)
+	}
+}
@@ -1,6 +1,9 @@
 package valueobjects
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // SongMetadata contains metadata information about a song
 type SongMetadata struct {
@@ -9,6 +12,10 @@ type SongMetadata struct {
 	Duration  int    `json:"duration"` // seconds
 	Thumbnail string `json:"thumbnail,omitempty"`
 	Uploader  string `json:"uploader,omitempty"`
+	// StartOffset is the playback start position parsed from a "share at
+	// current time" URL (e.g. ?t=90s), honored when the stream is acquired
+	// and shown alongside the duration in the now-playing display
+	StartOffset time.Duration `json:"start_offset,omitempty"`
 }
 
 // DisplayName returns the best display name for the song
@@ -29,3 +36,16 @@ func (m *SongMetadata) DurationFormatted() string {
 	seconds := m.Duration % 60
 	return fmt.Sprintf("%02d:%02d", minutes, seconds)
 }
+
+// StartOffsetFormatted returns the start offset in MM:SS format, or "" if
+// none was set
+func (m *SongMetadata) StartOffsetFormatted() string {
+	if m.StartOffset <= 0 {
+		return ""
+	}
+
+	total := int(m.StartOffset.Seconds())
+	minutes := total / 60
+	seconds := total % 60
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
@@ -0,0 +1,21 @@
+package valueobjects
+
+// SourceKind distinguishes a single-track URL from a playlist URL, so
+// handlers can branch between adding one song and fanning out many
+type SourceKind string
+
+const (
+	SourceKindTrackURL    SourceKind = "track_url"
+	SourceKindPlaylistURL SourceKind = "playlist_url"
+	SourceKindSearch      SourceKind = "search"
+)
+
+// String returns the string representation
+func (k SourceKind) String() string {
+	return string(k)
+}
+
+// IsPlaylist reports whether this kind fans out into multiple tracks
+func (k SourceKind) IsPlaylist() bool {
+	return k == SourceKindPlaylistURL
+}
@@ -0,0 +1,47 @@
+// Package exec wraps external process execution behind an interface, so
+// code that shells out to yt-dlp/ffmpeg can be unit tested without either
+// binary installed by swapping in a fake CommandExecutor.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+)
+
+// DefaultTimeout bounds OSExecutor.RunWithTimeout when the caller's ctx
+// carries no deadline of its own, so a hung yt-dlp/ffmpeg process can't block
+// a caller forever.
+const DefaultTimeout = 60 * time.Second
+
+// CommandExecutor runs an external command to completion and returns its
+// captured stdout/stderr. Implemented by OSExecutor for real use and by
+// testutils.MockCommandExecutor in tests.
+type CommandExecutor interface {
+	// RunWithTimeout runs bin with args, waiting for it to exit. ctx bounds
+	// how long it may run; if ctx carries no deadline, implementations
+	// should apply a sensible default (see DefaultTimeout).
+	RunWithTimeout(ctx context.Context, bin string, args ...string) (stdout []byte, stderr []byte, err error)
+}
+
+// OSExecutor is the real CommandExecutor, spawning an actual OS process.
+type OSExecutor struct{}
+
+// RunWithTimeout implements CommandExecutor by running bin via os/exec.
+func (OSExecutor) RunWithTimeout(ctx context.Context, bin string, args ...string) ([]byte, []byte, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
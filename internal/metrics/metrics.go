@@ -0,0 +1,88 @@
+// Package metrics records guild/playback observability counters and gauges,
+// mirroring the optional stats feature in Spoticord. A Recorder is wired
+// into MusicBot.New behind cfg.MetricsEnabled / cfg.MetricsBackend; when
+// metrics are disabled, NoopRecorder is used so call sites never need a
+// nil check.
+package metrics
+
+import "time"
+
+// Recorder receives observability events from the hot paths of the bot.
+// All methods must be safe for concurrent use.
+type Recorder interface {
+	// IncActiveGuilds/DecActiveGuilds track how many guilds currently have
+	// playback state, incremented in PlaybackService.getOrCreateState and
+	// decremented in Cleanup
+	IncActiveGuilds()
+	DecActiveGuilds()
+
+	// IncTracksPlayed/IncTracksFailed count completed and failed tracks
+	IncTracksPlayed()
+	IncTracksFailed()
+
+	// ObservePlaybackLatency records the time from playNextSong entry to
+	// player.Play returning
+	ObservePlaybackLatency(d time.Duration)
+
+	// IncVoiceIdleDisconnects counts auto-disconnects from an empty channel
+	IncVoiceIdleDisconnects()
+
+	// SetQueueDepth reports the current ProcessingService queue size
+	SetQueueDepth(depth int)
+
+	// ObserveQueueLength samples the current ProcessingService queue size
+	// into a distribution, complementing SetQueueDepth's single
+	// current-value gauge with a view of how it's actually spread over time.
+	ObserveQueueLength(n int)
+
+	// ObserveCacheStats reports a named utils.Cache's hits/misses/evictions/
+	// size, pushed periodically from a Stats() scrape (see
+	// youtube.Service.CacheStats) rather than per-operation, since a cache
+	// has no metrics hook of its own to push live events through.
+	ObserveCacheStats(name string, hits, misses, evictions int64, size int)
+
+	// ObserveExtractionLatency and IncExtractionError record yt-dlp
+	// extraction timing and failures, split by urlType ("video", "playlist",
+	// "search", "stream_url")
+	ObserveExtractionLatency(urlType string, d time.Duration)
+	IncExtractionError(urlType string)
+
+	// IncEncodeFramesSent and ObserveEncodeStall track FFmpeg encode frame
+	// throughput and buffer-fill pressure, from encodeWithYtDlpPipe's frame
+	// loop: IncEncodeFramesSent counts every Opus frame handed off to a
+	// player, and ObserveEncodeStall records how long that hand-off blocked
+	// on a full frame channel.
+	IncEncodeFramesSent()
+	ObserveEncodeStall(d time.Duration)
+
+	// IncActiveVoiceConnections/DecActiveVoiceConnections track how many
+	// guilds currently hold an open voice connection, incremented in
+	// AudioService.connectLocal and decremented in disconnectLocal.
+	IncActiveVoiceConnections()
+	DecActiveVoiceConnections()
+
+	// Close releases any resources held by the recorder (listeners,
+	// connections). It's safe to call on a Recorder that was never started.
+	Close() error
+}
+
+// NoopRecorder discards every event. It's the default Recorder when metrics
+// are disabled in config.
+type NoopRecorder struct{}
+
+func (NoopRecorder) IncActiveGuilds()                                                       {}
+func (NoopRecorder) DecActiveGuilds()                                                       {}
+func (NoopRecorder) IncTracksPlayed()                                                       {}
+func (NoopRecorder) IncTracksFailed()                                                       {}
+func (NoopRecorder) ObservePlaybackLatency(d time.Duration)                                 {}
+func (NoopRecorder) IncVoiceIdleDisconnects()                                               {}
+func (NoopRecorder) SetQueueDepth(depth int)                                                {}
+func (NoopRecorder) ObserveQueueLength(n int)                                               {}
+func (NoopRecorder) ObserveCacheStats(name string, hits, misses, evictions int64, size int) {}
+func (NoopRecorder) ObserveExtractionLatency(urlType string, d time.Duration)               {}
+func (NoopRecorder) IncExtractionError(urlType string)                                      {}
+func (NoopRecorder) IncEncodeFramesSent()                                                   {}
+func (NoopRecorder) ObserveEncodeStall(d time.Duration)                                     {}
+func (NoopRecorder) IncActiveVoiceConnections()                                             {}
+func (NoopRecorder) DecActiveVoiceConnections()                                             {}
+func (NoopRecorder) Close() error                                                           { return nil }
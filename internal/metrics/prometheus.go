@@ -0,0 +1,303 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// latencyBucketBoundsMs are the histogram bucket upper bounds (milliseconds)
+// for the playback-latency histogram, roughly log-spaced from 10ms to 10s
+var latencyBucketBoundsMs = []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// encodeStallBucketBoundsMs are the histogram bucket upper bounds
+// (milliseconds) for the encode-stall histogram. Frame hand-off stalls are
+// expected to be small (well under a 20ms Opus frame) unless the consumer is
+// falling behind, so these are tighter than latencyBucketBoundsMs.
+var encodeStallBucketBoundsMs = []float64{1, 5, 10, 20, 50, 100, 250, 500}
+
+// queueLengthBucketBounds are the histogram bucket upper bounds for the
+// queue-length distribution.
+var queueLengthBucketBounds = []float64{0, 1, 2, 5, 10, 20, 50, 100}
+
+// histogramState is a simple bucketed histogram: counts[i] holds the number
+// of observations <= bounds[i], shared by every *_ms/_length histogram this
+// recorder exposes.
+type histogramState struct {
+	bounds       []float64
+	counts       []int64
+	sum          int64
+	observations int64
+}
+
+func newHistogramState(bounds []float64) *histogramState {
+	return &histogramState{bounds: bounds, counts: make([]int64, len(bounds))}
+}
+
+func (h *histogramState) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += int64(v)
+	h.observations++
+}
+
+// cacheStat is the last scrape of a named cache's Stats(), reported by
+// ObserveCacheStats.
+type cacheStat struct {
+	hits, misses, evictions int64
+	size                    int
+}
+
+// PrometheusRecorder exposes counters and gauges on a plain-text /metrics
+// endpoint in the Prometheus exposition format. It has no dependency on the
+// official client library - just stdlib net/http and the text format.
+type PrometheusRecorder struct {
+	activeGuilds           int64
+	tracksPlayed           int64
+	tracksFailed           int64
+	voiceIdleDisconnects   int64
+	queueDepth             int64
+	activeVoiceConnections int64
+	encodeFramesSent       int64
+	latencyBucketCounts    []int64
+	latencySum             int64 // milliseconds, for the histogram's _sum line
+	latencyObservations    int64
+
+	// cacheStats, extractionStats, extractionErrors, encodeStall, and
+	// queueLength are all guarded by mu, since they're keyed maps or shared
+	// bucket slices rather than single atomics.
+	cacheStats       map[string]cacheStat
+	extractionStats  map[string]*histogramState
+	extractionErrors map[string]int64
+	encodeStall      *histogramState
+	queueLength      *histogramState
+
+	server *http.Server
+	logger *logger.Logger
+	mu     sync.Mutex
+}
+
+// NewPrometheusRecorder creates a recorder and starts serving /metrics on
+// addr (e.g. ":9090") in the background. Call Close to shut the server down.
+func NewPrometheusRecorder(addr string, log *logger.Logger) (*PrometheusRecorder, error) {
+	r := &PrometheusRecorder{
+		latencyBucketCounts: make([]int64, len(latencyBucketBoundsMs)),
+		cacheStats:          make(map[string]cacheStat),
+		extractionStats:     make(map[string]*histogramState),
+		extractionErrors:    make(map[string]int64),
+		encodeStall:         newHistogramState(encodeStallBucketBoundsMs),
+		queueLength:         newHistogramState(queueLengthBucketBounds),
+		logger:              log,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", r.handleMetrics)
+	r.server = &http.Server{Addr: addr, Handler: mux}
+
+	listenErr := make(chan error, 1)
+	go func() {
+		log.WithField("addr", addr).Info("Serving Prometheus metrics")
+		if err := r.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			listenErr <- err
+		}
+	}()
+
+	select {
+	case err := <-listenErr:
+		return nil, fmt.Errorf("failed to start metrics server: %w", err)
+	case <-time.After(100 * time.Millisecond):
+		return r, nil
+	}
+}
+
+func (r *PrometheusRecorder) IncActiveGuilds() { atomic.AddInt64(&r.activeGuilds, 1) }
+func (r *PrometheusRecorder) DecActiveGuilds() { atomic.AddInt64(&r.activeGuilds, -1) }
+func (r *PrometheusRecorder) IncTracksPlayed() { atomic.AddInt64(&r.tracksPlayed, 1) }
+func (r *PrometheusRecorder) IncTracksFailed() { atomic.AddInt64(&r.tracksFailed, 1) }
+func (r *PrometheusRecorder) IncVoiceIdleDisconnects() {
+	atomic.AddInt64(&r.voiceIdleDisconnects, 1)
+}
+func (r *PrometheusRecorder) SetQueueDepth(depth int) { atomic.StoreInt64(&r.queueDepth, int64(depth)) }
+func (r *PrometheusRecorder) IncActiveVoiceConnections() {
+	atomic.AddInt64(&r.activeVoiceConnections, 1)
+}
+func (r *PrometheusRecorder) DecActiveVoiceConnections() {
+	atomic.AddInt64(&r.activeVoiceConnections, -1)
+}
+func (r *PrometheusRecorder) IncEncodeFramesSent() { atomic.AddInt64(&r.encodeFramesSent, 1) }
+
+// ObservePlaybackLatency records d into the playback-latency histogram
+func (r *PrometheusRecorder) ObservePlaybackLatency(d time.Duration) {
+	ms := float64(d.Milliseconds())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, bound := range latencyBucketBoundsMs {
+		if ms <= bound {
+			r.latencyBucketCounts[i]++
+		}
+	}
+	r.latencySum += d.Milliseconds()
+	r.latencyObservations++
+}
+
+// ObserveQueueLength samples n into the queue-length histogram
+func (r *PrometheusRecorder) ObserveQueueLength(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queueLength.observe(float64(n))
+}
+
+// ObserveCacheStats records name's latest Stats() scrape, overwriting its
+// previous reading - these are point-in-time gauges, not cumulative.
+func (r *PrometheusRecorder) ObserveCacheStats(name string, hits, misses, evictions int64, size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cacheStats[name] = cacheStat{hits: hits, misses: misses, evictions: evictions, size: size}
+}
+
+// ObserveExtractionLatency records d into urlType's extraction-latency
+// histogram, creating it on first use.
+func (r *PrometheusRecorder) ObserveExtractionLatency(urlType string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.extractionStats[urlType]
+	if !ok {
+		h = newHistogramState(latencyBucketBoundsMs)
+		r.extractionStats[urlType] = h
+	}
+	h.observe(float64(d.Milliseconds()))
+}
+
+// IncExtractionError counts a failed yt-dlp extraction for urlType.
+func (r *PrometheusRecorder) IncExtractionError(urlType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extractionErrors[urlType]++
+}
+
+// ObserveEncodeStall records d into the encode-stall histogram - how long a
+// frame hand-off blocked on a full frame channel.
+func (r *PrometheusRecorder) ObserveEncodeStall(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.encodeStall.observe(float64(d.Milliseconds()))
+}
+
+// Close shuts down the /metrics HTTP server
+func (r *PrometheusRecorder) Close() error {
+	if r.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return r.server.Shutdown(ctx)
+}
+
+func (r *PrometheusRecorder) handleMetrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_active_guilds Number of guilds with active playback state\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_active_guilds gauge\n")
+	fmt.Fprintf(w, "discord_music_bot_active_guilds %d\n", atomic.LoadInt64(&r.activeGuilds))
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_tracks_played_total Total tracks that finished playing\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_tracks_played_total counter\n")
+	fmt.Fprintf(w, "discord_music_bot_tracks_played_total %d\n", atomic.LoadInt64(&r.tracksPlayed))
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_tracks_failed_total Total tracks that failed to play\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_tracks_failed_total counter\n")
+	fmt.Fprintf(w, "discord_music_bot_tracks_failed_total %d\n", atomic.LoadInt64(&r.tracksFailed))
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_voice_idle_disconnects_total Total auto-disconnects from an empty voice channel\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_voice_idle_disconnects_total counter\n")
+	fmt.Fprintf(w, "discord_music_bot_voice_idle_disconnects_total %d\n", atomic.LoadInt64(&r.voiceIdleDisconnects))
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_processing_queue_depth Pending songs in the processing queue\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_processing_queue_depth gauge\n")
+	fmt.Fprintf(w, "discord_music_bot_processing_queue_depth %d\n", atomic.LoadInt64(&r.queueDepth))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(w, "# HELP discord_music_bot_playback_latency_ms Time from playNextSong entry to player.Play returning\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_playback_latency_ms histogram\n")
+	for i, bound := range latencyBucketBoundsMs {
+		fmt.Fprintf(w, "discord_music_bot_playback_latency_ms_bucket{le=\"%g\"} %d\n", bound, r.latencyBucketCounts[i])
+	}
+	fmt.Fprintf(w, "discord_music_bot_playback_latency_ms_bucket{le=\"+Inf\"} %d\n", r.latencyObservations)
+	fmt.Fprintf(w, "discord_music_bot_playback_latency_ms_sum %d\n", r.latencySum)
+	fmt.Fprintf(w, "discord_music_bot_playback_latency_ms_count %d\n", r.latencyObservations)
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_active_voice_connections Guilds currently holding an open voice connection\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_active_voice_connections gauge\n")
+	fmt.Fprintf(w, "discord_music_bot_active_voice_connections %d\n", atomic.LoadInt64(&r.activeVoiceConnections))
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_encode_frames_sent_total Opus frames handed off to a player by encodeWithYtDlpPipe\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_encode_frames_sent_total counter\n")
+	fmt.Fprintf(w, "discord_music_bot_encode_frames_sent_total %d\n", atomic.LoadInt64(&r.encodeFramesSent))
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_encode_stall_ms Time a frame hand-off blocked on a full frame channel\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_encode_stall_ms histogram\n")
+	writeHistogram(w, "discord_music_bot_encode_stall_ms", nil, r.encodeStall)
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_queue_length Distribution of the ProcessingService queue size\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_queue_length histogram\n")
+	writeHistogram(w, "discord_music_bot_queue_length", nil, r.queueLength)
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_extraction_latency_ms yt-dlp extraction latency, by URL type\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_extraction_latency_ms histogram\n")
+	for urlType, h := range r.extractionStats {
+		writeHistogram(w, "discord_music_bot_extraction_latency_ms", map[string]string{"url_type": urlType}, h)
+	}
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_extraction_errors_total Failed yt-dlp extractions, by URL type\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_extraction_errors_total counter\n")
+	for urlType, count := range r.extractionErrors {
+		fmt.Fprintf(w, "discord_music_bot_extraction_errors_total{url_type=%q} %d\n", urlType, count)
+	}
+
+	fmt.Fprintf(w, "# HELP discord_music_bot_cache_hits_total Cache hits, by cache name, from the last periodic Stats() scrape\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_cache_hits_total counter\n")
+	for name, stat := range r.cacheStats {
+		fmt.Fprintf(w, "discord_music_bot_cache_hits_total{cache=%q} %d\n", name, stat.hits)
+	}
+	fmt.Fprintf(w, "# HELP discord_music_bot_cache_misses_total Cache misses, by cache name, from the last periodic Stats() scrape\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_cache_misses_total counter\n")
+	for name, stat := range r.cacheStats {
+		fmt.Fprintf(w, "discord_music_bot_cache_misses_total{cache=%q} %d\n", name, stat.misses)
+	}
+	fmt.Fprintf(w, "# HELP discord_music_bot_cache_evictions_total Cache evictions, by cache name, from the last periodic Stats() scrape\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_cache_evictions_total counter\n")
+	for name, stat := range r.cacheStats {
+		fmt.Fprintf(w, "discord_music_bot_cache_evictions_total{cache=%q} %d\n", name, stat.evictions)
+	}
+	fmt.Fprintf(w, "# HELP discord_music_bot_cache_size Current entry count, by cache name, from the last periodic Stats() scrape\n")
+	fmt.Fprintf(w, "# TYPE discord_music_bot_cache_size gauge\n")
+	for name, stat := range r.cacheStats {
+		fmt.Fprintf(w, "discord_music_bot_cache_size{cache=%q} %d\n", name, stat.size)
+	}
+}
+
+// writeHistogram writes h's bucket/sum/count lines under name, merging extraLabels
+// (if any) into every label set alongside the bucket's "le".
+func writeHistogram(w http.ResponseWriter, name string, extraLabels map[string]string, h *histogramState) {
+	labels := ""
+	for k, v := range extraLabels {
+		labels += fmt.Sprintf("%s=%q,", k, v)
+	}
+	for i, bound := range h.bounds {
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", name, labels, bound, h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, labels, h.observations)
+	fmt.Fprintf(w, "%s_sum{%s} %d\n", name, strings.TrimSuffix(labels, ","), h.sum)
+	fmt.Fprintf(w, "%s_count{%s} %d\n", name, strings.TrimSuffix(labels, ","), h.observations)
+}
@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// Redis key names pushed by RedisRecorder
+const (
+	redisKeyActiveGuilds         = "discord_music_bot:active_guilds"
+	redisKeyTracksPlayed         = "discord_music_bot:tracks_played_total"
+	redisKeyTracksFailed         = "discord_music_bot:tracks_failed_total"
+	redisKeyVoiceIdleDisconnects = "discord_music_bot:voice_idle_disconnects_total"
+	redisKeyQueueDepth           = "discord_music_bot:processing_queue_depth"
+	redisKeyLatencySum           = "discord_music_bot:playback_latency_ms_sum"
+	redisKeyLatencyCount         = "discord_music_bot:playback_latency_ms_count"
+	redisKeyActiveVoiceConns     = "discord_music_bot:active_voice_connections"
+	redisKeyEncodeFramesSent     = "discord_music_bot:encode_frames_sent_total"
+	redisKeyEncodeStallSum       = "discord_music_bot:encode_stall_ms_sum"
+	redisKeyEncodeStallCount     = "discord_music_bot:encode_stall_ms_count"
+	redisKeyQueueLengthSum       = "discord_music_bot:queue_length_sum"
+	redisKeyQueueLengthCount     = "discord_music_bot:queue_length_count"
+)
+
+// redisKeyCacheStat and redisKeyExtraction build the per-cache-name and
+// per-urlType keys for ObserveCacheStats/ObserveExtractionLatency/
+// IncExtractionError, since those metrics are labeled rather than singular.
+func redisKeyCacheStat(name, stat string) string {
+	return fmt.Sprintf("discord_music_bot:cache:%s:%s", name, stat)
+}
+
+func redisKeyExtraction(urlType, stat string) string {
+	return fmt.Sprintf("discord_music_bot:extraction:%s:%s", urlType, stat)
+}
+
+// RedisRecorder pushes counters to Redis via INCRBY/SET, mirroring the
+// optional stats feature in Spoticord. It speaks the RESP protocol directly
+// over a plain net.Conn instead of pulling in a client library, since it
+// only ever needs a handful of commands. Failures are logged and swallowed -
+// a Redis hiccup should never affect playback.
+type RedisRecorder struct {
+	addr   string
+	logger *logger.Logger
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewRedisRecorder creates a recorder that pushes counters to the Redis
+// instance at addr (host:port). The connection is established lazily on
+// first use and re-dialed on error.
+func NewRedisRecorder(addr string, log *logger.Logger) *RedisRecorder {
+	return &RedisRecorder{addr: addr, logger: log}
+}
+
+func (r *RedisRecorder) IncActiveGuilds() { r.incrBy(redisKeyActiveGuilds, 1) }
+func (r *RedisRecorder) DecActiveGuilds() { r.incrBy(redisKeyActiveGuilds, -1) }
+func (r *RedisRecorder) IncTracksPlayed() { r.incrBy(redisKeyTracksPlayed, 1) }
+func (r *RedisRecorder) IncTracksFailed() { r.incrBy(redisKeyTracksFailed, 1) }
+func (r *RedisRecorder) IncVoiceIdleDisconnects() {
+	r.incrBy(redisKeyVoiceIdleDisconnects, 1)
+}
+func (r *RedisRecorder) SetQueueDepth(depth int) { r.set(redisKeyQueueDepth, depth) }
+
+// ObservePlaybackLatency pushes the observation into two running counters
+// (sum and count) so the average can be derived downstream
+func (r *RedisRecorder) ObservePlaybackLatency(d time.Duration) {
+	r.incrBy(redisKeyLatencySum, int(d.Milliseconds()))
+	r.incrBy(redisKeyLatencyCount, 1)
+}
+
+func (r *RedisRecorder) IncActiveVoiceConnections() { r.incrBy(redisKeyActiveVoiceConns, 1) }
+func (r *RedisRecorder) DecActiveVoiceConnections() { r.incrBy(redisKeyActiveVoiceConns, -1) }
+func (r *RedisRecorder) IncEncodeFramesSent()       { r.incrBy(redisKeyEncodeFramesSent, 1) }
+
+// ObserveEncodeStall pushes the observation into a sum/count pair, mirroring
+// ObservePlaybackLatency.
+func (r *RedisRecorder) ObserveEncodeStall(d time.Duration) {
+	r.incrBy(redisKeyEncodeStallSum, int(d.Milliseconds()))
+	r.incrBy(redisKeyEncodeStallCount, 1)
+}
+
+// ObserveQueueLength pushes the observation into a sum/count pair, mirroring
+// ObservePlaybackLatency.
+func (r *RedisRecorder) ObserveQueueLength(n int) {
+	r.incrBy(redisKeyQueueLengthSum, n)
+	r.incrBy(redisKeyQueueLengthCount, 1)
+}
+
+// ObserveCacheStats overwrites name's hits/misses/evictions/size keys with
+// this scrape's reading, since these are point-in-time gauges rather than
+// cumulative counters.
+func (r *RedisRecorder) ObserveCacheStats(name string, hits, misses, evictions int64, size int) {
+	r.set(redisKeyCacheStat(name, "hits"), int(hits))
+	r.set(redisKeyCacheStat(name, "misses"), int(misses))
+	r.set(redisKeyCacheStat(name, "evictions"), int(evictions))
+	r.set(redisKeyCacheStat(name, "size"), size)
+}
+
+// ObserveExtractionLatency pushes the observation into urlType's sum/count
+// pair, mirroring ObservePlaybackLatency.
+func (r *RedisRecorder) ObserveExtractionLatency(urlType string, d time.Duration) {
+	r.incrBy(redisKeyExtraction(urlType, "latency_ms_sum"), int(d.Milliseconds()))
+	r.incrBy(redisKeyExtraction(urlType, "latency_ms_count"), 1)
+}
+
+// IncExtractionError counts a failed yt-dlp extraction for urlType.
+func (r *RedisRecorder) IncExtractionError(urlType string) {
+	r.incrBy(redisKeyExtraction(urlType, "errors_total"), 1)
+}
+
+// Close closes the underlying connection, if one is open
+func (r *RedisRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn = nil
+	return err
+}
+
+func (r *RedisRecorder) incrBy(key string, delta int) {
+	if err := r.send(respArray("INCRBY", key, fmt.Sprintf("%d", delta))); err != nil {
+		r.logger.WithError(err).WithField("key", key).Warn("Failed to push metric to Redis")
+	}
+}
+
+func (r *RedisRecorder) set(key string, value int) {
+	if err := r.send(respArray("SET", key, fmt.Sprintf("%d", value))); err != nil {
+		r.logger.WithError(err).WithField("key", key).Warn("Failed to push metric to Redis")
+	}
+}
+
+// send writes a RESP command and reads (and discards) its single reply line,
+// dialing a fresh connection if none is open or the previous one errored
+func (r *RedisRecorder) send(cmd []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		conn, err := net.DialTimeout("tcp", r.addr, 2*time.Second)
+		if err != nil {
+			return fmt.Errorf("dial redis: %w", err)
+		}
+		r.conn = conn
+	}
+
+	r.conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := r.conn.Write(cmd); err != nil {
+		r.conn.Close()
+		r.conn = nil
+		return fmt.Errorf("write redis command: %w", err)
+	}
+
+	if _, err := bufio.NewReader(r.conn).ReadString('\n'); err != nil {
+		r.conn.Close()
+		r.conn = nil
+		return fmt.Errorf("read redis reply: %w", err)
+	}
+
+	return nil
+}
+
+// respArray encodes args as a RESP array of bulk strings, e.g.
+// respArray("SET", "key", "1") -> "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$1\r\n1\r\n"
+func respArray(args ...string) []byte {
+	buf := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		buf = append(buf, []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg))...)
+	}
+	return buf
+}
@@ -0,0 +1,95 @@
+package audio_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// simulatedGuildCount mirrors the scale the sharding in shard.go targets -
+// see BenchmarkAudioServiceManyGuilds and chunk3-5's motivation.
+const simulatedGuildCount = 1000
+
+// BenchmarkAudioServiceManyGuilds drives concurrent per-guild operations
+// across simulatedGuildCount distinct guilds, the scenario sharding (see
+// shard.go) exists for: unrelated guilds should proceed without serializing
+// on one lock. Run with -cpu to vary GOMAXPROCS and see contention drop off.
+func BenchmarkAudioServiceManyGuilds(b *testing.B) {
+	log := logger.New(logger.Config{Level: "error"})
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
+
+	guildIDs := make([]string, simulatedGuildCount)
+	for i := range guildIDs {
+		guildIDs[i] = fmt.Sprintf("bench-guild-%d", i)
+		service.GetTracklist(guildIDs[i])
+	}
+
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < b.N; i++ {
+		guildID := guildIDs[i%simulatedGuildCount]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.GetTracklist(guildID)
+			_ = service.IsConnected(guildID)
+			_ = service.IsPlaying(guildID)
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkAudioServiceGetStats measures aggregating stats across every
+// shard with simulatedGuildCount guilds already registered.
+func BenchmarkAudioServiceGetStats(b *testing.B) {
+	log := logger.New(logger.Config{Level: "error"})
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
+
+	for i := 0; i < simulatedGuildCount; i++ {
+		service.GetTracklist(fmt.Sprintf("bench-guild-%d", i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		service.GetStats()
+	}
+}
+
+// TestAudioServiceManyGuildsConcurrent is BenchmarkAudioServiceManyGuilds's
+// correctness counterpart: simulatedGuildCount guilds hammered concurrently
+// should never deadlock or race (run with -race), and every tracklist should
+// come back the same instance its guild was first given.
+func TestAudioServiceManyGuildsConcurrent(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, simulatedGuildCount)
+	for i := 0; i < simulatedGuildCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			guildID := fmt.Sprintf("concurrent-guild-%d", i)
+			tracklist := service.GetTracklist(guildID)
+			results[i] = tracklist
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < simulatedGuildCount; i++ {
+		guildID := fmt.Sprintf("concurrent-guild-%d", i)
+		if service.GetTracklist(guildID) != results[i] {
+			t.Errorf("guild %s: tracklist instance changed after concurrent creation", guildID)
+		}
+	}
+
+	stats := service.GetStats()
+	if stats["total_guilds"].(int) != simulatedGuildCount {
+		t.Errorf("expected total_guilds=%d, got %v", simulatedGuildCount, stats["total_guilds"])
+	}
+}
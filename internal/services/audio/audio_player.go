@@ -1,12 +1,14 @@
 package audio
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
@@ -18,8 +20,9 @@ var (
 	ErrPlayerNotPlaying = errors.New("player is not playing")
 )
 
-// PlaybackCallback is called when playback ends or errors occur
-type PlaybackCallback func(song *entities.Song, err error)
+// opusFrameDuration is how much audio a single Opus frame sent to Discord
+// represents, used to derive Position() from the frame count
+const opusFrameDuration = 20 * time.Millisecond
 
 // AudioPlayer manages audio playback for a guild
 type AudioPlayer struct {
@@ -32,44 +35,240 @@ type AudioPlayer struct {
 	isPlaying   atomic.Bool
 	isPaused    atomic.Bool
 	stopSignal  chan struct{}
-	callback    PlaybackCallback
 	volume      int // Volume level 0-100
 
+	// logCtx holds the context.Context passed to the most recent Play,
+	// PlayFrom, Stop, Pause, or Resume call, carrying the request's
+	// correlation_id/guild/user fields (see logger.WithContext). logEntry
+	// reads it for every log line this player emits, including from
+	// goroutines (playbackLoop, onVoiceReconnect) that outlive the call that
+	// started them. An internal restart-in-place (Seek, SetFilters, a
+	// successful reconnect) deliberately keeps logging under the context of
+	// the Play that originally started the track rather than resetting it.
+	logCtx atomic.Value // context.Context
+
+	// reconnecting and reconnectFailed back onVoiceReconnect, the handler
+	// wired to vc.SetOnReconnect in AudioService.connectLocal. reconnecting
+	// is set the instant the voice connection drops, and gates frame sends
+	// in playbackLoop the same way isPaused does, so it never writes to the
+	// dead OpusSend channel while the supervisor redials. reconnectFailed is
+	// set only when backoff is exhausted, so playbackLoop's own stopSignal
+	// handler knows to report TrackEventError (with Err=ErrReconnectFailed)
+	// instead of a clean TrackEventEnd.
+	reconnecting    atomic.Bool
+	reconnectFailed atomic.Bool
+
+	// filters is the guild's current DSP effect chain, applied as an
+	// ffmpeg -af graph on the next (re-)encode. See SetFilters.
+	filters *FilterSpec
+
+	// crossfadeMs is the fade-out/fade-in duration (ms) SetCrossfade
+	// configures; see SetCrossfade for what "crossfade" means on this
+	// player.
+	crossfadeMs int
+
+	// normalization is the guild's current loudness-normalization setting,
+	// applied as a precomputed volume stage on the next (re-)encode; see
+	// SetNormalization. nil means off.
+	normalization *NormalizationConfig
+
+	// elapsedFrames and startOffset back Position(): elapsed playback time is
+	// startOffset (where the stream began, e.g. a shared timestamp link, or
+	// where SetFilters resumed after a live re-encode) plus one
+	// opusFrameDuration per frame actually sent to Discord
+	elapsedFrames atomic.Int64
+	startOffset   time.Duration
+
+	// handlers holds the TrackEventHandlers registered via AddHandler for
+	// each TrackEventType, and handlerSeq assigns each one an id so its
+	// unsubscribe func can find it again. globalDispatch, if set by
+	// AudioService at construction, additionally forwards every dispatch to
+	// AudioService.AddGlobalHandler's subscribers; see dispatch.
+	handlers       map[TrackEventType][]handlerEntry
+	handlerSeq     atomic.Uint64
+	handlersMu     sync.RWMutex
+	globalDispatch func(TrackEventType, TrackContext)
+
+	// votes tracks this guild's in-progress skip/stop/pause vote session;
+	// see vote.go. voteCfg is its configured threshold/TTL, set via
+	// SetVoteConfig.
+	votes   VoteHolder
+	voteCfg voteConfig
+
 	mu sync.RWMutex
 }
 
+// positionReportInterval is how often TrackEventPositionTick fires during
+// playback
+const positionReportInterval = 15 * time.Second
+
+// defaultFadeInMs always smooths the first moments of any (re-)encode -
+// including a live re-encode from SetFilters/SetVolume/Seek - so a restart
+// in place never pops.
+const defaultFadeInMs = 250
+
 // NewAudioPlayer creates a new audio player
 func NewAudioPlayer(guildID string, vc *VoiceConnection, log *logger.Logger) *AudioPlayer {
-	return &AudioPlayer{
+	p := &AudioPlayer{
 		guildID:    guildID,
 		vc:         vc,
 		encoder:    NewAudioEncoder(log),
 		logger:     log,
 		stopSignal: make(chan struct{}),
 		volume:     30, // Default volume 30%
+		handlers:   make(map[TrackEventType][]handlerEntry),
+	}
+	p.logCtx.Store(context.Background())
+	p.encoder.SetFrameSink(p)
+	return p
+}
+
+// NextFrameDeadline implements FrameSink: playbackLoop drains frames as fast
+// as they arrive while playing, so it's always ready "now"; while paused or
+// waiting on a voice reconnect, it reports a deadline far enough in the
+// future that the encoder stops decoding ahead until Resume (or a completed
+// reconnect) clears the flag.
+func (p *AudioPlayer) NextFrameDeadline() time.Time {
+	if p.isPaused.Load() || p.reconnecting.Load() {
+		return time.Now().Add(time.Hour)
+	}
+	return time.Now()
+}
+
+// logEntry returns a log entry pre-populated with whatever request fields
+// were attached to the context passed to the most recent Play/PlayFrom/
+// Stop/Pause/Resume call; see logCtx.
+func (p *AudioPlayer) logEntry() *logrus.Entry {
+	ctx, _ := p.logCtx.Load().(context.Context)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return p.logger.FromContext(ctx)
+}
+
+// AddHandler registers handler to run whenever event fires on this player.
+// Multiple handlers may be registered for the same event; each runs in
+// registration order on dispatch's fan-out goroutine. The returned
+// unsubscribe func removes exactly this handler.
+func (p *AudioPlayer) AddHandler(event TrackEventType, handler TrackEventHandler) (unsubscribe func()) {
+	id := p.handlerSeq.Add(1)
+
+	p.handlersMu.Lock()
+	p.handlers[event] = append(p.handlers[event], handlerEntry{id: id, handler: handler})
+	p.handlersMu.Unlock()
+
+	return func() {
+		p.handlersMu.Lock()
+		defer p.handlersMu.Unlock()
+		entries := p.handlers[event]
+		for i, e := range entries {
+			if e.id == id {
+				p.handlers[event] = append(entries[:i:i], entries[i+1:]...)
+				return
+			}
+		}
 	}
 }
 
-// Play starts playing a song
-func (p *AudioPlayer) Play(song *entities.Song, callback PlaybackCallback) error {
+// dispatch fans event out to every handler registered for it, plus
+// AudioService's global handlers if this player was created with a
+// globalDispatch forwarder. Runs on its own goroutine so a slow or blocked
+// handler can never delay playback.
+func (p *AudioPlayer) dispatch(event TrackEventType, ctx TrackContext) {
+	p.handlersMu.RLock()
+	entries := append([]handlerEntry(nil), p.handlers[event]...)
+	p.handlersMu.RUnlock()
+
+	go func() {
+		for _, e := range entries {
+			e.handler(ctx)
+		}
+		if p.globalDispatch != nil {
+			p.globalDispatch(event, ctx)
+		}
+	}()
+}
+
+// NotifyQueueEmpty dispatches TrackEventQueueEmpty to this player's
+// subscribers. Called by PlaybackService once its tracklist runs dry, so
+// subsystems like the now-playing embed updater and the auto-disconnect
+// timer can react without needing to poll the queue themselves.
+func (p *AudioPlayer) NotifyQueueEmpty() {
+	p.dispatch(TrackEventQueueEmpty, TrackContext{GuildID: p.guildID})
+}
+
+// Play starts playing a song. ctx carries request-scoped logging fields
+// (correlation_id, guild, user - see logger.WithContext) that logEntry
+// attaches to every log line this playback emits, including from goroutines
+// that outlive this call.
+func (p *AudioPlayer) Play(ctx context.Context, song *entities.Song) error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
 	// Check if already playing
 	if p.isPlaying.Load() {
+		p.mu.Unlock()
 		return ErrAlreadyPlaying
 	}
 
 	// Check voice connection
 	if !p.vc.IsConnected() {
+		p.mu.Unlock()
 		return ErrNoVoiceConnection
 	}
 
 	// Ensure song is ready
 	if !song.IsReady() {
+		p.mu.Unlock()
+		return fmt.Errorf("song is not ready: status=%s", song.GetStatus())
+	}
+	p.mu.Unlock()
+
+	p.logCtx.Store(ctx)
+	p.logEntry().WithFields(logrus.Fields{
+		"song":   song.DisplayName(),
+		"status": song.GetStatus(),
+	}).Info("🎵 Starting playback...")
+
+	var offset time.Duration
+	if metadata := song.GetMetadata(); metadata != nil {
+		offset = metadata.StartOffset
+	}
+
+	return p.beginPlayback(song, offset)
+}
+
+// PlayFrom starts playback of song beginning at offset into the stream,
+// ignoring any share-link timestamp in the song's metadata. Used to resume a
+// track at its last known position after a bot restart; see
+// PlaybackService.RestoreAll. See Play for ctx.
+func (p *AudioPlayer) PlayFrom(ctx context.Context, song *entities.Song, offset time.Duration) error {
+	p.mu.Lock()
+
+	if p.isPlaying.Load() {
+		p.mu.Unlock()
+		return ErrAlreadyPlaying
+	}
+
+	if !p.vc.IsConnected() {
+		p.mu.Unlock()
+		return ErrNoVoiceConnection
+	}
+
+	if !song.IsReady() {
+		p.mu.Unlock()
 		return fmt.Errorf("song is not ready: status=%s", song.GetStatus())
 	}
+	p.mu.Unlock()
 
+	p.logCtx.Store(ctx)
+	return p.beginPlayback(song, offset)
+}
+
+// beginPlayback resets playback state for song and launches the playback
+// loop starting offset into the stream. Shared by Play, which honors a
+// share-link timestamp, and SetFilters, which resumes in place after a live
+// re-encode.
+func (p *AudioPlayer) beginPlayback(song *entities.Song, offset time.Duration) error {
 	// Use OriginalInput (YouTube URL) for yt-dlp pipe encoding
 	// This bypasses 403 errors that occur with direct stream URLs
 	sourceURL := song.OriginalInput
@@ -77,42 +276,62 @@ func (p *AudioPlayer) Play(song *entities.Song, callback PlaybackCallback) error
 		return fmt.Errorf("song has no source URL")
 	}
 
-	p.logger.WithFields(map[string]interface{}{
-		"song":   song.DisplayName(),
-		"status": song.GetStatus(),
-	}).Info("🎵 Starting playback...")
-
+	p.mu.Lock()
 	p.currentSong = song
-	p.callback = callback
 	p.stopSignal = make(chan struct{})
 	p.isPlaying.Store(true)
 	p.isPaused.Store(false)
+	p.reconnecting.Store(false)
+	p.reconnectFailed.Store(false)
+	p.elapsedFrames.Store(0)
+	p.startOffset = offset
+	p.mu.Unlock()
+
+	p.dispatch(TrackEventPlay, TrackContext{GuildID: p.guildID, Song: song, Position: offset})
 
 	// Start playback in goroutine
 	go p.playbackLoop(song, sourceURL)
+	go p.reportPosition(p.stopSignal)
 
 	return nil
 }
 
+// reportPosition dispatches TrackEventPositionTick with the current position
+// every positionReportInterval until stopSignal fires. Runs alongside
+// playbackLoop rather than inside it so a slow or blocked handler can never
+// delay frame delivery.
+func (p *AudioPlayer) reportPosition(stopSignal chan struct{}) {
+	ticker := time.NewTicker(positionReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopSignal:
+			return
+		case <-ticker.C:
+			p.dispatch(TrackEventPositionTick, TrackContext{GuildID: p.guildID, Song: p.GetCurrentSong(), Position: p.Position()})
+		}
+	}
+}
+
 // playbackLoop handles the actual playback
 func (p *AudioPlayer) playbackLoop(song *entities.Song, sourceURL string) {
+	var finalErr error
+	frameCount := 0
 	defer func() {
 		p.isPlaying.Store(false)
 		p.isPaused.Store(false)
 
-		p.mu.Lock()
-		callback := p.callback
-		p.callback = nil
-		p.mu.Unlock()
-
-		if callback != nil {
-			callback(song, nil)
+		event := TrackEventEnd
+		if finalErr != nil {
+			event = TrackEventError
 		}
+		p.dispatch(event, TrackContext{GuildID: p.guildID, Song: song, Position: p.Position(), FrameCount: frameCount, Err: finalErr})
 	}()
 
 	// Set speaking status
 	if err := p.vc.Speaking(true); err != nil {
-		p.logger.WithError(err).Error("Failed to set speaking status")
+		p.logEntry().WithError(err).Error("Failed to set speaking status")
 		return
 	}
 	defer p.vc.Speaking(false)
@@ -122,72 +341,86 @@ func (p *AudioPlayer) playbackLoop(song *entities.Song, sourceURL string) {
 	// Using sourceURL (original YouTube URL) to bypass 403 errors
 	options := DefaultEncodeOptions()
 
-	// Apply current volume setting
+	// Apply current volume, DSP filter chain, and stream start offset (a
+	// share-link timestamp on first play, or where SetFilters resumed after
+	// a live re-encode)
 	p.mu.RLock()
 	options.Volume = p.volume
+	options.Filters = p.filters
+	options.StartOffset = p.startOffset
+	options.FadeInMs = defaultFadeInMs
+	options.FadeOutMs = p.crossfadeMs
+	normalization := p.normalization
 	p.mu.RUnlock()
 
-	p.logger.WithField("volume", options.Volume).Debug("Starting playback with volume")
+	if meta := song.GetMetadata(); meta != nil {
+		options.Duration = time.Duration(meta.Duration) * time.Second
+	}
 
-	frameChannel, errorChannel, err := p.encoder.EncodeStream(sourceURL, options)
-	if err != nil {
-		p.logger.WithError(err).Error("Failed to start encoding")
+	// Loudness normalization needs its own ffmpeg analysis pass before the
+	// real encode starts, so measure (or fetch from cache) the track's gain
+	// up front rather than blocking EncodeStream on it internally.
+	if normalization != nil && normalization.Enabled {
+		gain, err := p.encoder.NormalizationGain(context.Background(), sourceURL, normalization.TargetLUFS)
+		if err != nil {
+			p.logEntry().WithError(err).Warn("⚠️ Loudness analysis failed, playing unnormalized")
+		} else {
+			options.Normalization = &NormalizationConfig{Enabled: true, TargetLUFS: normalization.TargetLUFS, GainDB: gain}
+		}
+	}
 
-		p.mu.Lock()
-		callback := p.callback
-		p.mu.Unlock()
+	p.logEntry().WithField("volume", options.Volume).Debug("Starting playback with volume")
 
-		if callback != nil {
-			callback(song, err)
-		}
+	frameChannel, errorChannel, err := p.encoder.EncodeStream(sourceURL, options)
+	if err != nil {
+		p.logEntry().WithError(err).Error("Failed to start encoding")
+		finalErr = err
 		return
 	}
 
 	// Get voice connection
 	vc := p.vc.GetVoiceConnection()
 	if vc == nil {
-		p.logger.Error("Voice connection is nil")
+		p.logEntry().Error("Voice connection is nil")
 		return
 	}
 
-	p.logger.Info("📻 Streaming audio to Discord...")
+	p.logEntry().Info("📻 Streaming audio to Discord...")
 
 	// Stream audio frames
-	frameCount := 0
 	for {
 		select {
 		case <-p.stopSignal:
-			p.logger.Info("⏹️ Playback stopped by user")
+			if p.reconnectFailed.Load() {
+				finalErr = ErrReconnectFailed
+				p.logEntry().Warn("⏹️ Playback stopped: voice reconnection failed")
+			} else {
+				p.logEntry().Info("⏹️ Playback stopped by user")
+			}
 			return
 
 		case err := <-errorChannel:
 			if err != nil {
-				p.logger.WithError(err).Error("Encoding error")
-
-				p.mu.Lock()
-				callback := p.callback
-				p.mu.Unlock()
-
-				if callback != nil {
-					callback(song, err)
-				}
+				p.logEntry().WithError(err).Error("Encoding error")
+				finalErr = err
 				return
 			}
 
 		case frame, ok := <-frameChannel:
 			if !ok {
 				// Channel closed, playback finished
-				p.logger.WithField("frames", frameCount).Info("✅ Playback completed")
+				p.logEntry().WithField("frames", frameCount).Info("✅ Playback completed")
 				return
 			}
 
-			// Handle pause
-			for p.isPaused.Load() {
+			// Handle pause, and the gap while the voice connection supervisor
+			// is redialing after an unexpected drop (see onVoiceReconnect)
+			for p.isPaused.Load() || p.reconnecting.Load() {
 				select {
 				case <-p.stopSignal:
 					return
 				case <-time.After(100 * time.Millisecond):
-					// Continue checking pause state
+					// Continue checking pause/reconnecting state
 				}
 			}
 
@@ -195,16 +428,26 @@ func (p *AudioPlayer) playbackLoop(song *entities.Song, sourceURL string) {
 			select {
 			case vc.OpusSend <- frame:
 				frameCount++
+				p.elapsedFrames.Add(1)
 			case <-p.stopSignal:
-				p.logger.Info("⏹️ Playback stopped during frame send")
+				p.logEntry().Info("⏹️ Playback stopped during frame send")
 				return
 			}
 		}
 	}
 }
 
-// Stop stops the current playback
-func (p *AudioPlayer) Stop() error {
+// Stop stops the current playback. ctx replaces the player's logging
+// context (see logEntry) before the stop is logged.
+func (p *AudioPlayer) Stop(ctx context.Context) error {
+	p.logCtx.Store(ctx)
+	return p.stop()
+}
+
+// stop is Stop's actual logic, reused by internal restart-in-place callers
+// (SetFilters, Seek, reconnect-resume) and teardown paths that stop playback
+// without a fresh request context to attach.
+func (p *AudioPlayer) stop() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -212,7 +455,7 @@ func (p *AudioPlayer) Stop() error {
 		return ErrPlayerNotPlaying
 	}
 
-	p.logger.Info("⏹️ Stopping playback...")
+	p.logEntry().Info("⏹️ Stopping playback...")
 
 	// Signal stop - use select to avoid panic on double close
 	select {
@@ -232,8 +475,11 @@ func (p *AudioPlayer) Stop() error {
 	return nil
 }
 
-// Pause pauses the playback
-func (p *AudioPlayer) Pause() error {
+// Pause pauses the playback. ctx replaces the player's logging context (see
+// logEntry) before the pause is logged.
+func (p *AudioPlayer) Pause(ctx context.Context) error {
+	p.logCtx.Store(ctx)
+
 	if !p.isPlaying.Load() {
 		return ErrPlayerNotPlaying
 	}
@@ -242,19 +488,24 @@ func (p *AudioPlayer) Pause() error {
 		return errors.New("already paused")
 	}
 
-	p.logger.Info("⏸️ Pausing playback...")
+	p.logEntry().Info("⏸️ Pausing playback...")
 	p.isPaused.Store(true)
 
 	// Set speaking to false when paused
 	if err := p.vc.Speaking(false); err != nil {
-		p.logger.WithError(err).Warn("Failed to update speaking status on pause")
+		p.logEntry().WithError(err).Warn("Failed to update speaking status on pause")
 	}
 
+	p.dispatch(TrackEventPause, TrackContext{GuildID: p.guildID, Song: p.GetCurrentSong(), Position: p.Position()})
+
 	return nil
 }
 
-// Resume resumes the playback
-func (p *AudioPlayer) Resume() error {
+// Resume resumes the playback. ctx replaces the player's logging context
+// (see logEntry) before the resume is logged.
+func (p *AudioPlayer) Resume(ctx context.Context) error {
+	p.logCtx.Store(ctx)
+
 	if !p.isPlaying.Load() {
 		return ErrPlayerNotPlaying
 	}
@@ -263,14 +514,16 @@ func (p *AudioPlayer) Resume() error {
 		return errors.New("not paused")
 	}
 
-	p.logger.Info("▶️ Resuming playback...")
+	p.logEntry().Info("▶️ Resuming playback...")
 	p.isPaused.Store(false)
 
 	// Set speaking to true when resumed
 	if err := p.vc.Speaking(true); err != nil {
-		p.logger.WithError(err).Warn("Failed to update speaking status on resume")
+		p.logEntry().WithError(err).Warn("Failed to update speaking status on resume")
 	}
 
+	p.dispatch(TrackEventResume, TrackContext{GuildID: p.guildID, Song: p.GetCurrentSong(), Position: p.Position()})
+
 	return nil
 }
 
@@ -294,22 +547,66 @@ func (p *AudioPlayer) GetCurrentSong() *entities.Song {
 // Cleanup performs cleanup when player is no longer needed
 func (p *AudioPlayer) Cleanup() {
 	if p.isPlaying.Load() {
-		p.Stop()
+		p.stop()
+	}
+}
+
+// Position returns how far into the current song playback has progressed,
+// including any start offset the stream began at (e.g. a shared timestamp
+// link). Returns 0 when nothing is playing.
+func (p *AudioPlayer) Position() time.Duration {
+	if !p.isPlaying.Load() {
+		return 0
+	}
+	p.mu.RLock()
+	offset := p.startOffset
+	p.mu.RUnlock()
+	return offset + time.Duration(p.elapsedFrames.Load())*opusFrameDuration
+}
+
+// Duration returns the current song's total duration, or 0 if unknown
+func (p *AudioPlayer) Duration() time.Duration {
+	song := p.GetCurrentSong()
+	if song == nil {
+		return 0
+	}
+	metadata := song.GetMetadata()
+	if metadata == nil {
+		return 0
 	}
+	return time.Duration(metadata.Duration) * time.Second
 }
 
-// SetVolume sets the volume level (0-100)
+// SetVolume sets the volume level (0-100). If a song is currently playing,
+// the new volume is applied immediately via the same live re-encode
+// SetFilters uses, rather than waiting for the next track to pick it up.
 func (p *AudioPlayer) SetVolume(level int) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
 	if level < 0 {
 		level = 0
 	}
 	if level > 100 {
 		level = 100
 	}
+
+	p.mu.Lock()
+	song := p.currentSong
+	playing := p.isPlaying.Load()
+	resumeAt := p.startOffset + time.Duration(p.elapsedFrames.Load())*opusFrameDuration
 	p.volume = level
+	p.mu.Unlock()
+
 	p.logger.WithField("volume", level).Info("Volume set")
+
+	if !playing || song == nil {
+		return
+	}
+
+	if err := p.stop(); err != nil {
+		return
+	}
+	if err := p.beginPlayback(song, resumeAt); err != nil {
+		p.logEntry().WithError(err).Warn("Failed to re-encode after volume change")
+	}
 }
 
 // GetVolume returns the current volume level
@@ -318,3 +615,167 @@ func (p *AudioPlayer) GetVolume() int {
 	defer p.mu.RUnlock()
 	return p.volume
 }
+
+// SetFilters updates the guild's DSP filter chain. If a song is currently
+// playing, the stream is stopped and immediately restarted from the same
+// position with the new filter graph applied - a live re-encode rather than
+// a restart from the top.
+func (p *AudioPlayer) SetFilters(spec *FilterSpec) error {
+	p.mu.Lock()
+	song := p.currentSong
+	playing := p.isPlaying.Load()
+	resumeAt := p.startOffset + time.Duration(p.elapsedFrames.Load())*opusFrameDuration
+	p.filters = spec
+	p.mu.Unlock()
+
+	if !playing || song == nil {
+		return nil
+	}
+
+	if err := p.stop(); err != nil {
+		return err
+	}
+	return p.beginPlayback(song, resumeAt)
+}
+
+// GetFilters returns the guild's current DSP filter chain, or nil if none
+// has been set.
+func (p *AudioPlayer) GetFilters() *FilterSpec {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.filters
+}
+
+// SetCrossfade configures d as the fade-out applied to the tail of every
+// track this player encodes from here on, mirrored as the fade-in at the
+// head of whichever track plays next (see beginPlayback's FadeOutMs/
+// FadeInMs). d <= 0 disables it.
+//
+// This player streams exactly one yt-dlp|ffmpeg Opus pipe at a time (see
+// playbackLoop), so there's no second decoded stream to mix the outgoing
+// track into - "crossfade" here is a fade-out immediately followed by the
+// next track's fade-in, not a true overlapping blend of two tracks. A real
+// overlap would need a PCM decode/mix stage this ffmpeg-piped encoder
+// doesn't have.
+func (p *AudioPlayer) SetCrossfade(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.crossfadeMs = int(d.Milliseconds())
+}
+
+// GetCrossfade returns the currently configured crossfade duration, or 0 if
+// none has been set.
+func (p *AudioPlayer) GetCrossfade() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return time.Duration(p.crossfadeMs) * time.Millisecond
+}
+
+// SetNormalization turns loudness normalization on or off for every track
+// this player encodes from here on, targeting targetLUFS once enabled. The
+// actual per-track gain is measured lazily in playbackLoop via
+// AudioEncoder.NormalizationGain, since that needs its own ffmpeg analysis
+// pass per track.
+func (p *AudioPlayer) SetNormalization(enabled bool, targetLUFS float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.normalization = &NormalizationConfig{Enabled: enabled, TargetLUFS: targetLUFS}
+}
+
+// GetNormalization returns the guild's current loudness-normalization
+// setting, defaulting to DefaultNormalizationConfig (off) if none has been
+// set yet.
+func (p *AudioPlayer) GetNormalization() *NormalizationConfig {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.normalization == nil {
+		return DefaultNormalizationConfig()
+	}
+	return p.normalization
+}
+
+// ErrSeekPastEnd is returned by Seek when d is beyond the current song's
+// known duration.
+var ErrSeekPastEnd = errors.New("seek position is past the end of the track")
+
+// Seek jumps the current track to d into the stream by tearing down the
+// in-flight yt-dlp|ffmpeg pipe and starting a fresh one with d passed to
+// ffmpeg as -ss (see EncodeOptions.StartOffset), the same restart-in-place
+// technique SetFilters uses for a live re-encode. Stop fully closes
+// stopSignal and waits out the old pipe's goroutines before beginPlayback
+// starts the new one, so there's no window where two encoders could both
+// write to OpusSend. Returns ErrPlayerNotPlaying if nothing is playing, and
+// ErrSeekPastEnd if d is beyond the song's known duration (unknown
+// duration, reported as <= 0 by yt-dlp, is never rejected here).
+func (p *AudioPlayer) Seek(d time.Duration) error {
+	p.mu.RLock()
+	song := p.currentSong
+	playing := p.isPlaying.Load()
+	p.mu.RUnlock()
+
+	if !playing || song == nil {
+		return ErrPlayerNotPlaying
+	}
+
+	if meta := song.GetMetadata(); meta != nil && meta.Duration > 0 {
+		if d > time.Duration(meta.Duration)*time.Second {
+			return ErrSeekPastEnd
+		}
+	}
+
+	if d < 0 {
+		d = 0
+	}
+
+	if err := p.stop(); err != nil {
+		return err
+	}
+	return p.beginPlayback(song, d)
+}
+
+// onVoiceReconnect is wired to vc.SetOnReconnect by AudioService.connectLocal
+// and reacts to the voice connection supervisor's two calls per drop: first
+// ok=false, err=nil the instant the drop is detected, to gate frame sends in
+// playbackLoop (via reconnecting) before they can hit a dead OpusSend
+// channel; then a second call once backoff resolves, either ok=true once the
+// channel is rejoined or ok=false, err=ErrReconnectFailed once backoff is
+// exhausted. A successful reconnect resumes playback in place with a fresh
+// yt-dlp|ffmpeg pipe bound to the new connection, the same restart-in-place
+// technique Seek uses; a failed one reports TrackEventError and gives up.
+func (p *AudioPlayer) onVoiceReconnect(ok bool, err error) {
+	if !ok && err == nil {
+		p.reconnecting.Store(true)
+		return
+	}
+
+	if !ok {
+		p.reconnectFailed.Store(true)
+		if stopErr := p.stop(); stopErr != nil {
+			p.logEntry().WithError(stopErr).Warn("Failed to stop playback after reconnect failure")
+		}
+		return
+	}
+
+	p.mu.RLock()
+	song := p.currentSong
+	playing := p.isPlaying.Load()
+	resumeAt := p.startOffset + time.Duration(p.elapsedFrames.Load())*opusFrameDuration
+	p.mu.RUnlock()
+
+	if !playing || song == nil {
+		p.reconnecting.Store(false)
+		return
+	}
+
+	if stopErr := p.stop(); stopErr != nil {
+		p.logEntry().WithError(stopErr).Warn("Failed to stop playback before resuming after reconnect")
+		p.reconnecting.Store(false)
+		return
+	}
+	if err := p.beginPlayback(song, resumeAt); err != nil {
+		p.logEntry().WithError(err).Error("Failed to resume playback after reconnect")
+	}
+}
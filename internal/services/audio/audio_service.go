@@ -1,12 +1,16 @@
 package audio
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/metrics"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
@@ -15,33 +19,218 @@ var (
 	ErrGuildNotFound = errors.New("guild not found")
 )
 
-// AudioService manages voice connections, audio players, and tracklists for all guilds
+// AudioService manages voice connections, audio players, and tracklists for
+// all guilds. Per-guild state lives in one of shardCount shards (see
+// shard.go), each with its own RWMutex, so operations on unrelated guilds
+// don't serialize on a single global lock as the guild count grows.
 type AudioService struct {
 	session *discordgo.Session
 	logger  *logger.Logger
 
-	voiceConnections map[string]*VoiceConnection    // guildID -> voice connection
-	audioPlayers     map[string]*AudioPlayer        // guildID -> audio player
-	tracklists       map[string]*entities.Tracklist // guildID -> tracklist
-
+	shards [shardCount]*shard
+
+	// encoderPool caps how many guilds' yt-dlp/FFmpeg pipelines may run at
+	// once process-wide; see EncoderPool. Shared across every shard.
+	encoderPool *EncoderPool
+
+	// onActivity, if set, is called after a guild connects or plays a song,
+	// so a caller (PlaybackService's state-persistence hook) can snapshot
+	// and save playback state. onCleanup is called from DisconnectFromGuild,
+	// when a guild's session ends on purpose (idle timeout, /leave), so any
+	// saved snapshot can be dropped rather than resumed on the next boot.
+	// Deliberately NOT called from Cleanup/CleanupAll, which run on process
+	// shutdown - those guilds should still resume on the next startup. Both
+	// hooks are nil-safe no-ops until SetActivityHook/SetCleanupHook is
+	// called.
+	onActivity func(guildID string)
+	onCleanup  func(guildID string)
+
+	// mu guards onActivity/onCleanup above; per-guild state has its own
+	// locking via shards and is never touched under mu.
 	mu sync.RWMutex
+
+	// idleTimeout and aloneTimeout configure the idle watcher started for
+	// every connected guild; see idle_watcher.go. defaultAutoLeave is the
+	// watcher's enabled state for a guild that has never called
+	// SetAutoLeave (derived from !Config.StayConnected247).
+	idleTimeout      time.Duration
+	aloneTimeout     time.Duration
+	defaultAutoLeave bool
+
+	// idleCheckHook reports whether a guild has nothing playing and an
+	// empty queue; wired from bot.go to PlaybackService.IsIdle, since
+	// AudioService's own tracklists map is vestigial - the real queue lives
+	// in PlaybackService. onAutoDisconnect is called just before the
+	// watcher disconnects a guild, so a caller can notify its bound text
+	// channel while the state needed to find it still exists.
+	idleCheckHook    func(guildID string) bool
+	onAutoDisconnect func(guildID, reason string)
+
+	// watchers and autoLeaveEnabled track the running per-guild idle
+	// watcher goroutines and any per-guild /autoleave override. Guarded by
+	// watchMu rather than mu, since the watcher reads them from outside any
+	// ConnectToChannel/DisconnectFromGuild critical section.
+	watchers         map[string]*guildWatcher
+	autoLeaveEnabled map[string]bool
+	watchMu          sync.Mutex
+
+	// globalHandlers holds the TrackEventHandlers registered via
+	// AddGlobalHandler, keyed like AudioPlayer.handlers. Every AudioPlayer
+	// created by ConnectToChannel is wired with dispatchGlobal as its
+	// globalDispatch, so these run for every guild's events, including ones
+	// registered after a guild is already connected.
+	globalHandlers   map[TrackEventType][]handlerEntry
+	globalHandlerSeq atomic.Uint64
+	globalHandlersMu sync.RWMutex
+
+	// lavalink, if set via SetLavalinkBackend, is used instead of this
+	// process's own yt-dlp/FFmpeg pipeline for every guild - see backend().
+	lavalink *LavalinkBackend
+
+	// voteThresholdRatio, voteMinVotes and voteTTL configure the
+	// skip/stop/pause vote sessions (see vote.go) of every AudioPlayer this
+	// service creates from here on; set via SetVoteConfig. Zero values let
+	// AudioPlayer fall back to its own defaults.
+	voteThresholdRatio float64
+	voteMinVotes       int
+	voteTTL            time.Duration
+
+	// metrics receives active-voice-connection and (via each AudioPlayer's
+	// encoder) encode throughput/stall observations. Defaults to
+	// metrics.NoopRecorder; set via SetMetricsRecorder.
+	metrics metrics.Recorder
+}
+
+// SetVoteConfig overrides the default vote threshold/TTL applied to every
+// AudioPlayer created by ConnectToChannel from here on (players created
+// before this call are unaffected). See AudioPlayer.SetVoteConfig.
+func (s *AudioService) SetVoteConfig(ratio float64, minVotes int, ttl time.Duration) {
+	s.voteThresholdRatio = ratio
+	s.voteMinVotes = minVotes
+	s.voteTTL = ttl
+}
+
+// SetLavalinkBackend switches every future ConnectToChannel/PlaySong/etc
+// call onto backend's pool of Lavalink nodes instead of the local ffmpeg
+// pipeline. Guilds already connected through the local backend are
+// unaffected until they reconnect. Passing nil reverts to the local
+// backend.
+func (s *AudioService) SetLavalinkBackend(backend *LavalinkBackend) {
+	if backend != nil {
+		backend.SetGlobalDispatch(s.dispatchGlobal)
+	}
+	s.lavalink = backend
+}
+
+// backend returns the Backend the next operation for guildID should use.
+// Every guild shares whichever backend is currently configured - Lavalink
+// doesn't support per-guild backend selection, since a node pool is either
+// available or it isn't.
+func (s *AudioService) backend() Backend {
+	if s.lavalink != nil {
+		return s.lavalink
+	}
+	return &localBackend{s}
 }
 
-// NewAudioService creates a new audio service
-func NewAudioService(session *discordgo.Session, log *logger.Logger) *AudioService {
-	return &AudioService{
+// NewAudioService creates a new audio service. idleTimeout and aloneTimeout
+// configure the background idle watcher (see idle_watcher.go);
+// defaultAutoLeave is whether that watcher is enabled for a guild that has
+// never overridden it with /autoleave.
+func NewAudioService(session *discordgo.Session, idleTimeout, aloneTimeout time.Duration, defaultAutoLeave bool, log *logger.Logger) *AudioService {
+	s := &AudioService{
 		session:          session,
 		logger:           log,
-		voiceConnections: make(map[string]*VoiceConnection),
-		audioPlayers:     make(map[string]*AudioPlayer),
-		tracklists:       make(map[string]*entities.Tracklist),
+		encoderPool:      NewEncoderPool(defaultMaxConcurrentEncodes),
+		idleTimeout:      idleTimeout,
+		aloneTimeout:     aloneTimeout,
+		defaultAutoLeave: defaultAutoLeave,
+		watchers:         make(map[string]*guildWatcher),
+		autoLeaveEnabled: make(map[string]bool),
+		globalHandlers:   make(map[TrackEventType][]handlerEntry),
+		metrics:          metrics.NoopRecorder{},
 	}
+	for i := range s.shards {
+		s.shards[i] = newShard()
+	}
+	return s
 }
 
-// ConnectToChannel connects to a voice channel
-func (s *AudioService) ConnectToChannel(guildID, channelID string) error {
+// AddGlobalHandler registers handler to run whenever event fires on any
+// guild's AudioPlayer, present or future - unlike AudioPlayer.AddHandler,
+// which only covers the one player it's called on. The returned unsubscribe
+// func removes exactly this handler.
+func (s *AudioService) AddGlobalHandler(event TrackEventType, handler TrackEventHandler) (unsubscribe func()) {
+	id := s.globalHandlerSeq.Add(1)
+
+	s.globalHandlersMu.Lock()
+	s.globalHandlers[event] = append(s.globalHandlers[event], handlerEntry{id: id, handler: handler})
+	s.globalHandlersMu.Unlock()
+
+	return func() {
+		s.globalHandlersMu.Lock()
+		defer s.globalHandlersMu.Unlock()
+		entries := s.globalHandlers[event]
+		for i, e := range entries {
+			if e.id == id {
+				s.globalHandlers[event] = append(entries[:i:i], entries[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// dispatchGlobal runs every handler registered via AddGlobalHandler for
+// event. Passed to each AudioPlayer as its globalDispatch, so it always runs
+// on that player's own dispatch fan-out goroutine, never blocking playback.
+func (s *AudioService) dispatchGlobal(event TrackEventType, ctx TrackContext) {
+	s.globalHandlersMu.RLock()
+	entries := append([]handlerEntry(nil), s.globalHandlers[event]...)
+	s.globalHandlersMu.RUnlock()
+
+	for _, e := range entries {
+		e.handler(ctx)
+	}
+}
+
+// SetActivityHook registers the callback invoked after ConnectToChannel and
+// after each song starts or finishes playing.
+func (s *AudioService) SetActivityHook(fn func(guildID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onActivity = fn
+}
+
+// SetCleanupHook registers the callback invoked once a guild's voice
+// connection and player are torn down for good (DisconnectFromGuild,
+// Cleanup, CleanupAll).
+func (s *AudioService) SetCleanupHook(fn func(guildID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onCleanup = fn
+}
+
+// SetMetricsRecorder wires a metrics.Recorder into the service and every
+// AudioPlayer it creates from here on (players created before this call are
+// unaffected, mirroring SetVoteConfig).
+func (s *AudioService) SetMetricsRecorder(recorder metrics.Recorder) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.metrics = recorder
+}
+
+// ConnectToChannel connects to a voice channel, via whichever Backend is
+// currently configured (see SetLavalinkBackend).
+func (s *AudioService) ConnectToChannel(guildID, channelID string) error {
+	return s.backend().Connect(guildID, channelID)
+}
+
+// connectLocal is localBackend's Connect: the original direct-voice-UDP
+// connect path.
+func (s *AudioService) connectLocal(guildID, channelID string) error {
+	sh := s.shardFor(guildID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
 	s.logger.WithFields(map[string]interface{}{
 		"guild":   guildID,
@@ -49,10 +238,10 @@ func (s *AudioService) ConnectToChannel(guildID, channelID string) error {
 	}).Info("Connecting to voice channel...")
 
 	// Get or create voice connection
-	vc, exists := s.voiceConnections[guildID]
+	vc, exists := sh.voiceConnections[guildID]
 	if !exists {
 		vc = NewVoiceConnection(guildID, s.logger)
-		s.voiceConnections[guildID] = vc
+		sh.voiceConnections[guildID] = vc
 	}
 
 	// Connect
@@ -61,111 +250,241 @@ func (s *AudioService) ConnectToChannel(guildID, channelID string) error {
 	}
 
 	// Initialize audio player if not exists
-	if _, exists := s.audioPlayers[guildID]; !exists {
+	if _, exists := sh.audioPlayers[guildID]; !exists {
 		player := NewAudioPlayer(guildID, vc, s.logger)
-		s.audioPlayers[guildID] = player
+		player.globalDispatch = s.dispatchGlobal
+		player.encoder.pool = s.encoderPool
+		player.encoder.metrics = s.metrics
+		player.SetVoteConfig(s.voteThresholdRatio, s.voteMinVotes, s.voteTTL)
+		vc.SetOnReconnect(player.onVoiceReconnect)
+		if s.onActivity != nil {
+			player.AddHandler(TrackEventPositionTick, func(TrackContext) { s.onActivity(guildID) })
+			player.AddHandler(TrackEventEnd, func(TrackContext) { s.onActivity(guildID) })
+			player.AddHandler(TrackEventError, func(TrackContext) { s.onActivity(guildID) })
+		}
+		sh.audioPlayers[guildID] = player
+		s.metrics.IncActiveVoiceConnections()
 	}
 
 	// Initialize tracklist if not exists
-	if _, exists := s.tracklists[guildID]; !exists {
+	if _, exists := sh.tracklists[guildID]; !exists {
 		tracklist := entities.NewTracklist(guildID)
-		s.tracklists[guildID] = tracklist
+		sh.tracklists[guildID] = tracklist
+	}
+
+	if s.onActivity != nil {
+		s.onActivity(guildID)
 	}
 
+	s.startIdleWatcher(guildID)
+
 	return nil
 }
 
-// DisconnectFromGuild disconnects from a guild's voice channel
+// DisconnectFromGuild disconnects from a guild's voice channel, via
+// whichever Backend is currently configured.
 func (s *AudioService) DisconnectFromGuild(guildID string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return s.backend().Disconnect(guildID)
+}
+
+// disconnectLocal is localBackend's Disconnect.
+func (s *AudioService) disconnectLocal(guildID string) error {
+	sh := s.shardFor(guildID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
 	s.logger.WithField("guild", guildID).Info("Disconnecting from guild...")
 
 	// Stop playback first
-	if player, exists := s.audioPlayers[guildID]; exists {
+	if player, exists := sh.audioPlayers[guildID]; exists {
 		if player.IsPlaying() {
-			if err := player.Stop(); err != nil {
+			if err := player.stop(); err != nil {
 				s.logger.WithError(err).Warn("Failed to stop player")
 			}
 		}
 		player.Cleanup()
-		delete(s.audioPlayers, guildID)
+		delete(sh.audioPlayers, guildID)
+		s.metrics.DecActiveVoiceConnections()
 	}
 
 	// Disconnect voice
-	if vc, exists := s.voiceConnections[guildID]; exists {
+	if vc, exists := sh.voiceConnections[guildID]; exists {
 		if err := vc.Disconnect(); err != nil {
 			s.logger.WithError(err).Warn("Failed to disconnect voice")
 		}
-		delete(s.voiceConnections, guildID)
+		delete(sh.voiceConnections, guildID)
 	}
 
 	// Clear tracklist
-	if tracklist, exists := s.tracklists[guildID]; exists {
+	if tracklist, exists := sh.tracklists[guildID]; exists {
 		tracklist.Clear()
 	}
 
+	s.stopIdleWatcher(guildID)
+
+	if s.onCleanup != nil {
+		s.onCleanup(guildID)
+	}
+
 	return nil
 }
 
-// PlaySong starts playing a song
-func (s *AudioService) PlaySong(guildID string, song *entities.Song, callback PlaybackCallback) error {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+// PlaySong starts playing a song, via whichever Backend is currently
+// configured. Completion, errors, and progress are reported via the
+// player's TrackEvent bus (see AudioPlayer.AddHandler), not a callback
+// passed here.
+func (s *AudioService) PlaySong(guildID string, song *entities.Song) error {
+	return s.backend().Play(guildID, song)
+}
+
+// playLocal is localBackend's Play.
+func (s *AudioService) playLocal(guildID string, song *entities.Song) error {
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
 	}
 
-	return player.Play(song, callback)
+	return player.Play(context.Background(), song)
 }
 
-// StopPlayback stops current playback
+// StopPlayback stops current playback, via whichever Backend is currently
+// configured.
 func (s *AudioService) StopPlayback(guildID string) error {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+	return s.backend().Stop(guildID)
+}
+
+// stopLocal is localBackend's Stop.
+func (s *AudioService) stopLocal(guildID string) error {
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
 	}
 
-	return player.Stop()
+	return player.stop()
 }
 
-// PausePlayback pauses current playback
+// PausePlayback pauses current playback, via whichever Backend is
+// currently configured.
 func (s *AudioService) PausePlayback(guildID string) error {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+	return s.backend().Pause(guildID)
+}
+
+// pauseLocal is localBackend's Pause.
+func (s *AudioService) pauseLocal(guildID string) error {
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
 	}
 
-	return player.Pause()
+	return player.Pause(context.Background())
 }
 
-// ResumePlayback resumes playback
+// ResumePlayback resumes playback, via whichever Backend is currently
+// configured.
 func (s *AudioService) ResumePlayback(guildID string) error {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+	return s.backend().Resume(guildID)
+}
+
+// resumeLocal is localBackend's Resume.
+func (s *AudioService) resumeLocal(guildID string) error {
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
+	}
+
+	return player.Resume(context.Background())
+}
+
+// Seek restarts the guild's current song at position, via whichever Backend
+// is currently configured.
+func (s *AudioService) Seek(guildID string, position time.Duration) error {
+	return s.backend().Seek(guildID, position)
+}
+
+// seekLocal is localBackend's Seek: restarting the stream at position is
+// the same re-encode-from-offset path SetFilters uses to keep the effect
+// chain applied across a restart.
+func (s *AudioService) seekLocal(guildID string, position time.Duration) error {
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
+	}
+
+	song := player.GetCurrentSong()
+	if song == nil {
+		return ErrPlayerNotPlaying
+	}
+
+	return player.PlayFrom(context.Background(), song, position)
+}
+
+// SetVolume sets the guild's playback volume (0-100), via whichever Backend
+// is currently configured.
+func (s *AudioService) SetVolume(guildID string, volume int) error {
+	return s.backend().SetVolume(guildID, volume)
+}
+
+// setVolumeLocal is localBackend's SetVolume.
+func (s *AudioService) setVolumeLocal(guildID string, volume int) error {
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
 	}
 
-	return player.Resume()
+	player.SetVolume(volume)
+	return nil
+}
+
+// ForwardVoiceServerUpdate relays a Discord VOICE_SERVER_UPDATE to the
+// Lavalink backend, if one is configured; a no-op otherwise, since the
+// local backend establishes its own voice connection directly.
+func (s *AudioService) ForwardVoiceServerUpdate(guildID, token, endpoint string) error {
+	if s.lavalink == nil {
+		return nil
+	}
+	return s.lavalink.ForwardVoiceServerUpdate(guildID, token, endpoint)
+}
+
+// ForwardVoiceStateUpdate relays a Discord VOICE_STATE_UPDATE for the bot's
+// own session to the Lavalink backend, if one is configured; a no-op
+// otherwise.
+func (s *AudioService) ForwardVoiceStateUpdate(guildID, sessionID, channelID string) error {
+	if s.lavalink == nil {
+		return nil
+	}
+	return s.lavalink.ForwardVoiceStateUpdate(guildID, sessionID, channelID)
 }
 
 // IsPlaying returns true if audio is playing in the guild
 func (s *AudioService) IsPlaying(guildID string) bool {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return false
@@ -176,9 +495,10 @@ func (s *AudioService) IsPlaying(guildID string) bool {
 
 // IsPaused returns true if playback is paused in the guild
 func (s *AudioService) IsPaused(guildID string) bool {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return false
@@ -189,9 +509,10 @@ func (s *AudioService) IsPaused(guildID string) bool {
 
 // IsConnected returns true if connected to voice in the guild
 func (s *AudioService) IsConnected(guildID string) bool {
-	s.mu.RLock()
-	vc, exists := s.voiceConnections[guildID]
-	s.mu.RUnlock()
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	vc, exists := sh.voiceConnections[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return false
@@ -202,9 +523,10 @@ func (s *AudioService) IsConnected(guildID string) bool {
 
 // GetCurrentSong returns the currently playing song
 func (s *AudioService) GetCurrentSong(guildID string) *entities.Song {
-	s.mu.RLock()
-	player, exists := s.audioPlayers[guildID]
-	s.mu.RUnlock()
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	player, exists := sh.audioPlayers[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return nil
@@ -215,42 +537,46 @@ func (s *AudioService) GetCurrentSong(guildID string) *entities.Song {
 
 // GetPlayer returns the audio player for a guild
 func (s *AudioService) GetPlayer(guildID string) *AudioPlayer {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.audioPlayers[guildID]
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.audioPlayers[guildID]
 }
 
 // GetTracklist returns the tracklist for a guild
 func (s *AudioService) GetTracklist(guildID string) *entities.Tracklist {
+	sh := s.shardFor(guildID)
+
 	// First try with read lock
-	s.mu.RLock()
-	tracklist, exists := s.tracklists[guildID]
-	s.mu.RUnlock()
+	sh.mu.RLock()
+	tracklist, exists := sh.tracklists[guildID]
+	sh.mu.RUnlock()
 
 	if exists {
 		return tracklist
 	}
 
 	// Need to create - acquire write lock
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
 	// Double-check after acquiring write lock
-	if tracklist, exists = s.tracklists[guildID]; exists {
+	if tracklist, exists = sh.tracklists[guildID]; exists {
 		return tracklist
 	}
 
 	// Create new tracklist
 	tracklist = entities.NewTracklist(guildID)
-	s.tracklists[guildID] = tracklist
+	sh.tracklists[guildID] = tracklist
 	return tracklist
 }
 
 // GetVoiceChannelID returns the current voice channel ID for a guild
 func (s *AudioService) GetVoiceChannelID(guildID string) string {
-	s.mu.RLock()
-	vc, exists := s.voiceConnections[guildID]
-	s.mu.RUnlock()
+	sh := s.shardFor(guildID)
+	sh.mu.RLock()
+	vc, exists := sh.voiceConnections[guildID]
+	sh.mu.RUnlock()
 
 	if !exists {
 		return ""
@@ -259,93 +585,141 @@ func (s *AudioService) GetVoiceChannelID(guildID string) string {
 	return vc.GetChannelID()
 }
 
-// Cleanup performs cleanup for all guilds
-func (s *AudioService) Cleanup() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// CountNonBotListeners returns how many non-bot users are in the guild's
+// current voice channel alongside the bot. Used both to auto-disconnect an
+// empty channel and to compute the required vote-skip threshold.
+func (s *AudioService) CountNonBotListeners(guildID string) (int, error) {
+	channelID := s.GetVoiceChannelID(guildID)
+	if channelID == "" {
+		return 0, ErrGuildNotFound
+	}
 
-	s.logger.Info("Cleaning up audio service...")
+	guild, err := s.session.State.Guild(guildID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get guild state: %w", err)
+	}
 
-	// Stop all players
-	for guildID, player := range s.audioPlayers {
-		if player.IsPlaying() {
-			player.Stop()
+	count := 0
+	for _, vs := range guild.VoiceStates {
+		if vs.ChannelID != channelID || vs.UserID == s.session.State.User.ID {
+			continue
+		}
+
+		member, err := s.session.GuildMember(guildID, vs.UserID)
+		if err != nil {
+			continue
+		}
+		if member.User != nil && !member.User.Bot {
+			count++
 		}
-		player.Cleanup()
-		s.logger.WithField("guild", guildID).Debug("Cleaned up audio player")
 	}
 
-	// Disconnect all voice connections
-	for guildID, vc := range s.voiceConnections {
-		if vc.IsConnected() {
-			vc.Disconnect()
+	return count, nil
+}
+
+// Cleanup performs cleanup for all guilds, shard by shard so one shard's
+// cleanup never blocks operations on another.
+func (s *AudioService) Cleanup() {
+	s.logger.Info("Cleaning up audio service...")
+
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+
+		for guildID, player := range sh.audioPlayers {
+			if player.IsPlaying() {
+				player.stop()
+			}
+			player.Cleanup()
+			s.logger.WithField("guild", guildID).Debug("Cleaned up audio player")
+		}
+
+		for guildID, vc := range sh.voiceConnections {
+			if vc.IsConnected() {
+				vc.Disconnect()
+			}
+			s.stopIdleWatcher(guildID)
+			s.logger.WithField("guild", guildID).Debug("Disconnected voice connection")
 		}
-		s.logger.WithField("guild", guildID).Debug("Disconnected voice connection")
+
+		sh.mu.Unlock()
 	}
 
 	s.logger.Info("✅ Audio service cleanup complete")
 }
 
-// CleanupAll disconnects all voice connections and cleans up all resources
+// CleanupAll disconnects all voice connections and cleans up all resources,
+// shard by shard so one shard's cleanup never blocks operations on another.
 func (s *AudioService) CleanupAll() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	s.logger.Info("Cleaning up all audio resources...")
 
-	// Stop all players
-	for guildID, player := range s.audioPlayers {
-		if player.IsPlaying() {
-			if err := player.Stop(); err != nil {
-				s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to stop player")
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+
+		for guildID, player := range sh.audioPlayers {
+			if player.IsPlaying() {
+				if err := player.stop(); err != nil {
+					s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to stop player")
+				}
 			}
+			player.Cleanup()
 		}
-		player.Cleanup()
-	}
-	s.audioPlayers = make(map[string]*AudioPlayer)
+		sh.audioPlayers = make(map[string]*AudioPlayer)
 
-	// Disconnect all voice connections
-	for guildID, vc := range s.voiceConnections {
-		if err := vc.Disconnect(); err != nil {
-			s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to disconnect voice")
+		for guildID, vc := range sh.voiceConnections {
+			if err := vc.Disconnect(); err != nil {
+				s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to disconnect voice")
+			}
+			s.stopIdleWatcher(guildID)
 		}
-	}
-	s.voiceConnections = make(map[string]*VoiceConnection)
+		sh.voiceConnections = make(map[string]*VoiceConnection)
 
-	// Clear all tracklists
-	for _, tracklist := range s.tracklists {
-		tracklist.Clear()
+		for _, tracklist := range sh.tracklists {
+			tracklist.Clear()
+		}
+		sh.tracklists = make(map[string]*entities.Tracklist)
+
+		sh.mu.Unlock()
 	}
-	s.tracklists = make(map[string]*entities.Tracklist)
 
 	s.logger.Info("✅ All audio resources cleaned up")
 }
 
-// GetStats returns statistics about the audio service
+// GetStats returns statistics about the audio service, aggregated across
+// every shard.
 func (s *AudioService) GetStats() map[string]interface{} {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
+	totalGuilds := 0
 	activeConnections := 0
 	activePlayers := 0
+	totalConnections := 0
+	totalPlayers := 0
+
+	for _, sh := range s.shards {
+		sh.mu.RLock()
 
-	for _, vc := range s.voiceConnections {
-		if vc.IsConnected() {
-			activeConnections++
+		totalGuilds += len(sh.tracklists)
+		totalConnections += len(sh.voiceConnections)
+		totalPlayers += len(sh.audioPlayers)
+
+		for _, vc := range sh.voiceConnections {
+			if vc.IsConnected() {
+				activeConnections++
+			}
 		}
-	}
 
-	for _, player := range s.audioPlayers {
-		if player.IsPlaying() {
-			activePlayers++
+		for _, player := range sh.audioPlayers {
+			if player.IsPlaying() {
+				activePlayers++
+			}
 		}
+
+		sh.mu.RUnlock()
 	}
 
 	return map[string]interface{}{
-		"total_guilds":       len(s.tracklists),
+		"total_guilds":       totalGuilds,
 		"active_connections": activeConnections,
 		"active_players":     activePlayers,
-		"total_connections":  len(s.voiceConnections),
-		"total_players":      len(s.audioPlayers),
+		"total_connections":  totalConnections,
+		"total_players":      totalPlayers,
 	}
 }
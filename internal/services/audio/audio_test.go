@@ -1,6 +1,7 @@
 package audio_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -101,20 +102,22 @@ func TestAudioPlayerStates(t *testing.T) {
 	vc := audio.NewVoiceConnection("test-guild-123", log)
 	player := audio.NewAudioPlayer("test-guild-123", vc, log)
 
+	ctx := context.Background()
+
 	// Test stop when not playing
-	err := player.Stop()
+	err := player.Stop(ctx)
 	if err != audio.ErrPlayerNotPlaying {
 		t.Errorf("Expected ErrPlayerNotPlaying, got %v", err)
 	}
 
 	// Test pause when not playing
-	err = player.Pause()
+	err = player.Pause(ctx)
 	if err != audio.ErrPlayerNotPlaying {
 		t.Errorf("Expected ErrPlayerNotPlaying, got %v", err)
 	}
 
 	// Test resume when not playing
-	err = player.Resume()
+	err = player.Resume(ctx)
 	if err != audio.ErrPlayerNotPlaying {
 		t.Errorf("Expected ErrPlayerNotPlaying, got %v", err)
 	}
@@ -135,7 +138,7 @@ func TestAudioPlayerPlayRequiresReadySong(t *testing.T) {
 	)
 
 	// Try to play song that's not ready
-	err := player.Play(song, nil)
+	err := player.Play(context.Background(), song)
 	if err == nil {
 		t.Error("Expected error when playing non-ready song")
 	}
@@ -162,12 +165,23 @@ func TestAudioPlayerPlayRequiresConnection(t *testing.T) {
 	song.MarkReady(metadata, "https://stream.example.com/audio.m3u8")
 
 	// Try to play without voice connection (should fail)
-	err := player.Play(song, nil)
+	err := player.Play(context.Background(), song)
 	if err != audio.ErrNoVoiceConnection {
 		t.Errorf("Expected ErrNoVoiceConnection, got %v", err)
 	}
 }
 
+func TestAudioPlayerSeekRequiresPlaying(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	vc := audio.NewVoiceConnection("test-guild-123", log)
+	player := audio.NewAudioPlayer("test-guild-123", vc, log)
+
+	if err := player.Seek(10 * time.Second); err != audio.ErrPlayerNotPlaying {
+		t.Errorf("Expected ErrPlayerNotPlaying, got %v", err)
+	}
+}
+
 func TestAudioPlayerCleanup(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
 
@@ -183,7 +197,7 @@ func TestAudioServiceCreation(t *testing.T) {
 
 	// Note: Cannot create real Discord session without token
 	// In real tests, we'd use a mock
-	service := audio.NewAudioService(nil, log)
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
 
 	if service == nil {
 		t.Fatal("Expected service to be created")
@@ -201,7 +215,7 @@ func TestAudioServiceCreation(t *testing.T) {
 
 func TestAudioServiceStates(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
-	service := audio.NewAudioService(nil, log)
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
 
 	guildID := "test-guild-123"
 
@@ -225,7 +239,7 @@ func TestAudioServiceStates(t *testing.T) {
 
 func TestAudioServiceTracklist(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
-	service := audio.NewAudioService(nil, log)
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
 
 	guildID := "test-guild-123"
 
@@ -248,7 +262,7 @@ func TestAudioServiceTracklist(t *testing.T) {
 
 func TestAudioServiceCleanup(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
-	service := audio.NewAudioService(nil, log)
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
 
 	// Cleanup should not panic
 	service.Cleanup()
@@ -262,7 +276,7 @@ func TestAudioServiceCleanup(t *testing.T) {
 
 func TestAudioServiceConcurrentAccess(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
-	service := audio.NewAudioService(nil, log)
+	service := audio.NewAudioService(nil, 5*time.Minute, 2*time.Minute, false, log)
 
 	done := make(chan bool, 100)
 
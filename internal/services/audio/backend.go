@@ -0,0 +1,55 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// Backend is implemented by anything AudioService can hand a guild's voice
+// connection and playback off to. localBackend, the default, plays audio
+// with this process's own yt-dlp/FFmpeg pipeline (encoder.go) over a direct
+// Discord voice UDP connection (voice_connection.go). LavalinkBackend
+// instead forwards everything to a pool of Lavalink v4 nodes (lavalink.go),
+// offloading Opus encoding to dedicated hardware - see AudioService.backend.
+type Backend interface {
+	// Connect opens the guild's voice connection to channelID.
+	Connect(guildID, channelID string) error
+	// Disconnect tears down the guild's voice connection and any playback
+	// state associated with it.
+	Disconnect(guildID string) error
+	// Play starts playing song, replacing whatever is currently playing.
+	Play(guildID string, song *entities.Song) error
+	Pause(guildID string) error
+	Resume(guildID string) error
+	Stop(guildID string) error
+	// Seek restarts the current song at position.
+	Seek(guildID string, position time.Duration) error
+	// SetVolume sets playback volume as a 0-100 percentage.
+	SetVolume(guildID string, volume int) error
+}
+
+// localBackend adapts AudioService's own voice/playback pipeline to the
+// Backend interface. Its methods just forward to the *Local counterparts
+// still defined on AudioService, so the local path didn't need rewriting
+// around shard-held state to fit this interface.
+type localBackend struct {
+	svc *AudioService
+}
+
+func (b *localBackend) Connect(guildID, channelID string) error {
+	return b.svc.connectLocal(guildID, channelID)
+}
+func (b *localBackend) Disconnect(guildID string) error { return b.svc.disconnectLocal(guildID) }
+func (b *localBackend) Play(guildID string, song *entities.Song) error {
+	return b.svc.playLocal(guildID, song)
+}
+func (b *localBackend) Pause(guildID string) error  { return b.svc.pauseLocal(guildID) }
+func (b *localBackend) Resume(guildID string) error { return b.svc.resumeLocal(guildID) }
+func (b *localBackend) Stop(guildID string) error   { return b.svc.stopLocal(guildID) }
+func (b *localBackend) Seek(guildID string, position time.Duration) error {
+	return b.svc.seekLocal(guildID, position)
+}
+func (b *localBackend) SetVolume(guildID string, volume int) error {
+	return b.svc.setVolumeLocal(guildID, volume)
+}
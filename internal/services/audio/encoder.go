@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/jonas747/ogg"
+	cmdexec "github.com/vuongmanhnghia/discord-music-bot/internal/exec"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/metrics"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/utils"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
@@ -19,24 +23,177 @@ var (
 	ErrAlreadyPlaying = errors.New("already playing")
 )
 
+// FrameSink lets encodeWithYtDlpPipe pace itself off actual playback demand
+// instead of a fixed wall-clock sleep, so it doesn't keep decoding ahead of
+// a sink that isn't draining frames (paused, or still waiting on a voice
+// reconnect). AudioPlayer implements this via its own pause/reconnect state.
+type FrameSink interface {
+	// NextFrameDeadline returns when the sink will next be ready to consume
+	// a frame - time.Now() (or earlier) while actively draining frames, or a
+	// time far in the future while paused/unavailable, so the encoder knows
+	// to stop decoding ahead rather than just filling frameChannel to its
+	// full BufferSize regardless of demand.
+	NextFrameDeadline() time.Time
+}
+
+// encodeLowWaterMark is how much buffered audio encodeWithYtDlpPipe tries to
+// stay ahead by. Once frameChannel holds this much, the encoder stops
+// decoding further ahead unless sink reports it's actually about to need a
+// frame - see encodeLowWaterPollInterval.
+const encodeLowWaterMark = 2 * time.Second
+
+// encodeLowWaterPollInterval is how often encodeWithYtDlpPipe rechecks
+// sink.NextFrameDeadline() once it's hit encodeLowWaterMark, mirroring
+// AudioPlayer.playbackLoop's own pause-polling cadence.
+const encodeLowWaterPollInterval = 100 * time.Millisecond
+
 // AudioEncoder handles encoding audio streams for Discord
 type AudioEncoder struct {
 	logger *logger.Logger
+
+	// pool, if set, caps how many of this encoder's yt-dlp/FFmpeg pipelines
+	// may run at once across the whole process - see EncoderPool. Wired by
+	// AudioService so the limit applies across every guild, not per guild.
+	// nil means unlimited, which is what standalone/test encoders get.
+	pool *EncoderPool
+
+	// loudnessCache caches each track's measured integrated loudness (LUFS),
+	// keyed by stream URL, so repeat plays and the same track queued by
+	// other guilds skip the ffmpeg analysis pass. See NormalizationGain.
+	loudnessCache *utils.Cache[string, float64]
+
+	// executor runs the ffmpeg loudnorm analysis pass (see
+	// analyzeIntegratedLoudness). The yt-dlp|FFmpeg encode pipeline itself
+	// keeps shelling out directly via exec.Command, since it holds two live
+	// piped processes rather than waiting on a single command's output.
+	executor cmdexec.CommandExecutor
+
+	// metrics receives encode frame throughput/stall observations from
+	// encodeWithYtDlpPipe's frame loop. Defaults to metrics.NoopRecorder so
+	// call sites never need a nil check. Wired by AudioService alongside pool.
+	metrics metrics.Recorder
+
+	// sink paces encodeWithYtDlpPipe's frame loop off actual playback demand
+	// instead of a fixed wall-clock sleep - see FrameSink. nil (the default
+	// for a standalone/test encoder) disables low-water pacing entirely, so
+	// the loop just runs as fast as frameChannel's backpressure allows.
+	sink FrameSink
 }
 
+// loudnessCacheSize and loudnessCacheTTL bound NewAudioEncoder's
+// loudnessCache, mirroring youtube.Service's infoCache/streamCache sizing.
+const (
+	loudnessCacheSize = 500
+	loudnessCacheTTL  = 6 * time.Hour
+)
+
 // NewAudioEncoder creates a new audio encoder
 func NewAudioEncoder(log *logger.Logger) *AudioEncoder {
 	return &AudioEncoder{
-		logger: log,
+		logger:        log,
+		loudnessCache: utils.NewCache[string, float64](loudnessCacheSize, loudnessCacheTTL),
+		executor:      cmdexec.OSExecutor{},
+		metrics:       metrics.NoopRecorder{},
 	}
 }
 
+// SetCommandExecutor overrides how the encoder runs its ffmpeg loudness
+// analysis pass, for injecting a testutils.MockCommandExecutor in unit
+// tests. NewAudioEncoder wires up the real cmdexec.OSExecutor by default.
+func (e *AudioEncoder) SetCommandExecutor(executor cmdexec.CommandExecutor) {
+	e.executor = executor
+}
+
+// SetFrameSink wires the FrameSink encodeWithYtDlpPipe paces its frame loop
+// against. AudioPlayer sets this to itself in NewAudioPlayer; a standalone/
+// test encoder left unset just skips low-water pacing.
+func (e *AudioEncoder) SetFrameSink(sink FrameSink) {
+	e.sink = sink
+}
+
+// defaultMaxConcurrentEncodes caps how many yt-dlp/FFmpeg pipelines
+// AudioService's shared EncoderPool allows to run at once. Each pipeline is
+// two OS processes plus real CPU/memory, so this bounds the whole bot's
+// encoding footprint regardless of how many guilds are playing at once.
+const defaultMaxConcurrentEncodes = 64
+
+// EncoderPool caps how many AudioEncoder pipelines may run concurrently
+// process-wide, via a buffered channel used as a counting semaphore. Without
+// it, one AudioEncoder per guild would spawn yt-dlp/FFmpeg unboundedly as
+// guild count grows.
+type EncoderPool struct {
+	sem chan struct{}
+}
+
+// NewEncoderPool creates a pool allowing at most max concurrent encodes.
+func NewEncoderPool(max int) *EncoderPool {
+	return &EncoderPool{sem: make(chan struct{}, max)}
+}
+
+// acquire blocks until a slot is free.
+func (p *EncoderPool) acquire() {
+	p.sem <- struct{}{}
+}
+
+// release frees the slot acquire took.
+func (p *EncoderPool) release() {
+	<-p.sem
+}
+
 // EncodeOptions contains options for encoding
 type EncodeOptions struct {
-	Volume      int    // 0-100, default 100
-	Bitrate     int    // in kbps, default 128
-	Application string // audio, voip, or lowdelay
-	BufferSize  int    // buffer size in samples
+	Volume      int           // 0-100, default 100
+	Bitrate     int           // in kbps, default 128
+	Application string        // audio, voip, or lowdelay
+	BufferSize  int           // buffer size in samples
+	StartOffset time.Duration // seek position, e.g. from a "?t=90s" link
+	Filters     *FilterSpec   // DSP effect chain to apply via ffmpeg -af, if any
+
+	// Duration is the song's total known length, used to place FadeOutMs
+	// relative to the end of the track. 0 (unknown) disables the fade-out.
+	Duration time.Duration
+	// FadeInMs linearly fades in from silence over this many ms, starting
+	// the instant this encode's stream begins (i.e. at StartOffset, not the
+	// top of the track). 0 disables it.
+	FadeInMs int
+	// FadeOutMs linearly fades to silence over this many ms, ending when
+	// Duration is reached. 0, or an unknown Duration, disables it. See
+	// AudioPlayer.SetCrossfade.
+	FadeOutMs int
+
+	// Backend selects how EncodeStream produces Opus frames. The zero value,
+	// EncoderBackendYtDlpPipe, keeps the existing yt-dlp/FFmpeg pipeline -
+	// callers that never set this are unaffected. See EncoderBackendNative.
+	Backend EncoderBackend
+
+	// Normalization applies a precomputed ReplayGain/EBU-R128-style volume
+	// adjustment, if enabled - see AudioEncoder.NormalizationGain and
+	// DefaultNormalizationConfig. nil disables it, same as Enabled: false.
+	Normalization *NormalizationConfig
+}
+
+// buildFadeStages returns the ffmpeg "afade" stages for o's configured
+// fade-in/fade-out, if any. Times are relative to the stream ffmpeg actually
+// produces - StartOffset is already consumed via -ss - so "st=0" is the
+// instant this encode's audio starts, not the top of the track.
+func (o *EncodeOptions) buildFadeStages() []string {
+	var stages []string
+
+	if o.FadeInMs > 0 {
+		stages = append(stages, fmt.Sprintf("afade=t=in:st=0:d=%.3f", float64(o.FadeInMs)/1000))
+	}
+
+	if o.FadeOutMs > 0 && o.Duration > o.StartOffset {
+		remaining := o.Duration - o.StartOffset
+		fadeOutMs := o.FadeOutMs
+		if time.Duration(fadeOutMs)*time.Millisecond > remaining {
+			fadeOutMs = int(remaining / time.Millisecond)
+		}
+		fadeOutStart := remaining - time.Duration(fadeOutMs)*time.Millisecond
+		stages = append(stages, fmt.Sprintf("afade=t=out:st=%.3f:d=%.3f", fadeOutStart.Seconds(), float64(fadeOutMs)/1000))
+	}
+
+	return stages
 }
 
 // DefaultEncodeOptions returns default encoding options
@@ -61,8 +218,13 @@ func (e *AudioEncoder) EncodeStream(streamURL string, options *EncodeOptions) (<
 	frameChannel := make(chan []byte, options.BufferSize)
 	errorChannel := make(chan error, 1)
 
-	// Start encoding in goroutine - use yt-dlp pipe approach to bypass 403 errors
-	go e.encodeWithYtDlpPipe(streamURL, options, frameChannel, errorChannel)
+	switch options.Backend {
+	case EncoderBackendNative:
+		go e.encodeNative(streamURL, options, frameChannel, errorChannel)
+	default:
+		// Start encoding in goroutine - use yt-dlp pipe approach to bypass 403 errors
+		go e.encodeWithYtDlpPipe(streamURL, options, frameChannel, errorChannel)
+	}
 
 	return frameChannel, errorChannel, nil
 }
@@ -73,6 +235,11 @@ func (e *AudioEncoder) encodeWithYtDlpPipe(streamURL string, options *EncodeOpti
 	defer close(frameChannel)
 	defer close(errorChannel)
 
+	if e.pool != nil {
+		e.pool.acquire()
+		defer e.pool.release()
+	}
+
 	e.logger.WithField("url", streamURL[:min(80, len(streamURL))]).Info("📻 Starting yt-dlp -> FFmpeg piped encoding...")
 
 	// Build yt-dlp | FFmpeg pipeline
@@ -115,13 +282,28 @@ func (e *AudioEncoder) encodeWithYtDlpPipe(streamURL string, options *EncodeOpti
 	// Start FFmpeg process to encode to OGG/Opus
 	// FFmpeg reads from stdin (pipe from yt-dlp) and outputs to stdout
 	// Using similar args to TwiN/discord-music-bot
-	ffmpegArgs := []string{
+	ffmpegArgs := []string{}
+	if options.StartOffset > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-ss", fmt.Sprintf("%.3f", options.StartOffset.Seconds()))
+	}
+	ffmpegArgs = append(ffmpegArgs,
 		"-i", "pipe:0", // Read from stdin
 		"-reconnect", "1",
 		"-reconnect_at_eof", "1",
 		"-reconnect_streamed", "1",
 		"-reconnect_delay_max", "2",
 		"-map", "0:a",
+	)
+	var afStages []string
+	if graph := options.Filters.BuildFilterGraph(); graph != "" {
+		afStages = append(afStages, graph)
+	}
+	afStages = append(afStages, options.buildFadeStages()...)
+	afStages = append(afStages, options.buildNormalizationStage()...)
+	if len(afStages) > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-af", strings.Join(afStages, ","))
+	}
+	ffmpegArgs = append(ffmpegArgs,
 		"-acodec", "libopus",
 		"-f", "ogg",
 		"-compression_level", "5",
@@ -132,7 +314,7 @@ func (e *AudioEncoder) encodeWithYtDlpPipe(streamURL string, options *EncodeOpti
 		"-frame_duration", "20",
 		"-loglevel", "error",
 		"pipe:1", // Output to stdout
-	}
+	)
 
 	ffmpegCmd := exec.Command("ffmpeg", ffmpegArgs...)
 	ffmpegCmd.Stdin = ytDlpStdout // Connect yt-dlp stdout to FFmpeg stdin
@@ -192,10 +374,10 @@ func (e *AudioEncoder) encodeWithYtDlpPipe(streamURL string, options *EncodeOpti
 	frameCount := 0
 	lastLogTime := time.Now()
 
-	// Rate limiting: Opus frames are 20ms each, so 50 frames/second
-	// We need to throttle encoding to match playback rate
-	frameInterval := 20 * time.Millisecond
-	startTime := time.Now()
+	// lowWaterFrames is encodeLowWaterMark expressed in frames (Opus frames
+	// are 20ms each), since frameChannel's buffered depth is measured in
+	// frames, not wall-clock time.
+	lowWaterFrames := int(encodeLowWaterMark / (20 * time.Millisecond))
 
 	// Skip first 2 packets (Opus header and comment metadata)
 	skipPackets := 2
@@ -233,16 +415,21 @@ func (e *AudioEncoder) encodeWithYtDlpPipe(streamURL string, options *EncodeOpti
 				lastLogTime = time.Now()
 			}
 
-			// Rate limiting: wait until it's time to send this frame
-			// This prevents buffer overflow by matching encode rate to playback rate
-			expectedTime := startTime.Add(time.Duration(frameCount) * frameInterval)
-			now := time.Now()
-			if now.Before(expectedTime) {
-				time.Sleep(expectedTime.Sub(now))
+			// Demand-driven pacing: once frameChannel already holds
+			// lowWaterFrames, stop decoding further ahead until sink reports
+			// it's actually about to consume one - e.g. while paused, sink
+			// reports a far-future deadline and this blocks here instead of
+			// draining yt-dlp's stdout into a full buffer for no reason.
+			for e.sink != nil && len(frameChannel) >= lowWaterFrames && e.sink.NextFrameDeadline().After(time.Now()) {
+				time.Sleep(encodeLowWaterPollInterval)
 			}
 
-			// Send frame to channel (blocking)
+			// Send frame to channel (blocking) - time the hand-off so a full
+			// channel (the consumer falling behind) shows up as stall
+			sendStart := time.Now()
 			frameChannel <- packet
+			e.metrics.ObserveEncodeStall(time.Since(sendStart))
+			e.metrics.IncEncodeFramesSent()
 		}
 	}
 }
@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EqualizerBands is the number of configurable equalizer bands, following
+// the standard ISO 15-band graphic-EQ layout
+const EqualizerBands = 15
+
+// equalizerCenterFrequencies are the ISO center frequencies (Hz) for each of
+// the 15 equalizer bands, in order
+var equalizerCenterFrequencies = [EqualizerBands]int{
+	25, 40, 63, 100, 160, 250, 400, 630, 1000, 1600, 2500, 4000, 6300, 10000, 16000,
+}
+
+// FilterSpec describes a guild's stacked DSP effect chain. AudioPlayer
+// translates it into an ffmpeg `-af` filter graph via BuildFilterGraph and
+// applies it on the next (re-)encode, so SetFilters can reapply a changed
+// chain live without restarting the track from the top.
+type FilterSpec struct {
+	BassBoost bool
+	Nightcore bool
+	Vaporwave bool
+	EightD    bool
+	Karaoke   bool
+
+	// EqualizerGains holds a gain in dB (-12..12) for each of the 15 bands
+	// in equalizerCenterFrequencies; 0 leaves a band flat
+	EqualizerGains [EqualizerBands]float64
+}
+
+// NewFilterSpec returns a FilterSpec with every effect off and every
+// equalizer band flat
+func NewFilterSpec() *FilterSpec {
+	return &FilterSpec{}
+}
+
+// IsEmpty reports whether f applies no processing at all, so callers can
+// skip building and passing a filter graph entirely
+func (f *FilterSpec) IsEmpty() bool {
+	if f == nil {
+		return true
+	}
+	if f.BassBoost || f.Nightcore || f.Vaporwave || f.EightD || f.Karaoke {
+		return false
+	}
+	for _, gain := range f.EqualizerGains {
+		if gain != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildFilterGraph translates f into an ffmpeg `-af` filter graph, or ""
+// if it applies no processing.
+func (f *FilterSpec) BuildFilterGraph() string {
+	if f.IsEmpty() {
+		return ""
+	}
+
+	var stages []string
+
+	if f.BassBoost {
+		stages = append(stages, "bass=g=15")
+	}
+	if f.Nightcore {
+		stages = append(stages, "asetrate=48000*1.25,aresample=48000,atempo=1.06")
+	}
+	if f.Vaporwave {
+		stages = append(stages, "asetrate=48000*0.8,aresample=48000,atempo=1.1")
+	}
+	if f.EightD {
+		stages = append(stages, "apulsator=hz=0.09")
+	}
+	if f.Karaoke {
+		stages = append(stages, "stereotools=mlev=0.03")
+	}
+
+	for band, gain := range f.EqualizerGains {
+		if gain == 0 {
+			continue
+		}
+		stages = append(stages, fmt.Sprintf("equalizer=f=%d:width_type=o:width=2:g=%g", equalizerCenterFrequencies[band], gain))
+	}
+
+	return strings.Join(stages, ",")
+}
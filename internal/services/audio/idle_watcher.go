@@ -0,0 +1,176 @@
+package audio
+
+import "time"
+
+// idleWatchInterval is how often a guild's idle watcher re-checks whether
+// it's alone in its voice channel or sitting on a stopped, empty queue
+const idleWatchInterval = 20 * time.Second
+
+// guildWatcher holds the channels used to stop a running idle watcher
+// goroutine, or nudge it to re-check immediately instead of waiting for the
+// next idleWatchInterval tick
+type guildWatcher struct {
+	stop   chan struct{}
+	notify chan struct{}
+}
+
+// SetIdleCheckHook registers the callback the idle watcher uses to decide
+// whether a guild counts as idle: nothing playing and an empty queue.
+// AudioService's own tracklists map is vestigial - the real queue lives in
+// PlaybackService - so this is wired from bot.go to PlaybackService.IsIdle.
+func (s *AudioService) SetIdleCheckHook(fn func(guildID string) bool) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	s.idleCheckHook = fn
+}
+
+// SetAutoDisconnectHook registers the callback fired just before the idle
+// watcher disconnects a guild, so a caller (PlaybackService) can post a
+// notice to the guild's bound text channel while the state needed to find
+// it still exists.
+func (s *AudioService) SetAutoDisconnectHook(fn func(guildID, reason string)) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	s.onAutoDisconnect = fn
+}
+
+// SetAutoLeave overrides the idle/alone auto-disconnect watcher for a single
+// guild, independent of the server-wide default (see NewAudioService's
+// defaultAutoLeave). Used by the /autoleave command.
+func (s *AudioService) SetAutoLeave(guildID string, enabled bool) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	s.autoLeaveEnabled[guildID] = enabled
+}
+
+// AutoLeaveEnabled reports whether the idle/alone watcher is currently
+// allowed to disconnect guildID, falling back to the configured default if
+// the guild has never overridden it with /autoleave.
+func (s *AudioService) AutoLeaveEnabled(guildID string) bool {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	if enabled, exists := s.autoLeaveEnabled[guildID]; exists {
+		return enabled
+	}
+	return s.defaultAutoLeave
+}
+
+// NotifyVoiceStateChange wakes guildID's idle watcher immediately instead of
+// waiting for the next idleWatchInterval tick, so the alone timer starts (or
+// resets) as soon as a user leaves or joins the bot's channel. A no-op for a
+// guild with no running watcher.
+func (s *AudioService) NotifyVoiceStateChange(guildID string) {
+	s.watchMu.Lock()
+	w, exists := s.watchers[guildID]
+	s.watchMu.Unlock()
+	if !exists {
+		return
+	}
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// startIdleWatcher launches the background goroutine that watches guildID
+// for the alone/idle auto-disconnect conditions. No-op if one is already
+// running for that guild.
+func (s *AudioService) startIdleWatcher(guildID string) {
+	s.watchMu.Lock()
+	if _, exists := s.watchers[guildID]; exists {
+		s.watchMu.Unlock()
+		return
+	}
+	w := &guildWatcher{stop: make(chan struct{}), notify: make(chan struct{}, 1)}
+	s.watchers[guildID] = w
+	s.watchMu.Unlock()
+
+	go s.watchGuild(guildID, w)
+}
+
+// stopIdleWatcher stops guildID's idle watcher and clears any /autoleave
+// override, if one was running.
+func (s *AudioService) stopIdleWatcher(guildID string) {
+	s.watchMu.Lock()
+	w, exists := s.watchers[guildID]
+	delete(s.watchers, guildID)
+	delete(s.autoLeaveEnabled, guildID)
+	s.watchMu.Unlock()
+
+	if exists {
+		close(w.stop)
+	}
+}
+
+// watchGuild runs until w.stop closes, periodically checking whether
+// guildID has been alone in its voice channel or idle (stopped, empty
+// queue) for longer than the configured timeout, and disconnecting it if
+// so. Runs once per connected guild; see startIdleWatcher/stopIdleWatcher.
+func (s *AudioService) watchGuild(guildID string, w *guildWatcher) {
+	ticker := time.NewTicker(idleWatchInterval)
+	defer ticker.Stop()
+
+	var aloneSince, idleSince time.Time
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.notify:
+		case <-ticker.C:
+		}
+
+		if !s.AutoLeaveEnabled(guildID) {
+			aloneSince, idleSince = time.Time{}, time.Time{}
+			continue
+		}
+
+		if count, err := s.CountNonBotListeners(guildID); err == nil && count == 0 {
+			if aloneSince.IsZero() {
+				aloneSince = time.Now()
+			}
+		} else {
+			aloneSince = time.Time{}
+		}
+
+		s.watchMu.Lock()
+		idleCheck := s.idleCheckHook
+		s.watchMu.Unlock()
+
+		if idleCheck != nil && idleCheck(guildID) {
+			if idleSince.IsZero() {
+				idleSince = time.Now()
+			}
+		} else {
+			idleSince = time.Time{}
+		}
+
+		var reason string
+		switch {
+		case !aloneSince.IsZero() && time.Since(aloneSince) >= s.aloneTimeout:
+			reason = "alone in the voice channel"
+		case !idleSince.IsZero() && time.Since(idleSince) >= s.idleTimeout:
+			reason = "idle with an empty queue"
+		default:
+			continue
+		}
+
+		s.logger.WithFields(map[string]interface{}{
+			"guild":  guildID,
+			"reason": reason,
+		}).Info("💤 Auto-disconnecting idle guild")
+
+		s.watchMu.Lock()
+		onAutoDisconnect := s.onAutoDisconnect
+		s.watchMu.Unlock()
+		if onAutoDisconnect != nil {
+			onAutoDisconnect(guildID, reason)
+		}
+
+		if err := s.DisconnectFromGuild(guildID); err != nil {
+			s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to auto-disconnect idle guild")
+		}
+		return
+	}
+}
@@ -0,0 +1,541 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// LavalinkNodeConfig is one node in the pool a LavalinkBackend load-balances
+// and fails over across.
+type LavalinkNodeConfig struct {
+	Name     string // used only for logging
+	Host     string // host:port, no scheme
+	Password string
+	Secure   bool // use wss/https instead of ws/http
+}
+
+const (
+	// lavalinkReconnectMinBackoff and lavalinkReconnectMaxBackoff bound a
+	// node's WS reconnect delay, doubling on each consecutive failure.
+	lavalinkReconnectMinBackoff = 1 * time.Second
+	lavalinkReconnectMaxBackoff = 1 * time.Minute
+
+	// lavalinkRESTTimeout bounds a single player-update REST call, so a
+	// stalled node can't hang a /pause or /skip command forever.
+	lavalinkRESTTimeout = 5 * time.Second
+)
+
+// LavalinkBackend is a Backend that forwards voice connections and playback
+// to a pool of Lavalink v4 nodes instead of running yt-dlp/FFmpeg locally.
+// Each guild is pinned to one healthy node (see pick) for the lifetime of
+// its connection; if that node drops its WebSocket, the guild fails over to
+// the next healthy node on its next operation.
+type LavalinkBackend struct {
+	nodes   []*lavalinkNode
+	session *discordgo.Session // only used to read our own user id, lazily - see dialAndRead
+	logger  *logger.Logger
+
+	mu      sync.RWMutex
+	players map[string]*lavalinkGuildState // guildID -> state
+
+	globalDispatch func(TrackEventType, TrackContext)
+}
+
+// lavalinkGuildState tracks which node a guild is pinned to and the voice
+// credentials Discord has handed us so far, since Lavalink needs both the
+// VOICE_SERVER_UPDATE (token/endpoint) and VOICE_STATE_UPDATE (sessionID)
+// payloads before it can open its own voice connection.
+type lavalinkGuildState struct {
+	node      *lavalinkNode
+	sessionID string
+	token     string
+	endpoint  string
+
+	handlers   map[TrackEventType][]handlerEntry
+	handlerSeq atomic.Uint64
+	handlersMu sync.RWMutex
+}
+
+// lavalinkNode is a single Lavalink node connection: a long-lived WebSocket
+// for receiving player/track events, and REST calls for issuing commands.
+type lavalinkNode struct {
+	cfg     LavalinkNodeConfig
+	backend *LavalinkBackend
+
+	mu        sync.Mutex
+	conn      *websocket.Conn
+	sessionID string // Lavalink (not Discord) session id, from the "ready" op
+	healthy   atomic.Bool
+
+	httpClient *http.Client
+}
+
+// NewLavalinkBackend dials every configured node in the background and
+// returns immediately; nodes that fail to connect are retried with backoff
+// by connectLoop and simply stay unhealthy (excluded from pick) until they
+// succeed. session is used only to read our own Discord user id (required
+// by the Lavalink v4 WebSocket handshake) at dial time, since this backend
+// is typically constructed before session.Open() has populated it.
+func NewLavalinkBackend(nodes []LavalinkNodeConfig, session *discordgo.Session, log *logger.Logger) *LavalinkBackend {
+	b := &LavalinkBackend{
+		session: session,
+		logger:  log,
+		players: make(map[string]*lavalinkGuildState),
+	}
+
+	for _, cfg := range nodes {
+		n := &lavalinkNode{
+			cfg:        cfg,
+			backend:    b,
+			httpClient: &http.Client{Timeout: lavalinkRESTTimeout},
+		}
+		b.nodes = append(b.nodes, n)
+		go n.connectLoop()
+	}
+
+	return b
+}
+
+// SetGlobalDispatch wires fn to run on every TrackEvent from every guild
+// this backend handles, mirroring AudioPlayer.globalDispatch/AudioService's
+// dispatchGlobal for the local backend.
+func (b *LavalinkBackend) SetGlobalDispatch(fn func(TrackEventType, TrackContext)) {
+	b.globalDispatch = fn
+}
+
+// pick deterministically maps guildID onto one of the currently healthy
+// nodes, so the same guild tends to land on the same node across calls
+// without every guild hashing to node 0 when a node is unhealthy. If every
+// node is unhealthy, pick returns nil and callers report ErrGuildNotFound-
+// style errors rather than silently dropping the operation.
+func (b *LavalinkBackend) pick(guildID string) *lavalinkNode {
+	if len(b.nodes) == 0 {
+		return nil
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(guildID))
+	start := int(h.Sum32()) % len(b.nodes)
+
+	for i := 0; i < len(b.nodes); i++ {
+		n := b.nodes[(start+i)%len(b.nodes)]
+		if n.healthy.Load() {
+			return n
+		}
+	}
+	return nil
+}
+
+func (b *LavalinkBackend) stateFor(guildID string) *lavalinkGuildState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st, ok := b.players[guildID]
+	if !ok {
+		st = &lavalinkGuildState{handlers: make(map[TrackEventType][]handlerEntry)}
+		b.players[guildID] = st
+	}
+	return st
+}
+
+// AddHandler registers handler to run whenever event fires for guildID,
+// mirroring AudioPlayer.AddHandler for guilds running on this backend.
+func (b *LavalinkBackend) AddHandler(guildID string, event TrackEventType, handler TrackEventHandler) (unsubscribe func()) {
+	st := b.stateFor(guildID)
+	id := st.handlerSeq.Add(1)
+
+	st.handlersMu.Lock()
+	st.handlers[event] = append(st.handlers[event], handlerEntry{id: id, handler: handler})
+	st.handlersMu.Unlock()
+
+	return func() {
+		st.handlersMu.Lock()
+		defer st.handlersMu.Unlock()
+		entries := st.handlers[event]
+		for i, e := range entries {
+			if e.id == id {
+				st.handlers[event] = append(entries[:i], entries[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *LavalinkBackend) dispatch(guildID string, event TrackEventType, ctx TrackContext) {
+	b.mu.RLock()
+	st, ok := b.players[guildID]
+	b.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	st.handlersMu.RLock()
+	entries := append([]handlerEntry(nil), st.handlers[event]...)
+	st.handlersMu.RUnlock()
+
+	for _, e := range entries {
+		go e.handler(ctx)
+	}
+	if b.globalDispatch != nil {
+		go b.globalDispatch(event, ctx)
+	}
+}
+
+// Connect pins guildID to a healthy node and sends Discord the raw voice
+// channel join (op 4) manually, without completing discordgo's own voice
+// handshake - the resulting VOICE_SERVER_UPDATE/VOICE_STATE_UPDATE are
+// intercepted by bot.go and relayed on via ForwardVoiceServerUpdate/
+// ForwardVoiceStateUpdate so the node can complete the handshake itself.
+func (b *LavalinkBackend) Connect(guildID, channelID string) error {
+	node := b.pick(guildID)
+	if node == nil {
+		return fmt.Errorf("lavalink: no healthy nodes available for guild %s", guildID)
+	}
+
+	st := b.stateFor(guildID)
+	b.mu.Lock()
+	st.node = node
+	b.mu.Unlock()
+
+	return b.session.ChannelVoiceJoinManual(guildID, channelID, false, false)
+}
+
+// Disconnect releases guildID's player on its node, drops local state, and
+// leaves the voice channel.
+func (b *LavalinkBackend) Disconnect(guildID string) error {
+	b.mu.Lock()
+	st, ok := b.players[guildID]
+	delete(b.players, guildID)
+	b.mu.Unlock()
+
+	if err := b.session.ChannelVoiceJoinManual(guildID, "", false, false); err != nil {
+		b.logger.WithError(err).WithField("guild", guildID).Warn("Lavalink: failed to leave voice channel")
+	}
+
+	if !ok || st.node == nil {
+		return nil
+	}
+	return st.node.deletePlayer(guildID)
+}
+
+func (b *LavalinkBackend) nodeFor(guildID string) (*lavalinkNode, error) {
+	b.mu.RLock()
+	st, ok := b.players[guildID]
+	b.mu.RUnlock()
+	if !ok || st.node == nil {
+		return nil, fmt.Errorf("%w: %s", ErrGuildNotFound, guildID)
+	}
+	return st.node, nil
+}
+
+// Play asks guildID's node to load and play song.Identifier, letting the
+// node itself resolve and encode the stream - this is what offloads Opus
+// encoding off this process.
+func (b *LavalinkBackend) Play(guildID string, song *entities.Song) error {
+	node, err := b.nodeFor(guildID)
+	if err != nil {
+		return err
+	}
+	return node.updatePlayer(guildID, map[string]interface{}{
+		"track": map[string]interface{}{"identifier": song.OriginalInput},
+	})
+}
+
+func (b *LavalinkBackend) Pause(guildID string) error  { return b.setPaused(guildID, true) }
+func (b *LavalinkBackend) Resume(guildID string) error { return b.setPaused(guildID, false) }
+
+func (b *LavalinkBackend) setPaused(guildID string, paused bool) error {
+	node, err := b.nodeFor(guildID)
+	if err != nil {
+		return err
+	}
+	return node.updatePlayer(guildID, map[string]interface{}{"paused": paused})
+}
+
+// Stop clears guildID's current track without disconnecting.
+func (b *LavalinkBackend) Stop(guildID string) error {
+	node, err := b.nodeFor(guildID)
+	if err != nil {
+		return err
+	}
+	return node.updatePlayer(guildID, map[string]interface{}{"track": map[string]interface{}{"encoded": nil}})
+}
+
+// Seek restarts guildID's current track at position.
+func (b *LavalinkBackend) Seek(guildID string, position time.Duration) error {
+	node, err := b.nodeFor(guildID)
+	if err != nil {
+		return err
+	}
+	return node.updatePlayer(guildID, map[string]interface{}{"position": position.Milliseconds()})
+}
+
+// SetVolume sets guildID's playback volume, 0-100.
+func (b *LavalinkBackend) SetVolume(guildID string, volume int) error {
+	node, err := b.nodeFor(guildID)
+	if err != nil {
+		return err
+	}
+	return node.updatePlayer(guildID, map[string]interface{}{"volume": volume})
+}
+
+// ForwardVoiceServerUpdate relays Discord's VOICE_SERVER_UPDATE payload to
+// guildID's node, completing the voice handshake once a VOICE_STATE_UPDATE
+// has also arrived (see ForwardVoiceStateUpdate).
+func (b *LavalinkBackend) ForwardVoiceServerUpdate(guildID, token, endpoint string) error {
+	st := b.stateFor(guildID)
+
+	b.mu.Lock()
+	st.token = token
+	st.endpoint = endpoint
+	sessionID := st.sessionID
+	node := st.node
+	b.mu.Unlock()
+
+	if node == nil || sessionID == "" {
+		return nil
+	}
+	return node.sendVoiceUpdate(guildID, sessionID, token, endpoint)
+}
+
+// ForwardVoiceStateUpdate relays Discord's VOICE_STATE_UPDATE for our own
+// session to guildID's node. An empty channelID means we left the channel;
+// the cached voice credentials are cleared so a stale token/endpoint can't
+// be replayed into a later reconnect.
+func (b *LavalinkBackend) ForwardVoiceStateUpdate(guildID, sessionID, channelID string) error {
+	st := b.stateFor(guildID)
+
+	if channelID == "" {
+		b.mu.Lock()
+		st.sessionID, st.token, st.endpoint = "", "", ""
+		b.mu.Unlock()
+		return nil
+	}
+
+	b.mu.Lock()
+	st.sessionID = sessionID
+	token, endpoint, node := st.token, st.endpoint, st.node
+	b.mu.Unlock()
+
+	if node == nil || token == "" || endpoint == "" {
+		return nil
+	}
+	return node.sendVoiceUpdate(guildID, sessionID, token, endpoint)
+}
+
+func (n *lavalinkNode) sendVoiceUpdate(guildID, sessionID, token, endpoint string) error {
+	return n.updatePlayer(guildID, map[string]interface{}{
+		"voice": map[string]interface{}{
+			"token":     token,
+			"endpoint":  endpoint,
+			"sessionId": sessionID,
+		},
+	})
+}
+
+func (n *lavalinkNode) scheme(ws bool) string {
+	switch {
+	case ws && n.cfg.Secure:
+		return "wss"
+	case ws:
+		return "ws"
+	case n.cfg.Secure:
+		return "https"
+	default:
+		return "http"
+	}
+}
+
+// updatePlayer issues the REST "update player" call Lavalink v4 uses for
+// every playback command (play/pause/stop/seek/volume/voice), identified
+// by the node's current session id from its WebSocket handshake.
+func (n *lavalinkNode) updatePlayer(guildID string, body map[string]interface{}) error {
+	n.mu.Lock()
+	sessionID := n.sessionID
+	n.mu.Unlock()
+	if sessionID == "" {
+		return fmt.Errorf("lavalink: node %s has no active session", n.cfg.Name)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("lavalink: marshal player update: %w", err)
+	}
+
+	url := fmt.Sprintf("%s://%s/v4/sessions/%s/players/%s", n.scheme(false), n.cfg.Host, sessionID, guildID)
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", n.cfg.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("lavalink: update player on %s: %w", n.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("lavalink: node %s rejected player update (%d): %s", n.cfg.Name, resp.StatusCode, data)
+	}
+	return nil
+}
+
+func (n *lavalinkNode) deletePlayer(guildID string) error {
+	n.mu.Lock()
+	sessionID := n.sessionID
+	n.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s://%s/v4/sessions/%s/players/%s", n.scheme(false), n.cfg.Host, sessionID, guildID)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", n.cfg.Password)
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("lavalink: delete player on %s: %w", n.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// connectLoop dials the node's WebSocket and reads events off it until the
+// connection drops, then retries with exponential backoff. It never
+// returns; it's meant to run for the lifetime of the process.
+func (n *lavalinkNode) connectLoop() {
+	backoff := lavalinkReconnectMinBackoff
+
+	for {
+		if err := n.dialAndRead(); err != nil {
+			n.healthy.Store(false)
+			n.backend.logger.WithError(err).WithField("node", n.cfg.Name).Warn("Lavalink node unreachable, retrying")
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > lavalinkReconnectMaxBackoff {
+				backoff = lavalinkReconnectMaxBackoff
+			}
+			continue
+		}
+		// Clean read loop exit (server closed the socket): reconnect promptly.
+		n.healthy.Store(false)
+		backoff = lavalinkReconnectMinBackoff
+	}
+}
+
+// dialAndRead opens the node's WebSocket and blocks reading events until the
+// connection closes or errors.
+func (n *lavalinkNode) dialAndRead() error {
+	header := http.Header{}
+	header.Set("Authorization", n.cfg.Password)
+	header.Set("User-Id", n.backend.session.State.User.ID)
+	header.Set("Client-Name", "discord-music-bot/lavalink")
+
+	n.mu.Lock()
+	if n.sessionID != "" {
+		// A prior session id lets the node resume our player state across a
+		// brief reconnect instead of tearing it down.
+		header.Set("Session-Id", n.sessionID)
+	}
+	n.mu.Unlock()
+
+	url := fmt.Sprintf("%s://%s/v4/websocket", n.scheme(true), n.cfg.Host)
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	n.mu.Lock()
+	n.conn = conn
+	n.mu.Unlock()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		n.handleMessage(data)
+	}
+}
+
+// lavalinkMessage covers the fields used across every Lavalink v4 WS op
+// ("ready", "playerUpdate", "event", "stats"); unused fields for a given op
+// are simply left at their zero value.
+type lavalinkMessage struct {
+	Op        string `json:"op"`
+	SessionID string `json:"sessionId"`
+	GuildID   string `json:"guildId"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Exception struct {
+		Message string `json:"message"`
+	} `json:"exception"`
+}
+
+func (n *lavalinkNode) handleMessage(data []byte) {
+	var msg lavalinkMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		n.backend.logger.WithError(err).WithField("node", n.cfg.Name).Warn("Lavalink: malformed message")
+		return
+	}
+
+	switch msg.Op {
+	case "ready":
+		n.mu.Lock()
+		n.sessionID = msg.SessionID
+		n.mu.Unlock()
+		n.healthy.Store(true)
+		n.backend.logger.WithField("node", n.cfg.Name).Info("Lavalink node ready")
+
+	case "event":
+		n.handleTrackEvent(msg)
+
+	case "playerUpdate", "stats":
+		// Position/stats telemetry - nothing to react to yet.
+	}
+}
+
+func (n *lavalinkNode) handleTrackEvent(msg lavalinkMessage) {
+	ctx := TrackContext{GuildID: msg.GuildID}
+
+	var event TrackEventType
+	switch msg.Type {
+	case "TrackStartEvent":
+		event = TrackEventPlay
+	case "TrackEndEvent":
+		event = TrackEventEnd
+	case "TrackExceptionEvent":
+		event = TrackEventError
+		ctx.Err = fmt.Errorf("lavalink: %s", msg.Exception.Message)
+	case "TrackStuckEvent":
+		event = TrackEventError
+		ctx.Err = fmt.Errorf("lavalink: track stuck, exceeded threshold")
+	case "WebSocketClosedEvent":
+		// The node's own voice WS to Discord closed; our node WS reconnect
+		// loop handles re-establishing things, nothing to dispatch here.
+		return
+	default:
+		return
+	}
+
+	n.backend.dispatch(msg.GuildID, event, ctx)
+}
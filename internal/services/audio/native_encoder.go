@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"io"
+	"time"
+
+	"github.com/at-wat/ebml-go/mkvcore"
+	youtube "github.com/kkdai/youtube/v2"
+)
+
+// EncoderBackend selects how AudioEncoder.EncodeStream turns a stream URL
+// into Discord-ready Opus frames.
+type EncoderBackend string
+
+const (
+	// EncoderBackendYtDlpPipe pipes yt-dlp's download through FFmpeg, as
+	// EncodeStream has always done. It's the zero value, so existing callers
+	// that never set EncodeOptions.Backend keep today's behavior.
+	EncoderBackendYtDlpPipe EncoderBackend = ""
+	// EncoderBackendNative fetches the audio stream directly via
+	// kkdai/youtube and demuxes it in-process instead of shelling out to
+	// yt-dlp/FFmpeg, removing both processes' startup latency. See
+	// encodeNative for what it does and doesn't cover.
+	EncoderBackendNative EncoderBackend = "native"
+)
+
+// opusCodecID is the WebM CodecID YouTube uses for its Opus adaptive audio
+// formats - the only container layout encodeNative can forward without
+// re-encoding.
+const opusCodecID = "A_OPUS"
+
+// nativeFrameInterval paces forwarded Opus frames to Discord's 20ms frame
+// duration, mirroring encodeWithYtDlpPipe's rate limiting.
+const nativeFrameInterval = 20 * time.Millisecond
+
+// encodeNative streams streamURL's audio straight from YouTube, without
+// spawning yt-dlp or FFmpeg. It only handles the WebM/Opus adaptive audio
+// formats YouTube serves for most videos: those packets are already exactly
+// what Discord wants, so they're forwarded frame-for-frame with zero
+// re-encoding. There's no pure-Go AAC decoder available to decode the M4A
+// formats YouTube serves as a fallback on some videos, so whenever a
+// no-re-encode Opus track can't be found - or anything about resolving or
+// demuxing the stream fails - this falls back to encodeWithYtDlpPipe rather
+// than failing playback outright.
+func (e *AudioEncoder) encodeNative(streamURL string, options *EncodeOptions, frameChannel chan []byte, errorChannel chan error) {
+	audioTrack, closeStream, ok := e.openNativeOpusTrack(streamURL)
+	if !ok {
+		e.encodeWithYtDlpPipe(streamURL, options, frameChannel, errorChannel)
+		return
+	}
+	defer closeStream()
+	defer close(frameChannel)
+	defer close(errorChannel)
+
+	if e.pool != nil {
+		e.pool.acquire()
+		defer e.pool.release()
+	}
+
+	e.logger.Info("✅ Native encoder streaming Opus frames straight from WebM container, no subprocess")
+
+	frameCount := 0
+	startTime := time.Now()
+
+	for {
+		packet, _, _, err := audioTrack.Read()
+		if err != nil {
+			if err == io.EOF {
+				e.logger.WithField("frames", frameCount).Info("✅ Native encoding completed (EOF)")
+			} else {
+				e.logger.WithError(err).WithField("frames", frameCount).Warn("⚠️ Native encoding ended")
+			}
+			return
+		}
+
+		if len(packet) == 0 {
+			continue
+		}
+		frameCount++
+
+		// Rate limiting: wait until it's time to send this frame, matching
+		// encode rate to playback rate so the buffered channel can't overrun.
+		expectedTime := startTime.Add(time.Duration(frameCount) * nativeFrameInterval)
+		if now := time.Now(); now.Before(expectedTime) {
+			time.Sleep(expectedTime.Sub(now))
+		}
+
+		frameChannel <- packet
+	}
+}
+
+// openNativeOpusTrack resolves streamURL's best WebM/Opus adaptive audio
+// format via kkdai/youtube and opens a block reader positioned on its Opus
+// track. ok is false - with nothing left open - if streamURL couldn't be
+// resolved, no WebM/Opus format exists for it, or the container couldn't be
+// demuxed, so the caller can fall back to encodeWithYtDlpPipe.
+func (e *AudioEncoder) openNativeOpusTrack(streamURL string) (track mkvcore.BlockReadCloserWithTrackEntry, closeStream func(), ok bool) {
+	client := youtube.Client{}
+
+	video, err := client.GetVideo(streamURL)
+	if err != nil {
+		e.logger.WithError(err).Warn("⚠️ Native encoder could not resolve video, falling back to yt-dlp pipe")
+		return nil, nil, false
+	}
+
+	formats := video.Formats.Type("audio/webm").AudioChannels(2)
+	if len(formats) == 0 {
+		e.logger.Warn("⚠️ No WebM/Opus audio format available for native encoding, falling back to yt-dlp pipe")
+		return nil, nil, false
+	}
+	formats.Sort()
+
+	stream, _, err := client.GetStream(video, &formats[0])
+	if err != nil {
+		e.logger.WithError(err).Warn("⚠️ Native encoder could not open audio stream, falling back to yt-dlp pipe")
+		return nil, nil, false
+	}
+
+	tracks, err := mkvcore.NewSimpleBlockReader(stream)
+	if err != nil {
+		stream.Close()
+		e.logger.WithError(err).Warn("⚠️ Native encoder could not demux WebM container, falling back to yt-dlp pipe")
+		return nil, nil, false
+	}
+
+	for _, t := range tracks {
+		if t.TrackEntry().CodecID == opusCodecID {
+			return t, func() { stream.Close() }, true
+		}
+	}
+
+	stream.Close()
+	e.logger.Warn("⚠️ WebM container has no Opus audio track, falling back to yt-dlp pipe")
+	return nil, nil, false
+}
@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrLoudnessAnalysisFailed is returned when MeasureLoudness's ffmpeg
+// analysis pass doesn't produce a usable integrated-loudness reading.
+var ErrLoudnessAnalysisFailed = errors.New("loudness analysis failed")
+
+// defaultTargetLUFS is the integrated loudness DefaultNormalizationConfig
+// targets - the reference level ReplayGain 2.0 and the major streaming
+// services (Spotify, YouTube Music) share, chosen so normalized tracks sit
+// at a consistent, comfortable level next to non-music Discord audio.
+const defaultTargetLUFS = -14.0
+
+// normalizationGainClampDB bounds the per-track gain NormalizationGain
+// computes, so a bad analysis reading (e.g. a near-silent intro) can't
+// blast listeners' ears or crush a track into silence.
+const normalizationGainClampDB = 12.0
+
+// loudnessAnalysisWindow bounds how much of a track's audio the ffmpeg
+// loudnorm analysis pass decodes. A representative integrated-loudness
+// estimate doesn't need the whole track, and capping this keeps a vote to
+// enable normalization from stalling playback on a 3-hour mix.
+const loudnessAnalysisWindow = 20 * time.Second
+
+// NormalizationConfig controls EBU R128/ReplayGain-style loudness
+// normalization for a single encode. GainDB is precomputed by the caller,
+// typically via AudioEncoder.NormalizationGain, since measuring a track's
+// integrated loudness needs its own ffmpeg analysis pass before the real
+// encode can start.
+type NormalizationConfig struct {
+	Enabled bool
+	// TargetLUFS is the integrated loudness EncodeStream normalizes toward.
+	// See defaultTargetLUFS.
+	TargetLUFS float64
+	// GainDB is TargetLUFS minus the track's measured integrated loudness,
+	// clamped to +/-normalizationGainClampDB.
+	GainDB float64
+}
+
+// DefaultNormalizationConfig returns loudness normalization in its default
+// state: off, targeting defaultTargetLUFS once a guild turns it on via
+// /normalize.
+func DefaultNormalizationConfig() *NormalizationConfig {
+	return &NormalizationConfig{TargetLUFS: defaultTargetLUFS}
+}
+
+// buildNormalizationStage returns the ffmpeg "volume" stage for o's
+// precomputed normalization gain, or nil if normalization is off or no gain
+// has been measured yet.
+func (o *EncodeOptions) buildNormalizationStage() []string {
+	if o.Normalization == nil || !o.Normalization.Enabled || o.Normalization.GainDB == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("volume=%.2fdB", o.Normalization.GainDB)}
+}
+
+// measuredLoudness returns streamURL's integrated loudness in LUFS, running
+// an ffmpeg loudnorm analysis pass on cache miss and caching the result
+// keyed by streamURL - the same keying AudioEncoder's YouTube caches use -
+// so replays and the same track queued by other guilds skip the pass.
+func (e *AudioEncoder) measuredLoudness(ctx context.Context, streamURL string) (float64, error) {
+	return e.loudnessCache.GetOrLoad(streamURL, func() (float64, error) {
+		return e.analyzeIntegratedLoudness(ctx, streamURL)
+	})
+}
+
+// NormalizationGain returns the dB gain needed to bring streamURL's track to
+// target LUFS, analyzing and caching its integrated loudness first if it
+// isn't already known.
+func (e *AudioEncoder) NormalizationGain(ctx context.Context, streamURL string, target float64) (float64, error) {
+	measured, err := e.measuredLoudness(ctx, streamURL)
+	if err != nil {
+		return 0, err
+	}
+
+	gain := target - measured
+	switch {
+	case gain > normalizationGainClampDB:
+		gain = normalizationGainClampDB
+	case gain < -normalizationGainClampDB:
+		gain = -normalizationGainClampDB
+	}
+	return gain, nil
+}
+
+// analyzeIntegratedLoudness runs ffmpeg's loudnorm filter in its single-pass
+// analysis mode over streamURL's first loudnessAnalysisWindow of audio and
+// returns the measured integrated loudness in LUFS.
+func (e *AudioEncoder) analyzeIntegratedLoudness(ctx context.Context, streamURL string) (float64, error) {
+	args := []string{
+		"-hide_banner",
+		"-i", streamURL,
+		"-t", fmt.Sprintf("%.0f", loudnessAnalysisWindow.Seconds()),
+		"-af", "loudnorm=print_format=json",
+		"-f", "null",
+		"-",
+	}
+
+	// loudnorm's analysis pass reports via stderr JSON, not the exit code, so
+	// a non-nil err here doesn't necessarily mean the reading is unusable -
+	// only parseLoudnormJSON failing does.
+	_, stderr, err := e.executor.RunWithTimeout(ctx, "ffmpeg", args...)
+
+	lufs, parseErr := parseLoudnormJSON(stderr)
+	if parseErr != nil {
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrLoudnessAnalysisFailed, err)
+		}
+		return 0, fmt.Errorf("%w: %v", ErrLoudnessAnalysisFailed, parseErr)
+	}
+	return lufs, nil
+}
+
+// parseLoudnormJSON extracts input_i (integrated loudness, in LUFS) from
+// ffmpeg's loudnorm print_format=json stderr, which interleaves that JSON
+// block with regular progress lines.
+func parseLoudnormJSON(stderr []byte) (float64, error) {
+	start := bytes.IndexByte(stderr, '{')
+	end := bytes.LastIndexByte(stderr, '}')
+	if start < 0 || end < 0 || end < start {
+		return 0, errors.New("no loudnorm JSON block found in ffmpeg output")
+	}
+
+	var report struct {
+		InputI string `json:"input_i"`
+	}
+	if err := json.Unmarshal(stderr[start:end+1], &report); err != nil {
+		return 0, err
+	}
+
+	lufs, err := strconv.ParseFloat(report.InputI, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid input_i value %q: %w", report.InputI, err)
+	}
+	return lufs, nil
+}
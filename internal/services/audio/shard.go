@@ -0,0 +1,96 @@
+package audio
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// shardCount is the number of shards AudioService splits its per-guild state
+// across. Chosen to comfortably outnumber typical GOMAXPROCS so concurrent
+// operations on different guilds rarely contend on the same shard's mutex,
+// without creating so many shards that shardCleanupInterval sweeps become
+// their own source of overhead.
+const shardCount = 32
+
+// shardCleanupInterval is how often a shard's background goroutine prunes
+// disconnected, inactive guild entries, so a long-running deployment with
+// high guild churn doesn't leak map entries for guilds that were abandoned
+// without a clean DisconnectFromGuild (e.g. the bot was kicked).
+const shardCleanupInterval = 10 * time.Minute
+
+// shard holds the per-guild voice/player/tracklist state for the subset of
+// guilds that hash to it, each guarded by its own RWMutex - see
+// AudioService.shardFor. This is what lets operations on unrelated guilds
+// proceed in parallel instead of serializing on one global lock.
+type shard struct {
+	mu               sync.RWMutex
+	voiceConnections map[string]*VoiceConnection
+	audioPlayers     map[string]*AudioPlayer
+	tracklists       map[string]*entities.Tracklist
+
+	stop chan struct{}
+}
+
+// newShard creates an empty shard and starts its cleanup goroutine.
+func newShard() *shard {
+	sh := &shard{
+		voiceConnections: make(map[string]*VoiceConnection),
+		audioPlayers:     make(map[string]*AudioPlayer),
+		tracklists:       make(map[string]*entities.Tracklist),
+		stop:             make(chan struct{}),
+	}
+	go sh.runCleanup()
+	return sh
+}
+
+// runCleanup prunes disconnected, inactive guilds every shardCleanupInterval
+// until close stops it.
+func (sh *shard) runCleanup() {
+	ticker := time.NewTicker(shardCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sh.stop:
+			return
+		case <-ticker.C:
+			sh.pruneInactive()
+		}
+	}
+}
+
+// pruneInactive drops guilds whose voice connection is disconnected and
+// whose player (if any) isn't playing, so a guild that was abandoned without
+// a clean DisconnectFromGuild doesn't hold its map entries forever.
+func (sh *shard) pruneInactive() {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for guildID, vc := range sh.voiceConnections {
+		if vc.IsConnected() {
+			continue
+		}
+		if player, ok := sh.audioPlayers[guildID]; ok && player.IsPlaying() {
+			continue
+		}
+		delete(sh.voiceConnections, guildID)
+		delete(sh.audioPlayers, guildID)
+		delete(sh.tracklists, guildID)
+	}
+}
+
+// close stops the shard's cleanup goroutine.
+func (sh *shard) close() {
+	close(sh.stop)
+}
+
+// shardFor returns the shard guildID's state lives in, deterministic across
+// calls for the same guild.
+func (s *AudioService) shardFor(guildID string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(guildID))
+	return s.shards[h.Sum32()%shardCount]
+}
@@ -0,0 +1,56 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// TrackEventType identifies a point in a track's lifecycle that callers can
+// subscribe to via AudioPlayer.AddHandler or AudioService.AddGlobalHandler,
+// modeled after songbird's TrackEvent system. This replaces the old
+// single-shot PlaybackCallback with a proper publish/subscribe bus, so
+// decoupled features (stats, state persistence, now-playing auto-update,
+// webhooks) can all observe the same player without stuffing more closures
+// through Play.
+type TrackEventType string
+
+const (
+	TrackEventPlay         TrackEventType = "play"
+	TrackEventPause        TrackEventType = "pause"
+	TrackEventResume       TrackEventType = "resume"
+	TrackEventEnd          TrackEventType = "end"
+	TrackEventError        TrackEventType = "error"
+	TrackEventLoop         TrackEventType = "loop"
+	TrackEventPositionTick TrackEventType = "position_tick"
+	TrackEventQueueEmpty   TrackEventType = "queue_empty"
+)
+
+// TrackContext is the payload delivered to a TrackEventHandler: the guild
+// and song an event applies to, how far into playback it occurred, and -
+// for TrackEventError - what went wrong. A guild-level event with no single
+// owning track (TrackEventQueueEmpty) leaves Song unset. FrameCount is only
+// populated for TrackEventEnd/TrackEventError, where it's the number of
+// Opus frames actually sent to Discord for that track.
+type TrackContext struct {
+	GuildID    string
+	Song       *entities.Song
+	Position   time.Duration
+	FrameCount int
+	Err        error
+}
+
+// TrackEventHandler reacts to a single TrackEventType firing. Handlers for
+// one event run one after another on a dedicated fan-out goroutine (see
+// AudioPlayer.dispatch), never on the playback goroutine itself, so a slow
+// handler can't delay frame delivery.
+type TrackEventHandler func(ctx TrackContext)
+
+// handlerEntry pairs a registered TrackEventHandler with an id, so
+// AddHandler's returned unsubscribe func can remove exactly the handler it
+// was given back even if others for the same event were added or removed
+// in between.
+type handlerEntry struct {
+	id      uint64
+	handler TrackEventHandler
+}
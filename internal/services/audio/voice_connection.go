@@ -17,15 +17,70 @@ var (
 	ErrNotConnected = errors.New("not connected to voice channel")
 	// ErrConnectionFailed is returned when connection fails
 	ErrConnectionFailed = errors.New("failed to connect to voice channel")
+	// ErrReconnectFailed is returned (via the OnReconnect hook) when the
+	// reconnection supervisor exhausts its backoff without re-establishing
+	// a ready voice connection.
+	ErrReconnectFailed = errors.New("failed to reconnect to voice channel")
+)
+
+// errStopped is returned internally by waitUntilReady when stopSignal fires
+// before the connection becomes ready, so callers can tell that apart from
+// a real timeout and skip error reporting on an intentional teardown.
+var errStopped = errors.New("stopped while waiting for voice connection")
+
+// waitUntilReady blocks until vc.Ready, returning ErrConnectionFailed after
+// 10s or errStopped if stopSignal fires first. stopSignal may be nil, in
+// which case only the timeout applies.
+func waitUntilReady(vc *discordgo.VoiceConnection, stopSignal <-chan struct{}) error {
+	timeout := time.After(10 * time.Second)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !vc.Ready {
+		select {
+		case <-timeout:
+			return fmt.Errorf("%w: connection not ready after 10s", ErrConnectionFailed)
+		case <-stopSignal:
+			return errStopped
+		case <-ticker.C:
+			continue
+		}
+	}
+	return nil
+}
+
+// reconnectAttempts and reconnectBaseDelay bound the supervisor's
+// exponential backoff: delays are reconnectBaseDelay * 2^attempt, so with
+// the defaults below it retries at 1s, 2s, 4s, 8s, 16s before giving up.
+const (
+	reconnectAttempts  = 5
+	reconnectBaseDelay = 1 * time.Second
 )
 
 // VoiceConnection represents a voice connection to a Discord channel
 type VoiceConnection struct {
 	guildID   string
 	channelID string
+	session   *discordgo.Session
 	vc        *discordgo.VoiceConnection
 	logger    *logger.Logger
 	mu        sync.RWMutex
+
+	// closing is set while disconnectLocked is tearing this connection down
+	// on purpose, so the supervisor goroutine watching Ready doesn't mistake
+	// it for an unexpected drop and try to reconnect.
+	closing bool
+
+	// supervisorStop, closed by disconnectLocked, stops the goroutine
+	// started by Connect that watches for an unexpected disconnect.
+	supervisorStop chan struct{}
+
+	// onReconnect, set via SetOnReconnect, is invoked by the supervisor:
+	// once with ok=false right when an unexpected drop is detected (so
+	// AudioPlayer can pause frame emission), then again with the final
+	// outcome once backoff either re-establishes the connection (ok=true)
+	// or is exhausted (ok=false, err=ErrReconnectFailed).
+	onReconnect func(ok bool, err error)
 }
 
 // NewVoiceConnection creates a new voice connection
@@ -36,6 +91,15 @@ func NewVoiceConnection(guildID string, log *logger.Logger) *VoiceConnection {
 	}
 }
 
+// SetOnReconnect registers the callback the reconnection supervisor invokes
+// on an unexpected disconnect and again once backoff resolves; see
+// onReconnect. Not safe to call concurrently with Connect.
+func (v *VoiceConnection) SetOnReconnect(fn func(ok bool, err error)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.onReconnect = fn
+}
+
 // Connect connects to a voice channel
 func (v *VoiceConnection) Connect(session *discordgo.Session, channelID string) error {
 	v.mu.Lock()
@@ -64,27 +128,117 @@ func (v *VoiceConnection) Connect(session *discordgo.Session, channelID string)
 	}
 
 	// Wait for voice connection to be ready with timeout
-	readyTimeout := time.After(10 * time.Second)
-	readyTicker := time.NewTicker(100 * time.Millisecond)
-	defer readyTicker.Stop()
-
-	for !vc.Ready {
-		select {
-		case <-readyTimeout:
-			vc.Disconnect()
-			return fmt.Errorf("%w: connection not ready after 10s", ErrConnectionFailed)
-		case <-readyTicker.C:
-			continue
-		}
+	if err := waitUntilReady(vc, nil); err != nil {
+		vc.Disconnect()
+		return err
 	}
 
+	v.session = session
 	v.vc = vc
 	v.channelID = channelID
+	v.closing = false
+	v.supervisorStop = make(chan struct{})
 
 	v.logger.WithField("channel", channelID).Info("✅ Successfully connected to voice channel")
+
+	go v.superviseReconnect(v.supervisorStop)
+
 	return nil
 }
 
+// superviseReconnect watches for this connection's underlying
+// discordgo.VoiceConnection unexpectedly going not-Ready (discordgo itself
+// retries the voice websocket, but doesn't redo the full
+// ChannelVoiceJoin handshake if that fails) and, on that happening, attempts
+// to rejoin the same channel with exponential backoff. Runs until
+// stopSignal closes, which disconnectLocked does on any intentional
+// Disconnect/move.
+func (v *VoiceConnection) superviseReconnect(stopSignal chan struct{}) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	wasReady := true
+	for {
+		select {
+		case <-stopSignal:
+			return
+		case <-ticker.C:
+			v.mu.RLock()
+			ready := v.vc != nil && v.vc.Ready
+			closing := v.closing
+			v.mu.RUnlock()
+
+			if closing {
+				return
+			}
+			if ready {
+				wasReady = true
+				continue
+			}
+			if !wasReady {
+				continue // already reconnecting
+			}
+			wasReady = false
+
+			v.attemptReconnect(stopSignal)
+		}
+	}
+}
+
+// attemptReconnect rejoins this connection's channel with exponential
+// backoff, reporting through onReconnect as described on that field. Gives
+// up (reporting ErrReconnectFailed) after reconnectAttempts tries.
+func (v *VoiceConnection) attemptReconnect(stopSignal chan struct{}) {
+	v.mu.RLock()
+	session := v.session
+	guildID := v.guildID
+	channelID := v.channelID
+	onReconnect := v.onReconnect
+	v.mu.RUnlock()
+
+	v.logger.WithField("channel", channelID).Warn("Voice connection dropped unexpectedly, attempting to reconnect...")
+	if onReconnect != nil {
+		onReconnect(false, nil)
+	}
+
+	delay := reconnectBaseDelay
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		select {
+		case <-stopSignal:
+			return
+		case <-time.After(delay):
+		}
+
+		vc, err := session.ChannelVoiceJoin(guildID, channelID, false, true)
+		if err == nil {
+			err = waitUntilReady(vc, stopSignal)
+		}
+		if err == errStopped {
+			return
+		}
+
+		if err == nil {
+			v.mu.Lock()
+			v.vc = vc
+			v.mu.Unlock()
+
+			v.logger.WithFields(map[string]interface{}{"channel": channelID, "attempt": attempt}).Info("✅ Reconnected to voice channel")
+			if onReconnect != nil {
+				onReconnect(true, nil)
+			}
+			return
+		}
+
+		v.logger.WithError(err).WithField("attempt", attempt).Warn("Reconnect attempt failed")
+		delay *= 2
+	}
+
+	v.logger.WithField("channel", channelID).Error("Giving up on voice reconnection")
+	if onReconnect != nil {
+		onReconnect(false, ErrReconnectFailed)
+	}
+}
+
 // Disconnect disconnects from the voice channel
 func (v *VoiceConnection) Disconnect() error {
 	v.mu.Lock()
@@ -100,6 +254,12 @@ func (v *VoiceConnection) disconnectLocked() error {
 
 	v.logger.Info("Disconnecting from voice channel...")
 
+	v.closing = true
+	if v.supervisorStop != nil {
+		close(v.supervisorStop)
+		v.supervisorStop = nil
+	}
+
 	if err := v.vc.Disconnect(); err != nil {
 		v.logger.WithError(err).Error("Failed to disconnect")
 		return err
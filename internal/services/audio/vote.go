@@ -0,0 +1,174 @@
+package audio
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// VoteKind identifies what an AudioPlayer's in-progress vote session
+// decides.
+type VoteKind string
+
+const (
+	VoteKindSkip  VoteKind = "skip"
+	VoteKindStop  VoteKind = "stop"
+	VoteKindPause VoteKind = "pause"
+)
+
+// defaultVoteThresholdRatio and defaultVoteTTL are the fallbacks used when
+// SetVoteConfig hasn't set a positive override for that field.
+const (
+	defaultVoteThresholdRatio = 0.5
+	defaultVoteTTL            = 60 * time.Second
+)
+
+// voteConfig holds the threshold AudioPlayer applies to every vote session
+// started via VoteStart; see SetVoteConfig.
+type voteConfig struct {
+	ratio    float64
+	minVotes int
+	ttl      time.Duration
+}
+
+// VoteHolder tracks a single in-progress vote session (skip/stop/pause) for
+// one guild's AudioPlayer. voters dedups by user ID via a sync.Map, so the
+// same user voting twice doesn't inflate the tally; a session started by
+// VoteStart is only valid until its ttl elapses, reclaimed by VoteExpire.
+type VoteHolder struct {
+	mu             sync.RWMutex
+	active         bool
+	kind           VoteKind
+	initiator      string
+	eligibleVoters int
+	startedAt      time.Time
+	ttl            time.Duration
+	voters         sync.Map // userID string -> struct{}
+}
+
+// SetVoteConfig overrides the default vote threshold/TTL this player applies
+// to every VoteStart from here on. ratio <= 0 and minVotes <= 0 leave that
+// field's built-in default alone, same for ttl <= 0.
+func (p *AudioPlayer) SetVoteConfig(ratio float64, minVotes int, ttl time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ratio > 0 {
+		p.voteCfg.ratio = ratio
+	}
+	if minVotes > 0 {
+		p.voteCfg.minVotes = minVotes
+	}
+	if ttl > 0 {
+		p.voteCfg.ttl = ttl
+	}
+}
+
+// voteTTL returns the configured session TTL, or defaultVoteTTL if unset.
+func (p *AudioPlayer) voteTTL() time.Duration {
+	p.mu.RLock()
+	ttl := p.voteCfg.ttl
+	p.mu.RUnlock()
+	if ttl <= 0 {
+		return defaultVoteTTL
+	}
+	return ttl
+}
+
+// VoteStart begins a new vote session of kind, started by initiator
+// (automatically counted as the first yes vote), replacing whatever
+// session (likely already expired) was running before. eligibleVoters is
+// the number of non-bot listeners currently in the bound voice channel -
+// typically AudioService.CountNonBotListeners - used by VoteTally to
+// compute how many votes are required.
+func (p *AudioPlayer) VoteStart(kind VoteKind, initiator string, eligibleVoters int) {
+	p.votes.mu.Lock()
+	p.votes.voters = sync.Map{}
+	p.votes.active = true
+	p.votes.kind = kind
+	p.votes.initiator = initiator
+	p.votes.eligibleVoters = eligibleVoters
+	p.votes.startedAt = time.Now()
+	p.votes.ttl = p.voteTTL()
+	p.votes.mu.Unlock()
+
+	p.votes.voters.Store(initiator, struct{}{})
+}
+
+// VoteAdd registers userID's vote in the active session, deduplicating by
+// user ID. ok is false if there's no active (unexpired) session to vote in,
+// in which case the caller should tell userID to start one with VoteStart.
+func (p *AudioPlayer) VoteAdd(userID string) (yes, needed int, ok bool) {
+	if p.VoteExpire() {
+		return 0, 0, false
+	}
+
+	p.votes.mu.RLock()
+	active := p.votes.active
+	p.votes.mu.RUnlock()
+	if !active {
+		return 0, 0, false
+	}
+
+	p.votes.voters.Store(userID, struct{}{})
+	yes, needed = p.VoteTally()
+	return yes, needed, true
+}
+
+// VoteTally returns the active session's current yes-vote count and how
+// many votes are required to pass, per this player's configured
+// ratio/minVotes threshold. Safe to call with no active session (returns
+// 0, 0).
+func (p *AudioPlayer) VoteTally() (yes, needed int) {
+	p.votes.mu.RLock()
+	eligible := p.votes.eligibleVoters
+	p.votes.mu.RUnlock()
+
+	count := 0
+	p.votes.voters.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+
+	return count, p.requiredVotes(eligible)
+}
+
+// requiredVotes computes the pass threshold from eligibleVoters using this
+// player's configured ratio (rounded up), raised to minVotes if that's
+// higher, with an absolute floor of 1 so a lone listener can always pass a
+// vote.
+func (p *AudioPlayer) requiredVotes(eligibleVoters int) int {
+	p.mu.RLock()
+	ratio := p.voteCfg.ratio
+	minVotes := p.voteCfg.minVotes
+	p.mu.RUnlock()
+	if ratio <= 0 {
+		ratio = defaultVoteThresholdRatio
+	}
+
+	needed := int(math.Ceil(float64(eligibleVoters) * ratio))
+	if minVotes > needed {
+		needed = minVotes
+	}
+	if needed < 1 {
+		needed = 1
+	}
+	return needed
+}
+
+// VoteExpire clears the active session if its ttl has elapsed since
+// VoteStart, reporting whether it did so. VoteAdd calls this before
+// registering a vote, so a session nobody finished voting on doesn't linger
+// forever.
+func (p *AudioPlayer) VoteExpire() bool {
+	p.votes.mu.Lock()
+	defer p.votes.mu.Unlock()
+	if !p.votes.active {
+		return false
+	}
+	if time.Since(p.votes.startedAt) < p.votes.ttl {
+		return false
+	}
+	p.votes.active = false
+	p.votes.voters = sync.Map{}
+	return true
+}
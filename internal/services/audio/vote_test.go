@@ -0,0 +1,86 @@
+package audio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+func newTestVotePlayer() *audio.AudioPlayer {
+	log := logger.New(logger.Config{Level: "error"})
+	vc := audio.NewVoiceConnection("test-guild-vote", log)
+	return audio.NewAudioPlayer("test-guild-vote", vc, log)
+}
+
+func TestVoteStartAndTally(t *testing.T) {
+	player := newTestVotePlayer()
+
+	player.VoteStart(audio.VoteKindSkip, "user-1", 4)
+
+	current, required := player.VoteTally()
+	if current != 1 {
+		t.Errorf("Expected 1 vote after VoteStart, got %d", current)
+	}
+	if required != 2 {
+		t.Errorf("Expected 2 required votes for 4 eligible at default ratio, got %d", required)
+	}
+}
+
+func TestVoteAddDeduplicatesByUser(t *testing.T) {
+	player := newTestVotePlayer()
+	player.VoteStart(audio.VoteKindSkip, "user-1", 4)
+
+	current, _, ok := player.VoteAdd("user-1")
+	if !ok {
+		t.Fatal("Expected VoteAdd to find an active session")
+	}
+	if current != 1 {
+		t.Errorf("Expected duplicate vote from the initiator to not inflate the tally, got %d", current)
+	}
+
+	current, required, ok := player.VoteAdd("user-2")
+	if !ok {
+		t.Fatal("Expected VoteAdd to find an active session")
+	}
+	if current != 2 || required != 2 {
+		t.Errorf("Expected vote to pass at 2/%d, got %d/%d", required, current, required)
+	}
+}
+
+func TestVoteAddWithoutActiveSession(t *testing.T) {
+	player := newTestVotePlayer()
+
+	if _, _, ok := player.VoteAdd("user-1"); ok {
+		t.Error("Expected VoteAdd to fail with no active session")
+	}
+}
+
+func TestVoteConfigOverridesThreshold(t *testing.T) {
+	player := newTestVotePlayer()
+	player.SetVoteConfig(1.0, 3, 0)
+
+	player.VoteStart(audio.VoteKindStop, "user-1", 4)
+
+	_, required := player.VoteTally()
+	if required != 4 {
+		t.Errorf("Expected ratio 1.0 over 4 eligible voters to require 4, got %d", required)
+	}
+}
+
+func TestVoteExpire(t *testing.T) {
+	player := newTestVotePlayer()
+	player.SetVoteConfig(0, 0, time.Millisecond)
+
+	player.VoteStart(audio.VoteKindPause, "user-1", 2)
+	time.Sleep(5 * time.Millisecond)
+
+	if !player.VoteExpire() {
+		t.Error("Expected an expired session to be reported as expired")
+	}
+
+	if _, _, ok := player.VoteAdd("user-2"); ok {
+		t.Error("Expected VoteAdd to find no active session after expiry")
+	}
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// sweepInterval is how often Manager scans both stores for stale/oversized
+// entries
+const sweepInterval = 30 * time.Minute
+
+// Manager bundles the persistent stores ResolveSongURLs draws on: a
+// Spotify-track-ID -> YouTube-video-ID store, an ISRC -> YouTube-video-ID
+// store, and a YouTube-URL -> metadata store. Bundling them lets /cache
+// stats and /cache clear treat "the song resolution cache" as one unit
+// instead of the caller juggling three handles.
+type Manager struct {
+	SpotifyToYouTube *Store
+	ISRCToYouTube    *Store
+	YouTubeMetadata  *Store
+
+	logger *logger.Logger
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewManager creates a Manager rooted at baseDir, splitting maxBytes evenly
+// between its stores. protect reports whether a key must never be evicted -
+// passed to all three stores, though in practice only YouTubeMetadata
+// (keyed by the song URL also used as the currently-playing song's
+// identity) ever matches it.
+func NewManager(baseDir string, expireAfter time.Duration, maxBytes int64, protect func(key string) bool, log *logger.Logger) (*Manager, error) {
+	perStoreMax := maxBytes / 3
+
+	spotifyToYouTube, err := NewStore(filepath.Join(baseDir, "spotify_youtube"), expireAfter, perStoreMax, protect, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// An ISRC identifies the exact recording, not a Spotify catalog entry,
+	// so unlike SpotifyToYouTube it never goes stale - pass expireAfter 0
+	// (never expire) rather than the store's usual TTL.
+	isrcToYouTube, err := NewStore(filepath.Join(baseDir, "isrc_youtube"), 0, perStoreMax, protect, log)
+	if err != nil {
+		return nil, err
+	}
+
+	youtubeMetadata, err := NewStore(filepath.Join(baseDir, "youtube_metadata"), expireAfter, perStoreMax, protect, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		SpotifyToYouTube: spotifyToYouTube,
+		ISRCToYouTube:    isrcToYouTube,
+		YouTubeMetadata:  youtubeMetadata,
+		logger:           log,
+		stopCh:           make(chan struct{}),
+	}, nil
+}
+
+// Start runs a periodic sweep of both stores in a background goroutine
+func (m *Manager) Start() {
+	m.wg.Add(1)
+	go m.run()
+
+	m.logger.WithField("interval", sweepInterval).Info("Song resolution cache sweeper started")
+}
+
+// Stop signals the sweep loop to exit and waits for it to finish
+func (m *Manager) Stop() {
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+func (m *Manager) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sweepOnce()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+func (m *Manager) sweepOnce() {
+	removed := 0
+	for _, store := range []*Store{m.SpotifyToYouTube, m.ISRCToYouTube, m.YouTubeMetadata} {
+		n, err := store.Sweep()
+		if err != nil {
+			m.logger.WithError(err).Warn("Song resolution cache sweep failed")
+			continue
+		}
+		removed += n
+	}
+	if removed > 0 {
+		m.logger.WithField("removed", removed).Info("🧹 Song resolution cache sweep evicted stale entries")
+	}
+}
+
+// Stats reports the combined entry count and on-disk size of both stores
+func (m *Manager) Stats() (Stats, error) {
+	var total Stats
+	for _, store := range []*Store{m.SpotifyToYouTube, m.ISRCToYouTube, m.YouTubeMetadata} {
+		stats, err := store.Stats()
+		if err != nil {
+			return Stats{}, err
+		}
+		total.Entries += stats.Entries
+		total.Bytes += stats.Bytes
+	}
+	return total, nil
+}
+
+// Clear empties all stores
+func (m *Manager) Clear() error {
+	if err := m.SpotifyToYouTube.Clear(); err != nil {
+		return err
+	}
+	if err := m.ISRCToYouTube.Clear(); err != nil {
+		return err
+	}
+	return m.YouTubeMetadata.Clear()
+}
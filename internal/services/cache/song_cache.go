@@ -0,0 +1,264 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// SongCache persists the decoded audio of played YouTube videos to disk as
+// Opus files under dir, one <videoID>.opus per video, so replaying a song
+// skips yt-dlp's network download entirely. Unlike Store (small JSON
+// lookups), entries here are full audio files and are written once after a
+// track finishes playing rather than on every resolution, so eviction is
+// driven by file mtimes instead of a stored-at timestamp.
+type SongCache struct {
+	dir         string
+	ytDlpPath   string
+	expireAfter time.Duration
+	maxBytes    int64
+	protect     func(videoID string) bool
+	logger      *logger.Logger
+
+	mu     sync.Mutex
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSongCache creates a song audio cache rooted at dir, creating it if
+// needed. protect reports whether a videoID must never be evicted (it backs
+// whatever's currently playing in some guild); a nil protect never protects
+// anything.
+func NewSongCache(dir, ytDlpPath string, expireAfter time.Duration, maxBytes int64, protect func(videoID string) bool, log *logger.Logger) (*SongCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create song cache directory: %w", err)
+	}
+	if protect == nil {
+		protect = func(string) bool { return false }
+	}
+
+	return &SongCache{
+		dir:         dir,
+		ytDlpPath:   ytDlpPath,
+		expireAfter: expireAfter,
+		maxBytes:    maxBytes,
+		protect:     protect,
+		logger:      log,
+		stopCh:      make(chan struct{}),
+	}, nil
+}
+
+// Start runs a periodic Sweep in a background goroutine, on the same
+// interval as the song resolution cache's Manager
+func (c *SongCache) Start() {
+	c.wg.Add(1)
+	go c.run()
+
+	c.logger.WithField("interval", sweepInterval).Info("Song audio cache sweeper started")
+}
+
+// Stop signals the sweep loop to exit and waits for it to finish
+func (c *SongCache) Stop() {
+	close(c.stopCh)
+	c.wg.Wait()
+}
+
+func (c *SongCache) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if removed, err := c.Sweep(); err != nil {
+				c.logger.WithError(err).Warn("Song audio cache sweep failed")
+			} else if removed > 0 {
+				c.logger.WithField("removed", removed).Info("🧹 Song audio cache sweep evicted stale entries")
+			}
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+func (c *SongCache) path(videoID string) string {
+	return filepath.Join(c.dir, videoID+".opus")
+}
+
+// Path returns the file:// URL of videoID's cached audio and true, if it's
+// been downloaded and hasn't aged out - touching its mtime so Sweep's LRU
+// pass treats this as a fresh access. Callers should fall back to a network
+// stream URL when ok is false.
+func (c *SongCache) Path(videoID string) (url string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := c.path(videoID)
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", false
+	}
+
+	if c.expireAfter > 0 && time.Since(info.ModTime()) > c.expireAfter && !c.protect(videoID) {
+		_ = os.Remove(p)
+		return "", false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(p, now, now)
+
+	return "file://" + p, true
+}
+
+// Download fetches videoID's best audio track from sourceURL via yt-dlp and
+// stores it as Opus, unless it's already cached. Meant to run in a
+// background goroutine once a track has finished playing successfully, so
+// the first play of a song pays no extra yt-dlp cost over the existing
+// stream-and-encode pipeline.
+func (c *SongCache) Download(videoID, sourceURL string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dest := c.path(videoID)
+	if _, err := os.Stat(dest); err == nil {
+		return nil
+	}
+
+	args := []string{
+		"-x", "--audio-format", "opus",
+		"--no-playlist",
+		"--no-check-certificate",
+		"--geo-bypass",
+		"--quiet",
+		"--no-warnings",
+		"-o", filepath.Join(c.dir, videoID+".%(ext)s"),
+		sourceURL,
+	}
+
+	cmd := exec.Command(c.ytDlpPath, args...)
+	if err := cmd.Run(); err != nil {
+		_ = os.Remove(dest)
+		return fmt.Errorf("failed to download song to cache: %w", err)
+	}
+
+	return nil
+}
+
+// Stats reports the cache's current entry count and on-disk size
+func (c *SongCache) Stats() (Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	var stats Stats
+	for _, de := range entries {
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+	}
+	return stats, nil
+}
+
+// Clear removes every cached song file
+func (c *SongCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, de := range entries {
+		_ = os.Remove(filepath.Join(c.dir, de.Name()))
+	}
+	return nil
+}
+
+// Sweep removes files older than expireAfter, then - if the cache is still
+// over maxBytes - evicts the least recently accessed remaining files until
+// it fits. Protected videoIDs survive both passes. Returns how many files
+// were removed.
+func (c *SongCache) Sweep() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	type alive struct {
+		path       string
+		videoID    string
+		size       int64
+		accessedAt time.Time
+	}
+
+	removed := 0
+	var survivors []alive
+
+	for _, de := range files {
+		p := filepath.Join(c.dir, de.Name())
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+
+		videoID := strings.TrimSuffix(de.Name(), ".opus")
+
+		if c.expireAfter > 0 && time.Since(info.ModTime()) > c.expireAfter && !c.protect(videoID) {
+			if err := os.Remove(p); err == nil {
+				removed++
+			}
+			continue
+		}
+
+		survivors = append(survivors, alive{path: p, videoID: videoID, size: info.Size(), accessedAt: info.ModTime()})
+	}
+
+	if c.maxBytes <= 0 {
+		return removed, nil
+	}
+
+	var total int64
+	for _, a := range survivors {
+		total += a.size
+	}
+	if total <= c.maxBytes {
+		return removed, nil
+	}
+
+	sort.Slice(survivors, func(i, j int) bool { return survivors[i].accessedAt.Before(survivors[j].accessedAt) })
+
+	for _, a := range survivors {
+		if total <= c.maxBytes {
+			break
+		}
+		if c.protect(a.videoID) {
+			continue
+		}
+		if err := os.Remove(a.path); err != nil {
+			continue
+		}
+		total -= a.size
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,250 @@
+// Package cache provides a persistent, disk-backed cache for the
+// (Spotify track ID -> YouTube video ID) and (YouTube URL -> metadata)
+// lookups ResolveSongURLs performs, so a popular Spotify playlist doesn't
+// re-run the same yt-dlp search or extraction on every /play.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// record is the on-disk shape of one cached entry. Key is kept alongside
+// Value (rather than relying on the filename, which is a hash of Key) so a
+// sweep can still report which logical key a file belongs to.
+type record struct {
+	Key        string          `json:"key"`
+	Value      json.RawMessage `json:"value"`
+	StoredAt   time.Time       `json:"stored_at"`
+	AccessedAt time.Time       `json:"accessed_at"`
+}
+
+// Stats summarizes a Store's on-disk footprint
+type Stats struct {
+	Entries int
+	Bytes   int64
+}
+
+// Store is a persistent cache keyed by arbitrary strings, one JSON file per
+// key under dir. Sweep drops entries older than expireAfter, then - if the
+// directory is still over maxBytes - evicts the least recently accessed
+// remaining entries until it fits. protect reports whether a key must never
+// be evicted regardless of age or size pressure (e.g. it backs a song
+// that's currently playing); a nil protect never protects anything.
+type Store struct {
+	dir         string
+	expireAfter time.Duration
+	maxBytes    int64
+	protect     func(key string) bool
+	logger      *logger.Logger
+
+	mu sync.Mutex
+}
+
+// NewStore creates a persistent cache rooted at dir, creating it if needed.
+func NewStore(dir string, expireAfter time.Duration, maxBytes int64, protect func(key string) bool, log *logger.Logger) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if protect == nil {
+		protect = func(string) bool { return false }
+	}
+
+	return &Store{
+		dir:         dir,
+		expireAfter: expireAfter,
+		maxBytes:    maxBytes,
+		protect:     protect,
+		logger:      log,
+	}, nil
+}
+
+// path returns the file a key is stored under - a hash of the key rather
+// than the key itself, since keys here (URLs, Spotify IDs) aren't
+// guaranteed to be safe filenames
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get unmarshals the cached value for key into out, reporting whether an
+// unexpired entry was found. A cache hit refreshes the entry's access time
+// for the LRU eviction Sweep performs.
+func (s *Store) Get(key string, out interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return false, err
+	}
+
+	if s.expireAfter > 0 && time.Since(rec.StoredAt) > s.expireAfter && !s.protect(rec.Key) {
+		_ = os.Remove(s.path(key))
+		return false, nil
+	}
+
+	if err := json.Unmarshal(rec.Value, out); err != nil {
+		return false, err
+	}
+
+	rec.AccessedAt = time.Now()
+	if data, err := json.Marshal(rec); err == nil {
+		_ = os.WriteFile(s.path(key), data, 0644)
+	}
+
+	return true, nil
+}
+
+// Set stores value under key, overwriting any existing entry
+func (s *Store) Set(key string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	data, err := json.Marshal(record{Key: key, Value: raw, StoredAt: now, AccessedAt: now})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(key), data, 0644)
+}
+
+// Clear removes every entry from the store
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, de := range entries {
+		_ = os.Remove(filepath.Join(s.dir, de.Name()))
+	}
+	return nil
+}
+
+// Stats reports the store's current entry count and on-disk size
+func (s *Store) Stats() (Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	var stats Stats
+	for _, de := range entries {
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+	}
+	return stats, nil
+}
+
+// Sweep removes expired entries, then - if the store is still over
+// maxBytes - evicts the least recently accessed remaining entries until it
+// fits. Protected keys survive both passes. It returns how many entries
+// were removed.
+func (s *Store) Sweep() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	type alive struct {
+		path       string
+		key        string
+		size       int64
+		accessedAt time.Time
+	}
+
+	removed := 0
+	var survivors []alive
+
+	for _, de := range files {
+		p := filepath.Join(s.dir, de.Name())
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			continue
+		}
+
+		if s.expireAfter > 0 && time.Since(rec.StoredAt) > s.expireAfter && !s.protect(rec.Key) {
+			if err := os.Remove(p); err == nil {
+				removed++
+			}
+			continue
+		}
+
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		survivors = append(survivors, alive{path: p, key: rec.Key, size: info.Size(), accessedAt: rec.AccessedAt})
+	}
+
+	if s.maxBytes <= 0 {
+		return removed, nil
+	}
+
+	var total int64
+	for _, a := range survivors {
+		total += a.size
+	}
+	if total <= s.maxBytes {
+		return removed, nil
+	}
+
+	sort.Slice(survivors, func(i, j int) bool { return survivors[i].accessedAt.Before(survivors[j].accessedAt) })
+
+	for _, a := range survivors {
+		if total <= s.maxBytes {
+			break
+		}
+		if s.protect(a.key) {
+			continue
+		}
+		if err := os.Remove(a.path); err != nil {
+			continue
+		}
+		total -= a.size
+		removed++
+	}
+
+	return removed, nil
+}
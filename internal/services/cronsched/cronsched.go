@@ -0,0 +1,143 @@
+// Package cronsched parses standard 5-field cron expressions and checks
+// whether a given time matches them, so the playlist sync scheduler can
+// honor per-playlist schedule overrides without pulling in an external cron
+// dependency.
+package cronsched
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed 5-field cron expression: minute, hour, day-of-month,
+// month, day-of-week. Each field is a set of matching values; "*" matches
+// everything.
+type Schedule struct {
+	minute  fieldSet
+	hour    fieldSet
+	dom     fieldSet
+	month   fieldSet
+	dow     fieldSet
+	rawExpr string
+}
+
+// fieldSet is the set of values a cron field matches; a nil set means "*"
+type fieldSet map[int]bool
+
+var fieldRanges = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 = Sunday)
+}
+
+// Parse parses a standard 5-field cron expression ("minute hour dom month
+// dow"), supporting "*", single values, ranges ("1-5"), steps ("*/15",
+// "1-30/5") and comma-separated lists of any of the above.
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cronsched: expected 5 fields, got %d in %q", len(fields), expr)
+	}
+
+	sets := make([]fieldSet, 5)
+	for i, field := range fields {
+		set, err := parseField(field, fieldRanges[i][0], fieldRanges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cronsched: field %d (%q): %w", i, field, err)
+		}
+		sets[i] = set
+	}
+
+	return &Schedule{
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		rawExpr: expr,
+	}, nil
+}
+
+// String returns the original cron expression
+func (s *Schedule) String() string {
+	return s.rawExpr
+}
+
+// Matches reports whether t falls within this schedule's minute-granularity
+// window, following standard cron semantics where day-of-month and
+// day-of-week are OR'd together when both are restricted.
+func (s *Schedule) Matches(t time.Time) bool {
+	if !s.minute.matches(t.Minute()) || !s.hour.matches(t.Hour()) || !s.month.matches(int(t.Month())) {
+		return false
+	}
+
+	if s.dom == nil || s.dow == nil {
+		return s.dom.matches(t.Day()) && s.dow.matches(int(t.Weekday()))
+	}
+	return s.dom.matches(t.Day()) || s.dow.matches(int(t.Weekday()))
+}
+
+// parseField parses a single cron field into the set of values it matches
+func parseField(field string, min, max int) (fieldSet, error) {
+	if field == "*" {
+		return nil, nil
+	}
+
+	set := fieldSet{}
+	for _, part := range strings.Split(field, ",") {
+		lo, hi, step := min, max, 1
+
+		rangePart := part
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+			rangePart = part[:idx]
+		}
+
+		switch {
+		case rangePart == "*":
+			// lo, hi already default to the field's full range
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			lo, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range start %q", bounds[0])
+			}
+			hi, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range end %q", bounds[1])
+			}
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = v, v
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("value %q out of range [%d-%d]", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+func (s fieldSet) matches(v int) bool {
+	if s == nil {
+		return true
+	}
+	return s[v]
+}
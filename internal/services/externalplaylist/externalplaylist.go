@@ -0,0 +1,211 @@
+// Package externalplaylist imports remote playlists (YouTube, Spotify,
+// SoundCloud) into local entities.Playlist records that can later be
+// re-synced against their source.
+package externalplaylist
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/soundcloud"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+	sourcesspotify "github.com/vuongmanhnghia/discord-music-bot/internal/sources/spotify"
+)
+
+// Entry is a single track discovered on the remote source
+type Entry struct {
+	OriginalInput string
+	Title         string
+	SourceType    valueobjects.SourceType
+	ExternalID    string
+}
+
+// ImportResult is what a PlaylistImporter returns for a given URL
+type ImportResult struct {
+	Source     valueobjects.SourceType
+	ExternalID string
+	Entries    []Entry
+}
+
+// PlaylistImporter fetches the current contents of a remote playlist
+type PlaylistImporter interface {
+	// CanImport reports whether this importer handles the given URL
+	CanImport(url string) bool
+	// Import fetches the playlist's current tracks
+	Import(url string) (*ImportResult, error)
+}
+
+// Registry picks the right importer for a URL out of a fixed set
+type Registry struct {
+	importers []PlaylistImporter
+}
+
+// NewRegistry builds a registry from the available importers, skipping any
+// nil entries (e.g. Spotify when no credentials are configured)
+func NewRegistry(importers ...PlaylistImporter) *Registry {
+	r := &Registry{}
+	for _, imp := range importers {
+		if imp != nil {
+			r.importers = append(r.importers, imp)
+		}
+	}
+	return r
+}
+
+// For returns the importer that can handle the given URL
+func (r *Registry) For(url string) (PlaylistImporter, error) {
+	for _, imp := range r.importers {
+		if imp.CanImport(url) {
+			return imp, nil
+		}
+	}
+	return nil, fmt.Errorf("no importer available for URL: %s", url)
+}
+
+// YouTubeImporter imports YouTube playlists via the existing yt-dlp service
+type YouTubeImporter struct {
+	yt *youtube.Service
+}
+
+// NewYouTubeImporter creates a YouTube playlist importer
+func NewYouTubeImporter(yt *youtube.Service) *YouTubeImporter {
+	return &YouTubeImporter{yt: yt}
+}
+
+// CanImport reports whether the URL is a YouTube playlist
+func (i *YouTubeImporter) CanImport(url string) bool {
+	return youtube.IsYouTubeURL(url) && youtube.IsPlaylistURL(url)
+}
+
+// Import fetches all videos in the playlist
+func (i *YouTubeImporter) Import(url string) (*ImportResult, error) {
+	videos, err := i.yt.ExtractPlaylist(url)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(videos))
+	for _, v := range videos {
+		entries = append(entries, Entry{
+			OriginalInput: v.WebpageURL,
+			Title:         v.Title,
+			SourceType:    valueobjects.SourceTypeYouTube,
+			ExternalID:    v.ID,
+		})
+	}
+
+	return &ImportResult{
+		Source:     valueobjects.SourceTypeYouTube,
+		ExternalID: url,
+		Entries:    entries,
+	}, nil
+}
+
+// SpotifyImporter imports Spotify playlists/albums via the Spotify Web API,
+// resolving each track to a playable YouTube URL through the shared
+// ISRC-first bridge instead of leaving a lossy search query for playback
+// time to fail on
+type SpotifyImporter struct {
+	sp     *spotify.Service
+	bridge *sourcesspotify.Bridge
+}
+
+// NewSpotifyImporter creates a Spotify playlist importer
+func NewSpotifyImporter(sp *spotify.Service, bridge *sourcesspotify.Bridge) *SpotifyImporter {
+	return &SpotifyImporter{sp: sp, bridge: bridge}
+}
+
+// CanImport reports whether the URL is a Spotify playlist or album
+func (i *SpotifyImporter) CanImport(url string) bool {
+	urlType, _, err := spotify.ParseSpotifyURL(url)
+	return err == nil && (urlType == "playlist" || urlType == "album")
+}
+
+// Import fetches all tracks in the Spotify playlist/album
+func (i *SpotifyImporter) Import(url string) (*ImportResult, error) {
+	urlType, id, err := spotify.ParseSpotifyURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []spotify.Track
+	switch urlType {
+	case "playlist":
+		tracks, err = i.sp.GetPlaylistTracks(context.Background(), id)
+	case "album":
+		tracks, err = i.sp.GetAlbumTracks(context.Background(), id)
+	default:
+		return nil, fmt.Errorf("unsupported spotify URL type: %s", urlType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(tracks))
+	for _, t := range tracks {
+		originalInput := t.ToSearchQuery()
+		sourceType := valueobjects.SourceTypeSpotify
+		if i.bridge != nil {
+			if videoID := i.bridge.ResolveTrack(t); videoID != "" {
+				originalInput = fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+				sourceType = valueobjects.SourceTypeYouTube
+			}
+		}
+
+		entries = append(entries, Entry{
+			OriginalInput: originalInput,
+			Title:         t.Name,
+			SourceType:    sourceType,
+			ExternalID:    t.ID,
+		})
+	}
+
+	return &ImportResult{
+		Source:     valueobjects.SourceTypeSpotify,
+		ExternalID: id,
+		Entries:    entries,
+	}, nil
+}
+
+// SoundCloudImporter imports SoundCloud sets, reusing the YouTube (yt-dlp)
+// service since yt-dlp extracts SoundCloud sets the same way it does
+// YouTube playlists
+type SoundCloudImporter struct {
+	yt *youtube.Service
+}
+
+// NewSoundCloudImporter creates a SoundCloud playlist importer
+func NewSoundCloudImporter(yt *youtube.Service) *SoundCloudImporter {
+	return &SoundCloudImporter{yt: yt}
+}
+
+// CanImport reports whether the URL is a SoundCloud set
+func (i *SoundCloudImporter) CanImport(url string) bool {
+	return soundcloud.IsPlaylistURL(url)
+}
+
+// Import fetches all tracks in the SoundCloud set
+func (i *SoundCloudImporter) Import(url string) (*ImportResult, error) {
+	tracks, err := i.yt.ExtractPlaylist(url)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(tracks))
+	for _, t := range tracks {
+		entries = append(entries, Entry{
+			OriginalInput: t.WebpageURL,
+			Title:         t.Title,
+			SourceType:    valueobjects.SourceTypeSoundCloud,
+			ExternalID:    t.ID,
+		})
+	}
+
+	return &ImportResult{
+		Source:     valueobjects.SourceTypeSoundCloud,
+		ExternalID: url,
+		Entries:    entries,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
+)
+
+// ToggleFilter flips a single named DSP effect (bassboost, nightcore,
+// vaporwave, 8d, karaoke) on or off for guildID, leaving the rest of the
+// chain and the equalizer bands untouched, and reports the effect's new
+// state. If no player is connected yet, it returns ErrNotPlaying - a guild
+// must be in a voice channel before it has a filter chain to edit.
+func (s *PlaybackService) ToggleFilter(guildID, effect string) (enabled bool, err error) {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return false, ErrNotPlaying
+	}
+
+	spec := player.GetFilters()
+	if spec == nil {
+		spec = audio.NewFilterSpec()
+	} else {
+		copied := *spec
+		spec = &copied
+	}
+
+	switch effect {
+	case "bassboost":
+		spec.BassBoost = !spec.BassBoost
+		enabled = spec.BassBoost
+	case "nightcore":
+		spec.Nightcore = !spec.Nightcore
+		enabled = spec.Nightcore
+	case "vaporwave":
+		spec.Vaporwave = !spec.Vaporwave
+		enabled = spec.Vaporwave
+	case "8d":
+		spec.EightD = !spec.EightD
+		enabled = spec.EightD
+	case "karaoke":
+		spec.Karaoke = !spec.Karaoke
+		enabled = spec.Karaoke
+	default:
+		return false, ErrInvalidFilter
+	}
+
+	return enabled, player.SetFilters(spec)
+}
+
+// SetEqualizerBand sets a single equalizer band (0-14, see
+// audio.EqualizerBands) to a gain in dB (-12..12) for guildID, leaving
+// every other band and effect toggle untouched.
+func (s *PlaybackService) SetEqualizerBand(guildID string, band int, gainDB float64) error {
+	if band < 0 || band >= audio.EqualizerBands {
+		return ErrInvalidFilter
+	}
+
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return ErrNotPlaying
+	}
+
+	spec := player.GetFilters()
+	if spec == nil {
+		spec = audio.NewFilterSpec()
+	} else {
+		copied := *spec
+		spec = &copied
+	}
+
+	spec.EqualizerGains[band] = gainDB
+	return player.SetFilters(spec)
+}
+
+// ResetFilters clears guildID's entire DSP filter chain, turning off every
+// effect and flattening the equalizer.
+func (s *PlaybackService) ResetFilters(guildID string) error {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return ErrNotPlaying
+	}
+	return player.SetFilters(audio.NewFilterSpec())
+}
+
+// GetFilters returns guildID's current DSP filter chain. It never returns
+// nil: a guild with no player connected, or no filters set, gets an empty
+// FilterSpec.
+func (s *PlaybackService) GetFilters(guildID string) *audio.FilterSpec {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return audio.NewFilterSpec()
+	}
+	spec := player.GetFilters()
+	if spec == nil {
+		return audio.NewFilterSpec()
+	}
+	return spec
+}
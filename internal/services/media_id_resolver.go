@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/soundcloud"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+)
+
+// MediaIDResolver normalizes a song's raw input into a stable cross-platform
+// ID (e.g. "yt:VIDEOID", "sp:TRACKID", "sc:soundcloud.com/artist/track") so
+// that differently-formatted URLs pointing at the same track (a youtu.be
+// share link vs. the canonical youtube.com/watch?v= URL, for instance) are
+// recognized as the same entry. Resolve returns "" for inputs it doesn't
+// recognize (plain search queries, generic URLs), in which case callers
+// should fall back to matching on the raw input itself.
+type MediaIDResolver interface {
+	Resolve(originalInput string, sourceType valueobjects.SourceType) string
+}
+
+// defaultMediaIDResolver implements MediaIDResolver using each platform's
+// own URL-parsing helpers. It never makes a network call, so it's cheap
+// enough to run on every AddToPlaylist.
+type defaultMediaIDResolver struct{}
+
+// NewMediaIDResolver creates the default MediaIDResolver
+func NewMediaIDResolver() MediaIDResolver {
+	return &defaultMediaIDResolver{}
+}
+
+// Resolve sniffs originalInput's URL shape directly rather than trusting
+// sourceType, so callers that don't have it handy (e.g.
+// RemoveFromPlaylistForGuild, which only gets the raw input back from the
+// user) still get a usable ResolvedID.
+func (r *defaultMediaIDResolver) Resolve(originalInput string, sourceType valueobjects.SourceType) string {
+	if videoID := youtube.VideoIDFromURL(originalInput); videoID != "" {
+		return "yt:" + videoID
+	}
+	if _, id, err := spotify.ParseSpotifyURL(originalInput); err == nil && id != "" {
+		return "sp:" + id
+	}
+	if soundcloud.IsSoundCloudURL(originalInput) {
+		return "sc:" + normalizeSoundCloudURL(originalInput)
+	}
+	return ""
+}
+
+// normalizeSoundCloudURL strips the scheme, query string, and trailing
+// slash from a SoundCloud track URL, since SoundCloud track IDs aren't
+// embedded in the URL itself (resolving one requires an API call) but the
+// www./non-www and http/https variants of the same path are common enough
+// to be worth collapsing.
+func normalizeSoundCloudURL(url string) string {
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	url = strings.TrimPrefix(url, "www.")
+	if i := strings.IndexAny(url, "?#"); i != -1 {
+		url = url[:i]
+	}
+	return strings.TrimSuffix(url, "/")
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
+)
+
+// nowPlayingUpdateInterval is how often the live now-playing message is
+// edited with a fresh progress bar while a song plays
+const nowPlayingUpdateInterval = 5 * time.Second
+
+// nowPlayingColor and nowPlayingPlayedColor mirror the blurple/green the
+// command layer uses for its embeds (see commands.ColorPrimary/ColorSuccess);
+// duplicated here since this package can't import commands
+const (
+	nowPlayingColor       = 0x5865F2
+	nowPlayingPlayedColor = 0x57F287
+)
+
+// BindNowPlayingChannel sets the text channel where PlaybackService posts a
+// live, self-updating "Now Playing" message for guildID. Pass "" to unbind
+// and stop posting future messages; a message already in flight still runs
+// to completion.
+func (s *PlaybackService) BindNowPlayingChannel(guildID, channelID string) {
+	state := s.getOrCreateState(guildID)
+	state.nowPlayingMu.Lock()
+	state.nowPlayingChannelID = channelID
+	state.nowPlayingMu.Unlock()
+
+	s.ScheduleStateSave(guildID)
+}
+
+// NotifyAutoDisconnect posts a notice to guildID's bound now-playing channel
+// that the idle/alone watcher disconnected it for reason. Wired to
+// AudioService.SetAutoDisconnectHook from bot.go, and called just before the
+// watcher tears down the voice connection.
+func (s *PlaybackService) NotifyAutoDisconnect(guildID, reason string) {
+	state := s.getState(guildID)
+	if state == nil {
+		return
+	}
+
+	state.nowPlayingMu.Lock()
+	channelID := state.nowPlayingChannelID
+	state.nowPlayingMu.Unlock()
+
+	if channelID == "" {
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "👋 Auto-disconnected",
+		Description: fmt.Sprintf("Left the voice channel: %s", reason),
+		Color:       nowPlayingColor,
+	}
+	if _, err := s.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}); err != nil {
+		s.logger.WithError(err).WithField("guild", guildID).Debug("Failed to post auto-disconnect notice")
+	}
+}
+
+// TogglePause flips playback between paused and playing for guildID and
+// reports which state it ended up in, for callers (the now-playing ⏯️
+// button) that don't know which one currently applies. See
+// PlaybackService.Play for ctx.
+func (s *PlaybackService) TogglePause(ctx context.Context, guildID string) (paused bool, err error) {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return false, ErrNotPlaying
+	}
+	if player.IsPaused() {
+		return false, player.Resume(ctx)
+	}
+	return true, player.Pause(ctx)
+}
+
+// CycleRepeatMode advances a guild's repeat mode to the next in the cycle
+// none -> track -> queue -> none, for callers (the now-playing 🔁 button)
+// that toggle rather than set an explicit mode.
+func (s *PlaybackService) CycleRepeatMode(guildID string) entities.RepeatMode {
+	state := s.getOrCreateState(guildID)
+
+	next := entities.RepeatModeTrack
+	switch state.tracklist.GetRepeatMode() {
+	case entities.RepeatModeTrack:
+		next = entities.RepeatModeQueue
+	case entities.RepeatModeQueue:
+		next = entities.RepeatModeNone
+	}
+
+	state.tracklist.SetRepeatMode(next)
+	return next
+}
+
+// runNowPlayingUpdater posts a rich now-playing embed for song in state's
+// bound channel and edits it every nowPlayingUpdateInterval with updated
+// elapsed/total progress until songCtx is done, at which point it finalizes
+// the message to a "played" state and drops its buttons. It deletes the
+// previous now-playing message first so a fast-moving queue doesn't leave
+// one stale message per track behind.
+func (s *PlaybackService) runNowPlayingUpdater(songCtx context.Context, state *GuildPlaybackState, song *entities.Song, player *audio.AudioPlayer) {
+	state.nowPlayingMu.Lock()
+	channelID := state.nowPlayingChannelID
+	prevMessageID := state.nowPlayingMessageID
+	state.nowPlayingMu.Unlock()
+
+	if channelID == "" {
+		return
+	}
+
+	if prevMessageID != "" {
+		if err := s.session.ChannelMessageDelete(channelID, prevMessageID); err != nil {
+			s.logger.WithError(err).Debug("Failed to collapse previous now-playing message")
+		}
+	}
+
+	msg, err := s.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{buildNowPlayingEmbed(song, player, nowPlayingColor, "▶️ Now Playing")},
+		Components: nowPlayingComponents(),
+	})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to post now-playing message")
+		return
+	}
+
+	state.nowPlayingMu.Lock()
+	state.nowPlayingMessageID = msg.ID
+	state.nowPlayingMu.Unlock()
+
+	ticker := time.NewTicker(nowPlayingUpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			embed := buildNowPlayingEmbed(song, player, nowPlayingColor, "▶️ Now Playing")
+			components := nowPlayingComponents()
+			_, err := s.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				Channel:    channelID,
+				ID:         msg.ID,
+				Embeds:     &[]*discordgo.MessageEmbed{embed},
+				Components: &components,
+			})
+			if err != nil {
+				s.logger.WithError(err).Debug("Failed to update now-playing progress")
+			}
+		case <-songCtx.Done():
+			finalEmbed := buildNowPlayingEmbed(song, player, nowPlayingPlayedColor, "✅ Played")
+			emptyComponents := []discordgo.MessageComponent{}
+			_, err := s.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				Channel:    channelID,
+				ID:         msg.ID,
+				Embeds:     &[]*discordgo.MessageEmbed{finalEmbed},
+				Components: &emptyComponents,
+			})
+			if err != nil {
+				s.logger.WithError(err).Debug("Failed to finalize now-playing message")
+			}
+			return
+		}
+	}
+}
+
+// nowPlayingComponents builds the ⏯️/⏭️/🔁 control row attached to a live
+// now-playing message. Clicks route back through the command handler's
+// button dispatcher (custom ID prefix "npctl"), which gates them behind the
+// same session-owner/admin check as the /pause, /skip, and /repeat commands.
+func nowPlayingComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "⏯️",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "npctl:pause",
+				},
+				discordgo.Button{
+					Label:    "⏭️",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "npctl:skip",
+				},
+				discordgo.Button{
+					Label:    "🔁",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "npctl:repeat",
+				},
+			},
+		},
+	}
+}
+
+// buildNowPlayingEmbed renders song's title/uploader/thumbnail plus an
+// elapsed/total progress bar sourced from player.Position()/Duration().
+func buildNowPlayingEmbed(song *entities.Song, player *audio.AudioPlayer, color int, title string) *discordgo.MessageEmbed {
+	meta := song.GetMetadata()
+
+	embed := &discordgo.MessageEmbed{
+		Title: title,
+		Color: color,
+	}
+
+	if meta == nil {
+		embed.Description = fmt.Sprintf("**%s**", song.DisplayName())
+		return embed
+	}
+
+	embed.Description = fmt.Sprintf("**%s**", meta.Title)
+	if meta.Thumbnail != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: meta.Thumbnail}
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "Progress",
+		Value: progressBar(player.Position(), player.Duration()),
+	})
+	if meta.Uploader != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Artist",
+			Value:  meta.Uploader,
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+// progressBar renders a 10-segment elapsed/total bar like
+// "████░░░░░░ 01:15 / 03:45"
+func progressBar(elapsed, total time.Duration) string {
+	var filled int
+	if total > 0 {
+		filled = int(10 * elapsed / total)
+		if filled > 10 {
+			filled = 10
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < 10; i++ {
+		if i < filled {
+			sb.WriteString("█")
+		} else {
+			sb.WriteString("░")
+		}
+	}
+
+	return fmt.Sprintf("%s %s / %s", sb.String(), formatDuration(elapsed), formatDuration(total))
+}
+
+// formatDuration renders d as MM:SS, matching SongMetadata.DurationFormatted
+func formatDuration(d time.Duration) string {
+	total := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
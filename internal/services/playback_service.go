@@ -4,21 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/metrics"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/audio"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/externalplaylist"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/scrobble"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
+// maxScrobbleSubmitWait caps how long into a track we wait before submitting
+// a scrobble, so very long tracks don't wait for the full 50% mark
+const maxScrobbleSubmitWait = 4 * time.Minute
+
+// stateSaveDebounce coalesces bursts of state-changing operations (e.g. a
+// playlist import adding dozens of songs) into a single snapshot write
+const stateSaveDebounce = 2 * time.Second
+
 var (
 	// ErrNotPlaying is returned when no song is playing
 	ErrNotPlaying = errors.New("no song is currently playing")
 	// ErrAlreadyPlaying is returned when already playing
 	ErrAlreadyPlaying = errors.New("already playing")
+	// ErrNotOwner is returned when a caller other than the current session
+	// owner attempts to TransferOwner
+	ErrNotOwner = errors.New("not the session owner")
+	// ErrInvalidFilter is returned for an unknown effect name or an
+	// out-of-range equalizer band
+	ErrInvalidFilter = errors.New("invalid filter")
 )
 
 // PlaybackService orchestrates the complete playback flow
@@ -26,9 +46,31 @@ type PlaybackService struct {
 	session           *discordgo.Session
 	audioService      *audio.AudioService
 	processingService *ProcessingService
+	scrobbleService   *ScrobbleService
+	externalImporters *externalplaylist.Registry
+	metrics           metrics.Recorder
 	logger            *logger.Logger
 	guildStates       map[string]*GuildPlaybackState
 	mu                sync.RWMutex
+
+	// skipRatio is the fraction of non-bot voice-channel listeners required
+	// to approve a vote-skip
+	skipRatio float64
+
+	// maxPlaylistSize caps how many tracks AddURL will fan a single playlist
+	// URL out into, so one oversized playlist can't flood the queue
+	maxPlaylistSize int
+
+	// stateRepo persists a per-guild GuildState snapshot so playback can
+	// resume across restarts; see ScheduleStateSave and RestoreAll. nil
+	// disables persistence entirely.
+	stateRepo   repositories.StateRepositoryInterface
+	saveTimers  map[string]*time.Timer
+	saveTimerMu sync.Mutex
+
+	// playHistory records each song played, backing smart playlists'
+	// play_count criteria. nil disables recording.
+	playHistory repositories.PlayHistoryRepositoryInterface
 }
 
 // GuildPlaybackState represents playback state for a guild
@@ -39,28 +81,105 @@ type GuildPlaybackState struct {
 	currentPos int
 	loopCtx    context.Context
 	loopCancel context.CancelFunc
+	votes      *VoteHolder
 	mu         sync.RWMutex
+
+	// Session owner: the first user to Play or AddSong in this guild.
+	// Control commands are gated to them in the command handler until they
+	// leave the voice channel or transfer control. See ClaimOwner.
+	ownerID    string
+	ownerSince time.Time
+	ownerMu    sync.Mutex
+
+	// listenerJoinTimes tracks when each non-bot user currently in the
+	// voice channel joined, so HandleListenerLeft can promote whoever has
+	// been present longest if the owner leaves
+	listenerJoinTimes map[string]time.Time
+
+	// nowPlayingChannelID is the text channel bound via
+	// BindNowPlayingChannel where the live now-playing message is posted.
+	// nowPlayingMessageID is that message, kept so the next song can
+	// collapse it instead of leaving it to go stale. Empty channel means
+	// no live message is posted.
+	nowPlayingChannelID string
+	nowPlayingMessageID string
+	nowPlayingMu        sync.Mutex
+
+	// pendingResumeOffset, when non-zero, is consumed once by the next
+	// playNextSong call to seek the first resumed song back to its last
+	// known position instead of starting from the top. Set by RestoreAll.
+	pendingResumeOffset time.Duration
+
+	// queueEmptyNotified guards TrackEventQueueEmpty so it fires once per
+	// transition into an empty queue, not on every playbackLoop tick while
+	// it stays empty.
+	queueEmptyNotified bool
+}
+
+// VoteHolder tracks in-progress vote-skip ballots for the song currently
+// playing in a guild. TrackID pins the ballot to a specific song so a vote
+// cast for one track can't carry over and skip the next one.
+type VoteHolder struct {
+	mu      sync.Mutex
+	Voters  map[string]bool
+	TrackID string
 }
 
-// NewPlaybackService creates a new playback service
+// resetIfStale clears the ballot if it belongs to a different track than
+// trackID, so votes never carry over to a song nobody voted on.
+func (v *VoteHolder) resetIfStale(trackID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.TrackID != trackID {
+		v.TrackID = trackID
+		v.Voters = make(map[string]bool)
+	}
+}
+
+// NewPlaybackService creates a new playback service. skipRatio is the
+// fraction of non-bot voice-channel listeners required to approve a
+// vote-skip (see VoteSkip). maxPlaylistSize caps how many tracks a single
+// playlist URL passed to AddURL can fan out into. stateRepo persists
+// per-guild playback snapshots for resume-on-boot (see ScheduleStateSave,
+// RestoreAll); pass nil to disable persistence.
 func NewPlaybackService(
 	session *discordgo.Session,
 	audioSvc *audio.AudioService,
 	processingSvc *ProcessingService,
+	scrobbleSvc *ScrobbleService,
+	externalImporters *externalplaylist.Registry,
+	skipRatio float64,
+	maxPlaylistSize int,
+	metricsRecorder metrics.Recorder,
+	stateRepo repositories.StateRepositoryInterface,
+	playHistory repositories.PlayHistoryRepositoryInterface,
 	log *logger.Logger,
 ) *PlaybackService {
 	return &PlaybackService{
 		session:           session,
 		audioService:      audioSvc,
 		processingService: processingSvc,
+		scrobbleService:   scrobbleSvc,
+		externalImporters: externalImporters,
+		metrics:           metricsRecorder,
 		logger:            log,
 		guildStates:       make(map[string]*GuildPlaybackState),
+		skipRatio:         skipRatio,
+		maxPlaylistSize:   maxPlaylistSize,
+		stateRepo:         stateRepo,
+		playHistory:       playHistory,
+		saveTimers:        make(map[string]*time.Timer),
 	}
 }
 
-// Play starts or resumes playback in a guild
-func (s *PlaybackService) Play(guildID, channelID string) error {
+// Play starts or resumes playback in a guild. userID claims session
+// ownership if nobody has claimed it yet (see ClaimOwner). ctx carries
+// request-scoped logging fields (correlation_id, guild, user - see
+// logger.WithContext) that are attached to every song this guild plays for
+// the lifetime of the resulting playback loop, not just this call.
+func (s *PlaybackService) Play(ctx context.Context, guildID, channelID, userID string) error {
 	state := s.getOrCreateState(guildID)
+	s.ClaimOwner(guildID, userID)
 
 	state.mu.Lock()
 	defer state.mu.Unlock()
@@ -69,7 +188,7 @@ func (s *PlaybackService) Play(guildID, channelID string) error {
 	if state.isPlaying {
 		// Try to resume if paused
 		if player := s.audioService.GetPlayer(guildID); player != nil {
-			return player.Resume()
+			return player.Resume(ctx)
 		}
 		return ErrAlreadyPlaying
 	}
@@ -79,18 +198,27 @@ func (s *PlaybackService) Play(guildID, channelID string) error {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 
-	// Start playback loop
-	state.isPlaying = true
-	state.loopCtx, state.loopCancel = context.WithCancel(context.Background())
-
-	go s.playbackLoop(state)
+	s.startPlaybackLoopLocked(ctx, state)
 
 	s.logger.WithField("guild", guildID).Info("✅ Playback started")
 	return nil
 }
 
-// Stop stops playback in a guild
-func (s *PlaybackService) Stop(guildID string) error {
+// startPlaybackLoopLocked marks state as playing and launches its playback
+// loop. ctx's logging fields (see logger.WithContext) are carried by
+// state.loopCtx, so every song the loop plays - not just the one that
+// started it - logs under the same correlation_id. Must be called with
+// state.mu held.
+func (s *PlaybackService) startPlaybackLoopLocked(ctx context.Context, state *GuildPlaybackState) {
+	state.isPlaying = true
+	loopCtx := logger.WithContext(ctx, logrus.Fields{"guild": state.guildID})
+	state.loopCtx, state.loopCancel = context.WithCancel(loopCtx)
+
+	go s.playbackLoop(state)
+}
+
+// Stop stops playback in a guild. See Play for ctx.
+func (s *PlaybackService) Stop(ctx context.Context, guildID string) error {
 	state := s.getState(guildID)
 	if state == nil {
 		return ErrNotPlaying
@@ -111,33 +239,37 @@ func (s *PlaybackService) Stop(guildID string) error {
 
 	// Stop audio
 	if player := s.audioService.GetPlayer(guildID); player != nil {
-		player.Stop()
+		player.Stop(ctx)
 	}
 
+	s.ScheduleStateSave(guildID)
+
 	s.logger.WithField("guild", guildID).Info("Playback stopped")
 	return nil
 }
 
-// Pause pauses playback
-func (s *PlaybackService) Pause(guildID string) error {
+// Pause pauses playback. See Play for ctx.
+func (s *PlaybackService) Pause(ctx context.Context, guildID string) error {
 	player := s.audioService.GetPlayer(guildID)
 	if player == nil {
 		return ErrNotPlaying
 	}
-	return player.Pause()
+	return player.Pause(ctx)
 }
 
-// Resume resumes playback
-func (s *PlaybackService) Resume(guildID string) error {
+// Resume resumes playback. See Play for ctx.
+func (s *PlaybackService) Resume(ctx context.Context, guildID string) error {
 	player := s.audioService.GetPlayer(guildID)
 	if player == nil {
 		return ErrNotPlaying
 	}
-	return player.Resume()
+	return player.Resume(ctx)
 }
 
-// Skip skips to the next song
-func (s *PlaybackService) Skip(guildID string) error {
+// ForceSkip skips to the next song unconditionally, bypassing any in-progress
+// vote. Intended for admins/DJs, not the regular /skip path. See Play for
+// ctx.
+func (s *PlaybackService) ForceSkip(ctx context.Context, guildID string) error {
 	state := s.getState(guildID)
 	if state == nil {
 		return ErrNotPlaying
@@ -145,26 +277,374 @@ func (s *PlaybackService) Skip(guildID string) error {
 
 	// Stop current song to trigger next
 	if player := s.audioService.GetPlayer(guildID); player != nil {
-		player.Stop()
+		player.Stop(ctx)
 	}
 
 	return nil
 }
 
-// AddSong adds a song to the queue and starts processing
-func (s *PlaybackService) AddSong(guildID string, song *entities.Song) error {
+// VoteSkip registers userID's vote to skip the currently playing song and
+// force-skips it once the required threshold is met. required is the number
+// of non-bot listeners in the voice channel times skipRatio, rounded up to
+// at least 1. Voting again for the same track is a no-op; current/required
+// are still returned so the caller can report progress.
+func (s *PlaybackService) VoteSkip(ctx context.Context, guildID, userID string) (current, required int, skipped bool, err error) {
+	state := s.getState(guildID)
+	if state == nil {
+		return 0, 0, false, ErrNotPlaying
+	}
+
+	song := state.tracklist.CurrentSong()
+	if song == nil {
+		return 0, 0, false, ErrNotPlaying
+	}
+
+	listeners, err := s.audioService.CountNonBotListeners(guildID)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to count voice channel listeners: %w", err)
+	}
+	required = requiredVotes(listeners, s.skipRatio)
+
+	votes := state.votes
+	votes.resetIfStale(song.ID)
+	votes.mu.Lock()
+	votes.Voters[userID] = true
+	current = len(votes.Voters)
+	votes.mu.Unlock()
+
+	s.logger.WithFields(map[string]interface{}{
+		"guild":    guildID,
+		"song_id":  song.ID,
+		"current":  current,
+		"required": required,
+	}).Info("Vote skip registered")
+
+	if current < required {
+		return current, required, false, nil
+	}
+
+	if err := s.ForceSkip(ctx, guildID); err != nil {
+		return current, required, false, err
+	}
+	return current, required, true, nil
+}
+
+// ResetVotes clears any in-progress vote-skip ballot for a guild, e.g. when
+// the current song changes.
+func (s *PlaybackService) ResetVotes(guildID string) {
+	state := s.getState(guildID)
+	if state == nil {
+		return
+	}
+
+	state.votes.mu.Lock()
+	state.votes.TrackID = ""
+	state.votes.Voters = make(map[string]bool)
+	state.votes.mu.Unlock()
+}
+
+// requiredVotes computes the vote-skip threshold from the live listener
+// count and the configured ratio, rounded up and floored at 1 so a single
+// listener can always skip their own song.
+func requiredVotes(listeners int, ratio float64) int {
+	required := int(math.Ceil(float64(listeners) * ratio))
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+// VoteAction registers userID's vote for the given kind (skip/stop/pause)
+// against guildID's AudioPlayer vote subsystem, starting a new session if
+// none is active or the previous one expired. passed reports whether this
+// vote just pushed the tally to the required threshold; the caller is
+// responsible for carrying out kind's actual effect (skip, stop, pause) and
+// should do so at most once per passing vote.
+func (s *PlaybackService) VoteAction(guildID, userID string, kind audio.VoteKind) (current, required int, passed bool, err error) {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return 0, 0, false, ErrNotPlaying
+	}
+
+	eligible, err := s.audioService.CountNonBotListeners(guildID)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to count voice channel listeners: %w", err)
+	}
+
+	current, required, ok := player.VoteAdd(userID)
+	if !ok {
+		player.VoteStart(kind, userID, eligible)
+		current, required = player.VoteTally()
+	}
+
+	return current, required, current >= required, nil
+}
+
+// VoteRemoveSong registers userID's vote to remove the song at position
+// (1-indexed) and removes it once eligible listeners' majority agrees. See
+// entities.Tracklist.VoteRemove.
+func (s *PlaybackService) VoteRemoveSong(guildID string, position int, userID string) (current, required int, removed bool, err error) {
+	state := s.getState(guildID)
+	if state == nil {
+		return 0, 0, false, ErrNotPlaying
+	}
+
+	eligible, err := s.audioService.CountNonBotListeners(guildID)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to count voice channel listeners: %w", err)
+	}
+
+	current, required, removed = state.tracklist.VoteRemove(position, userID, eligible)
+	if removed {
+		s.ScheduleStateSave(guildID)
+	}
+	return current, required, removed, nil
+}
+
+// ForceRemoveSong removes the song at position (1-indexed) unconditionally,
+// bypassing any in-progress vote - for DJs/admins, not the /voteremove
+// voting path.
+func (s *PlaybackService) ForceRemoveSong(guildID string, position int) bool {
+	state := s.getState(guildID)
+	if state == nil {
+		return false
+	}
+
+	removed := state.tracklist.RemoveSong(position)
+	if removed {
+		s.ScheduleStateSave(guildID)
+	}
+	return removed
+}
+
+// VoteShuffleQueue registers userID's vote to smart-shuffle the queue and
+// shuffles it once eligible listeners' majority agrees. See
+// entities.Tracklist.VoteShuffle.
+func (s *PlaybackService) VoteShuffleQueue(guildID, userID string) (current, required int, shuffled bool, err error) {
+	state := s.getState(guildID)
+	if state == nil {
+		return 0, 0, false, ErrNotPlaying
+	}
+
+	eligible, err := s.audioService.CountNonBotListeners(guildID)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to count voice channel listeners: %w", err)
+	}
+
+	current, required, shuffled = state.tracklist.VoteShuffle(userID, eligible)
+	if shuffled {
+		s.ScheduleStateSave(guildID)
+	}
+	return current, required, shuffled, nil
+}
+
+// ForceSmartShuffle smart-shuffles the queue unconditionally, bypassing any
+// in-progress vote - for DJs/admins, not the /voteshuffle voting path.
+func (s *PlaybackService) ForceSmartShuffle(guildID string) bool {
+	state := s.getState(guildID)
+	if state == nil {
+		return false
+	}
+
+	state.tracklist.SmartShuffle()
+	s.ScheduleStateSave(guildID)
+	return true
+}
+
+// ClaimOwner makes userID the session owner of guildID if nobody has
+// claimed it yet. Called on the first Play or AddSong in a guild; later
+// calls from other users are a no-op so ownership doesn't shift on every
+// subsequent /play.
+func (s *PlaybackService) ClaimOwner(guildID, userID string) error {
+	if userID == "" {
+		return nil
+	}
+
 	state := s.getOrCreateState(guildID)
 
+	state.ownerMu.Lock()
+	defer state.ownerMu.Unlock()
+	if state.ownerID == "" {
+		state.ownerID = userID
+		state.ownerSince = time.Now()
+	}
+	return nil
+}
+
+// TransferOwner hands control of guildID from the current owner to to. from
+// must be the current owner, or ErrNotOwner is returned.
+func (s *PlaybackService) TransferOwner(guildID, from, to string) error {
+	state := s.getState(guildID)
+	if state == nil {
+		return ErrNotPlaying
+	}
+
+	state.ownerMu.Lock()
+	defer state.ownerMu.Unlock()
+	if state.ownerID != from {
+		return ErrNotOwner
+	}
+	state.ownerID = to
+	state.ownerSince = time.Now()
+	return nil
+}
+
+// Owner returns the current session owner of guildID, or "" if nobody has
+// claimed it yet.
+func (s *PlaybackService) Owner(guildID string) string {
+	state := s.getState(guildID)
+	if state == nil {
+		return ""
+	}
+
+	state.ownerMu.Lock()
+	defer state.ownerMu.Unlock()
+	return state.ownerID
+}
+
+// IsOwner reports whether userID is the current session owner of guildID.
+// A guild with no claimed owner has nothing to gate, so it returns true for
+// anyone.
+func (s *PlaybackService) IsOwner(guildID, userID string) bool {
+	owner := s.Owner(guildID)
+	return owner == "" || owner == userID
+}
+
+// RecordListenerJoin notes that userID joined guildID's voice channel, so
+// they're considered for auto-promotion via HandleListenerLeft if the
+// current owner later leaves.
+func (s *PlaybackService) RecordListenerJoin(guildID, userID string) {
+	state := s.getOrCreateState(guildID)
+
+	state.ownerMu.Lock()
+	defer state.ownerMu.Unlock()
+	if _, exists := state.listenerJoinTimes[userID]; !exists {
+		state.listenerJoinTimes[userID] = time.Now()
+	}
+}
+
+// HandleListenerLeft removes userID from the tracked listeners and, if they
+// were the session owner, promotes whoever has been present longest. If no
+// other listener remains, ownership is cleared so the next Play/AddSong
+// claims it fresh.
+func (s *PlaybackService) HandleListenerLeft(guildID, userID string) {
+	state := s.getState(guildID)
+	if state == nil {
+		return
+	}
+
+	state.ownerMu.Lock()
+	defer state.ownerMu.Unlock()
+	delete(state.listenerJoinTimes, userID)
+
+	if state.ownerID != userID {
+		return
+	}
+
+	var nextOwner string
+	var earliest time.Time
+	for candidate, joinedAt := range state.listenerJoinTimes {
+		if nextOwner == "" || joinedAt.Before(earliest) {
+			nextOwner = candidate
+			earliest = joinedAt
+		}
+	}
+
+	state.ownerID = nextOwner
+	state.ownerSince = earliest
+}
+
+// AddSong adds a song to the queue and starts processing. ctx carries the
+// request's logging fields (guild, user, command, correlation ID) through to
+// the processing worker and its downstream YouTube calls.
+func (s *PlaybackService) AddSong(ctx context.Context, guildID string, song *entities.Song) error {
+	state := s.getOrCreateState(guildID)
+	s.ClaimOwner(guildID, song.RequestedBy)
+
 	// Add to tracklist
 	state.tracklist.AddSong(song)
+	s.ScheduleStateSave(guildID)
 
-	s.logger.WithFields(map[string]interface{}{
-		"guild":   guildID,
-		"song_id": song.ID,
-	}).Info("Song added to queue")
+	s.logger.FromContext(ctx).WithField("song_id", song.ID).Info("Song added to queue")
 
 	// Submit for processing
-	return s.processingService.Submit(song, 0)
+	return s.processingService.Submit(ctx, song, 0)
+}
+
+// InsertAt adds a song at position (1-indexed) instead of appending, so it
+// plays sooner than the rest of the queue. Used by /addnext (position right
+// after the currently playing song) and /playnow (same position, followed by
+// a ForceSkip so it starts immediately). Submitted for processing with the
+// same top priority as AddSong, since an inserted song is by definition
+// wanted sooner than anything already queued.
+func (s *PlaybackService) InsertAt(ctx context.Context, guildID string, position int, song *entities.Song) error {
+	state := s.getOrCreateState(guildID)
+	s.ClaimOwner(guildID, song.RequestedBy)
+
+	state.tracklist.InsertAt(position, song)
+	s.ScheduleStateSave(guildID)
+
+	s.logger.FromContext(ctx).WithField("song_id", song.ID).Info("Song inserted into queue")
+
+	return s.processingService.Submit(ctx, song, 0)
+}
+
+// AddURL resolves url into one or more songs and enqueues all of them,
+// expanding YouTube playlist (list=), Spotify playlist/album, and SoundCloud
+// set URLs via externalImporters. A URL that isn't a recognized playlist is
+// queued as a single song. Tracks are submitted for processing with
+// descending priority so the first one is ready soonest. Returns how many
+// songs were actually queued.
+func (s *PlaybackService) AddURL(ctx context.Context, guildID, url, requesterID string) (added int, err error) {
+	log := s.logger.FromContext(ctx)
+
+	importer, err := s.externalImporters.For(url)
+	if err != nil {
+		song := entities.NewSong(url, valueobjects.SourceTypeYouTube, requesterID, guildID)
+		if err := s.AddSong(ctx, guildID, song); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	result, err := importer.Import(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expand playlist: %w", err)
+	}
+
+	entries := result.Entries
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("playlist is empty or invalid")
+	}
+
+	total := len(entries)
+	if total > s.maxPlaylistSize {
+		entries = entries[:s.maxPlaylistSize]
+	}
+
+	state := s.getOrCreateState(guildID)
+	s.ClaimOwner(guildID, requesterID)
+
+	for idx, entry := range entries {
+		song := entities.NewSong(entry.OriginalInput, entry.SourceType, requesterID, guildID)
+		state.tracklist.AddSong(song)
+
+		priority := len(entries) - idx
+		if err := s.processingService.Submit(ctx, song, priority); err != nil {
+			log.WithError(err).WithField("song_id", song.ID).Warn("Failed to submit song for processing")
+			continue
+		}
+		added++
+	}
+	s.ScheduleStateSave(guildID)
+
+	log.WithFields(map[string]interface{}{
+		"added": added,
+		"total": total,
+		"url":   url,
+	}).Infof("%d of %d tracks queued", added, total)
+
+	return added, nil
 }
 
 // playbackLoop is the main playback loop for a guild
@@ -188,12 +668,31 @@ func (s *PlaybackService) playbackLoop(state *GuildPlaybackState) {
 
 // playNextSong plays the next available song
 func (s *PlaybackService) playNextSong(state *GuildPlaybackState) bool {
+	entryTime := time.Now()
+
 	// Get next song
 	song := state.tracklist.CurrentSong()
 	if song == nil {
+		state.mu.Lock()
+		alreadyNotified := state.queueEmptyNotified
+		state.queueEmptyNotified = true
+		state.mu.Unlock()
+
+		if !alreadyNotified {
+			if player := s.audioService.GetPlayer(state.guildID); player != nil {
+				player.NotifyQueueEmpty()
+			}
+		}
 		return false
 	}
 
+	state.mu.Lock()
+	state.queueEmptyNotified = false
+	state.mu.Unlock()
+
+	// A new song means any in-progress vote-skip ballot is stale
+	state.votes.resetIfStale(song.ID)
+
 	// Wait for song to be ready
 	if !s.waitForSong(song, state.loopCtx) {
 		// Song failed or context cancelled
@@ -225,22 +724,65 @@ func (s *PlaybackService) playNextSong(state *GuildPlaybackState) bool {
 		"song":  song.GetMetadata().Title,
 	}).Info("▶️ Now playing")
 
-	// Set completion callback
-	done := make(chan error)
-	onComplete := func(completedSong *entities.Song, err error) {
-		done <- err
-	}
+	// Subscribe to this song's completion/error before starting playback, so
+	// we can't miss a fast-failing encode; both unsubscribe once this song
+	// is done, since playNextSong re-subscribes fresh for the next one.
+	songCtx, songCancel := context.WithCancel(state.loopCtx)
+	done := make(chan error, 1)
+	unsubEnd := player.AddHandler(audio.TrackEventEnd, func(audio.TrackContext) {
+		select {
+		case done <- nil:
+		default:
+		}
+	})
+	unsubErr := player.AddHandler(audio.TrackEventError, func(ctx audio.TrackContext) {
+		select {
+		case done <- ctx.Err:
+		default:
+		}
+	})
+	defer unsubEnd()
+	defer unsubErr()
+
+	// A song resumed from a saved snapshot (see RestoreAll) seeks back to
+	// its last known position instead of starting from the top; the offset
+	// is consumed once and cleared so every later song plays normally.
+	state.mu.Lock()
+	resumeOffset := state.pendingResumeOffset
+	state.pendingResumeOffset = 0
+	state.mu.Unlock()
 
 	// Play song
-	if err := player.Play(song, onComplete); err != nil {
+	var err error
+	if resumeOffset > 0 {
+		err = player.PlayFrom(songCtx, song, resumeOffset)
+	} else {
+		err = player.Play(songCtx, song)
+	}
+	s.metrics.ObservePlaybackLatency(time.Since(entryTime))
+	if err != nil {
+		songCancel()
 		s.logger.WithError(err).Error("Failed to play song")
 		s.handleFailedSong(state, song)
 		return false
 	}
 
+	if s.playHistory != nil {
+		if err := s.playHistory.RecordPlay(state.guildID, song.OriginalInput); err != nil {
+			s.logger.WithError(err).Warn("Failed to record play history")
+		}
+	}
+
+	if s.scrobbleService != nil {
+		s.fireScrobbles(songCtx, state.guildID, song)
+	}
+
+	go s.runNowPlayingUpdater(songCtx, state, song, player)
+
 	// Wait for completion or context cancellation
 	select {
 	case err := <-done:
+		songCancel()
 		if err != nil {
 			s.logger.WithError(err).Error("Playback failed")
 			s.handleFailedSong(state, song)
@@ -250,10 +792,40 @@ func (s *PlaybackService) playNextSong(state *GuildPlaybackState) bool {
 		}
 		return true
 	case <-state.loopCtx.Done():
+		songCancel()
 		return false
 	}
 }
 
+// fireScrobbles sends a now-playing update immediately, then schedules a
+// Submit once the track reaches 50% played or maxScrobbleSubmitWait,
+// whichever comes first. Cancelled via songCtx if the track ends early
+// (skip, failure, or playback stopping) before that point.
+func (s *PlaybackService) fireScrobbles(songCtx context.Context, guildID string, song *entities.Song) {
+	meta := song.GetMetadata()
+	track := scrobble.Track{
+		Title:    meta.Title,
+		Artist:   meta.Artist,
+		Duration: time.Duration(meta.Duration) * time.Second,
+	}
+	playedAt := time.Now()
+
+	s.scrobbleService.NowPlaying(guildID, song.RequestedBy, track)
+
+	threshold := maxScrobbleSubmitWait
+	if track.Duration > 0 && track.Duration/2 < threshold {
+		threshold = track.Duration / 2
+	}
+
+	go func() {
+		select {
+		case <-time.After(threshold):
+			s.scrobbleService.Submit(guildID, song.RequestedBy, track, playedAt, track.Duration)
+		case <-songCtx.Done():
+		}
+	}()
+}
+
 // waitForSong waits for a song to become ready
 func (s *PlaybackService) waitForSong(song *entities.Song, ctx context.Context) bool {
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -286,6 +858,8 @@ func (s *PlaybackService) waitForSong(song *entities.Song, ctx context.Context)
 
 // handleSongComplete handles song completion
 func (s *PlaybackService) handleSongComplete(state *GuildPlaybackState) {
+	s.metrics.IncTracksPlayed()
+
 	// Move to next based on repeat mode
 	switch state.tracklist.GetRepeatMode() {
 	case entities.RepeatModeNone:
@@ -295,12 +869,16 @@ func (s *PlaybackService) handleSongComplete(state *GuildPlaybackState) {
 	case entities.RepeatModeQueue:
 		state.tracklist.NextSong() // Will wrap around
 	}
+
+	s.ScheduleStateSave(state.guildID)
 }
 
 // handleFailedSong handles a failed song
 func (s *PlaybackService) handleFailedSong(state *GuildPlaybackState, song *entities.Song) {
 	s.logger.WithField("song_id", song.ID).Warn("Skipping failed song")
+	s.metrics.IncTracksFailed()
 	state.tracklist.NextSong()
+	s.ScheduleStateSave(state.guildID)
 }
 
 // getOrCreateState gets or creates guild state
@@ -313,10 +891,13 @@ func (s *PlaybackService) getOrCreateState(guildID string) *GuildPlaybackState {
 	}
 
 	state := &GuildPlaybackState{
-		guildID:   guildID,
-		tracklist: entities.NewTracklist(guildID),
+		guildID:           guildID,
+		tracklist:         entities.NewTracklist(guildID),
+		votes:             &VoteHolder{Voters: make(map[string]bool)},
+		listenerJoinTimes: make(map[string]time.Time),
 	}
 	s.guildStates[guildID] = state
+	s.metrics.IncActiveGuilds()
 	return state
 }
 
@@ -336,6 +917,58 @@ func (s *PlaybackService) GetTracklist(guildID string) *entities.Tracklist {
 	return state.tracklist
 }
 
+// CurrentlyPlayingURLs returns the OriginalInput (source URL) of the
+// currently playing song in every guild with an active tracklist. Long-lived
+// caches (e.g. the Spotify/YouTube resolution cache) use this to protect
+// entries backing live playback from age/size-based eviction.
+func (s *PlaybackService) CurrentlyPlayingURLs() []string {
+	s.mu.RLock()
+	states := make([]*GuildPlaybackState, 0, len(s.guildStates))
+	for _, state := range s.guildStates {
+		states = append(states, state)
+	}
+	s.mu.RUnlock()
+
+	urls := make([]string, 0, len(states))
+	for _, state := range states {
+		if state.tracklist == nil {
+			continue
+		}
+		if song := state.tracklist.CurrentSong(); song != nil {
+			urls = append(urls, song.OriginalInput)
+		}
+	}
+	return urls
+}
+
+// SetQueueMode switches a guild's tracklist between FIFO and round-robin DJ
+// queueing.
+func (s *PlaybackService) SetQueueMode(guildID string, mode entities.QueueMode) error {
+	state := s.getOrCreateState(guildID)
+	if err := state.tracklist.SetQueueMode(mode); err != nil {
+		return err
+	}
+	s.ScheduleStateSave(guildID)
+	return nil
+}
+
+// SwitchQueueType toggles a guild's queue mode between FIFO and round-robin,
+// draining and regrouping the pending queue accordingly.
+func (s *PlaybackService) SwitchQueueType(guildID string) (entities.QueueMode, error) {
+	state := s.getOrCreateState(guildID)
+
+	next := entities.QueueModeRoundRobin
+	if state.tracklist.QueueMode() == entities.QueueModeRoundRobin {
+		next = entities.QueueModeFIFO
+	}
+
+	if err := state.tracklist.SetQueueMode(next); err != nil {
+		return "", err
+	}
+	s.ScheduleStateSave(guildID)
+	return next, nil
+}
+
 // IsPlaying checks if a guild is currently playing
 func (s *PlaybackService) IsPlaying(guildID string) bool {
 	state := s.getState(guildID)
@@ -348,20 +981,59 @@ func (s *PlaybackService) IsPlaying(guildID string) bool {
 	return state.isPlaying
 }
 
+// IsIdle reports whether guildID has nothing playing and an empty upcoming
+// queue. Used by AudioService's idle watcher (see SetIdleCheckHook) to
+// decide when a guild is a candidate for auto-disconnect.
+func (s *PlaybackService) IsIdle(guildID string) bool {
+	state := s.getState(guildID)
+	if state == nil {
+		return true
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return !state.isPlaying && state.tracklist.Size() == 0
+}
+
+// SetAutoLeave overrides guildID's idle/alone auto-disconnect watcher,
+// independent of the server-wide 24/7 default. See AudioService.SetAutoLeave.
+func (s *PlaybackService) SetAutoLeave(guildID string, enabled bool) {
+	s.audioService.SetAutoLeave(guildID, enabled)
+}
+
+// AutoLeaveEnabled reports whether guildID's idle/alone watcher is currently
+// enabled.
+func (s *PlaybackService) AutoLeaveEnabled(guildID string) bool {
+	return s.audioService.AutoLeaveEnabled(guildID)
+}
+
+// NotifyVoiceStateChange wakes guildID's idle watcher immediately on a
+// voice-state change in its channel, instead of waiting for the next poll.
+func (s *PlaybackService) NotifyVoiceStateChange(guildID string) {
+	s.audioService.NotifyVoiceStateChange(guildID)
+}
+
 // Cleanup cleans up resources for a guild
 func (s *PlaybackService) Cleanup(guildID string) {
 	s.logger.WithField("guild", guildID).Info("Cleaning up playback state")
 
 	// Stop playback
-	s.Stop(guildID)
+	s.Stop(context.Background(), guildID)
 
-	// Disconnect audio
+	// Disconnect audio. This also fires AudioService's cleanup hook, which
+	// calls DeleteState - the guild is leaving on purpose, so nothing should
+	// be resumed here on the next restart.
 	s.audioService.DisconnectFromGuild(guildID)
 
 	// Remove state
 	s.mu.Lock()
+	_, existed := s.guildStates[guildID]
 	delete(s.guildStates, guildID)
 	s.mu.Unlock()
+
+	if existed {
+		s.metrics.DecActiveGuilds()
+	}
 }
 
 // SetVolume sets the volume for a guild (0-100)
@@ -377,3 +1049,57 @@ func (s *PlaybackService) SetVolume(guildID string, level int) error {
 
 	return nil
 }
+
+// Seek jumps the currently playing song in guildID to position d.
+func (s *PlaybackService) Seek(guildID string, d time.Duration) error {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return ErrNotPlaying
+	}
+
+	return player.Seek(d)
+}
+
+// SetCrossfade configures guildID's fade-out/fade-in transition length; see
+// audio.AudioPlayer.SetCrossfade for what "crossfade" means on this
+// single-stream player. d <= 0 disables it.
+func (s *PlaybackService) SetCrossfade(guildID string, d time.Duration) error {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return ErrNotPlaying
+	}
+	player.SetCrossfade(d)
+	return nil
+}
+
+// GetCrossfade returns guildID's configured crossfade duration, or 0 if no
+// player is connected or none has been set.
+func (s *PlaybackService) GetCrossfade(guildID string) time.Duration {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return 0
+	}
+	return player.GetCrossfade()
+}
+
+// SetNormalization turns loudness normalization on or off for guildID,
+// targeting targetLUFS once enabled; see audio.AudioPlayer.SetNormalization.
+func (s *PlaybackService) SetNormalization(guildID string, enabled bool, targetLUFS float64) error {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return ErrNotPlaying
+	}
+	player.SetNormalization(enabled, targetLUFS)
+	return nil
+}
+
+// GetNormalization returns guildID's current loudness-normalization
+// setting, defaulting to audio.DefaultNormalizationConfig (off) if no
+// player is connected or none has been set.
+func (s *PlaybackService) GetNormalization(guildID string) *audio.NormalizationConfig {
+	player := s.audioService.GetPlayer(guildID)
+	if player == nil {
+		return audio.DefaultNormalizationConfig()
+	}
+	return player.GetNormalization()
+}
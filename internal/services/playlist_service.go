@@ -2,42 +2,118 @@ package services
 
 import (
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/externalplaylist"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/playlistformat"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
 // PlaylistService manages playlist operations
 type PlaylistService struct {
-	repo        repositories.PlaylistRepositoryInterface
-	fileRepo    *repositories.PlaylistRepository // Legacy file-based repo
-	useDatabase bool
-	logger      *logger.Logger
+	repo          repositories.PlaylistRepositoryInterface
+	fileRepo      *repositories.PlaylistRepository // Legacy file-based repo
+	useDatabase   bool
+	searchService *SearchService
+	// playHistory backs smart playlists' play_count criteria. Always
+	// file-based, independent of useDatabase, since play counts are a thin
+	// side-store rather than part of a playlist's own persistence.
+	playHistory repositories.PlayHistoryRepositoryInterface
+	// dataStore groups repo and playHistory writes into atomic transactions
+	// (see RenamePlaylistForGuild) via the file or SQL journal/WithTx
+	// machinery behind repositories.DataStore.
+	dataStore repositories.DataStore
+	// mediaIDResolver normalizes a new entry's OriginalInput into a
+	// cross-platform ResolvedID (see AddToPlaylistForGuild) so the same
+	// track added via different URL formats is recognized as a duplicate.
+	mediaIDResolver MediaIDResolver
+	logger          *logger.Logger
 }
 
-// NewPlaylistService creates a new playlist service with file-based storage
-func NewPlaylistService(playlistDir string, log *logger.Logger) *PlaylistService {
+// NewPlaylistService creates a new playlist service with file-based storage.
+// playHistory backs smart playlists' play_count criteria; pass the same
+// instance given to PlaybackService so both see the same play counts.
+func NewPlaylistService(playlistDir string, playHistory repositories.PlayHistoryRepositoryInterface, searchSvc *SearchService, log *logger.Logger) (*PlaylistService, error) {
 	fileRepo := repositories.NewPlaylistRepository(playlistDir)
-	return &PlaylistService{
-		repo:        &fileRepoAdapter{repo: fileRepo},
-		fileRepo:    fileRepo,
-		useDatabase: false,
-		logger:      log,
+	dataStore, err := repositories.NewFileDataStore(fileRepo, playHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open playlist data store: %w", err)
 	}
+
+	return &PlaylistService{
+		repo:            &fileRepoAdapter{repo: fileRepo},
+		fileRepo:        fileRepo,
+		useDatabase:     false,
+		searchService:   searchSvc,
+		playHistory:     playHistory,
+		dataStore:       dataStore,
+		mediaIDResolver: NewMediaIDResolver(),
+		logger:          log,
+	}, nil
 }
 
-// NewPlaylistServiceWithDB creates a new playlist service with database storage
-func NewPlaylistServiceWithDB(db *database.DB, log *logger.Logger) *PlaylistService {
+// NewPlaylistServiceWithDB creates a new playlist service with database
+// storage. See NewPlaylistService for playHistory.
+func NewPlaylistServiceWithDB(db *database.DB, playHistory repositories.PlayHistoryRepositoryInterface, searchSvc *SearchService, log *logger.Logger) *PlaylistService {
 	return &PlaylistService{
-		repo:        repositories.NewDatabasePlaylistRepository(db),
-		useDatabase: true,
-		logger:      log,
+		repo:            repositories.NewDatabasePlaylistRepository(db),
+		useDatabase:     true,
+		searchService:   searchSvc,
+		playHistory:     playHistory,
+		dataStore:       repositories.NewDatabaseDataStore(db, playHistory),
+		mediaIDResolver: NewMediaIDResolver(),
+		logger:          log,
+	}
+}
+
+// searchIDForPlaylist builds the SearchService ID for a playlist's name
+func searchIDForPlaylist(guildID, name string) string {
+	return fmt.Sprintf("playlist:%s:%s", guildID, name)
+}
+
+// searchPrefixForEntries builds the SearchService ID prefix shared by every
+// entry in a playlist, so they can all be dropped with RemoveByPrefix
+func searchPrefixForEntries(guildID, name string) string {
+	return fmt.Sprintf("entry:%s:%s:", guildID, name)
+}
+
+// reindexPlaylist replaces a playlist's name and entries in the search index
+// with their current contents
+func (s *PlaylistService) reindexPlaylist(guildID string, playlist *entities.Playlist) {
+	if s.searchService == nil {
+		return
+	}
+
+	s.searchService.Index(searchIDForPlaylist(guildID, playlist.Name), SearchKindPlaylist, playlist.Name)
+
+	prefix := searchPrefixForEntries(guildID, playlist.Name)
+	s.searchService.RemoveByPrefix(prefix)
+	for i, entry := range playlist.Entries {
+		text := entry.Title
+		if text == "" {
+			text = entry.OriginalInput
+		}
+		s.searchService.Index(fmt.Sprintf("%s%d", prefix, i), SearchKindPlaylistEntry, text)
 	}
 }
 
+// unindexPlaylist removes a playlist's name and all of its entries from the
+// search index
+func (s *PlaylistService) unindexPlaylist(guildID, name string) {
+	if s.searchService == nil {
+		return
+	}
+
+	s.searchService.Remove(searchIDForPlaylist(guildID, name))
+	s.searchService.RemoveByPrefix(searchPrefixForEntries(guildID, name))
+}
+
 // fileRepoAdapter adapts the old PlaylistRepository to the new interface
 type fileRepoAdapter struct {
 	repo *repositories.PlaylistRepository
@@ -64,6 +140,26 @@ func (a *fileRepoAdapter) Exists(guildID, name string) bool {
 	return a.repo.Exists(name)
 }
 
+func (a *fileRepoAdapter) SaveExternalInfo(guildID, name string, info *entities.ExternalInfo) error {
+	return a.repo.SaveExternalInfo(name, info)
+}
+
+func (a *fileRepoAdapter) GetExternalInfo(guildID, name string) (*entities.ExternalInfo, error) {
+	return a.repo.GetExternalInfo(name)
+}
+
+func (a *fileRepoAdapter) SaveSmart(guildID string, playlist *entities.SmartPlaylist) error {
+	return a.repo.SaveSmart(playlist)
+}
+
+func (a *fileRepoAdapter) LoadSmart(guildID, name string) (*entities.SmartPlaylist, error) {
+	return a.repo.LoadSmart(name)
+}
+
+func (a *fileRepoAdapter) IsSmart(guildID, name string) (bool, error) {
+	return a.repo.IsSmart(name)
+}
+
 // ListPlaylists returns all available playlists for a guild
 func (s *PlaylistService) ListPlaylists() ([]string, error) {
 	return s.ListPlaylistsForGuild("")
@@ -113,6 +209,7 @@ func (s *PlaylistService) CreatePlaylistForGuild(guildID, name string) error {
 		s.logger.WithError(err).WithField("name", name).Error("Failed to create playlist")
 		return err
 	}
+	s.reindexPlaylist(guildID, playlist)
 
 	s.logger.WithField("name", name).Info("Playlist created")
 	return nil
@@ -129,6 +226,7 @@ func (s *PlaylistService) DeletePlaylistForGuild(guildID, name string) error {
 		s.logger.WithError(err).WithField("name", name).Error("Failed to delete playlist")
 		return err
 	}
+	s.unindexPlaylist(guildID, name)
 
 	s.logger.WithField("name", name).Info("Playlist deleted")
 	return nil
@@ -149,17 +247,20 @@ func (s *PlaylistService) AddToPlaylistForGuild(guildID, name, originalInput str
 		return fmt.Errorf("playlist '%s' not found", name)
 	}
 
+	resolvedID := s.mediaIDResolver.Resolve(originalInput, sourceType)
+
 	// Check for duplicates
-	if playlist.HasEntry(originalInput) {
+	if playlist.HasEntry(originalInput, resolvedID) {
 		return fmt.Errorf("song already exists in playlist '%s'", name)
 	}
 
-	playlist.AddEntry(originalInput, sourceType, title)
+	playlist.AddEntry(originalInput, sourceType, title, resolvedID)
 
 	if err := s.repo.Save(guildID, playlist); err != nil {
 		s.logger.WithError(err).Error("Failed to save playlist")
 		return err
 	}
+	s.reindexPlaylist(guildID, playlist)
 
 	s.logger.WithFields(map[string]interface{}{
 		"playlist": name,
@@ -184,7 +285,8 @@ func (s *PlaylistService) RemoveFromPlaylistForGuild(guildID, name, originalInpu
 		return fmt.Errorf("playlist '%s' not found", name)
 	}
 
-	if !playlist.RemoveEntry(originalInput) {
+	resolvedID := s.mediaIDResolver.Resolve(originalInput, "")
+	if !playlist.RemoveEntry(originalInput, resolvedID) {
 		return fmt.Errorf("song not found in playlist")
 	}
 
@@ -192,6 +294,7 @@ func (s *PlaylistService) RemoveFromPlaylistForGuild(guildID, name, originalInpu
 		s.logger.WithError(err).Error("Failed to save playlist")
 		return err
 	}
+	s.reindexPlaylist(guildID, playlist)
 
 	s.logger.WithFields(map[string]interface{}{
 		"playlist": name,
@@ -205,8 +308,19 @@ func (s *PlaylistService) GetPlaylistSongs(name string) ([]*entities.Song, error
 	return s.GetPlaylistSongsForGuild("", name)
 }
 
-// GetPlaylistSongsForGuild returns all songs in a playlist for a specific guild
+// GetPlaylistSongsForGuild returns all songs in a playlist for a specific
+// guild. name may be a smart playlist, in which case its criteria are
+// transparently evaluated against the guild's regular playlists instead of
+// loading a fixed entry list.
 func (s *PlaylistService) GetPlaylistSongsForGuild(guildID, name string) ([]*entities.Song, error) {
+	isSmart, err := s.repo.IsSmart(guildID, name)
+	if err != nil {
+		return nil, err
+	}
+	if isSmart {
+		return s.getSmartPlaylistSongsForGuild(guildID, name)
+	}
+
 	playlist, err := s.repo.Load(guildID, name)
 	if err != nil {
 		return nil, err
@@ -217,17 +331,128 @@ func (s *PlaylistService) GetPlaylistSongsForGuild(guildID, name string) ([]*ent
 
 	songs := make([]*entities.Song, 0, len(playlist.Entries))
 	for _, entry := range playlist.Entries {
-		song := entities.NewSong(entry.OriginalInput, entry.SourceType, "", "")
-		// Pre-set metadata with title from playlist (won't be ready until processed)
-		song.Metadata = &valueobjects.SongMetadata{
-			Title: entry.Title,
+		songs = append(songs, entryToSong(entry))
+	}
+
+	return songs, nil
+}
+
+// entryToSong converts a playlist entry into a Song with its playlist title
+// pre-set as metadata; the rest of the metadata isn't ready until the song
+// is processed for playback.
+func entryToSong(entry *entities.PlaylistEntry) *entities.Song {
+	song := entities.NewSong(entry.OriginalInput, entry.SourceType, "", "")
+	song.Metadata = &valueobjects.SongMetadata{
+		Title: entry.Title,
+	}
+	return song
+}
+
+// getSmartPlaylistSongsForGuild evaluates a smart playlist's criteria against
+// the union of the guild's regular playlist entries and returns the matches
+// materialized as Songs
+func (s *PlaylistService) getSmartPlaylistSongsForGuild(guildID, name string) ([]*entities.Song, error) {
+	smart, err := s.repo.LoadSmart(guildID, name)
+	if err != nil {
+		return nil, err
+	}
+	if smart == nil {
+		return nil, fmt.Errorf("playlist '%s' not found", name)
+	}
+
+	entries, err := s.regularEntriesForGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]*entities.Song, 0)
+	for _, entry := range entries {
+		playCount, err := s.playHistory.PlayCount(guildID, entry.OriginalInput)
+		if err != nil {
+			s.logger.WithError(err).WithField("song", entry.OriginalInput).Warn("Failed to look up play count")
+		}
+		if smart.Criteria.Matches(entry, playCount) {
+			songs = append(songs, entryToSong(entry))
 		}
-		songs = append(songs, song)
 	}
 
 	return songs, nil
 }
 
+// regularEntriesForGuild returns the de-duplicated union of every regular
+// (non-smart) playlist's entries for a guild
+func (s *PlaylistService) regularEntriesForGuild(guildID string) ([]*entities.PlaylistEntry, error) {
+	names, err := s.repo.List(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var entries []*entities.PlaylistEntry
+	for _, name := range names {
+		isSmart, err := s.repo.IsSmart(guildID, name)
+		if err != nil {
+			return nil, err
+		}
+		if isSmart {
+			continue
+		}
+
+		playlist, err := s.repo.Load(guildID, name)
+		if err != nil || playlist == nil {
+			continue
+		}
+		for _, entry := range playlist.Entries {
+			if seen[entry.OriginalInput] {
+				continue
+			}
+			seen[entry.OriginalInput] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// CreateSmartPlaylist creates a new smart playlist for a guild, whose
+// membership is computed from criteria instead of a fixed entry list; see
+// GetPlaylistSongsForGuild
+func (s *PlaylistService) CreateSmartPlaylist(guildID, name string, criteria entities.Criteria) error {
+	if s.repo.Exists(guildID, name) {
+		return fmt.Errorf("playlist '%s' already exists", name)
+	}
+
+	smart := entities.NewSmartPlaylist(name, criteria)
+	if err := s.repo.SaveSmart(guildID, smart); err != nil {
+		s.logger.WithError(err).WithField("name", name).Error("Failed to create smart playlist")
+		return err
+	}
+
+	s.logger.WithField("name", name).Info("Smart playlist created")
+	return nil
+}
+
+// UpdateSmartPlaylistCriteria replaces an existing smart playlist's criteria
+func (s *PlaylistService) UpdateSmartPlaylistCriteria(guildID, name string, criteria entities.Criteria) error {
+	smart, err := s.repo.LoadSmart(guildID, name)
+	if err != nil {
+		return err
+	}
+	if smart == nil {
+		return fmt.Errorf("smart playlist '%s' not found", name)
+	}
+
+	smart.Criteria = criteria
+	smart.UpdatedAt = entities.FlexTime{Time: time.Now()}
+	if err := s.repo.SaveSmart(guildID, smart); err != nil {
+		s.logger.WithError(err).WithField("name", name).Error("Failed to update smart playlist")
+		return err
+	}
+
+	s.logger.WithField("name", name).Info("Smart playlist updated")
+	return nil
+}
+
 // PlaylistExists checks if a playlist exists
 func (s *PlaylistService) PlaylistExists(name string) bool {
 	return s.PlaylistExistsForGuild("", name)
@@ -238,7 +463,280 @@ func (s *PlaylistService) PlaylistExistsForGuild(guildID, name string) bool {
 	return s.repo.Exists(guildID, name)
 }
 
-// RenamePlaylistForGuild renames an existing playlist for a specific guild
+// ImportExternalForGuild imports a remote playlist (YouTube/Spotify/SoundCloud)
+// as a new local playlist and records its external source for later syncing
+func (s *PlaylistService) ImportExternalForGuild(guildID, name string, importer externalplaylist.PlaylistImporter, url string) (int, error) {
+	if s.repo.Exists(guildID, name) {
+		return 0, fmt.Errorf("playlist '%s' already exists", name)
+	}
+
+	result, err := importer.Import(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import playlist: %w", err)
+	}
+
+	playlist, err := s.saveImportedPlaylist(guildID, name, url, result)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(playlist.Entries), nil
+}
+
+// saveImportedPlaylist builds and persists a new playlist from a freshly
+// fetched remote result, recording its external source for later syncing.
+// Shared by ImportExternalForGuild, which lets the caller choose the
+// playlist's name, and ImportExternal, which derives one from the URL.
+func (s *PlaylistService) saveImportedPlaylist(guildID, name, url string, result *externalplaylist.ImportResult) (*entities.Playlist, error) {
+	playlist := entities.NewPlaylist(name)
+	for _, entry := range result.Entries {
+		resolvedID := s.mediaIDResolver.Resolve(entry.OriginalInput, entry.SourceType)
+		playlist.AddEntry(entry.OriginalInput, entry.SourceType, entry.Title, resolvedID)
+	}
+
+	if err := s.repo.Save(guildID, playlist); err != nil {
+		return nil, fmt.Errorf("failed to save imported playlist: %w", err)
+	}
+	s.reindexPlaylist(guildID, playlist)
+
+	info := &entities.ExternalInfo{
+		Source:     result.Source,
+		ExternalID: result.ExternalID,
+		URL:        url,
+		LastSync:   entities.FlexTime{Time: time.Now()},
+	}
+	if err := s.repo.SaveExternalInfo(guildID, name, info); err != nil {
+		s.logger.WithError(err).Warn("Failed to record external source, playlist was still imported")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"playlist": name,
+		"source":   result.Source,
+		"count":    len(result.Entries),
+	}).Info("Imported external playlist")
+
+	return playlist, nil
+}
+
+// ImportExternal imports a remote playlist from url as a new local playlist,
+// resolving the importer and a default playlist name from the URL itself.
+// It's the registry-driven counterpart to ImportExternalForGuild for callers
+// (e.g. a future /play-style shortcut) that don't already have an importer
+// in hand and don't need the user to pick a name.
+func (s *PlaylistService) ImportExternal(guildID, url string, importers *externalplaylist.Registry) (*entities.Playlist, error) {
+	importer, err := importers.For(url)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "ext-" + sanitizePlaylistName(url)
+	if s.repo.Exists(guildID, name) {
+		return nil, fmt.Errorf("playlist '%s' already exists", name)
+	}
+
+	result, err := importer.Import(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import playlist: %w", err)
+	}
+
+	return s.saveImportedPlaylist(guildID, name, url, result)
+}
+
+// sanitizePlaylistName turns a remote URL into a filesystem- and
+// database-safe playlist name slug for ImportExternal, since the importers'
+// ImportResult.ExternalID isn't always a short ID - YouTube and SoundCloud
+// both set it to the original URL.
+func sanitizePlaylistName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// ResyncExternal re-syncs a previously-imported playlist by looking up its
+// recorded external source and resolving the matching importer from the
+// registry, so callers don't need to already hold the right importer the
+// way SyncExternalForGuild requires.
+func (s *PlaylistService) ResyncExternal(guildID, name string, importers *externalplaylist.Registry) (added, removed int, err error) {
+	info, err := s.repo.GetExternalInfo(guildID, name)
+	if err != nil {
+		return 0, 0, err
+	}
+	if info == nil {
+		return 0, 0, fmt.Errorf("playlist '%s' has no external source", name)
+	}
+
+	importer, err := importers.For(info.URL)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return s.SyncExternalForGuild(guildID, name, importer)
+}
+
+// reconcileExternalEntries diffs a playlist's current entries against a
+// freshly-fetched remote result, matching by ExternalID so upstream renames
+// don't produce duplicates. It returns the reconciled entry list plus
+// added/removed counts without mutating the playlist, so both the real sync
+// and a dry-run preview can share the same logic.
+func reconcileExternalEntries(existing []*entities.PlaylistEntry, result *externalplaylist.ImportResult) (reconciled []*entities.PlaylistEntry, added, removed int) {
+	existingByID := make(map[string]*entities.PlaylistEntry, len(existing))
+	for _, e := range existing {
+		if e.ExternalID != "" {
+			existingByID[e.ExternalID] = e
+		}
+	}
+
+	seen := make(map[string]bool, len(result.Entries))
+	reconciled = make([]*entities.PlaylistEntry, 0, len(result.Entries))
+	for _, remote := range result.Entries {
+		seen[remote.ExternalID] = true
+		if existing, ok := existingByID[remote.ExternalID]; ok {
+			entry := *existing
+			entry.Title = remote.Title
+			reconciled = append(reconciled, &entry)
+			continue
+		}
+		reconciled = append(reconciled, &entities.PlaylistEntry{
+			OriginalInput: remote.OriginalInput,
+			SourceType:    remote.SourceType,
+			Title:         remote.Title,
+			ExternalID:    remote.ExternalID,
+			AddedAt:       entities.FlexTime{Time: time.Now()},
+		})
+		added++
+	}
+	for id := range existingByID {
+		if !seen[id] {
+			removed++
+		}
+	}
+
+	return reconciled, added, removed
+}
+
+// SyncExternalForGuild reconciles a previously-imported playlist against its
+// remote source, adding new entries and removing ones no longer present
+// while preserving order. Entries are matched by ExternalID so renames
+// upstream don't produce duplicates.
+func (s *PlaylistService) SyncExternalForGuild(guildID, name string, importer externalplaylist.PlaylistImporter) (added, removed int, err error) {
+	info, playlist, result, err := s.fetchSyncInputs(guildID, name, importer)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reconciled, added, removed := reconcileExternalEntries(playlist.Entries, result)
+	playlist.Entries = reconciled
+	playlist.UpdatedAt = entities.FlexTime{Time: time.Now()}
+
+	if err := s.repo.Save(guildID, playlist); err != nil {
+		return 0, 0, fmt.Errorf("failed to save synced playlist: %w", err)
+	}
+	s.reindexPlaylist(guildID, playlist)
+
+	info.LastSync = entities.FlexTime{Time: time.Now()}
+	if err := s.repo.SaveExternalInfo(guildID, name, info); err != nil {
+		s.logger.WithError(err).Warn("Failed to update last sync timestamp")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"playlist": name,
+		"added":    added,
+		"removed":  removed,
+	}).Info("Synced external playlist")
+
+	return added, removed, nil
+}
+
+// PreviewSyncExternalForGuild computes the same diff as SyncExternalForGuild
+// would apply, without saving anything, so a dry-run schedule or the
+// `/playlist sync` dry-run option can report what would change.
+func (s *PlaylistService) PreviewSyncExternalForGuild(guildID, name string, importer externalplaylist.PlaylistImporter) (added, removed int, err error) {
+	_, playlist, result, err := s.fetchSyncInputs(guildID, name, importer)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	_, added, removed = reconcileExternalEntries(playlist.Entries, result)
+	return added, removed, nil
+}
+
+// fetchSyncInputs loads the external-source record, the current playlist and
+// the freshly-fetched remote result for a sync, shared by the real sync and
+// its dry-run preview
+func (s *PlaylistService) fetchSyncInputs(guildID, name string, importer externalplaylist.PlaylistImporter) (*entities.ExternalInfo, *entities.Playlist, *externalplaylist.ImportResult, error) {
+	info, err := s.repo.GetExternalInfo(guildID, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if info == nil {
+		return nil, nil, nil, fmt.Errorf("playlist '%s' has no external source", name)
+	}
+
+	playlist, err := s.repo.Load(guildID, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if playlist == nil {
+		return nil, nil, nil, fmt.Errorf("playlist '%s' not found", name)
+	}
+
+	result, err := importer.Import(info.URL)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to re-fetch playlist: %w", err)
+	}
+
+	return info, playlist, result, nil
+}
+
+// SyncAllExternalForGuild re-syncs every externally-sourced playlist in a
+// guild, skipping playlists that weren't imported from a remote source. It
+// keeps going on a per-playlist failure so one broken sync doesn't block the
+// rest.
+func (s *PlaylistService) SyncAllExternalForGuild(guildID string, importers *externalplaylist.Registry) (synced int, err error) {
+	names, err := s.ListPlaylistsForGuild(guildID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, name := range names {
+		info, err := s.GetExternalInfoForGuild(guildID, name)
+		if err != nil || info == nil {
+			continue
+		}
+
+		importer, err := importers.For(info.URL)
+		if err != nil {
+			s.logger.WithError(err).WithField("playlist", name).Warn("No importer available for playlist's external source")
+			continue
+		}
+
+		if _, _, err := s.SyncExternalForGuild(guildID, name, importer); err != nil {
+			s.logger.WithError(err).WithField("playlist", name).Warn("Scheduled sync failed")
+			continue
+		}
+		synced++
+	}
+
+	return synced, nil
+}
+
+// GetExternalInfoForGuild returns the remote source a playlist was imported
+// from, or nil if it wasn't imported from an external source
+func (s *PlaylistService) GetExternalInfoForGuild(guildID, name string) (*entities.ExternalInfo, error) {
+	return s.repo.GetExternalInfo(guildID, name)
+}
+
+// RenamePlaylistForGuild renames an existing playlist for a specific guild.
+// The save-new/delete-old pair runs inside a single DataStore transaction,
+// so a crash partway through leaves either the old name or the new name in
+// place - never both, and never neither.
 func (s *PlaylistService) RenamePlaylistForGuild(guildID, oldName, newName string) error {
 	// Check if old playlist exists
 	if !s.repo.Exists(guildID, oldName) {
@@ -259,16 +757,102 @@ func (s *PlaylistService) RenamePlaylistForGuild(guildID, oldName, newName strin
 	// Update playlist name
 	playlist.Name = newName
 
-	// Save with new name
+	if err := s.dataStore.WithTransaction(func(tx repositories.DataStore) error {
+		if err := tx.Playlists().Save(guildID, playlist); err != nil {
+			return fmt.Errorf("failed to save renamed playlist: %w", err)
+		}
+		if err := tx.Playlists().Delete(guildID, oldName); err != nil {
+			return fmt.Errorf("failed to delete old playlist: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.reindexPlaylist(guildID, playlist)
+	s.unindexPlaylist(guildID, oldName)
+
+	return nil
+}
+
+// ImportFileForGuild creates a new playlist for a guild from raw playlist
+// data (M3U, PLS, or XSPF), as sniffed from filename/content by the caller
+func (s *PlaylistService) ImportFileForGuild(guildID, name string, format playlistformat.Format, data []byte) (int, error) {
+	if s.repo.Exists(guildID, name) {
+		return 0, fmt.Errorf("playlist '%s' already exists", name)
+	}
+
+	entries, err := playlistformat.Import(format, data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse playlist file: %w", err)
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("playlist file contained no entries")
+	}
+
+	playlist := entities.NewPlaylist(name)
+	for _, entry := range entries {
+		resolvedID := s.mediaIDResolver.Resolve(entry.OriginalInput, entry.SourceType)
+		playlist.AddEntry(entry.OriginalInput, entry.SourceType, entry.Title, resolvedID)
+	}
+
 	if err := s.repo.Save(guildID, playlist); err != nil {
-		return fmt.Errorf("failed to save renamed playlist: %w", err)
+		return 0, fmt.Errorf("failed to save imported playlist: %w", err)
 	}
+	s.reindexPlaylist(guildID, playlist)
+
+	s.logger.WithFields(map[string]interface{}{
+		"playlist": name,
+		"format":   format,
+		"count":    len(entries),
+	}).Info("Imported playlist file")
 
-	// Delete old playlist
-	if err := s.repo.Delete(guildID, oldName); err != nil {
-		s.logger.WithError(err).Warn("Failed to delete old playlist after rename")
-		// Not returning error here because the new playlist is already saved
+	return len(entries), nil
+}
+
+// ExportFileForGuild serializes a guild's playlist into the requested
+// on-disk format
+func (s *PlaylistService) ExportFileForGuild(guildID, name string, format playlistformat.Format) ([]byte, error) {
+	playlist, err := s.repo.Load(guildID, name)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("playlist '%s' not found", name)
 	}
 
-	return nil
+	data, err := playlistformat.Export(playlist, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export playlist: %w", err)
+	}
+
+	return data, nil
+}
+
+// ImportM3U creates a new playlist for guildID named name from an M3U/M3U8
+// stream, the same way /playlist import-file does for an uploaded file -
+// this just takes an io.Reader instead of the whole file already buffered
+// into memory.
+func (s *PlaylistService) ImportM3U(guildID, name string, r io.Reader) (*entities.Playlist, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read m3u playlist: %w", err)
+	}
+
+	if _, err := s.ImportFileForGuild(guildID, name, playlistformat.FormatM3U, data); err != nil {
+		return nil, err
+	}
+
+	return s.GetPlaylistForGuild(guildID, name)
+}
+
+// ExportM3U writes guildID's playlist name to w as extended M3U
+func (s *PlaylistService) ExportM3U(guildID, name string, w io.Writer) error {
+	data, err := s.ExportFileForGuild(guildID, name, playlistformat.FormatM3U)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
 }
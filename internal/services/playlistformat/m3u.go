@@ -0,0 +1,67 @@
+package playlistformat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// parseM3U parses an extended M3U playlist (#EXTM3U, #EXTINF:duration,title
+// lines followed by the URL or path). Plain M3U (no #EXTINF) is also
+// accepted; entries then have no title.
+func parseM3U(data []byte) ([]*entities.PlaylistEntry, error) {
+	var entries []*entities.PlaylistEntry
+	var pendingTitle string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line == "#EXTM3U" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#EXTINF:") {
+			// Format: #EXTINF:<duration>,<title>
+			rest := strings.TrimPrefix(line, "#EXTINF:")
+			parts := strings.SplitN(rest, ",", 2)
+			if len(parts) == 2 {
+				pendingTitle = strings.TrimSpace(parts[1])
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			// Unrecognized directive/comment
+			continue
+		}
+
+		entries = append(entries, newEntry(line, pendingTitle))
+		pendingTitle = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read m3u playlist: %w", err)
+	}
+
+	return entries, nil
+}
+
+// exportM3U serializes a playlist to extended M3U
+func exportM3U(playlist *entities.Playlist) []byte {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+
+	for _, entry := range playlist.Entries {
+		duration := -1
+		title := searchQuery(entry)
+		b.WriteString(fmt.Sprintf("#EXTINF:%s,%s\n", strconv.Itoa(duration), title))
+		b.WriteString(entry.OriginalInput)
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String())
+}
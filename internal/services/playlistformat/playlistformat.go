@@ -0,0 +1,128 @@
+// Package playlistformat converts between entities.Playlist and the
+// standard on-disk playlist formats (extended M3U, PLS, XSPF) so users can
+// migrate their libraries in and out of the bot.
+package playlistformat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+)
+
+// Format identifies an on-disk playlist format
+type Format string
+
+const (
+	FormatM3U  Format = "m3u"
+	FormatPLS  Format = "pls"
+	FormatXSPF Format = "xspf"
+)
+
+// Extension returns the file extension (without a leading dot) for a format
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// SniffFormat infers a playlist format from a filename and/or its content,
+// preferring the file extension and falling back to magic bytes
+func SniffFormat(filename string, data []byte) (Format, error) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".m3u8"), strings.HasSuffix(lower, ".m3u"):
+		return FormatM3U, nil
+	case strings.HasSuffix(lower, ".pls"):
+		return FormatPLS, nil
+	case strings.HasSuffix(lower, ".xspf"):
+		return FormatXSPF, nil
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case strings.HasPrefix(trimmed, "#EXTM3U"):
+		return FormatM3U, nil
+	case strings.HasPrefix(strings.ToLower(trimmed), "[playlist]"):
+		return FormatPLS, nil
+	case strings.Contains(trimmed[:min(len(trimmed), 512)], "<playlist"):
+		return FormatXSPF, nil
+	}
+
+	return "", fmt.Errorf("could not determine playlist format for %q", filename)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Import parses raw playlist data in the given format into entries ready to
+// be appended to an entities.Playlist
+func Import(format Format, data []byte) ([]*entities.PlaylistEntry, error) {
+	switch format {
+	case FormatM3U:
+		return parseM3U(data)
+	case FormatPLS:
+		return parsePLS(data)
+	case FormatXSPF:
+		return parseXSPF(data)
+	default:
+		return nil, fmt.Errorf("unsupported playlist format: %s", format)
+	}
+}
+
+// Export serializes a playlist's entries into the given format
+func Export(playlist *entities.Playlist, format Format) ([]byte, error) {
+	switch format {
+	case FormatM3U:
+		return exportM3U(playlist), nil
+	case FormatPLS:
+		return exportPLS(playlist), nil
+	case FormatXSPF:
+		return exportXSPF(playlist), nil
+	default:
+		return nil, fmt.Errorf("unsupported playlist format: %s", format)
+	}
+}
+
+// newEntry builds a PlaylistEntry for an imported line, inferring its
+// source type from the original input the same way the bot's own /add
+// command would
+func newEntry(originalInput, title string) *entities.PlaylistEntry {
+	return &entities.PlaylistEntry{
+		OriginalInput: originalInput,
+		Title:         title,
+		SourceType:    inferSourceType(originalInput),
+		AddedAt:       entities.FlexTime{Time: time.Now()},
+	}
+}
+
+// inferSourceType guesses a SourceType from an imported entry the same way
+// the bot would classify a pasted /add query
+func inferSourceType(input string) valueobjects.SourceType {
+	lower := strings.ToLower(input)
+	switch {
+	case strings.Contains(lower, "youtube.com"), strings.Contains(lower, "youtu.be"):
+		return valueobjects.SourceTypeYouTube
+	case strings.Contains(lower, "soundcloud.com"):
+		return valueobjects.SourceTypeSoundCloud
+	case strings.Contains(lower, "spotify.com"):
+		return valueobjects.SourceTypeSpotify
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return valueobjects.SourceTypeURL
+	default:
+		return valueobjects.SourceTypeSearch
+	}
+}
+
+// searchQuery returns what an entry's "Artist - Title" search text should
+// be when exporting a non-URL (SourceTypeSearch) entry
+func searchQuery(entry *entities.PlaylistEntry) string {
+	if entry.Title != "" {
+		return entry.Title
+	}
+	return entry.OriginalInput
+}
@@ -0,0 +1,85 @@
+package playlistformat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// parsePLS parses a PLS playlist:
+//
+//	[playlist]
+//	File1=<url>
+//	Title1=<title>
+//	NumberOfEntries=<n>
+//	Version=2
+func parsePLS(data []byte) ([]*entities.PlaylistEntry, error) {
+	files := make(map[int]string)
+	titles := make(map[int]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "[") || strings.EqualFold(line, "Version=2") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(key, "File"):
+			idx, err := strconv.Atoi(strings.TrimPrefix(key, "File"))
+			if err == nil {
+				files[idx] = value
+			}
+		case strings.HasPrefix(key, "Title"):
+			idx, err := strconv.Atoi(strings.TrimPrefix(key, "Title"))
+			if err == nil {
+				titles[idx] = value
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pls playlist: %w", err)
+	}
+
+	indexes := make([]int, 0, len(files))
+	for idx := range files {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	entries := make([]*entities.PlaylistEntry, 0, len(indexes))
+	for _, idx := range indexes {
+		entries = append(entries, newEntry(files[idx], titles[idx]))
+	}
+
+	return entries, nil
+}
+
+// exportPLS serializes a playlist to PLS
+func exportPLS(playlist *entities.Playlist) []byte {
+	var b strings.Builder
+	b.WriteString("[playlist]\n")
+
+	for i, entry := range playlist.Entries {
+		n := i + 1
+		fmt.Fprintf(&b, "File%d=%s\n", n, entry.OriginalInput)
+		fmt.Fprintf(&b, "Title%d=%s\n", n, searchQuery(entry))
+		fmt.Fprintf(&b, "Length%d=-1\n", n)
+	}
+
+	fmt.Fprintf(&b, "NumberOfEntries=%d\n", len(playlist.Entries))
+	b.WriteString("Version=2\n")
+
+	return []byte(b.String())
+}
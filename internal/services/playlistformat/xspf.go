@@ -0,0 +1,67 @@
+package playlistformat
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// xspfDoc mirrors the subset of the XSPF spec (https://xspf.org) this bot
+// round-trips: a flat list of tracks, each with a location and title.
+type xspfDoc struct {
+	XMLName   xml.Name      `xml:"playlist"`
+	Version   string        `xml:"version,attr"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	TrackList xspfTrackList `xml:"trackList"`
+}
+
+type xspfTrackList struct {
+	Tracks []xspfTrack `xml:"track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title,omitempty"`
+}
+
+// parseXSPF parses an XSPF playlist
+func parseXSPF(data []byte) ([]*entities.PlaylistEntry, error) {
+	var doc xspfDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse xspf playlist: %w", err)
+	}
+
+	entries := make([]*entities.PlaylistEntry, 0, len(doc.TrackList.Tracks))
+	for _, track := range doc.TrackList.Tracks {
+		if track.Location == "" {
+			continue
+		}
+		entries = append(entries, newEntry(track.Location, track.Title))
+	}
+
+	return entries, nil
+}
+
+// exportXSPF serializes a playlist to XSPF
+func exportXSPF(playlist *entities.Playlist) []byte {
+	doc := xspfDoc{
+		Version: "1",
+		Xmlns:   "http://xspf.org/ns/0/",
+	}
+
+	for _, entry := range playlist.Entries {
+		doc.TrackList.Tracks = append(doc.TrackList.Tracks, xspfTrack{
+			Location: entry.OriginalInput,
+			Title:    searchQuery(entry),
+		})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		// xspfDoc only contains strings; marshaling cannot fail in practice
+		return nil
+	}
+
+	return append([]byte(xml.Header), out...)
+}
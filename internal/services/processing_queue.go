@@ -0,0 +1,181 @@
+package services
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// queuedTask wraps a ProcessingTask with the bookkeeping the priority queue
+// needs: a monotonic sequence number to break priority ties FIFO, and a
+// cancellation flag so a task already popped out of the heap (by Cancel,
+// between the mutex unlock and the worker picking it up) is dropped instead
+// of processed.
+type queuedTask struct {
+	task     *ProcessingTask
+	seq      int64
+	canceled bool
+}
+
+// taskHeap is a container/heap.Interface over pending tasks, ordered by
+// descending priority and, within a priority, ascending sequence number (the
+// task submitted first runs first)
+type taskHeap []*queuedTask
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].task.Priority != h[j].task.Priority {
+		return h[i].task.Priority > h[j].task.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x interface{}) {
+	*h = append(*h, x.(*queuedTask))
+}
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// priorityTaskQueue is a bounded, priority-ordered queue of processing tasks
+// guarded by a mutex, with a sync.Cond workers block on while it's empty.
+// When full, submitting a higher-priority task evicts the lowest-priority
+// pending one instead of blocking or failing, so a user's `/play` always
+// jumps ahead of a bulk playlist import already queued.
+type priorityTaskQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	heap     taskHeap
+	maxSize  int
+	nextSeq  int64
+	closed   bool
+}
+
+func newPriorityTaskQueue(maxSize int) *priorityTaskQueue {
+	q := &priorityTaskQueue{maxSize: maxSize}
+	q.notEmpty = sync.NewCond(&q.mu)
+	return q
+}
+
+// evictedTask identifies the task Push bumped out of a full queue to make
+// room for a higher-priority submission
+type evictedTask struct {
+	songID   string
+	priority int
+}
+
+// Push adds a task to the queue. If the queue is at maxSize, the task is
+// accepted only if it outranks the current lowest-priority pending task,
+// which is evicted to make room; ok reports whether the task was queued.
+func (q *priorityTaskQueue) Push(task *ProcessingTask) (evicted *evictedTask, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, false
+	}
+
+	if q.maxSize > 0 && len(q.heap) >= q.maxSize {
+		worstIdx := q.worstIndexLocked()
+		if worstIdx < 0 || q.heap[worstIdx].task.Priority >= task.Priority {
+			return nil, false
+		}
+		worst := heap.Remove(&q.heap, worstIdx).(*queuedTask)
+		evicted = &evictedTask{songID: worst.task.Song.ID, priority: worst.task.Priority}
+	}
+
+	q.nextSeq++
+	heap.Push(&q.heap, &queuedTask{task: task, seq: q.nextSeq})
+	q.notEmpty.Signal()
+	return evicted, true
+}
+
+// worstIndexLocked returns the index of the lowest-priority pending item, or
+// -1 if the queue is empty. Caller must hold mu.
+func (q *priorityTaskQueue) worstIndexLocked() int {
+	if len(q.heap) == 0 {
+		return -1
+	}
+
+	worst := 0
+	for i := 1; i < len(q.heap); i++ {
+		// Less(worst, i) means worst outranks i, so i is the new worst
+		if q.heap.Less(worst, i) {
+			worst = i
+		}
+	}
+	return worst
+}
+
+// Pop blocks until a task is available or the queue is closed, returning the
+// highest-priority pending task (nil, false if closed with nothing left)
+func (q *priorityTaskQueue) Pop() (*ProcessingTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.heap) == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+
+	for len(q.heap) > 0 {
+		item := heap.Pop(&q.heap).(*queuedTask)
+		if item.canceled {
+			continue
+		}
+		return item.task, true
+	}
+
+	return nil, false
+}
+
+// Cancel marks a queued-but-not-yet-started task as canceled and removes it
+// from the queue, reporting whether it found one for songID
+func (q *priorityTaskQueue) Cancel(songID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, item := range q.heap {
+		if item.task.Song.ID == songID {
+			item.canceled = true
+			heap.Remove(&q.heap, i)
+			return true
+		}
+	}
+	return false
+}
+
+// Close wakes up every blocked Pop so workers can exit
+func (q *priorityTaskQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+}
+
+// Len returns the current number of pending tasks
+func (q *priorityTaskQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.heap)
+}
+
+// PendingByPriority returns a snapshot of pending task counts keyed by
+// priority level
+func (q *priorityTaskQueue) PendingByPriority() map[int]int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	counts := make(map[int]int64, 4)
+	for _, item := range q.heap {
+		counts[item.task.Priority]++
+	}
+	return counts
+}
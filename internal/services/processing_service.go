@@ -9,6 +9,7 @@ import (
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/validation"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
@@ -23,41 +24,47 @@ var (
 type ProcessingTask struct {
 	Song     *entities.Song
 	Priority int // Higher = more urgent
+	// Ctx carries the originating request's logging fields (guild, user,
+	// command, correlation ID) through to the worker and its downstream calls
+	Ctx context.Context
 }
 
-// ProcessingService handles async song processing with worker pool
+// ProcessingService handles async song processing with a priority worker pool
 type ProcessingService struct {
-	ytService  *youtube.Service
-	logger     *logger.Logger
-	queue      chan *ProcessingTask
-	workers    int
-	wg         sync.WaitGroup
-	ctx        context.Context
-	cancel     context.CancelFunc
-	mu         sync.RWMutex
-	processing map[string]bool // Track songs being processed
-	stats      ProcessingStats
+	ytService     *youtube.Service
+	searchService *SearchService
+	logger        *logger.Logger
+	queue         *priorityTaskQueue
+	workers       int
+	wg            sync.WaitGroup
+	ctx           context.Context
+	cancel        context.CancelFunc
+	mu            sync.RWMutex
+	processing    map[string]bool // Track songs being processed
+	stats         ProcessingStats
 }
 
 // ProcessingStats tracks processing statistics
 type ProcessingStats struct {
-	Processed int64
-	Failed    int64
-	Pending   int64
+	Processed         int64
+	Failed            int64
+	Pending           int64
+	PendingByPriority map[int]int64
 }
 
 // NewProcessingService creates a new processing service
-func NewProcessingService(ytService *youtube.Service, workers int, queueSize int, log *logger.Logger) *ProcessingService {
+func NewProcessingService(ytService *youtube.Service, searchSvc *SearchService, workers int, queueSize int, log *logger.Logger) *ProcessingService {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &ProcessingService{
-		ytService:  ytService,
-		logger:     log,
-		queue:      make(chan *ProcessingTask, queueSize),
-		workers:    workers,
-		ctx:        ctx,
-		cancel:     cancel,
-		processing: make(map[string]bool),
+		ytService:     ytService,
+		searchService: searchSvc,
+		logger:        log,
+		queue:         newPriorityTaskQueue(queueSize),
+		workers:       workers,
+		ctx:           ctx,
+		cancel:        cancel,
+		processing:    make(map[string]bool),
 	}
 }
 
@@ -77,18 +84,30 @@ func (s *ProcessingService) Start() {
 func (s *ProcessingService) Stop() {
 	s.logger.Info("Stopping processing service...")
 	s.cancel()
-	close(s.queue)
+	s.queue.Close()
 	s.wg.Wait()
 	s.logger.Info("✅ Processing service stopped")
 }
 
-// Submit submits a song for processing
-func (s *ProcessingService) Submit(song *entities.Song, priority int) error {
+// Submit submits a song for processing. Higher priority tasks jump ahead of
+// lower priority ones already queued; if the queue is full, a higher
+// priority submission evicts the lowest-priority pending task instead of
+// being rejected. ctx carries the originating request's logging fields
+// through to the worker and its downstream YouTube calls.
+func (s *ProcessingService) Submit(ctx context.Context, song *entities.Song, priority int) error {
+	select {
+	case <-s.ctx.Done():
+		return ErrProcessingServiceStopped
+	default:
+	}
+
+	log := s.logger.FromContext(ctx)
+
 	// Check if already processing
 	s.mu.Lock()
 	if s.processing[song.ID] {
 		s.mu.Unlock()
-		s.logger.WithField("song_id", song.ID).Debug("Song already being processed")
+		log.WithField("song_id", song.ID).Debug("Song already being processed")
 		return nil
 	}
 	s.processing[song.ID] = true
@@ -97,56 +116,74 @@ func (s *ProcessingService) Submit(song *entities.Song, priority int) error {
 	task := &ProcessingTask{
 		Song:     song,
 		Priority: priority,
+		Ctx:      ctx,
 	}
 
-	select {
-	case s.queue <- task:
+	evicted, ok := s.queue.Push(task)
+	if !ok {
 		s.mu.Lock()
-		s.stats.Pending++
+		delete(s.processing, song.ID)
 		s.mu.Unlock()
-		s.logger.WithFields(map[string]interface{}{
+		log.WithFields(map[string]interface{}{
 			"song_id":  song.ID,
 			"priority": priority,
-		}).Debug("Song submitted for processing")
-		return nil
-	case <-s.ctx.Done():
-		s.mu.Lock()
-		delete(s.processing, song.ID)
-		s.mu.Unlock()
-		return ErrProcessingServiceStopped
-	default:
-		s.mu.Lock()
-		delete(s.processing, song.ID)
-		s.mu.Unlock()
-		s.logger.WithFields(map[string]interface{}{
-			"song_id":    song.ID,
-			"queue_size": len(s.queue),
-			"max_size":   cap(s.queue),
+			"max_size": s.queue.maxSize,
 		}).Warn("Processing queue is full, rejecting song")
 		return ErrMaxQueueSize
 	}
+
+	if evicted != nil {
+		s.mu.Lock()
+		delete(s.processing, evicted.songID)
+		s.mu.Unlock()
+		log.WithFields(map[string]interface{}{
+			"evicted_song_id":  evicted.songID,
+			"evicted_priority": evicted.priority,
+			"song_id":          song.ID,
+			"priority":         priority,
+		}).Info("Evicted lower-priority task to make room")
+	}
+
+	s.mu.Lock()
+	s.stats.Pending++
+	s.mu.Unlock()
+	log.WithFields(map[string]interface{}{
+		"song_id":  song.ID,
+		"priority": priority,
+	}).Debug("Song submitted for processing")
+	return nil
 }
 
-// worker processes tasks from the queue
+// Cancel aborts a queued-but-not-yet-started task so a user can back out of
+// a song they skipped before a worker started processing it
+func (s *ProcessingService) Cancel(songID string) bool {
+	if !s.queue.Cancel(songID) {
+		return false
+	}
+
+	s.mu.Lock()
+	delete(s.processing, songID)
+	s.stats.Pending--
+	s.mu.Unlock()
+
+	s.logger.WithField("song_id", songID).Debug("Cancelled queued task")
+	return true
+}
+
+// worker processes tasks from the priority queue
 func (s *ProcessingService) worker(id int) {
 	defer s.wg.Done()
 
 	s.logger.WithField("worker_id", id).Debug("Worker started")
 
 	for {
-		select {
-		case task, ok := <-s.queue:
-			if !ok {
-				s.logger.WithField("worker_id", id).Debug("Worker stopping - queue closed")
-				return
-			}
-
-			s.processTask(task, id)
-
-		case <-s.ctx.Done():
-			s.logger.WithField("worker_id", id).Debug("Worker stopping - context cancelled")
+		task, ok := s.queue.Pop()
+		if !ok {
+			s.logger.WithField("worker_id", id).Debug("Worker stopping - queue closed")
 			return
 		}
+
+		s.processTask(task, id)
 	}
 }
 
@@ -154,6 +191,7 @@ func (s *ProcessingService) worker(id int) {
 func (s *ProcessingService) processTask(task *ProcessingTask, workerID int) {
 	song := task.Song
 	songID := song.ID
+	log := s.logger.FromContext(task.Ctx)
 
 	defer func() {
 		s.mu.Lock()
@@ -162,7 +200,7 @@ func (s *ProcessingService) processTask(task *ProcessingTask, workerID int) {
 		s.mu.Unlock()
 	}()
 
-	s.logger.WithFields(map[string]interface{}{
+	log.WithFields(map[string]interface{}{
 		"worker_id": workerID,
 		"song_id":   songID,
 		"source":    song.SourceType,
@@ -175,7 +213,7 @@ func (s *ProcessingService) processTask(task *ProcessingTask, workerID int) {
 	var err error
 	switch song.SourceType {
 	case valueobjects.SourceTypeYouTube:
-		err = s.processYouTubeSong(song)
+		err = s.processYouTubeSong(task.Ctx, song)
 	case valueobjects.SourceTypeURL:
 		err = s.processURLSong(song)
 	default:
@@ -183,24 +221,30 @@ func (s *ProcessingService) processTask(task *ProcessingTask, workerID int) {
 	}
 
 	if err != nil {
-		s.logger.WithError(err).WithField("song_id", songID).Error("Processing failed")
+		log.WithError(err).WithField("song_id", songID).Error("Processing failed")
 		song.MarkFailed(err.Error())
 		s.updateStats(false)
 		return
 	}
 
-	s.logger.WithField("song_id", songID).Info("✅ Song processed successfully")
+	log.WithField("song_id", songID).Info("✅ Song processed successfully")
 	s.updateStats(true)
+
+	if s.searchService != nil {
+		if metadata := song.GetMetadata(); metadata != nil && metadata.Title != "" {
+			s.searchService.Index("song:"+songID, SearchKindSong, metadata.Title)
+		}
+	}
 }
 
 // processYouTubeSong processes a YouTube song or web URL (including SoundCloud)
-func (s *ProcessingService) processYouTubeSong(song *entities.Song) error {
+func (s *ProcessingService) processYouTubeSong(ctx context.Context, song *entities.Song) error {
 	source := song.OriginalInput
 
 	// Check if it's a web URL (YouTube, SoundCloud, or other yt-dlp supported sites)
 	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
 		// Extract info from URL using yt-dlp
-		info, err := s.ytService.ExtractInfo(source)
+		info, err := s.ytService.ExtractInfo(ctx, source)
 		if err != nil {
 			return err
 		}
@@ -219,8 +263,13 @@ func (s *ProcessingService) processYouTubeSong(song *entities.Song) error {
 			return err
 		}
 
-		// Mark as ready with metadata
-		song.MarkReady(info.ToSongMetadata(), streamURL)
+		// Mark as ready with metadata, honoring a "share at current time"
+		// offset (?t=90s) pasted as part of the URL
+		metadata := info.ToSongMetadata()
+		if offset, err := validation.ParseStartOffset(source); err == nil {
+			metadata.StartOffset = offset
+		}
+		song.MarkReady(metadata, streamURL)
 		return nil
 	}
 
@@ -279,16 +328,20 @@ func (s *ProcessingService) updateStats(success bool) {
 	}
 }
 
-// GetStats returns processing statistics
+// GetStats returns processing statistics, including a snapshot of pending
+// task counts broken down by priority level
 func (s *ProcessingService) GetStats() ProcessingStats {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.stats
+	stats := s.stats
+	s.mu.RUnlock()
+
+	stats.PendingByPriority = s.queue.PendingByPriority()
+	return stats
 }
 
 // QueueSize returns current queue size
 func (s *ProcessingService) QueueSize() int {
-	return len(s.queue)
+	return s.queue.Len()
 }
 
 // IsProcessing checks if a song is currently being processed
@@ -0,0 +1,117 @@
+package scrobble
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lastFMAPIURL = "https://ws.audioscrobbler.com/2.0/"
+
+// LastFMScrobbler submits scrobbles to Last.fm using the bot's API key/secret
+// plus a per-user session key (stored as the user's token)
+type LastFMScrobbler struct {
+	apiKey     string
+	apiSecret  string
+	httpClient *http.Client
+}
+
+// NewLastFMScrobbler creates a new Last.fm scrobbler
+func NewLastFMScrobbler(apiKey, apiSecret string) *LastFMScrobbler {
+	return &LastFMScrobbler{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NowPlaying calls track.updateNowPlaying
+func (s *LastFMScrobbler) NowPlaying(ctx context.Context, sessionKey string, track Track) error {
+	params := url.Values{
+		"method": {"track.updateNowPlaying"},
+		"track":  {track.Title},
+		"artist": {track.Artist},
+	}
+	if track.Duration > 0 {
+		params.Set("duration", strconv.Itoa(int(track.Duration.Seconds())))
+	}
+	return s.call(ctx, sessionKey, params)
+}
+
+// Submit calls track.scrobble
+func (s *LastFMScrobbler) Submit(ctx context.Context, sessionKey string, track Track, playedAt time.Time, duration time.Duration) error {
+	params := url.Values{
+		"method":    {"track.scrobble"},
+		"track":     {track.Title},
+		"artist":    {track.Artist},
+		"timestamp": {strconv.FormatInt(playedAt.Unix(), 10)},
+	}
+	if duration > 0 {
+		params.Set("duration", strconv.Itoa(int(duration.Seconds())))
+	}
+	return s.call(ctx, sessionKey, params)
+}
+
+// call signs and POSTs a Last.fm API method
+func (s *LastFMScrobbler) call(ctx context.Context, sessionKey string, params url.Values) error {
+	params.Set("api_key", s.apiKey)
+	params.Set("sk", sessionKey)
+	params.Set("api_sig", s.sign(params))
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lastFMAPIURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	err = fmt.Errorf("lastfm %s failed: %s - %s", params.Get("method"), resp.Status, string(body))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
+
+// sign computes the Last.fm method signature: the MD5 hex digest of every
+// param (excluding format/callback) sorted by key and concatenated as
+// key+value, followed by the shared secret
+func (s *LastFMScrobbler) sign(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "format" || k == "callback" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteString(params.Get(k))
+	}
+	sb.WriteString(s.apiSecret)
+
+	sum := md5.Sum([]byte(sb.String()))
+	return hex.EncodeToString(sum[:])
+}
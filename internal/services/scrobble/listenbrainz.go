@@ -0,0 +1,98 @@
+package scrobble
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const listenBrainzSubmitURL = "https://api.listenbrainz.org/1/submit-listens"
+
+// ListenBrainzScrobbler submits listens to ListenBrainz using a per-user token
+type ListenBrainzScrobbler struct {
+	httpClient *http.Client
+}
+
+// NewListenBrainzScrobbler creates a new ListenBrainz scrobbler
+func NewListenBrainzScrobbler() *ListenBrainzScrobbler {
+	return &ListenBrainzScrobbler{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type listenBrainzSubmission struct {
+	ListenType string               `json:"listen_type"`
+	Payload    []listenBrainzListen `json:"payload"`
+}
+
+type listenBrainzListen struct {
+	ListenedAt int64                     `json:"listened_at,omitempty"`
+	TrackMeta  listenBrainzTrackMetadata `json:"track_metadata"`
+}
+
+type listenBrainzTrackMetadata struct {
+	TrackName      string `json:"track_name"`
+	ArtistName     string `json:"artist_name"`
+	AdditionalInfo struct {
+		DurationMs int `json:"duration_ms,omitempty"`
+	} `json:"additional_info,omitempty"`
+}
+
+// NowPlaying sends a "playing_now" listen with no timestamp
+func (s *ListenBrainzScrobbler) NowPlaying(ctx context.Context, token string, track Track) error {
+	listen := listenBrainzListen{TrackMeta: toListenBrainzMetadata(track)}
+	return s.submit(ctx, token, "playing_now", []listenBrainzListen{listen})
+}
+
+// Submit sends a "single" listen for a completed track
+func (s *ListenBrainzScrobbler) Submit(ctx context.Context, token string, track Track, playedAt time.Time, duration time.Duration) error {
+	listen := listenBrainzListen{
+		ListenedAt: playedAt.Unix(),
+		TrackMeta:  toListenBrainzMetadata(track),
+	}
+	return s.submit(ctx, token, "single", []listenBrainzListen{listen})
+}
+
+func toListenBrainzMetadata(track Track) listenBrainzTrackMetadata {
+	meta := listenBrainzTrackMetadata{
+		TrackName:  track.Title,
+		ArtistName: track.Artist,
+	}
+	meta.AdditionalInfo.DurationMs = int(track.Duration.Milliseconds())
+	return meta
+}
+
+func (s *ListenBrainzScrobbler) submit(ctx context.Context, token, listenType string, payload []listenBrainzListen) error {
+	body, err := json.Marshal(listenBrainzSubmission{ListenType: listenType, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, listenBrainzSubmitURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(resp.Body)
+	err = fmt.Errorf("listenbrainz submit-listens failed: %s - %s", resp.Status, string(respBody))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,272 @@
+package scrobble
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+const (
+	maxSubmitAttempts = 5
+	initialBackoff    = 5 * time.Second
+	maxBackoff        = 10 * time.Minute
+)
+
+// SubmitTask is a pending Submit call waiting to be delivered
+type SubmitTask struct {
+	Service  valueobjects.ScrobbleService `json:"service"`
+	Token    string                       `json:"token"`
+	Track    Track                        `json:"track"`
+	PlayedAt time.Time                    `json:"played_at"`
+	Duration time.Duration                `json:"duration"`
+	Attempts int                          `json:"attempts"`
+}
+
+// Queue is a durable submission queue: pending tasks survive a restart, and
+// failed deliveries are retried with exponential backoff. A task that fails
+// with a 4xx (PermanentError) is dropped instead of retried.
+type Queue struct {
+	scrobblers  map[valueobjects.ScrobbleService]Scrobbler
+	logger      *logger.Logger
+	persistPath string
+
+	mu      sync.Mutex
+	pending []*SubmitTask
+
+	tasks  chan *SubmitTask
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewQueue creates a submission queue backed by persistPath for durability
+// across restarts, loading any tasks left over from a previous run
+func NewQueue(scrobblers map[valueobjects.ScrobbleService]Scrobbler, persistPath string, log *logger.Logger) *Queue {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	q := &Queue{
+		scrobblers:  scrobblers,
+		logger:      log,
+		persistPath: persistPath,
+		tasks:       make(chan *SubmitTask, 256),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	if tasks, err := q.loadPending(); err != nil {
+		log.WithError(err).Warn("Failed to load persisted scrobble queue")
+	} else {
+		q.pending = tasks
+	}
+
+	return q
+}
+
+// Start launches the background worker that drains the queue
+func (q *Queue) Start() {
+	q.wg.Add(1)
+	go q.run()
+}
+
+// Stop stops the worker and persists any remaining tasks
+func (q *Queue) Stop() {
+	q.cancel()
+	q.wg.Wait()
+	q.persist()
+}
+
+// Enqueue schedules a Submit call for durable, retried delivery
+func (q *Queue) Enqueue(service valueobjects.ScrobbleService, token string, track Track, playedAt time.Time, duration time.Duration) {
+	task := &SubmitTask{
+		Service:  service,
+		Token:    token,
+		Track:    track,
+		PlayedAt: playedAt,
+		Duration: duration,
+	}
+
+	q.mu.Lock()
+	q.pending = append(q.pending, task)
+	q.persistLocked()
+	q.mu.Unlock()
+
+	select {
+	case q.tasks <- task:
+	case <-q.ctx.Done():
+	default:
+		// Worker is busy; run() also drains q.pending directly, so the task
+		// isn't lost - just not picked up until the next sweep.
+	}
+}
+
+// run repeatedly drains pending tasks, retrying failures with backoff
+func (q *Queue) run() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-q.tasks:
+			q.drain()
+		case <-ticker.C:
+			q.drain()
+		}
+	}
+}
+
+// drain attempts delivery of every pending task, removing those that
+// succeed or permanently fail
+func (q *Queue) drain() {
+	q.mu.Lock()
+	tasks := q.pending
+	q.mu.Unlock()
+
+	var remaining []*SubmitTask
+	for _, task := range tasks {
+		if !q.readyToRetry(task) {
+			remaining = append(remaining, task)
+			continue
+		}
+
+		if q.deliver(task) {
+			continue
+		}
+		remaining = append(remaining, task)
+	}
+
+	q.mu.Lock()
+	q.pending = remaining
+	q.persistLocked()
+	q.mu.Unlock()
+}
+
+// readyToRetry reports whether enough backoff time has passed since the
+// task's last attempt
+func (q *Queue) readyToRetry(task *SubmitTask) bool {
+	if task.Attempts == 0 {
+		return true
+	}
+	backoff := initialBackoff * time.Duration(1<<uint(task.Attempts-1))
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return time.Since(task.PlayedAt) >= backoff
+}
+
+// deliver attempts one submission, returning true if the task should be
+// removed from the queue (success, or a permanent/exhausted failure)
+func (q *Queue) deliver(task *SubmitTask) bool {
+	scrobbler, ok := q.scrobblers[task.Service]
+	if !ok {
+		q.logger.WithField("service", task.Service).Warn("Dropping scrobble task for unconfigured service")
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(q.ctx, 10*time.Second)
+	defer cancel()
+
+	err := scrobbler.Submit(ctx, task.Token, task.Track, task.PlayedAt, task.Duration)
+	if err == nil {
+		return true
+	}
+
+	task.Attempts++
+
+	var permErr *PermanentError
+	if isPermanentError(err, &permErr) {
+		q.logger.WithError(err).WithField("service", task.Service).Warn("Dropping scrobble after permanent failure")
+		return true
+	}
+
+	if task.Attempts >= maxSubmitAttempts {
+		q.logger.WithError(err).WithField("service", task.Service).Warn("Dropping scrobble after exhausting retries")
+		return true
+	}
+
+	q.logger.WithError(err).WithFields(map[string]interface{}{
+		"service":  task.Service,
+		"attempts": task.Attempts,
+	}).Warn("Scrobble submission failed, will retry")
+	return false
+}
+
+func isPermanentError(err error, target **PermanentError) bool {
+	for err != nil {
+		if pe, ok := err.(*PermanentError); ok {
+			*target = pe
+			return true
+		}
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return false
+		}
+		err = unwrapper.Unwrap()
+	}
+	return false
+}
+
+// persistLocked writes q.pending to disk; callers must hold q.mu
+func (q *Queue) persistLocked() {
+	if q.persistPath == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(q.pending, "", "  ")
+	if err != nil {
+		q.logger.WithError(err).Warn("Failed to encode scrobble queue")
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.persistPath), 0755); err != nil {
+		q.logger.WithError(err).Warn("Failed to create scrobble queue directory")
+		return
+	}
+
+	tempPath := q.persistPath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		q.logger.WithError(err).Warn("Failed to write scrobble queue")
+		return
+	}
+	if err := os.Rename(tempPath, q.persistPath); err != nil {
+		q.logger.WithError(err).Warn("Failed to rename scrobble queue file")
+	}
+}
+
+// persist acquires the lock before writing (used from Stop)
+func (q *Queue) persist() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.persistLocked()
+}
+
+// loadPending reads any tasks left over from a previous run
+func (q *Queue) loadPending() ([]*SubmitTask, error) {
+	if q.persistPath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(q.persistPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read scrobble queue: %w", err)
+	}
+
+	var tasks []*SubmitTask
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode scrobble queue: %w", err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,41 @@
+// Package scrobble submits played tracks to external scrobbling services
+// (ListenBrainz, Last.fm) on behalf of a user.
+package scrobble
+
+import (
+	"context"
+	"time"
+)
+
+// Track is the minimal track information a Scrobbler needs
+type Track struct {
+	Title    string
+	Artist   string
+	Duration time.Duration
+}
+
+// Scrobbler submits listening activity to a scrobbling provider. token is
+// the caller's stored credential (a ListenBrainz user token, or a Last.fm
+// session key).
+type Scrobbler interface {
+	// NowPlaying tells the provider a track has just started playing
+	NowPlaying(ctx context.Context, token string, track Track) error
+
+	// Submit records a completed (or sufficiently-played) listen
+	Submit(ctx context.Context, token string, track Track, playedAt time.Time, duration time.Duration) error
+}
+
+// PermanentError marks a Scrobbler failure that will never succeed on retry
+// (e.g. an invalid token), so the submission queue should drop it instead of
+// retrying with backoff.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
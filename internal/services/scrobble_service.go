@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/scrobble"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// ScrobbleService wires the scrobble.Scrobbler implementations, per-user
+// credentials, and per-guild opt-in together, and exposes the hooks the
+// playback pipeline calls when a track starts and finishes playing
+type ScrobbleService struct {
+	credRepo   repositories.ScrobbleCredentialRepositoryInterface
+	scrobblers map[valueobjects.ScrobbleService]scrobble.Scrobbler
+	queue      *scrobble.Queue
+	logger     *logger.Logger
+
+	guildEnabledPath string
+	mu               sync.RWMutex
+	guildEnabled     map[string]bool
+}
+
+// newScrobbleService builds the shared pieces common to both constructors
+func newScrobbleService(credRepo repositories.ScrobbleCredentialRepositoryInterface, stateDir, lastFMAPIKey, lastFMAPISecret string, log *logger.Logger) *ScrobbleService {
+	scrobblers := map[valueobjects.ScrobbleService]scrobble.Scrobbler{
+		valueobjects.ScrobbleServiceListenBrainz: scrobble.NewListenBrainzScrobbler(),
+	}
+	if lastFMAPIKey != "" && lastFMAPISecret != "" {
+		scrobblers[valueobjects.ScrobbleServiceLastFM] = scrobble.NewLastFMScrobbler(lastFMAPIKey, lastFMAPISecret)
+	}
+
+	queuePath := filepath.Join(stateDir, "scrobble_queue.json")
+	s := &ScrobbleService{
+		credRepo:         credRepo,
+		scrobblers:       scrobblers,
+		queue:            scrobble.NewQueue(scrobblers, queuePath, log),
+		logger:           log,
+		guildEnabledPath: filepath.Join(stateDir, "scrobble_guilds.json"),
+		guildEnabled:     make(map[string]bool),
+	}
+
+	if enabled, err := s.loadGuildEnabled(); err != nil {
+		log.WithError(err).Warn("Failed to load scrobble guild opt-ins")
+	} else {
+		s.guildEnabled = enabled
+	}
+
+	return s
+}
+
+// NewScrobbleService creates a scrobble service backed by file-based credential storage
+func NewScrobbleService(stateDir, lastFMAPIKey, lastFMAPISecret string, log *logger.Logger) *ScrobbleService {
+	return newScrobbleService(repositories.NewScrobbleCredentialRepository(stateDir), stateDir, lastFMAPIKey, lastFMAPISecret, log)
+}
+
+// NewScrobbleServiceWithDB creates a scrobble service backed by the database
+func NewScrobbleServiceWithDB(db *database.DB, stateDir, lastFMAPIKey, lastFMAPISecret string, log *logger.Logger) *ScrobbleService {
+	return newScrobbleService(repositories.NewDatabaseScrobbleCredentialRepository(db), stateDir, lastFMAPIKey, lastFMAPISecret, log)
+}
+
+// Start starts the durable submission queue's background worker
+func (s *ScrobbleService) Start() {
+	s.queue.Start()
+}
+
+// Stop stops the submission queue, persisting any undelivered scrobbles
+func (s *ScrobbleService) Stop() {
+	s.queue.Stop()
+}
+
+// Link records a user's token for a scrobbling service
+func (s *ScrobbleService) Link(userID, rawService, token string) error {
+	service, err := valueobjects.ParseScrobbleService(rawService)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.scrobblers[service]; !ok {
+		return fmt.Errorf("scrobble service %q is not configured on this bot", service)
+	}
+	return s.credRepo.Link(userID, service, token)
+}
+
+// Unlink removes a user's credential for a scrobbling service
+func (s *ScrobbleService) Unlink(userID, rawService string) error {
+	service, err := valueobjects.ParseScrobbleService(rawService)
+	if err != nil {
+		return err
+	}
+	return s.credRepo.Unlink(userID, service)
+}
+
+// SetGuildEnabled opts a guild in (or out) of scrobbling
+func (s *ScrobbleService) SetGuildEnabled(guildID string, enabled bool) error {
+	s.mu.Lock()
+	s.guildEnabled[guildID] = enabled
+	err := s.saveGuildEnabledLocked()
+	s.mu.Unlock()
+	return err
+}
+
+// IsGuildEnabled reports whether a guild has opted in to scrobbling
+func (s *ScrobbleService) IsGuildEnabled(guildID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.guildEnabled[guildID]
+}
+
+// NowPlaying fires a "now playing" update to every service userID has linked,
+// if their guild has scrobbling enabled. Best-effort: failures are logged,
+// not returned, since this runs on the playback hot path.
+func (s *ScrobbleService) NowPlaying(guildID, userID string, track scrobble.Track) {
+	if !s.IsGuildEnabled(guildID) || userID == "" {
+		return
+	}
+
+	for _, cred := range s.credentialsFor(userID) {
+		scrobbler := s.scrobblers[cred.Service]
+		if scrobbler == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := scrobbler.NowPlaying(ctx, cred.Token, track); err != nil {
+			s.logger.WithError(err).WithField("service", cred.Service).Warn("Failed to send now-playing update")
+		}
+		cancel()
+	}
+}
+
+// Submit enqueues a durable scrobble submission for every service userID has
+// linked, if their guild has scrobbling enabled
+func (s *ScrobbleService) Submit(guildID, userID string, track scrobble.Track, playedAt time.Time, duration time.Duration) {
+	if !s.IsGuildEnabled(guildID) || userID == "" {
+		return
+	}
+
+	for _, cred := range s.credentialsFor(userID) {
+		if _, ok := s.scrobblers[cred.Service]; !ok {
+			continue
+		}
+		s.queue.Enqueue(cred.Service, cred.Token, track, playedAt, duration)
+	}
+}
+
+func (s *ScrobbleService) credentialsFor(userID string) []*entities.ScrobbleCredential {
+	creds, err := s.credRepo.ListForUser(userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("user", userID).Warn("Failed to load scrobble credentials")
+		return nil
+	}
+	return creds
+}
+
+func (s *ScrobbleService) loadGuildEnabled() (map[string]bool, error) {
+	data, err := os.ReadFile(s.guildEnabledPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]bool), nil
+		}
+		return nil, err
+	}
+
+	enabled := make(map[string]bool)
+	if err := json.Unmarshal(data, &enabled); err != nil {
+		return nil, err
+	}
+	return enabled, nil
+}
+
+// saveGuildEnabledLocked writes guild opt-ins to disk; callers must hold s.mu
+func (s *ScrobbleService) saveGuildEnabledLocked() error {
+	if err := os.MkdirAll(filepath.Dir(s.guildEnabledPath), 0755); err != nil {
+		return fmt.Errorf("failed to create scrobble state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s.guildEnabled, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode guild opt-ins: %w", err)
+	}
+
+	return os.WriteFile(s.guildEnabledPath, data, 0644)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// DefaultSearchThreshold is the minimum Jaccard similarity a candidate must
+// reach to be considered a match
+const DefaultSearchThreshold = 0.3
+
+// SearchKind identifies what a SearchResult points at, so command handlers
+// know how to act on a chosen result
+type SearchKind string
+
+const (
+	SearchKindPlaylist      SearchKind = "playlist"
+	SearchKindPlaylistEntry SearchKind = "playlist_entry"
+	SearchKindSong          SearchKind = "song"
+)
+
+// searchCandidate is one indexed string and what it refers to
+type searchCandidate struct {
+	id       string
+	kind     SearchKind
+	text     string
+	trigrams map[string]bool
+}
+
+// SearchResult is a ranked match returned by Search
+type SearchResult struct {
+	ID    string
+	Kind  SearchKind
+	Text  string
+	Score float64
+}
+
+// SearchService answers "did you mean" style fuzzy queries against playlist
+// names, playlist entries, and previously processed song titles, using
+// trigram Jaccard similarity. It's kept eventually-consistent with an
+// in-memory inverted index (trigram -> candidate IDs) that callers rebuild
+// piecemeal as playlists and songs change, so query cost is proportional to
+// the number of candidates that share a trigram with the query rather than
+// the full candidate set.
+type SearchService struct {
+	threshold float64
+	logger    *logger.Logger
+
+	mu         sync.RWMutex
+	candidates map[string]*searchCandidate // id -> candidate
+	index      map[string]map[string]bool  // trigram -> set of candidate IDs
+}
+
+// NewSearchService creates a new search service with the default threshold
+func NewSearchService(log *logger.Logger) *SearchService {
+	return &SearchService{
+		threshold:  DefaultSearchThreshold,
+		logger:     log,
+		candidates: make(map[string]*searchCandidate),
+		index:      make(map[string]map[string]bool),
+	}
+}
+
+// SetThreshold overrides the minimum similarity score a match must reach
+func (s *SearchService) SetThreshold(threshold float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.threshold = threshold
+}
+
+// Index adds or replaces a candidate under the given ID
+func (s *SearchService) Index(id string, kind SearchKind, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(id)
+
+	trigrams := trigramSet(text)
+	s.candidates[id] = &searchCandidate{id: id, kind: kind, text: text, trigrams: trigrams}
+	for tg := range trigrams {
+		set, ok := s.index[tg]
+		if !ok {
+			set = make(map[string]bool)
+			s.index[tg] = set
+		}
+		set[id] = true
+	}
+}
+
+// Remove drops a candidate from the index
+func (s *SearchService) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(id)
+}
+
+// RemoveByPrefix drops every candidate whose ID starts with prefix, e.g. all
+// entries belonging to a deleted playlist
+func (s *SearchService) RemoveByPrefix(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.candidates {
+		if strings.HasPrefix(id, prefix) {
+			s.removeLocked(id)
+		}
+	}
+}
+
+func (s *SearchService) removeLocked(id string) {
+	candidate, ok := s.candidates[id]
+	if !ok {
+		return
+	}
+	for tg := range candidate.trigrams {
+		set := s.index[tg]
+		delete(set, id)
+		if len(set) == 0 {
+			delete(s.index, tg)
+		}
+	}
+	delete(s.candidates, id)
+}
+
+// Search ranks every indexed candidate that shares at least one trigram with
+// the query and clears the configured threshold, returning up to limit
+// results sorted by descending score
+func (s *SearchService) Search(query string, limit int) []SearchResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queryTrigrams := trigramSet(query)
+	if len(queryTrigrams) == 0 {
+		return nil
+	}
+
+	scores := make(map[string]int, 16)
+	for tg := range queryTrigrams {
+		for id := range s.index[tg] {
+			scores[id]++
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for id, intersection := range scores {
+		candidate := s.candidates[id]
+		union := len(queryTrigrams) + len(candidate.trigrams) - intersection
+		score := float64(intersection) / float64(union)
+		if score < s.threshold {
+			continue
+		}
+		results = append(results, SearchResult{
+			ID:    candidate.id,
+			Kind:  candidate.kind,
+			Text:  candidate.text,
+			Score: score,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Text < results[j].Text
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// trigramSet generates the set of padded 3-grams for a string, lowercased so
+// matching is case-insensitive. Short strings are padded with two spaces on
+// each side so they still yield at least one trigram.
+func trigramSet(text string) map[string]bool {
+	padded := "  " + strings.ToLower(strings.TrimSpace(text)) + "  "
+	runes := []rune(padded)
+
+	trigrams := make(map[string]bool)
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams[string(runes[i:i+3])] = true
+	}
+	return trigrams
+}
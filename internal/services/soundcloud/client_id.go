@@ -0,0 +1,112 @@
+package soundcloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// clientIDRefreshInterval is how long a scraped client_id is trusted before
+// ClientIDResolver.Get re-scrapes it. SoundCloud rotates the web player's
+// client_id whenever it ships a new bundle, which happens far less often
+// than this, but re-scraping periodically means an unannounced rotation
+// self-heals instead of wedging every request behind a 401 until restart.
+const clientIDRefreshInterval = 1 * time.Hour
+
+// scriptSrcPattern matches the web player's bundled <script src="..."> tags
+// on the soundcloud.com landing page; the client_id literal lives in one of
+// these bundles, not the page itself.
+var scriptSrcPattern = regexp.MustCompile(`<script[^>]+src="([^"]+\.js)"`)
+
+// clientIDLiteralPattern matches the `client_id:"..."` literal inside a
+// bundled script.
+var clientIDLiteralPattern = regexp.MustCompile(`client_id\s*:\s*"([a-zA-Z0-9]+)"`)
+
+// ClientIDResolver obtains SoundCloud's web-player client_id by scraping
+// soundcloud.com, the same credential the web player itself uses, so
+// SoundCloudProvider works without an operator registering their own app.
+// Safe for concurrent use.
+type ClientIDResolver struct {
+	client *http.Client
+
+	mu        sync.Mutex
+	clientID  string
+	fetchedAt time.Time
+}
+
+// NewClientIDResolver creates a ClientIDResolver that scrapes via client, or
+// http.DefaultClient's settings with a 15s timeout if client is nil.
+func NewClientIDResolver(client *http.Client) *ClientIDResolver {
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &ClientIDResolver{client: client}
+}
+
+// Get returns a cached client_id if it was fetched within
+// clientIDRefreshInterval, otherwise scrapes a fresh one.
+func (r *ClientIDResolver) Get(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.clientID != "" && time.Since(r.fetchedAt) < clientIDRefreshInterval {
+		return r.clientID, nil
+	}
+
+	id, err := r.scrape(ctx)
+	if err != nil {
+		if r.clientID != "" {
+			// Stale beats none - keep serving the old id until the next Get
+			return r.clientID, nil
+		}
+		return "", err
+	}
+
+	r.clientID = id
+	r.fetchedAt = time.Now()
+	return id, nil
+}
+
+// scrape fetches soundcloud.com, finds its bundled script tags, and returns
+// the first client_id literal it finds in one of them.
+func (r *ClientIDResolver) scrape(ctx context.Context) (string, error) {
+	body, err := r.get(ctx, "https://soundcloud.com")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch soundcloud.com: %w", err)
+	}
+
+	for _, m := range scriptSrcPattern.FindAllStringSubmatch(string(body), -1) {
+		script, err := r.get(ctx, m[1])
+		if err != nil {
+			continue
+		}
+		if id := clientIDLiteralPattern.FindStringSubmatch(string(script)); id != nil {
+			return id[1], nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find client_id in any soundcloud.com bundle")
+}
+
+func (r *ClientIDResolver) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, url)
+	}
+
+	return io.ReadAll(resp.Body)
+}
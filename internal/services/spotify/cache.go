@@ -0,0 +1,76 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+)
+
+// trackCacheTTL is how long a resolved track is cached before it is
+// re-fetched from the Spotify API
+const trackCacheTTL = 7 * 24 * time.Hour
+
+// trackCache looks up and stores resolved tracks keyed by Spotify ID, so
+// repeated requests for the same track/album/playlist item don't re-hit the
+// Spotify API
+type trackCache interface {
+	Get(id string) (*Track, bool)
+	Set(id string, track Track)
+}
+
+// noopCache never caches anything; used when no database is configured
+type noopCache struct{}
+
+func (noopCache) Get(id string) (*Track, bool) { return nil, false }
+func (noopCache) Set(id string, track Track)   {}
+
+// databaseTrackCache caches resolved tracks in Postgres with a TTL
+type databaseTrackCache struct {
+	db *database.DB
+}
+
+func newDatabaseTrackCache(db *database.DB) *databaseTrackCache {
+	return &databaseTrackCache{db: db}
+}
+
+// Get returns a cached track if one exists and hasn't expired
+func (c *databaseTrackCache) Get(id string) (*Track, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	row, err := c.db.Queries.GetSpotifyTrackCache(ctx, id)
+	if err != nil {
+		// Any lookup failure (including "not found") is treated as a cache
+		// miss; the caller falls back to the Spotify API.
+		return nil, false
+	}
+
+	if time.Now().After(row.ExpiresAt) {
+		return nil, false
+	}
+
+	var track Track
+	if err := json.Unmarshal(row.Data, &track); err != nil {
+		return nil, false
+	}
+	return &track, true
+}
+
+// Set stores a resolved track, replacing any existing entry
+func (c *databaseTrackCache) Set(id string, track Track) {
+	data, err := json.Marshal(track)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = c.db.Queries.UpsertSpotifyTrackCache(ctx, database.UpsertSpotifyTrackCacheParams{
+		SpotifyID: id,
+		Data:      data,
+		ExpiresAt: time.Now().Add(trackCacheTTL),
+	})
+}
@@ -0,0 +1,71 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// CallbackServer serves the OAuth2 redirect URI /spotify connect sends
+// users to, completing the Authorization Code exchange via
+// Service.HandleCallback. Start it on cfg.SpotifyOAuthAddr, reachable at
+// cfg.SpotifyRedirectURL.
+type CallbackServer struct {
+	server *http.Server
+	logger *logger.Logger
+}
+
+// NewCallbackServer creates a callback server for service and starts
+// serving on addr (e.g. ":8181") in the background. Call Close to shut it
+// down.
+func NewCallbackServer(addr string, service *Service, log *logger.Logger) (*CallbackServer, error) {
+	s := &CallbackServer{logger: log}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", s.handleCallback(service))
+	s.server = &http.Server{Addr: addr, Handler: mux}
+
+	listenErr := make(chan error, 1)
+	go func() {
+		log.WithField("addr", addr).Info("Serving Spotify OAuth callback")
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			listenErr <- err
+		}
+	}()
+
+	select {
+	case err := <-listenErr:
+		return nil, fmt.Errorf("failed to start spotify callback server: %w", err)
+	case <-time.After(100 * time.Millisecond):
+		return s, nil
+	}
+}
+
+func (s *CallbackServer) handleCallback(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := service.HandleCallback(r.Context(), r)
+		if err != nil {
+			s.logger.WithError(err).Warn("Spotify OAuth callback failed")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Spotify authorization failed: %v. Go back to Discord and try /spotify connect again.", err)
+			return
+		}
+
+		s.logger.WithField("user", userID).Info("Spotify account linked")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "Spotify account linked! You can close this tab and go back to Discord.")
+	}
+}
+
+// Close shuts down the callback HTTP server
+func (s *CallbackServer) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,198 @@
+package spotify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"golang.org/x/oauth2"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
+)
+
+// OAuthScopes are requested for every /spotify connect: enough to create and
+// populate a playlist on the user's own account, plus read their profile ID
+// to create it under.
+var OAuthScopes = []string{
+	spotifyauth.ScopePlaylistModifyPublic,
+	spotifyauth.ScopePlaylistModifyPrivate,
+	spotifyauth.ScopeUserReadPrivate,
+}
+
+// pendingStateTTL bounds how long a /spotify connect link stays valid, so an
+// old callback URL can't be replayed to link tokens to the wrong user.
+const pendingStateTTL = 10 * time.Minute
+
+// pendingAuth tracks which Discord user a still-unredeemed OAuth state token
+// belongs to.
+type pendingAuth struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// EnableUserAuth equips s with the OAuth2 Authorization Code flow behind
+// /spotify connect and /spotify export, on top of the client-credentials
+// client newService already set up for read-only lookups. A Service this
+// hasn't been called on still works for everything except those two
+// commands.
+func (s *Service) EnableUserAuth(clientID, clientSecret, redirectURL string, credRepo repositories.SpotifyCredentialRepositoryInterface) {
+	s.oauthAuth = spotifyauth.New(
+		spotifyauth.WithClientID(clientID),
+		spotifyauth.WithClientSecret(clientSecret),
+		spotifyauth.WithRedirectURL(redirectURL),
+		spotifyauth.WithScopes(OAuthScopes...),
+	)
+	s.credRepo = credRepo
+	s.pending = make(map[string]pendingAuth)
+}
+
+// UserAuthEnabled reports whether EnableUserAuth has been called, so command
+// handlers can tell a missing SPOTIFY_REDIRECT_URL apart from any other
+// error.
+func (s *Service) UserAuthEnabled() bool {
+	return s.oauthAuth != nil
+}
+
+// GenerateConnectURL returns a one-time Spotify authorization URL for
+// userID to open; completing it hands the resulting tokens to HandleCallback,
+// keyed by the state token embedded in the URL.
+func (s *Service) GenerateConnectURL(userID string) (string, error) {
+	if s.oauthAuth == nil {
+		return "", fmt.Errorf("spotify account linking is not configured on this bot")
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return "", err
+	}
+
+	s.pendingMu.Lock()
+	s.pending[state] = pendingAuth{userID: userID, expiresAt: time.Now().Add(pendingStateTTL)}
+	s.pendingMu.Unlock()
+
+	return s.oauthAuth.AuthURL(state, spotifyauth.ShowDialog), nil
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate oauth state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HandleCallback completes the Authorization Code exchange for an incoming
+// callback request and saves the resulting tokens under whichever Discord
+// user GenerateConnectURL issued its state token for. Intended to be called
+// from callbackServer's HTTP handler.
+func (s *Service) HandleCallback(ctx context.Context, r *http.Request) (userID string, err error) {
+	if s.oauthAuth == nil {
+		return "", fmt.Errorf("spotify account linking is not configured on this bot")
+	}
+
+	state := r.URL.Query().Get("state")
+
+	s.pendingMu.Lock()
+	pending, ok := s.pending[state]
+	delete(s.pending, state)
+	s.pendingMu.Unlock()
+
+	if !ok || time.Now().After(pending.expiresAt) {
+		return "", fmt.Errorf("this authorization link has expired or was already used - run /spotify connect again")
+	}
+
+	token, err := s.oauthAuth.Token(ctx, state, r)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete spotify authorization: %w", err)
+	}
+
+	cred := entities.NewSpotifyCredential(pending.userID, token.AccessToken, token.RefreshToken, token.Expiry)
+	if err := s.credRepo.Save(cred); err != nil {
+		return "", fmt.Errorf("failed to save spotify credential: %w", err)
+	}
+
+	return pending.userID, nil
+}
+
+// userClient returns a Spotify client authenticated as userID, refreshing
+// (and re-persisting) an expired access token first.
+func (s *Service) userClient(ctx context.Context, userID string) (*spotify.Client, error) {
+	if s.oauthAuth == nil || s.credRepo == nil {
+		return nil, fmt.Errorf("spotify account linking is not configured on this bot")
+	}
+
+	cred, err := s.credRepo.Get(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load your spotify credential: %w", err)
+	}
+	if cred == nil {
+		return nil, fmt.Errorf("you haven't connected your Spotify account yet - run /spotify connect")
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  cred.AccessToken,
+		RefreshToken: cred.RefreshToken,
+		Expiry:       cred.ExpiresAt,
+	}
+
+	refreshed, err := s.oauthAuth.RefreshToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh your spotify token, try /spotify connect again: %w", err)
+	}
+	if refreshed.AccessToken != token.AccessToken {
+		cred.AccessToken = refreshed.AccessToken
+		if refreshed.RefreshToken != "" {
+			cred.RefreshToken = refreshed.RefreshToken
+		}
+		cred.ExpiresAt = refreshed.Expiry
+		if err := s.credRepo.Save(cred); err != nil {
+			s.logger.WithError(err).WithField("user", userID).Warn("Failed to persist refreshed Spotify token")
+		}
+	}
+
+	return spotify.New(s.oauthAuth.Client(ctx, refreshed)), nil
+}
+
+// CreatePlaylistFromTracks creates a new private playlist named name on
+// userID's Spotify account containing trackIDs, in order, and returns its
+// spotify.com URL. userID must have already linked their account via
+// /spotify connect.
+func (s *Service) CreatePlaylistFromTracks(ctx context.Context, userID, name string, trackIDs []string) (string, error) {
+	client, err := s.userClient(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	me, err := client.CurrentUser(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get your spotify profile: %w", err)
+	}
+
+	playlist, err := client.CreatePlaylistForUser(ctx, me.ID, name, "Exported from Discord queue", false, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to create spotify playlist: %w", err)
+	}
+
+	ids := make([]spotify.ID, len(trackIDs))
+	for i, id := range trackIDs {
+		ids[i] = spotify.ID(id)
+	}
+
+	for start := 0; start < len(ids); start += playlistPageSize {
+		end := start + playlistPageSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if _, err := client.AddTracksToPlaylist(ctx, playlist.ID, ids[start:end]...); err != nil {
+			return "", fmt.Errorf("failed to add tracks to spotify playlist: %w", err)
+		}
+	}
+
+	return playlist.ExternalURLs["spotify"], nil
+}
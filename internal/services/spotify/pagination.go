@@ -0,0 +1,202 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// PlaylistFetchWorkers bounds how many pages GetPlaylistTracksAsync/
+// GetAlbumTracksAsync fetch concurrently once the first page reveals the
+// total track count.
+const PlaylistFetchWorkers = 4
+
+// maxPageRetries caps how many times a single page retries after a 429
+// before giving up and returning the error to the caller.
+const maxPageRetries = 5
+
+// baseBackoff and maxBackoff bound the exponential backoff between 429
+// retries: the Spotify client already honors the Retry-After header for a
+// single in-flight request, but gives workers no way to coordinate, so a
+// burst of concurrent pages hitting the rate limit at once could otherwise
+// all wait on the server's say-so with no ceiling. Capping it here (with
+// jitter, so PlaylistFetchWorkers workers don't all retry on the same tick)
+// bounds how long a stalled page can block the rest of the pool.
+const (
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 30 * time.Second
+)
+
+// PlaylistTracksJob streams a playlist's or album's tracks as each page
+// finishes fetching, mirroring youtube.PlaylistJob so a caller can start
+// queuing the first tracks of a large (1000+) playlist instead of blocking
+// until every page has loaded.
+type PlaylistTracksJob struct {
+	// Total is the track count the first page reported
+	Total int
+
+	// Results delivers each fetched track and is closed once every page
+	// has been attempted (or ctx was cancelled)
+	Results chan Track
+
+	loaded int32 // atomic count of pages attempted so far, success or not
+	cancel context.CancelFunc
+}
+
+// Loaded returns how many pages have been attempted so far, success or
+// not - enough for a "Loaded 400/1200…" progress display.
+func (j *PlaylistTracksJob) Loaded() int {
+	return int(atomic.LoadInt32(&j.loaded))
+}
+
+// TotalCount returns Total. It exists alongside the Total field so
+// *PlaylistTracksJob satisfies the same job-tracking interface as
+// youtube.PlaylistJob (see commands.playlistLoadJob).
+func (j *PlaylistTracksJob) TotalCount() int {
+	return j.Total
+}
+
+// Cancel stops any pages that haven't started fetching yet and aborts those
+// already in flight. Results still closes normally once in-flight fetches
+// return.
+func (j *PlaylistTracksJob) Cancel() {
+	j.cancel()
+}
+
+// pageFetcher fetches one page at offset, returning its tracks and the
+// total track count the API reported.
+type pageFetcher func(ctx context.Context, offset int) (tracks []Track, total int, err error)
+
+// paginateTracks fetches the first page synchronously to learn the total
+// track count, then - if more remain - fans the rest out across
+// PlaylistFetchWorkers workers pulling by offset, streaming every page's
+// tracks onto the returned job's Results as they complete.
+func (s *Service) paginateTracks(ctx context.Context, fetch pageFetcher) (*PlaylistTracksJob, error) {
+	jobCtx, cancel := context.WithCancel(ctx)
+
+	first, total, err := fetchPageWithBackoff(jobCtx, fetch, 0)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	job := &PlaylistTracksJob{
+		Total:   total,
+		Results: make(chan Track, len(first)),
+		cancel:  cancel,
+	}
+
+	for _, track := range first {
+		job.Results <- track
+	}
+	atomic.AddInt32(&job.loaded, 1)
+
+	offsets := make([]int, 0, total/playlistPageSize)
+	for offset := len(first); offset < total; offset += playlistPageSize {
+		offsets = append(offsets, offset)
+	}
+
+	if len(offsets) == 0 {
+		close(job.Results)
+		return job, nil
+	}
+
+	workers := PlaylistFetchWorkers
+	if workers > len(offsets) {
+		workers = len(offsets)
+	}
+
+	offsetCh := make(chan int)
+	resultCh := make(chan []Track, len(offsets))
+	done := make(chan struct{})
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for offset := range offsetCh {
+				tracks, _, err := fetchPageWithBackoff(jobCtx, fetch, offset)
+				atomic.AddInt32(&job.loaded, 1)
+				if err != nil {
+					s.logger.WithError(err).WithField("offset", offset).Warn("Failed to fetch Spotify page")
+					continue
+				}
+				resultCh <- tracks
+			}
+		}()
+	}
+
+	go func() {
+		defer close(done)
+		remaining := len(offsets)
+		for remaining > 0 {
+			select {
+			case tracks := <-resultCh:
+				for _, track := range tracks {
+					job.Results <- track
+				}
+				remaining--
+			case <-jobCtx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for _, offset := range offsets {
+			select {
+			case offsetCh <- offset:
+			case <-jobCtx.Done():
+				close(offsetCh)
+				return
+			}
+		}
+		close(offsetCh)
+	}()
+
+	go func() {
+		<-done
+		close(job.Results)
+	}()
+
+	return job, nil
+}
+
+// fetchPageWithBackoff calls fetch, retrying on HTTP 429 with a capped,
+// jittered exponential backoff up to maxPageRetries times. Returns
+// immediately (without retrying) on any other error, or if ctx is cancelled
+// while waiting.
+func fetchPageWithBackoff(ctx context.Context, fetch pageFetcher, offset int) ([]Track, int, error) {
+	for attempt := 0; ; attempt++ {
+		tracks, total, err := fetch(ctx, offset)
+		if err == nil {
+			return tracks, total, nil
+		}
+
+		var apiErr spotify.Error
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests || attempt >= maxPageRetries {
+			return nil, 0, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(backoffWithJitter(attempt)):
+		}
+	}
+}
+
+// backoffWithJitter returns an exponential backoff for the given retry
+// attempt (0-indexed), capped at maxBackoff and jittered by up to half its
+// value so concurrent workers retrying the same failure don't all wake up
+// on the same tick.
+func backoffWithJitter(attempt int) time.Duration {
+	d := baseBackoff << attempt
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
@@ -1,44 +1,66 @@
 package spotify
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"regexp"
 	"strings"
-	"time"
+	"sync"
 
+	"github.com/zmb3/spotify/v2"
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"golang.org/x/oauth2"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/database"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/repositories"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
+// playlistPageSize is how many items are requested per page when paginating
+// through a playlist or album
+const playlistPageSize = 100
+
+// defaultTopTracksMarket is the market passed to GetArtistsTopTracks when
+// the caller doesn't have a more specific one (e.g. from the user's locale)
+const defaultTopTracksMarket = "US"
+
 var (
 	// Regex patterns for Spotify URLs
 	trackRegex    = regexp.MustCompile(`spotify\.com/track/([a-zA-Z0-9]+)`)
 	playlistRegex = regexp.MustCompile(`spotify\.com/playlist/([a-zA-Z0-9]+)`)
 	albumRegex    = regexp.MustCompile(`spotify\.com/album/([a-zA-Z0-9]+)`)
+	artistRegex   = regexp.MustCompile(`spotify\.com/artist/([a-zA-Z0-9]+)`)
+	showRegex     = regexp.MustCompile(`spotify\.com/show/([a-zA-Z0-9]+)`)
+	episodeRegex  = regexp.MustCompile(`spotify\.com/episode/([a-zA-Z0-9]+)`)
 )
 
-// Service handles Spotify API operations
+// Service resolves Spotify URLs to track metadata via the Spotify Web API,
+// authenticating via a TokenProvider - the client-credentials grant when a
+// developer app is configured, or AnonymousWebProvider's scraped web-player
+// token as a zero-config fallback otherwise (no user login required either
+// way)
 type Service struct {
-	clientID     string
-	clientSecret string
-	accessToken  string
-	tokenExpiry  time.Time
-	logger       *logger.Logger
-	httpClient   *http.Client
+	client *spotify.Client
+	cache  trackCache
+	logger *logger.Logger
+
+	// oauthAuth, credRepo and pending are only set once EnableUserAuth has
+	// been called; they back /spotify connect and /spotify export. See
+	// oauth.go.
+	oauthAuth *spotifyauth.Authenticator
+	credRepo  repositories.SpotifyCredentialRepositoryInterface
+	pending   map[string]pendingAuth
+	pendingMu sync.Mutex
 }
 
 // Track represents a Spotify track
 type Track struct {
-	ID              string   `json:"id"`
-	Name            string   `json:"name"`
-	Artists         []Artist `json:"artists"`
-	Album           Album    `json:"album"`
-	DurationMs      int      `json:"duration_ms"`
-	ExternalIDs     ExternalIDs `json:"external_ids"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Artists     []Artist    `json:"artists"`
+	Album       Album       `json:"album"`
+	DurationMs  int         `json:"duration_ms"`
+	ExternalIDs ExternalIDs `json:"external_ids"`
 }
 
 // ExternalIDs represents external identifiers for a track
@@ -56,185 +78,278 @@ type Album struct {
 	Name string `json:"name"`
 }
 
-// PlaylistTracksResponse represents Spotify playlist tracks response
-type PlaylistTracksResponse struct {
-	Items []struct {
-		Track Track `json:"track"`
-	} `json:"items"`
-	Next string `json:"next"`
-}
-
-// AlbumTracksResponse represents Spotify album tracks response
-type AlbumTracksResponse struct {
-	Items []Track `json:"items"`
-	Next  string  `json:"next"`
+// NewService creates a new Spotify service with no persistent track cache
+func NewService(clientID, clientSecret string, log *logger.Logger) (*Service, error) {
+	return newService(clientID, clientSecret, noopCache{}, log)
 }
 
-// TokenResponse represents Spotify token response
-type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
+// NewServiceWithDB creates a new Spotify service that caches resolved
+// tracks in Postgres
+func NewServiceWithDB(db *database.DB, clientID, clientSecret string, log *logger.Logger) (*Service, error) {
+	return newService(clientID, clientSecret, newDatabaseTrackCache(db), log)
 }
 
-// NewService creates a new Spotify service
-func NewService(clientID, clientSecret string, log *logger.Logger) (*Service, error) {
+func newService(clientID, clientSecret string, cache trackCache, log *logger.Logger) (*Service, error) {
+	var provider TokenProvider
 	if clientID == "" || clientSecret == "" {
-		return nil, fmt.Errorf("spotify credentials not provided")
+		log.Warn("Spotify client credentials not configured, falling back to the anonymous web player token (lower rate limits apply)")
+		provider = NewAnonymousWebProvider(nil)
+	} else {
+		provider = NewClientCredentialsProvider(clientID, clientSecret)
 	}
 
-	s := &Service{
-		clientID:     clientID,
-		clientSecret: clientSecret,
-		logger:       log,
-		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	ctx := context.Background()
+	token, err := provider.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Spotify access token: %w", err)
 	}
 
-	// Get initial access token
-	if err := s.refreshAccessToken(); err != nil {
-		return nil, fmt.Errorf("failed to get Spotify access token: %w", err)
+	tokenSource := oauth2.ReuseTokenSource(token, tokenProviderSource{ctx: ctx, provider: provider})
+	httpClient := oauth2.NewClient(ctx, tokenSource)
+
+	s := &Service{
+		client: spotify.New(httpClient, spotify.WithRetry(true)),
+		cache:  cache,
+		logger: log,
 	}
 
 	log.Info("Spotify service initialized")
 	return s, nil
 }
 
-// refreshAccessToken gets a new access token from Spotify
-func (s *Service) refreshAccessToken() error {
-	data := url.Values{}
-	data.Set("grant_type", "client_credentials")
+// GetTrack gets track information by ID
+func (s *Service) GetTrack(trackID string) (*Track, error) {
+	if cached, ok := s.cache.Get(trackID); ok {
+		return cached, nil
+	}
 
-	req, err := http.NewRequest("POST", "https://accounts.spotify.com/api/token", strings.NewReader(data.Encode()))
+	full, err := s.client.GetTrack(context.Background(), spotify.ID(trackID))
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to get spotify track: %w", err)
 	}
 
-	auth := base64.StdEncoding.EncodeToString([]byte(s.clientID + ":" + s.clientSecret))
-	req.Header.Set("Authorization", "Basic "+auth)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	track := fromFullTrack(full)
+	s.cache.Set(trackID, track)
+	return &track, nil
+}
 
-	resp, err := s.httpClient.Do(req)
+// GetPlaylistTracks gets all tracks from a playlist, paginating across
+// PlaylistFetchWorkers concurrent workers once the first page reveals the
+// total count. For very large playlists, prefer GetPlaylistTracksAsync so
+// the caller can start queuing songs before every page has loaded.
+func (s *Service) GetPlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
+	job, err := s.GetPlaylistTracksAsync(ctx, playlistID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return drainTracksJob(job), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("spotify auth failed: %s - %s", resp.Status, string(body))
-	}
+// GetPlaylistTracksAsync starts fetching playlistID's tracks and returns
+// immediately with a job streaming them onto job.Results as each page
+// arrives, rather than blocking until the whole playlist has loaded. See
+// PlaylistTracksJob.
+func (s *Service) GetPlaylistTracksAsync(ctx context.Context, playlistID string) (*PlaylistTracksJob, error) {
+	return s.paginateTracks(ctx, func(pageCtx context.Context, offset int) ([]Track, int, error) {
+		page, err := s.client.GetPlaylistItems(pageCtx, spotify.ID(playlistID), spotify.Limit(playlistPageSize), spotify.Offset(offset))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get spotify playlist tracks: %w", err)
+		}
+
+		tracks := make([]Track, 0, len(page.Items))
+		for _, item := range page.Items {
+			if item.Track.Track == nil {
+				continue
+			}
+			track := fromFullTrack(item.Track.Track)
+			s.cache.Set(track.ID, track)
+			tracks = append(tracks, track)
+		}
+		return tracks, int(page.Total), nil
+	})
+}
 
-	var tokenResp TokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return err
+// GetAlbumTracks gets all tracks from an album, paginating across
+// PlaylistFetchWorkers concurrent workers once the first page reveals the
+// total count. For very large albums, prefer GetAlbumTracksAsync so the
+// caller can start queuing songs before every page has loaded.
+func (s *Service) GetAlbumTracks(ctx context.Context, albumID string) ([]Track, error) {
+	job, err := s.GetAlbumTracksAsync(ctx, albumID)
+	if err != nil {
+		return nil, err
 	}
+	return drainTracksJob(job), nil
+}
 
-	s.accessToken = tokenResp.AccessToken
-	s.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+// GetAlbumTracksAsync starts fetching albumID's tracks and returns
+// immediately with a job streaming them onto job.Results as each page
+// arrives, rather than blocking until the whole album has loaded. See
+// PlaylistTracksJob.
+func (s *Service) GetAlbumTracksAsync(ctx context.Context, albumID string) (*PlaylistTracksJob, error) {
+	return s.paginateTracks(ctx, func(pageCtx context.Context, offset int) ([]Track, int, error) {
+		page, err := s.client.GetAlbumTracks(pageCtx, spotify.ID(albumID), spotify.Limit(playlistPageSize), spotify.Offset(offset))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get spotify album tracks: %w", err)
+		}
 
-	s.logger.Debug("Spotify access token refreshed")
-	return nil
+		tracks := make([]Track, 0, len(page.Tracks))
+		for _, simple := range page.Tracks {
+			track := fromSimpleTrack(simple)
+			s.cache.Set(track.ID, track)
+			tracks = append(tracks, track)
+		}
+		return tracks, int(page.Total), nil
+	})
 }
 
-// ensureValidToken ensures we have a valid access token
-func (s *Service) ensureValidToken() error {
-	if time.Now().After(s.tokenExpiry.Add(-5 * time.Minute)) {
-		return s.refreshAccessToken()
+// drainTracksJob collects every track a PlaylistTracksJob streams, blocking
+// until it's done - for callers that just want the full list and don't need
+// to start acting on early results.
+func drainTracksJob(job *PlaylistTracksJob) []Track {
+	tracks := make([]Track, 0, job.Total)
+	for track := range job.Results {
+		tracks = append(tracks, track)
 	}
-	return nil
+	return tracks
 }
 
-// makeRequest makes an authenticated request to Spotify API
-func (s *Service) makeRequest(endpoint string) ([]byte, error) {
-	if err := s.ensureValidToken(); err != nil {
-		return nil, err
+// GetArtistTopTracks gets an artist's top tracks in the given market (an
+// ISO 3166-1 alpha-2 country code; pass "" to use defaultTopTracksMarket).
+// The Spotify API caps this at 10 tracks
+func (s *Service) GetArtistTopTracks(artistID, market string) ([]Track, error) {
+	if market == "" {
+		market = defaultTopTracksMarket
 	}
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	full, err := s.client.GetArtistsTopTracks(context.Background(), spotify.ID(artistID), market)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get spotify artist top tracks: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.accessToken)
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	tracks := make([]Track, 0, len(full))
+	for _, t := range full {
+		track := fromFullTrack(&t)
+		s.cache.Set(track.ID, track)
+		tracks = append(tracks, track)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("spotify API error: %s - %s", resp.Status, string(body))
-	}
-
-	return io.ReadAll(resp.Body)
+	return tracks, nil
 }
 
-// GetTrack gets track information by ID
-func (s *Service) GetTrack(trackID string) (*Track, error) {
-	endpoint := fmt.Sprintf("https://api.spotify.com/v1/tracks/%s", trackID)
+// GetShowEpisodes gets all episodes of a podcast show. Episodes have no
+// ISRC, so callers should resolve them by title+publisher search rather
+// than the usual ISRC-first strategy
+func (s *Service) GetShowEpisodes(showID string) ([]Track, error) {
+	ctx := context.Background()
 
-	body, err := s.makeRequest(endpoint)
+	show, err := s.client.GetShow(ctx, spotify.ID(showID))
 	if err != nil {
-		return nil, err
-	}
-
-	var track Track
-	if err := json.Unmarshal(body, &track); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get spotify show: %w", err)
 	}
 
-	return &track, nil
-}
-
-// GetPlaylistTracks gets all tracks from a playlist
-func (s *Service) GetPlaylistTracks(playlistID string) ([]Track, error) {
-	var allTracks []Track
-	endpoint := fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/tracks", playlistID)
-
-	for endpoint != "" {
-		body, err := s.makeRequest(endpoint)
+	var episodes []Track
+	offset := 0
+	for {
+		page, err := s.client.GetShowEpisodes(ctx, showID, spotify.Limit(playlistPageSize), spotify.Offset(offset))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to get spotify show episodes: %w", err)
 		}
 
-		var resp PlaylistTracksResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, err
+		for _, ep := range page.Episodes {
+			episodes = append(episodes, fromEpisode(ep, show.Publisher))
 		}
 
-		for _, item := range resp.Items {
-			allTracks = append(allTracks, item.Track)
+		offset += len(page.Episodes)
+		if offset >= int(page.Total) || len(page.Episodes) == 0 {
+			break
 		}
+	}
 
-		endpoint = resp.Next
+	return episodes, nil
+}
+
+// GetEpisode gets a single podcast episode by ID. Like show episodes, it
+// has no ISRC and should be resolved by title+publisher search
+func (s *Service) GetEpisode(episodeID string) (*Track, error) {
+	ep, err := s.client.GetEpisode(context.Background(), episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spotify episode: %w", err)
 	}
 
-	return allTracks, nil
+	track := fromEpisode(*ep, ep.Show.Publisher)
+	return &track, nil
 }
 
-// GetAlbumTracks gets all tracks from an album
-func (s *Service) GetAlbumTracks(albumID string) ([]Track, error) {
-	var allTracks []Track
-	endpoint := fmt.Sprintf("https://api.spotify.com/v1/albums/%s/tracks", albumID)
+// fromEpisode converts a Spotify podcast episode into our Track type,
+// treating the show's publisher as the "artist" so ToSearchQuery produces
+// a "Publisher - Episode Name" query
+func fromEpisode(ep spotify.EpisodePage, publisher string) Track {
+	return Track{
+		ID:         string(ep.ID),
+		Name:       ep.Name,
+		Artists:    []Artist{{Name: publisher}},
+		Album:      Album{Name: ep.Show.Name},
+		DurationMs: int(ep.Duration_ms),
+	}
+}
 
-	for endpoint != "" {
-		body, err := s.makeRequest(endpoint)
-		if err != nil {
-			return nil, err
-		}
+// SearchTracks searches Spotify for tracks matching query and returns up to
+// limit results, best match first
+func (s *Service) SearchTracks(query string, limit int) ([]Track, error) {
+	if limit <= 0 {
+		limit = 10
+	}
 
-		var resp AlbumTracksResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, err
-		}
+	results, err := s.client.Search(context.Background(), query, spotify.SearchTypeTrack, spotify.Limit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search spotify tracks: %w", err)
+	}
+
+	if results.Tracks == nil {
+		return nil, nil
+	}
+
+	tracks := make([]Track, 0, len(results.Tracks.Tracks))
+	for _, t := range results.Tracks.Tracks {
+		track := fromFullTrack(&t)
+		s.cache.Set(track.ID, track)
+		tracks = append(tracks, track)
+	}
 
-		allTracks = append(allTracks, resp.Items...)
-		endpoint = resp.Next
+	return tracks, nil
+}
+
+// fromFullTrack converts a Spotify API track (as returned for a single
+// track lookup or a playlist item) into our Track type
+func fromFullTrack(t *spotify.FullTrack) Track {
+	track := Track{
+		ID:         string(t.ID),
+		Name:       t.Name,
+		Album:      Album{Name: t.Album.Name},
+		DurationMs: int(t.Duration),
+	}
+	for _, artist := range t.Artists {
+		track.Artists = append(track.Artists, Artist{Name: artist.Name})
 	}
+	track.ExternalIDs.ISRC = t.ExternalIDs["isrc"]
+	return track
+}
 
-	return allTracks, nil
+// fromSimpleTrack converts a Spotify API track (as returned for album
+// tracks, which don't include full external ID / popularity data) into our
+// Track type
+func fromSimpleTrack(t spotify.SimpleTrack) Track {
+	track := Track{
+		ID:         string(t.ID),
+		Name:       t.Name,
+		Album:      Album{Name: t.Album.Name},
+		DurationMs: int(t.Duration),
+	}
+	for _, artist := range t.Artists {
+		track.Artists = append(track.Artists, Artist{Name: artist.Name})
+	}
+	track.ExternalIDs.ISRC = t.ExternalIDs.ISRC
+	return track
 }
 
 // ToSearchQuery converts a track to a YouTube search query
@@ -282,5 +397,14 @@ func ParseSpotifyURL(urlStr string) (urlType, id string, err error) {
 	if matches := albumRegex.FindStringSubmatch(urlStr); len(matches) > 1 {
 		return "album", matches[1], nil
 	}
+	if matches := artistRegex.FindStringSubmatch(urlStr); len(matches) > 1 {
+		return "artist", matches[1], nil
+	}
+	if matches := showRegex.FindStringSubmatch(urlStr); len(matches) > 1 {
+		return "show", matches[1], nil
+	}
+	if matches := episodeRegex.FindStringSubmatch(urlStr); len(matches) > 1 {
+		return "episode", matches[1], nil
+	}
 	return "", "", fmt.Errorf("invalid Spotify URL")
 }
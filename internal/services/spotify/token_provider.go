@@ -0,0 +1,157 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+)
+
+// TokenProvider supplies the oauth2 token Service authenticates its Spotify
+// Web API requests with. newService picks ClientCredentialsProvider when a
+// developer app's ID/secret are configured, or falls back to
+// AnonymousWebProvider so self-hosters without one still get Spotify
+// support, at the cost of the web player's lower rate limits.
+type TokenProvider interface {
+	Token(ctx context.Context) (*oauth2.Token, error)
+}
+
+// ClientCredentialsProvider authenticates via the client-credentials grant -
+// the existing behavior, requiring a registered developer app.
+type ClientCredentialsProvider struct {
+	config *clientcredentials.Config
+}
+
+// NewClientCredentialsProvider creates a ClientCredentialsProvider for the
+// given developer app credentials.
+func NewClientCredentialsProvider(clientID, clientSecret string) *ClientCredentialsProvider {
+	return &ClientCredentialsProvider{config: &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     spotifyauth.TokenURL,
+	}}
+}
+
+// Token implements TokenProvider.
+func (p *ClientCredentialsProvider) Token(ctx context.Context) (*oauth2.Token, error) {
+	return p.config.Token(ctx)
+}
+
+// anonymousTokenRefreshSkew renews the scraped web-player token this long
+// before its reported expiry, so a request doesn't race a token that's
+// about to lapse.
+const anonymousTokenRefreshSkew = 30 * time.Second
+
+// anonymousTokenPattern matches the JSON blob open.spotify.com embeds in its
+// landing page for the web player's own anonymous session.
+var anonymousTokenPattern = regexp.MustCompile(`\{"accessToken":"[^"]+","accessTokenExpirationTimestampMs":\d+[^}]*\}`)
+
+type anonymousTokenResponse struct {
+	AccessToken                      string `json:"accessToken"`
+	AccessTokenExpirationTimestampMs int64  `json:"accessTokenExpirationTimestampMs"`
+}
+
+// AnonymousWebProvider scrapes the access token open.spotify.com issues its
+// own web player - the same zero-config credential the page itself uses -
+// for self-hosters who haven't registered a developer app. Its rate limits
+// are lower than a client-credentials grant's. Safe for concurrent use.
+type AnonymousWebProvider struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// NewAnonymousWebProvider creates an AnonymousWebProvider that scrapes via
+// client, or http.DefaultClient's settings with a 15s timeout if client is
+// nil.
+func NewAnonymousWebProvider(client *http.Client) *AnonymousWebProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &AnonymousWebProvider{client: client}
+}
+
+// Token returns the cached anonymous token if it isn't within
+// anonymousTokenRefreshSkew of expiring, otherwise scrapes a fresh one.
+func (p *AnonymousWebProvider) Token(ctx context.Context) (*oauth2.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != nil && time.Until(p.token.Expiry) > anonymousTokenRefreshSkew {
+		return p.token, nil
+	}
+
+	token, err := p.scrape(ctx)
+	if err != nil {
+		if p.token != nil {
+			// Stale beats none - keep serving the old token until the next call
+			return p.token, nil
+		}
+		return nil, err
+	}
+
+	p.token = token
+	return p.token, nil
+}
+
+// scrape fetches open.spotify.com and extracts the embedded JSON blob
+// carrying the web player's anonymous access token.
+func (p *AnonymousWebProvider) scrape(ctx context.Context) (*oauth2.Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://open.spotify.com", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open.spotify.com: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching open.spotify.com", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	match := anonymousTokenPattern.Find(body)
+	if match == nil {
+		return nil, fmt.Errorf("could not find access token in open.spotify.com response")
+	}
+
+	var parsed anonymousTokenResponse
+	if err := json.Unmarshal(match, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse scraped access token: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: parsed.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      time.UnixMilli(parsed.AccessTokenExpirationTimestampMs),
+	}, nil
+}
+
+// tokenProviderSource adapts a TokenProvider to oauth2.TokenSource so it can
+// back an auto-refreshing http.Client via oauth2.NewClient.
+type tokenProviderSource struct {
+	ctx      context.Context
+	provider TokenProvider
+}
+
+// Token implements oauth2.TokenSource.
+func (s tokenProviderSource) Token() (*oauth2.Token, error) {
+	return s.provider.Token(s.ctx)
+}
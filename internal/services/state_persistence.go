@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/entities"
+)
+
+// ScheduleStateSave debounces a snapshot-and-save of guildID's playback state
+// to stateRepo, coalescing bursts of activity (e.g. a playlist import adding
+// dozens of songs, or AudioPlayer's periodic position reports) into a single
+// write stateSaveDebounce after the last call. A nil stateRepo makes this a
+// no-op, so callers don't need to check whether persistence is enabled.
+func (s *PlaybackService) ScheduleStateSave(guildID string) {
+	if s.stateRepo == nil {
+		return
+	}
+
+	s.saveTimerMu.Lock()
+	defer s.saveTimerMu.Unlock()
+
+	if timer, exists := s.saveTimers[guildID]; exists {
+		timer.Stop()
+	}
+	s.saveTimers[guildID] = time.AfterFunc(stateSaveDebounce, func() {
+		s.saveState(guildID)
+	})
+}
+
+// saveState snapshots guildID's current tracklist, player, and channel state
+// and writes it to stateRepo. A guild with no playback state yet, or no
+// active voice connection, has nothing worth persisting.
+func (s *PlaybackService) saveState(guildID string) {
+	state := s.getState(guildID)
+	if state == nil {
+		return
+	}
+
+	voiceChannelID := s.audioService.GetVoiceChannelID(guildID)
+	if voiceChannelID == "" {
+		return
+	}
+
+	tracklist := state.tracklist
+	current, _ := tracklist.Position()
+
+	songs := tracklist.GetAllSongs()
+	persisted := make([]entities.PersistedSong, len(songs))
+	for i, song := range songs {
+		persisted[i] = song.ToPersisted()
+	}
+
+	snapshot := &entities.GuildState{
+		GuildID:        guildID,
+		VoiceChannelID: voiceChannelID,
+		Songs:          persisted,
+		CurrentIndex:   current - 1,
+		History:        tracklist.HistoryIDs(),
+		RepeatMode:     tracklist.GetRepeatMode(),
+		QueueMode:      tracklist.QueueMode(),
+		Volume:         30,
+		UpdatedAt:      time.Now(),
+	}
+
+	state.nowPlayingMu.Lock()
+	snapshot.TextChannelID = state.nowPlayingChannelID
+	state.nowPlayingMu.Unlock()
+
+	if player := s.audioService.GetPlayer(guildID); player != nil {
+		snapshot.Volume = player.GetVolume()
+		snapshot.Position = player.Position()
+	}
+
+	if err := s.stateRepo.Save(snapshot); err != nil {
+		s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to save playback state")
+	}
+}
+
+// DeleteState drops any saved snapshot for guildID, e.g. once its session
+// ends on purpose and shouldn't be resumed on the next boot.
+func (s *PlaybackService) DeleteState(guildID string) {
+	if s.stateRepo == nil {
+		return
+	}
+
+	s.saveTimerMu.Lock()
+	if timer, exists := s.saveTimers[guildID]; exists {
+		timer.Stop()
+		delete(s.saveTimers, guildID)
+	}
+	s.saveTimerMu.Unlock()
+
+	if err := s.stateRepo.Delete(guildID); err != nil {
+		s.logger.WithError(err).WithField("guild", guildID).Warn("Failed to delete saved playback state")
+	}
+}
+
+// RestoreAll reconnects every guild with a saved playback snapshot: it
+// rejoins the saved voice channel, rebuilds the queue, resubmits the saved
+// songs for (re-)processing since their stream URLs have likely expired
+// since the last run, and resumes the current song at its last known
+// position once it's ready. Intended to be called once, after the Discord
+// session is ready.
+func (s *PlaybackService) RestoreAll(ctx context.Context) {
+	if s.stateRepo == nil {
+		return
+	}
+
+	guildIDs, err := s.stateRepo.ListGuildIDs()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to list saved playback states")
+		return
+	}
+
+	for _, guildID := range guildIDs {
+		s.restoreGuild(ctx, guildID)
+	}
+}
+
+// restoreGuild reconnects and resumes a single guild from its saved
+// snapshot. Any failure is logged and the snapshot is dropped rather than
+// retried, since a guild the bot can no longer join won't become joinable on
+// the next restart either.
+func (s *PlaybackService) restoreGuild(ctx context.Context, guildID string) {
+	log := s.logger.FromContext(ctx).WithField("guild", guildID)
+
+	saved, err := s.stateRepo.Load(guildID)
+	if err != nil {
+		log.WithError(err).Warn("Failed to load saved playback state")
+		return
+	}
+	if saved == nil || saved.VoiceChannelID == "" || len(saved.Songs) == 0 {
+		return
+	}
+
+	if err := s.audioService.ConnectToChannel(guildID, saved.VoiceChannelID); err != nil {
+		log.WithError(err).Warn("Failed to rejoin voice channel, dropping saved state")
+		s.DeleteState(guildID)
+		return
+	}
+
+	state := s.getOrCreateState(guildID)
+	state.mu.Lock()
+
+	if saved.QueueMode != "" {
+		if err := state.tracklist.SetQueueMode(saved.QueueMode); err != nil {
+			log.WithError(err).Debug("Failed to restore queue mode")
+		}
+	}
+	if saved.RepeatMode != "" {
+		state.tracklist.SetRepeatMode(saved.RepeatMode)
+	}
+
+	byID := make(map[string]*entities.Song, len(saved.Songs))
+	for _, ref := range saved.Songs {
+		song := entities.RestoreSong(ref, guildID)
+		byID[song.ID] = song
+
+		state.tracklist.AddSong(song)
+		priority := 0
+		if err := s.processingService.Submit(ctx, song, priority); err != nil {
+			log.WithError(err).WithField("song_id", song.ID).Warn("Failed to resubmit song for processing")
+		}
+	}
+	if saved.CurrentIndex > 0 {
+		state.tracklist.SkipToPosition(saved.CurrentIndex + 1)
+	}
+	state.pendingResumeOffset = saved.Position
+
+	if len(saved.History) > 0 {
+		history := make([]*entities.Song, 0, len(saved.History))
+		for _, id := range saved.History {
+			if song, ok := byID[id]; ok {
+				history = append(history, song)
+			}
+		}
+		state.tracklist.RestoreHistory(history)
+	}
+
+	if saved.TextChannelID != "" {
+		state.nowPlayingMu.Lock()
+		state.nowPlayingChannelID = saved.TextChannelID
+		state.nowPlayingMu.Unlock()
+	}
+
+	if !state.isPlaying {
+		s.startPlaybackLoopLocked(ctx, state)
+	}
+	state.mu.Unlock()
+
+	if player := s.audioService.GetPlayer(guildID); player != nil {
+		player.SetVolume(saved.Volume)
+	}
+
+	log.WithField("songs", len(saved.Songs)).Info("♻️ Resumed playback from saved state")
+}
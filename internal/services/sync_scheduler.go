@@ -0,0 +1,185 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/cronsched"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/externalplaylist"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// PlaylistSyncScheduler periodically reconciles externally-sourced playlists
+// against their remote source on a cron-style schedule. A playlist's own
+// ExternalInfo.SyncCron overrides the scheduler's default; playlists without
+// one fall back to it. If neither the default nor the playlist override
+// parses, the scheduler falls back to a fixed interval so syncing never
+// silently stops because of a config typo.
+type PlaylistSyncScheduler struct {
+	session     *discordgo.Session
+	playlistSvc *PlaylistService
+	importers   *externalplaylist.Registry
+	logger      *logger.Logger
+
+	defaultSchedule *cronsched.Schedule
+	interval        time.Duration
+	dryRun          bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewPlaylistSyncScheduler creates a scheduler. defaultCron may be empty, in
+// which case every playlist without its own SyncCron override falls back to
+// the fixed interval ticker.
+func NewPlaylistSyncScheduler(session *discordgo.Session, playlistSvc *PlaylistService, importers *externalplaylist.Registry, defaultCron string, interval time.Duration, dryRun bool, log *logger.Logger) *PlaylistSyncScheduler {
+	var defaultSchedule *cronsched.Schedule
+	if defaultCron != "" {
+		schedule, err := cronsched.Parse(defaultCron)
+		if err != nil {
+			log.WithError(err).WithField("cron", defaultCron).Warn("Invalid default playlist sync cron, falling back to fixed interval")
+		} else {
+			defaultSchedule = schedule
+		}
+	}
+
+	return &PlaylistSyncScheduler{
+		session:         session,
+		playlistSvc:     playlistSvc,
+		importers:       importers,
+		logger:          log,
+		defaultSchedule: defaultSchedule,
+		interval:        interval,
+		dryRun:          dryRun,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler loop in a background goroutine, ticking once a
+// minute to check cron schedules at minute granularity, falling back to the
+// configured fixed interval for guilds/playlists with no cron schedule
+func (s *PlaylistSyncScheduler) Start() {
+	s.wg.Add(1)
+	go s.run()
+
+	s.logger.WithFields(map[string]interface{}{
+		"default_cron": s.defaultSchedule,
+		"interval":     s.interval,
+		"dry_run":      s.dryRun,
+	}).Info("Playlist sync scheduler started")
+}
+
+// Stop signals the scheduler loop to exit and waits for it to finish
+func (s *PlaylistSyncScheduler) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+func (s *PlaylistSyncScheduler) run() {
+	defer s.wg.Done()
+
+	tickerInterval := time.Minute
+	if s.defaultSchedule == nil && s.interval < tickerInterval {
+		tickerInterval = s.interval
+	}
+	ticker := time.NewTimer(tickerInterval)
+	defer ticker.Stop()
+
+	lastFixedSync := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			s.syncDue(now, lastFixedSync)
+			if s.defaultSchedule == nil || now.Sub(lastFixedSync) >= s.interval {
+				lastFixedSync = now
+			}
+			ticker.Reset(tickerInterval)
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// syncDue reconciles every externally-sourced playlist whose schedule (its
+// own SyncCron override, the scheduler's default cron, or the fixed
+// interval, in that priority) is due as of now
+func (s *PlaylistSyncScheduler) syncDue(now, lastFixedSync time.Time) {
+	fixedDue := now.Sub(lastFixedSync) >= s.interval
+
+	for _, guild := range s.session.State.Guilds {
+		names, err := s.playlistSvc.ListPlaylistsForGuild(guild.ID)
+		if err != nil {
+			continue
+		}
+
+		for _, name := range names {
+			info, err := s.playlistSvc.GetExternalInfoForGuild(guild.ID, name)
+			if err != nil || info == nil {
+				continue
+			}
+
+			if !s.isDue(info.SyncCron, now, fixedDue) {
+				continue
+			}
+
+			importer, err := s.importers.For(info.URL)
+			if err != nil {
+				s.logger.WithError(err).WithField("playlist", name).Warn("No importer available for playlist's external source")
+				continue
+			}
+
+			s.syncOne(guild.ID, name, importer)
+		}
+	}
+}
+
+// isDue reports whether a playlist should sync now, given its own cron
+// override (if any), the scheduler's default cron, and whether the fixed
+// interval fallback has elapsed
+func (s *PlaylistSyncScheduler) isDue(playlistCron string, now time.Time, fixedDue bool) bool {
+	if playlistCron != "" {
+		if schedule, err := cronsched.Parse(playlistCron); err == nil {
+			return schedule.Matches(now)
+		}
+		s.logger.WithField("cron", playlistCron).Warn("Invalid per-playlist sync cron, falling back to scheduler default")
+	}
+
+	if s.defaultSchedule != nil {
+		return s.defaultSchedule.Matches(now)
+	}
+
+	return fixedDue
+}
+
+func (s *PlaylistSyncScheduler) syncOne(guildID, name string, importer externalplaylist.PlaylistImporter) {
+	if s.dryRun {
+		added, removed, err := s.playlistSvc.PreviewSyncExternalForGuild(guildID, name, importer)
+		if err != nil {
+			s.logger.WithError(err).WithField("playlist", name).Warn("Scheduled sync preview failed")
+			return
+		}
+		s.logger.WithFields(map[string]interface{}{
+			"guild":    guildID,
+			"playlist": name,
+			"added":    added,
+			"removed":  removed,
+		}).Info("[dry-run] Scheduled playlist sync would apply changes")
+		return
+	}
+
+	added, removed, err := s.playlistSvc.SyncExternalForGuild(guildID, name, importer)
+	if err != nil {
+		s.logger.WithError(err).WithField("playlist", name).Warn("Scheduled playlist sync failed")
+		return
+	}
+	if added > 0 || removed > 0 {
+		s.logger.WithFields(map[string]interface{}{
+			"guild":    guildID,
+			"playlist": name,
+			"added":    added,
+			"removed":  removed,
+		}).Info("Scheduled playlist sync completed")
+	}
+}
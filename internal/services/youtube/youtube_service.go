@@ -1,14 +1,23 @@
 package youtube
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	cmdexec "github.com/vuongmanhnghia/discord-music-bot/internal/exec"
+
 	"github.com/vuongmanhnghia/discord-music-bot/internal/domain/valueobjects"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/metrics"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/cache"
 	"github.com/vuongmanhnghia/discord-music-bot/internal/utils"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
@@ -20,6 +29,11 @@ var (
 	ErrExtractionFailed = errors.New("failed to extract video information")
 	// ErrInvalidURL is returned when the URL is invalid
 	ErrInvalidURL = errors.New("invalid YouTube URL")
+	// ErrEmptyMetadata is returned when yt-dlp produces JSON but it's
+	// missing fields a playable track needs - an age-restricted,
+	// region-blocked, or deleted video commonly yields this instead of a
+	// hard failure
+	ErrEmptyMetadata = errors.New("video metadata is empty or invalid")
 )
 
 // YouTubeInfo represents extracted YouTube video information
@@ -41,6 +55,20 @@ func (info *YouTubeInfo) IsPlaylist() bool {
 	return info.Type == "playlist"
 }
 
+// validateExtractedInfo rejects a parsed YouTubeInfo that's missing fields a
+// playable track needs, returning ErrEmptyMetadata. Playlists are exempt
+// from the Duration check since a playlist result has no single duration of
+// its own.
+func validateExtractedInfo(info *YouTubeInfo) error {
+	if info.ID == "" || info.Title == "" {
+		return ErrEmptyMetadata
+	}
+	if info.Duration == 0 && !info.IsPlaylist() {
+		return ErrEmptyMetadata
+	}
+	return nil
+}
+
 // Format represents an available stream format
 type Format struct {
 	FormatID   string  `json:"format_id"`
@@ -53,9 +81,45 @@ type Format struct {
 
 // Service handles YouTube operations
 type Service struct {
-	cache     *utils.SmartCache
-	logger    *logger.Logger
-	ytDlpPath string
+	infoCache   *utils.Cache[string, *YouTubeInfo]
+	streamCache *utils.Cache[string, string]
+	songCache   *cache.SongCache
+	logger      *logger.Logger
+	ytDlpPath   string
+
+	// executor runs every yt-dlp invocation that waits for a single
+	// complete output (ExtractInfo, extractPlaylistFlat, search, stream URL
+	// lookups). StreamPlaylist keeps its own exec.Command directly, since it
+	// reads yt-dlp's stdout line by line as the process runs rather than
+	// waiting for it to exit.
+	executor cmdexec.CommandExecutor
+
+	// metrics receives extraction latency/error observations, split by URL
+	// type ("video", "playlist", "search", "stream_url"). Defaults to
+	// metrics.NoopRecorder so call sites never need a nil check.
+	metrics metrics.Recorder
+}
+
+// SetSongCache wires a persistent on-disk audio cache into the service, so
+// GetStreamURL prefers a previously downloaded file over a fresh network
+// stream URL. nil (the default) disables it - GetStreamURL behaves exactly
+// as before.
+func (s *Service) SetSongCache(songCache *cache.SongCache) {
+	s.songCache = songCache
+}
+
+// SetCommandExecutor overrides how the service runs yt-dlp, for injecting a
+// testutils.MockCommandExecutor in unit tests. NewService wires up the real
+// cmdexec.OSExecutor by default.
+func (s *Service) SetCommandExecutor(executor cmdexec.CommandExecutor) {
+	s.executor = executor
+}
+
+// SetMetricsRecorder wires a metrics.Recorder into the service so extraction
+// latency and errors are observed. NewService defaults to
+// metrics.NoopRecorder.
+func (s *Service) SetMetricsRecorder(recorder metrics.Recorder) {
+	s.metrics = recorder
 }
 
 // NewService creates a new YouTube service
@@ -66,75 +130,286 @@ func NewService(log *logger.Logger) (*Service, error) {
 		return nil, fmt.Errorf("%w: please install yt-dlp", ErrYtDlpNotFound)
 	}
 
-	// Create cache with 5-minute TTL for stream URLs (they expire)
-	cache := utils.NewSmartCache(500, 5*time.Minute)
+	// Stream URLs expire, so keep a short TTL; negative results (e.g. a
+	// geo-blocked video) are cached briefly too so a burst of requests for
+	// the same bad URL doesn't re-invoke yt-dlp for each one.
+	infoCache := utils.NewCache[string, *YouTubeInfo](500, 30*time.Minute).WithNegativeTTL(30 * time.Second)
+	streamCache := utils.NewCache[string, string](500, 5*time.Minute).WithNegativeTTL(30 * time.Second)
 
 	log.WithField("ytdlp_path", ytDlpPath).Info("YouTube service initialized")
 
 	return &Service{
-		cache:     cache,
-		logger:    log,
-		ytDlpPath: ytDlpPath,
+		infoCache:   infoCache,
+		streamCache: streamCache,
+		logger:      log,
+		ytDlpPath:   ytDlpPath,
+		executor:    cmdexec.OSExecutor{},
+		metrics:     metrics.NoopRecorder{},
 	}, nil
 }
 
-// ExtractInfo extracts video/playlist information from URL
-func (s *Service) ExtractInfo(url string) (*YouTubeInfo, error) {
-	// Check cache first
-	if cached, ok := s.cache.Get(url); ok {
-		s.logger.Debug("Cache hit for URL")
-		return cached.(*YouTubeInfo), nil
-	}
+// ExtractInfo extracts video/playlist information from URL. ctx carries the
+// originating request's logging fields and bounds the yt-dlp subprocess, so a
+// cancelled request (e.g. the user skipped the song) doesn't leave it running.
+// Concurrent calls for the same URL (e.g. several guilds queuing the same
+// link at once) share a single yt-dlp invocation instead of each starting
+// their own.
+func (s *Service) ExtractInfo(ctx context.Context, url string) (*YouTubeInfo, error) {
+	log := s.logger.FromContext(ctx)
+
+	return s.infoCache.GetOrLoad(url, func() (*YouTubeInfo, error) {
+		log.WithField("url", url).Info("Extracting YouTube info...")
+
+		// Build yt-dlp command
+		args := []string{
+			"--dump-json",
+			"--no-playlist", // Handle playlists separately
+			"--format", "bestaudio/best",
+			"--no-check-certificate",
+			"--geo-bypass",
+			"--no-warnings", // Suppress warnings that break JSON parsing
+			url,
+		}
 
-	s.logger.WithField("url", url).Info("Extracting YouTube info...")
+		start := time.Now()
+		output, _, err := s.executor.RunWithTimeout(ctx, s.ytDlpPath, args...)
+		s.metrics.ObserveExtractionLatency("video", time.Since(start))
+		if err != nil {
+			s.metrics.IncExtractionError("video")
+			log.WithError(err).Error("yt-dlp extraction failed")
+			return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+		}
 
-	// Build yt-dlp command
-	args := []string{
-		"--dump-json",
-		"--no-playlist", // Handle playlists separately
-		"--format", "bestaudio/best",
-		"--no-check-certificate",
-		"--geo-bypass",
-		"--no-warnings", // Suppress warnings that break JSON parsing
-		url,
-	}
+		// Find JSON start (skip any non-JSON output)
+		jsonStart := strings.Index(string(output), "{")
+		if jsonStart == -1 {
+			log.Error("No JSON found in yt-dlp output")
+			return nil, fmt.Errorf("%w: no JSON in output", ErrExtractionFailed)
+		}
+		jsonOutput := output[jsonStart:]
+
+		var info YouTubeInfo
+		if err := json.Unmarshal(jsonOutput, &info); err != nil {
+			log.WithError(err).Error("Failed to parse yt-dlp output")
+			return nil, fmt.Errorf("failed to parse video info: %w", err)
+		}
+
+		if err := validateExtractedInfo(&info); err != nil {
+			log.WithField("url", url).Warn("yt-dlp returned empty/invalid metadata")
+			return nil, err
+		}
+
+		log.WithFields(map[string]interface{}{
+			"title":    info.Title,
+			"duration": info.Duration,
+		}).Info("✅ Successfully extracted video info")
+
+		return &info, nil
+	})
+}
 
-	cmd := exec.Command(s.ytDlpPath, args...)
+// ExtractPlaylist extracts all videos from a playlist. It only does the fast
+// --flat-playlist pass, so entries carry an ID/title but no stream format -
+// callers that need full per-entry metadata up front should use
+// ExtractPlaylistAsync instead.
+func (s *Service) ExtractPlaylist(url string) ([]YouTubeInfo, error) {
+	return s.extractPlaylistFlat(url)
+}
+
+// DefaultPlaylistExtractWorkers is used by ExtractPlaylistAsync when its
+// workers argument is <= 0
+const DefaultPlaylistExtractWorkers = 8
+
+// PlaylistJob tracks a concurrent playlist extraction started by
+// ExtractPlaylistAsync. Entries stream out over Results as each worker
+// finishes its ExtractInfo call, in whatever order they complete - not
+// playlist order - so a caller can start playback on the first one instead
+// of blocking on the whole playlist.
+type PlaylistJob struct {
+	// Total is the number of entries the flat pass enumerated
+	Total int
+
+	// Results delivers one YouTubeInfo per successfully extracted entry and
+	// is closed once every entry has been attempted
+	Results chan YouTubeInfo
+
+	loaded int32 // atomic count of entries attempted so far, success or not
+	cancel context.CancelFunc
+}
+
+// Loaded returns how many of Total entries have been attempted so far,
+// whether or not extraction succeeded - enough for a "Loaded 23/147…"
+// progress display.
+func (j *PlaylistJob) Loaded() int {
+	return int(atomic.LoadInt32(&j.loaded))
+}
+
+// TotalCount returns Total. It exists alongside the Total field so
+// *PlaylistJob satisfies the same job-tracking interface as
+// spotify.PlaylistTracksJob (see commands.playlistLoadJob).
+func (j *PlaylistJob) TotalCount() int {
+	return j.Total
+}
+
+// Cancel stops any workers that haven't started their ExtractInfo call yet
+// and aborts those already in flight. Results still closes normally once the
+// in-flight workers return.
+func (j *PlaylistJob) Cancel() {
+	j.cancel()
+}
 
-	// Use Output() instead of CombinedOutput() to separate stdout from stderr
-	output, err := cmd.Output()
+// ExtractPlaylistAsync enumerates url's entries with the fast flat pass,
+// then resolves each one to full metadata across a bounded worker pool
+// (workers, or DefaultPlaylistExtractWorkers if <= 0), streaming completed
+// entries onto the returned job's Results channel as they finish. Unlike
+// ExtractPlaylist, the caller gets the first entry as soon as it's resolved
+// instead of waiting on the whole playlist.
+func (s *Service) ExtractPlaylistAsync(ctx context.Context, url string, workers int) (*PlaylistJob, error) {
+	entries, err := s.extractPlaylistFlat(url)
 	if err != nil {
-		s.logger.WithError(err).Error("yt-dlp extraction failed")
-		return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+		return nil, err
 	}
 
-	// Find JSON start (skip any non-JSON output)
-	jsonStart := strings.Index(string(output), "{")
-	if jsonStart == -1 {
-		s.logger.Error("No JSON found in yt-dlp output")
-		return nil, fmt.Errorf("%w: no JSON in output", ErrExtractionFailed)
+	if workers <= 0 {
+		workers = DefaultPlaylistExtractWorkers
+	}
+	if workers > len(entries) {
+		workers = len(entries)
 	}
-	jsonOutput := output[jsonStart:]
 
-	var info YouTubeInfo
-	if err := json.Unmarshal(jsonOutput, &info); err != nil {
-		s.logger.WithError(err).Error("Failed to parse yt-dlp output")
-		return nil, fmt.Errorf("failed to parse video info: %w", err)
+	jobCtx, cancel := context.WithCancel(ctx)
+	job := &PlaylistJob{
+		Total:   len(entries),
+		Results: make(chan YouTubeInfo, len(entries)),
+		cancel:  cancel,
 	}
 
-	// Cache the result
-	s.cache.Set(url, &info)
+	if len(entries) == 0 {
+		cancel()
+		close(job.Results)
+		return job, nil
+	}
 
-	s.logger.WithFields(map[string]interface{}{
-		"title":    info.Title,
-		"duration": info.Duration,
-	}).Info("✅ Successfully extracted video info")
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				entryURL := entries[idx].WebpageURL
+				if entryURL == "" {
+					entryURL = entries[idx].ID
+				}
+
+				if info, err := s.ExtractInfo(jobCtx, entryURL); err == nil {
+					job.Results <- *info
+				} else {
+					s.logger.WithError(err).WithField("url", entryURL).Warn("Failed to extract playlist entry")
+				}
+				atomic.AddInt32(&job.loaded, 1)
+			}
+		}()
+	}
 
-	return &info, nil
+	go func() {
+		for idx := range entries {
+			select {
+			case indices <- idx:
+			case <-jobCtx.Done():
+				close(indices)
+				wg.Wait()
+				close(job.Results)
+				return
+			}
+		}
+		close(indices)
+		wg.Wait()
+		close(job.Results)
+	}()
+
+	return job, nil
 }
 
-// ExtractPlaylist extracts all videos from a playlist
-func (s *Service) ExtractPlaylist(url string) ([]YouTubeInfo, error) {
+// streamPlaylistBufferSize bounds StreamPlaylist's entries channel, which
+// doubles as its backpressure limit: once it's full, yt-dlp's stdout pipe
+// stops being drained, which blocks yt-dlp's own output and so the process
+// itself, until the caller catches up.
+const streamPlaylistBufferSize = 16
+
+// StreamPlaylist enumerates url's entries with the fast flat pass, same as
+// extractPlaylistFlat, but parses yt-dlp's --print-json output line by line
+// as the process emits it instead of buffering the whole pass into memory
+// first - so a caller can start resolving/queuing the first entry while
+// yt-dlp is still discovering later ones, which matters for playlists large
+// enough that even the flat pass takes a while. Cancelling ctx kills the
+// yt-dlp process; both channels close once it exits, in either case.
+func (s *Service) StreamPlaylist(ctx context.Context, url string) (<-chan YouTubeInfo, <-chan error) {
+	entries := make(chan YouTubeInfo, streamPlaylistBufferSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(entries)
+		defer close(errs)
+
+		s.logger.WithField("url", url).Info("Streaming playlist entries...")
+
+		args := []string{
+			"--flat-playlist",
+			"--print-json",
+			"--no-check-certificate",
+			"--geo-bypass",
+			"--no-warnings",
+			url,
+		}
+
+		cmd := exec.CommandContext(ctx, s.ytDlpPath, args...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			errs <- fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+			return
+		}
+		if err := cmd.Start(); err != nil {
+			errs <- fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+			return
+		}
+
+		count := 0
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || !strings.HasPrefix(line, "{") {
+				continue // Skip non-JSON lines
+			}
+
+			var info YouTubeInfo
+			if err := json.Unmarshal([]byte(line), &info); err != nil {
+				s.logger.WithError(err).Warn("Failed to parse streamed playlist entry")
+				continue
+			}
+
+			select {
+			case entries <- info:
+				count++
+			case <-ctx.Done():
+				cmd.Wait()
+				return
+			}
+		}
+
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Warn("yt-dlp playlist stream exited with error")
+		}
+		s.logger.WithField("count", count).Info("✅ Finished streaming playlist")
+	}()
+
+	return entries, errs
+}
+
+// extractPlaylistFlat does the fast --flat-playlist pass, returning each
+// entry's ID/title without resolving a stream format
+func (s *Service) extractPlaylistFlat(url string) ([]YouTubeInfo, error) {
 	s.logger.WithField("url", url).Info("Extracting playlist...")
 
 	// Build yt-dlp command for playlist
@@ -147,9 +422,11 @@ func (s *Service) ExtractPlaylist(url string) ([]YouTubeInfo, error) {
 		url,
 	}
 
-	cmd := exec.Command(s.ytDlpPath, args...)
-	output, err := cmd.Output() // Use Output() instead of CombinedOutput()
+	start := time.Now()
+	output, _, err := s.executor.RunWithTimeout(context.Background(), s.ytDlpPath, args...)
+	s.metrics.ObserveExtractionLatency("playlist", time.Since(start))
 	if err != nil {
+		s.metrics.IncExtractionError("playlist")
 		s.logger.WithError(err).Error("Playlist extraction failed")
 		return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
 	}
@@ -177,17 +454,31 @@ func (s *Service) ExtractPlaylist(url string) ([]YouTubeInfo, error) {
 
 // Search searches YouTube and returns top results
 func (s *Service) Search(query string, maxResults int) ([]YouTubeInfo, error) {
+	return s.searchWithProvider("ytsearch", query, maxResults)
+}
+
+// SearchSoundCloud searches SoundCloud via yt-dlp's "scsearch" provider and
+// returns top results. yt-dlp already drives every SoundCloud URL in this
+// bot (see ResolveSongURLs' playlist extraction), so its search provider is
+// reused here instead of calling SoundCloud's API directly.
+func (s *Service) SearchSoundCloud(query string, maxResults int) ([]YouTubeInfo, error) {
+	return s.searchWithProvider("scsearch", query, maxResults)
+}
+
+// searchWithProvider runs a yt-dlp "<provider>N:<query>" search and parses
+// the resulting line-delimited JSON into YouTubeInfo entries
+func (s *Service) searchWithProvider(provider, query string, maxResults int) ([]YouTubeInfo, error) {
 	if maxResults <= 0 {
 		maxResults = 5
 	}
 
 	s.logger.WithFields(map[string]interface{}{
+		"provider":   provider,
 		"query":      query,
 		"maxResults": maxResults,
-	}).Info("Searching YouTube...")
+	}).Info("Searching...")
 
-	// Build search URL
-	searchURL := fmt.Sprintf("ytsearch%d:%s", maxResults, query)
+	searchURL := fmt.Sprintf("%s%d:%s", provider, maxResults, query)
 
 	args := []string{
 		"--dump-json",
@@ -197,9 +488,11 @@ func (s *Service) Search(query string, maxResults int) ([]YouTubeInfo, error) {
 		searchURL,
 	}
 
-	cmd := exec.Command(s.ytDlpPath, args...)
-	output, err := cmd.Output() // Use Output() instead of CombinedOutput()
+	start := time.Now()
+	output, _, err := s.executor.RunWithTimeout(context.Background(), s.ytDlpPath, args...)
+	s.metrics.ObserveExtractionLatency("search", time.Since(start))
 	if err != nil {
+		s.metrics.IncExtractionError("search")
 		s.logger.WithError(err).Error("Search failed")
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
@@ -246,9 +539,11 @@ func (s *Service) SearchByISRC(isrc string) (*YouTubeInfo, error) {
 		searchQuery,
 	}
 
-	cmd := exec.Command(s.ytDlpPath, args...)
-	output, err := cmd.Output()
+	start := time.Now()
+	output, _, err := s.executor.RunWithTimeout(context.Background(), s.ytDlpPath, args...)
+	s.metrics.ObserveExtractionLatency("search", time.Since(start))
 	if err != nil {
+		s.metrics.IncExtractionError("search")
 		s.logger.WithError(err).Debug("ISRC search failed")
 		return nil, fmt.Errorf("ISRC search failed: %w", err)
 	}
@@ -273,47 +568,82 @@ func (s *Service) SearchByISRC(isrc string) (*YouTubeInfo, error) {
 	return nil, fmt.Errorf("no results found for ISRC: %s", isrc)
 }
 
-// GetStreamURL gets the best audio stream URL for a video
+// GetStreamURL gets the best audio stream URL for a video. A cached file
+// downloaded by DownloadToSongCache after an earlier play takes priority
+// over both the in-memory URL cache and a fresh yt-dlp lookup, since it
+// needs no network round-trip at all.
 func (s *Service) GetStreamURL(videoID string) (string, error) {
-	// Check cache first
-	cacheKey := fmt.Sprintf("stream:%s", videoID)
-	if cached, ok := s.cache.Get(cacheKey); ok {
-		s.logger.Debug("Cache hit for stream URL")
-		return cached.(string), nil
+	if s.songCache != nil {
+		if fileURL, ok := s.songCache.Path(videoID); ok {
+			s.logger.WithField("video_id", videoID).Debug("Song audio cache hit")
+			return fileURL, nil
+		}
 	}
 
-	videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+	cacheKey := fmt.Sprintf("stream:%s", videoID)
+	return s.streamCache.GetOrLoad(cacheKey, func() (string, error) {
+		videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+
+		args := []string{
+			"--get-url",
+			"--format", "bestaudio/best",
+			"--no-check-certificate",
+			"--geo-bypass",
+			"--no-warnings", // Suppress warnings
+			videoURL,
+		}
 
-	args := []string{
-		"--get-url",
-		"--format", "bestaudio/best",
-		"--no-check-certificate",
-		"--geo-bypass",
-		"--no-warnings", // Suppress warnings
-		videoURL,
-	}
+		start := time.Now()
+		output, _, err := s.executor.RunWithTimeout(context.Background(), s.ytDlpPath, args...)
+		s.metrics.ObserveExtractionLatency("stream_url", time.Since(start))
+		if err != nil {
+			s.metrics.IncExtractionError("stream_url")
+			s.logger.WithError(err).Error("Failed to get stream URL")
+			return "", fmt.Errorf("failed to get stream URL: %w", err)
+		}
 
-	cmd := exec.Command(s.ytDlpPath, args...)
-	output, err := cmd.Output() // Use Output() instead of CombinedOutput()
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to get stream URL")
-		return "", fmt.Errorf("failed to get stream URL: %w", err)
-	}
+		streamURL := strings.TrimSpace(string(output))
+		if streamURL == "" {
+			return "", errors.New("empty stream URL returned")
+		}
 
-	streamURL := strings.TrimSpace(string(output))
-	if streamURL == "" {
-		return "", errors.New("empty stream URL returned")
+		return streamURL, nil
+	})
+}
+
+// DownloadToSongCache fetches videoID's audio into the song audio cache set
+// by SetSongCache, a no-op if no cache is wired up. Meant to be called after
+// a track finishes playing successfully (see AudioService.AddGlobalHandler
+// on audio.TrackEventEnd), so a song is only ever downloaded once it's
+// proven worth keeping around.
+func (s *Service) DownloadToSongCache(videoID, sourceURL string) {
+	if s.songCache == nil {
+		return
+	}
+	if err := s.songCache.Download(videoID, sourceURL); err != nil {
+		s.logger.WithError(err).WithField("video_id", videoID).Warn("Failed to cache song audio")
 	}
+}
 
-	// Cache stream URL
-	s.cache.Set(cacheKey, streamURL)
+var videoIDPattern = regexp.MustCompile(`(?:v=|youtu\.be/|/embed/|/shorts/)([A-Za-z0-9_-]{6,})`)
 
-	return streamURL, nil
+// VideoIDFromURL extracts the video ID from a youtube.com or youtu.be URL,
+// or "" if url doesn't look like one. Used to key DownloadToSongCache's
+// cache entry off the TrackEventEnd song's OriginalInput.
+func VideoIDFromURL(url string) string {
+	if match := videoIDPattern.FindStringSubmatch(url); match != nil {
+		return match[1]
+	}
+	return ""
 }
 
 // IsPlaylistURL checks if URL is a playlist
 func IsPlaylistURL(url string) bool {
-	return strings.Contains(url, "playlist?list=") || strings.Contains(url, "&list=")
+	// A bare "&list=" only means the video was opened from within a
+	// playlist (or an auto-generated "RD..." radio/mix) - it's still a
+	// single-video request unless the URL explicitly points at the
+	// playlist itself via playlist?list=.
+	return strings.Contains(url, "playlist?list=")
 }
 
 // IsYouTubeURL checks if URL is a valid YouTube URL
@@ -331,13 +661,16 @@ func (info *YouTubeInfo) ToSongMetadata() *valueobjects.SongMetadata {
 	}
 }
 
-// CacheStats returns cache statistics
+// CacheStats returns combined statistics for the info and stream caches
 func (s *Service) CacheStats() (hits, misses, evictions int64, size int) {
-	return s.cache.Stats()
+	infoHits, infoMisses, infoEvictions, infoSize := s.infoCache.Stats()
+	streamHits, streamMisses, streamEvictions, streamSize := s.streamCache.Stats()
+	return infoHits + streamHits, infoMisses + streamMisses, infoEvictions + streamEvictions, infoSize + streamSize
 }
 
-// ClearCache clears the entire cache
+// ClearCache clears both the info and stream caches
 func (s *Service) ClearCache() {
-	s.cache.Clear()
+	s.infoCache.Clear()
+	s.streamCache.Clear()
 	s.logger.Info("Cache cleared")
 }
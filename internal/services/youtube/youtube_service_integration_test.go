@@ -0,0 +1,119 @@
+//go:build integration
+
+package youtube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// These tests hit the real yt-dlp binary and the network, so they're kept
+// behind the "integration" build tag instead of running in the default unit
+// test suite - see TestExtractInfo/TestGetStreamURL/TestSearch in
+// youtube_service_test.go for their deterministic, MockCommandExecutor-backed
+// equivalents.
+
+func TestExtractInfoIntegration(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	svc, err := NewService(log)
+	if err != nil {
+		t.Skipf("yt-dlp not installed: %v", err)
+		return
+	}
+
+	// Use a known stable video (Rick Roll)
+	url := "https://www.youtube.com/watch?v=dQw4w9WgXcQ"
+
+	info, err := svc.ExtractInfo(context.Background(), url)
+	if err != nil {
+		t.Fatalf("Failed to extract info: %v", err)
+	}
+
+	if info.ID == "" {
+		t.Error("Expected video ID to be set")
+	}
+
+	if info.Title == "" {
+		t.Error("Expected title to be set")
+	}
+
+	if info.Duration <= 0 {
+		t.Error("Expected positive duration")
+	}
+
+	// Test cache hit
+	info2, err := svc.ExtractInfo(context.Background(), url)
+	if err != nil {
+		t.Fatalf("Failed to extract info from cache: %v", err)
+	}
+
+	if info2.ID != info.ID {
+		t.Error("Expected cached result to match")
+	}
+
+	hits, _, _, _ := svc.CacheStats()
+	if hits != 1 {
+		t.Errorf("Expected 1 cache hit, got %d", hits)
+	}
+}
+
+func TestGetStreamURLIntegration(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	svc, err := NewService(log)
+	if err != nil {
+		t.Skipf("yt-dlp not installed: %v", err)
+		return
+	}
+
+	videoID := "dQw4w9WgXcQ"
+
+	streamURL, err := svc.GetStreamURL(videoID)
+	if err != nil {
+		t.Fatalf("Failed to get stream URL: %v", err)
+	}
+
+	if streamURL == "" {
+		t.Error("Expected non-empty stream URL")
+	}
+
+	// Should start with https://
+	if len(streamURL) < 8 || streamURL[:8] != "https://" {
+		t.Error("Expected HTTPS URL")
+	}
+}
+
+func TestSearchIntegration(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	svc, err := NewService(log)
+	if err != nil {
+		t.Skipf("yt-dlp not installed: %v", err)
+		return
+	}
+
+	results, err := svc.Search("never gonna give you up", 3)
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	if len(results) == 0 {
+		t.Error("Expected at least one search result")
+	}
+
+	if len(results) > 3 {
+		t.Errorf("Expected max 3 results, got %d", len(results))
+	}
+
+	// Check first result
+	if results[0].ID == "" {
+		t.Error("Expected video ID in search result")
+	}
+
+	if results[0].Title == "" {
+		t.Error("Expected title in search result")
+	}
+}
@@ -1,11 +1,39 @@
 package youtube
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	cmdexec "github.com/vuongmanhnghia/discord-music-bot/internal/exec"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/metrics"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/testutils"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/utils"
 	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
 )
 
+// newMockedService builds a Service around a MockCommandExecutor instead of
+// a real yt-dlp binary, so extraction/search/stream-URL logic is unit
+// testable without yt-dlp installed. See the *Integration tests in
+// youtube_service_integration_test.go for the real-yt-dlp equivalents.
+func newMockedService(t *testing.T) (*Service, *testutils.MockCommandExecutor) {
+	t.Helper()
+
+	mock := testutils.NewMockCommandExecutor()
+	svc := &Service{
+		infoCache:   utils.NewCache[string, *YouTubeInfo](500, 30*time.Minute),
+		streamCache: utils.NewCache[string, string](500, 5*time.Minute),
+		logger:      logger.New(logger.Config{Level: "error"}),
+		ytDlpPath:   "yt-dlp",
+		executor:    mock,
+		metrics:     metrics.NoopRecorder{},
+	}
+	return svc, mock
+}
+
+var _ cmdexec.CommandExecutor = (*testutils.MockCommandExecutor)(nil)
+
 func TestNewService(t *testing.T) {
 	log := logger.New(logger.Config{Level: "info"})
 
@@ -19,7 +47,7 @@ func TestNewService(t *testing.T) {
 		t.Fatal("Expected service to be created")
 	}
 
-	if svc.cache == nil {
+	if svc.infoCache == nil || svc.streamCache == nil {
 		t.Error("Expected cache to be initialized")
 	}
 
@@ -49,6 +77,27 @@ func TestIsYouTubeURL(t *testing.T) {
 	}
 }
 
+func TestVideoIDFromURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=30s", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/embed/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/shorts/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://open.spotify.com/track/123", ""},
+		{"not a url", ""},
+	}
+
+	for _, tt := range tests {
+		if result := VideoIDFromURL(tt.url); result != tt.expected {
+			t.Errorf("VideoIDFromURL(%s) = %q, expected %q", tt.url, result, tt.expected)
+		}
+	}
+}
+
 func TestIsPlaylistURL(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -145,118 +194,183 @@ func TestServiceCacheOperations(t *testing.T) {
 	}
 }
 
-// Integration tests (require yt-dlp and network)
-func TestExtractInfoIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test")
-	}
-
-	log := logger.New(logger.Config{Level: "error"})
-
-	svc, err := NewService(log)
-	if err != nil {
-		t.Skipf("yt-dlp not installed: %v", err)
-		return
-	}
+// fixtureExtractInfoJSON is a trimmed copy of real yt-dlp --dump-json output
+// for a single video, used to deterministically exercise ExtractInfo's
+// parsing without installing yt-dlp or hitting the network.
+const fixtureExtractInfoJSON = `{"id":"dQw4w9WgXcQ","title":"Rick Astley - Never Gonna Give You Up","duration":213,"uploader":"Rick Astley","thumbnail":"https://i.ytimg.com/vi/dQw4w9WgXcQ/maxresdefault.jpg","webpage_url":"https://www.youtube.com/watch?v=dQw4w9WgXcQ","_type":"video"}`
 
-	// Use a known stable video (Rick Roll)
+func TestExtractInfo(t *testing.T) {
+	svc, mock := newMockedService(t)
 	url := "https://www.youtube.com/watch?v=dQw4w9WgXcQ"
-
-	info, err := svc.ExtractInfo(url)
+	args := []string{
+		"--dump-json",
+		"--no-playlist",
+		"--format", "bestaudio/best",
+		"--no-check-certificate",
+		"--geo-bypass",
+		"--no-warnings",
+		url,
+	}
+	mock.SetResponse(testutils.MockResponse{Stdout: []byte(fixtureExtractInfoJSON)}, svc.ytDlpPath, args...)
+
+	info, err := svc.ExtractInfo(context.Background(), url)
 	if err != nil {
-		t.Fatalf("Failed to extract info: %v", err)
+		t.Fatalf("ExtractInfo failed: %v", err)
 	}
-
-	if info.ID == "" {
-		t.Error("Expected video ID to be set")
+	if info.ID != "dQw4w9WgXcQ" {
+		t.Errorf("expected ID dQw4w9WgXcQ, got %q", info.ID)
 	}
-
 	if info.Title == "" {
-		t.Error("Expected title to be set")
+		t.Error("expected title to be set")
 	}
-
-	if info.Duration <= 0 {
-		t.Error("Expected positive duration")
+	if info.Duration != 213 {
+		t.Errorf("expected duration 213, got %d", info.Duration)
 	}
 
-	// Test cache hit
-	info2, err := svc.ExtractInfo(url)
+	// Second call should hit infoCache without invoking the executor again.
+	info2, err := svc.ExtractInfo(context.Background(), url)
 	if err != nil {
-		t.Fatalf("Failed to extract info from cache: %v", err)
+		t.Fatalf("ExtractInfo (cached) failed: %v", err)
 	}
-
 	if info2.ID != info.ID {
-		t.Error("Expected cached result to match")
+		t.Error("expected cached result to match")
 	}
-
-	hits, _, _, _ := svc.CacheStats()
-	if hits != 1 {
-		t.Errorf("Expected 1 cache hit, got %d", hits)
+	if len(mock.Calls()) != 1 {
+		t.Errorf("expected 1 executor call (second should hit cache), got %d", len(mock.Calls()))
 	}
 }
 
-func TestGetStreamURLIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test")
+func TestExtractInfoInvalidJSON(t *testing.T) {
+	svc, mock := newMockedService(t)
+	url := "https://www.youtube.com/watch?v=bad"
+	args := []string{
+		"--dump-json",
+		"--no-playlist",
+		"--format", "bestaudio/best",
+		"--no-check-certificate",
+		"--geo-bypass",
+		"--no-warnings",
+		url,
 	}
+	mock.SetResponse(testutils.MockResponse{Stdout: []byte("not json")}, svc.ytDlpPath, args...)
 
-	log := logger.New(logger.Config{Level: "error"})
-
-	svc, err := NewService(log)
-	if err != nil {
-		t.Skipf("yt-dlp not installed: %v", err)
-		return
+	if _, err := svc.ExtractInfo(context.Background(), url); err == nil {
+		t.Error("expected an error for output with no JSON")
 	}
+}
 
+func TestGetStreamURL(t *testing.T) {
+	svc, mock := newMockedService(t)
 	videoID := "dQw4w9WgXcQ"
+	args := []string{
+		"--get-url",
+		"--format", "bestaudio/best",
+		"--no-check-certificate",
+		"--geo-bypass",
+		"--no-warnings",
+		"https://www.youtube.com/watch?v=" + videoID,
+	}
+	mock.SetResponse(testutils.MockResponse{Stdout: []byte("https://rr-cdn.googlevideo.com/stream?id=1\n")}, svc.ytDlpPath, args...)
 
 	streamURL, err := svc.GetStreamURL(videoID)
 	if err != nil {
-		t.Fatalf("Failed to get stream URL: %v", err)
+		t.Fatalf("GetStreamURL failed: %v", err)
 	}
-
-	if streamURL == "" {
-		t.Error("Expected non-empty stream URL")
-	}
-
-	// Should start with https://
-	if len(streamURL) < 8 || streamURL[:8] != "https://" {
-		t.Error("Expected HTTPS URL")
+	if streamURL != "https://rr-cdn.googlevideo.com/stream?id=1" {
+		t.Errorf("unexpected stream URL: %q", streamURL)
 	}
 }
 
-func TestSearchIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test")
+func TestGetStreamURLEmptyOutput(t *testing.T) {
+	svc, mock := newMockedService(t)
+	videoID := "deadbeef"
+	args := []string{
+		"--get-url",
+		"--format", "bestaudio/best",
+		"--no-check-certificate",
+		"--geo-bypass",
+		"--no-warnings",
+		"https://www.youtube.com/watch?v=" + videoID,
 	}
+	mock.SetResponse(testutils.MockResponse{Stdout: []byte("  \n")}, svc.ytDlpPath, args...)
 
-	log := logger.New(logger.Config{Level: "error"})
-
-	svc, err := NewService(log)
-	if err != nil {
-		t.Skipf("yt-dlp not installed: %v", err)
-		return
+	if _, err := svc.GetStreamURL(videoID); err == nil {
+		t.Error("expected an error for empty stream URL output")
 	}
+}
 
-	results, err := svc.Search("never gonna give you up", 3)
+func TestSearch(t *testing.T) {
+	svc, mock := newMockedService(t)
+	query := "never gonna give you up"
+	searchURL := "ytsearch3:" + query
+	args := []string{
+		"--dump-json",
+		"--no-check-certificate",
+		"--geo-bypass",
+		"--no-warnings",
+		searchURL,
+	}
+	fixture := `{"id":"dQw4w9WgXcQ","title":"Rick Astley - Never Gonna Give You Up"}
+{"id":"other1","title":"Other Result"}`
+	mock.SetResponse(testutils.MockResponse{Stdout: []byte(fixture)}, svc.ytDlpPath, args...)
+
+	results, err := svc.Search(query, 3)
 	if err != nil {
-		t.Fatalf("Failed to search: %v", err)
+		t.Fatalf("Search failed: %v", err)
 	}
-
-	if len(results) == 0 {
-		t.Error("Expected at least one search result")
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
 	}
-
-	if len(results) > 3 {
-		t.Errorf("Expected max 3 results, got %d", len(results))
+	if results[0].ID == "" || results[0].Title == "" {
+		t.Error("expected ID and title on first result")
 	}
+}
 
-	// Check first result
-	if results[0].ID == "" {
-		t.Error("Expected video ID in search result")
+func TestValidateExtractedInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    YouTubeInfo
+		wantErr bool
+	}{
+		{
+			name: "valid video",
+			info: YouTubeInfo{ID: "dQw4w9WgXcQ", Title: "Never Gonna Give You Up", Duration: 213},
+		},
+		{
+			name:    "missing ID (deleted video)",
+			info:    YouTubeInfo{Title: "Some Title", Duration: 213},
+			wantErr: true,
+		},
+		{
+			name:    "missing title (age-restricted video)",
+			info:    YouTubeInfo{ID: "dQw4w9WgXcQ", Duration: 213},
+			wantErr: true,
+		},
+		{
+			name:    "zero duration (region-blocked video)",
+			info:    YouTubeInfo{ID: "dQw4w9WgXcQ", Title: "Some Title", Duration: 0},
+			wantErr: true,
+		},
+		{
+			name:    "completely empty payload",
+			info:    YouTubeInfo{},
+			wantErr: true,
+		},
+		{
+			name: "playlist result is exempt from the duration check",
+			info: YouTubeInfo{ID: "PLtest", Title: "My Playlist", Type: "playlist"},
+		},
 	}
 
-	if results[0].Title == "" {
-		t.Error("Expected title in search result")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtractedInfo(&tt.info)
+			if tt.wantErr && !errors.Is(err, ErrEmptyMetadata) {
+				t.Errorf("expected ErrEmptyMetadata, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
 	}
 }
@@ -0,0 +1,224 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/soundcloud"
+)
+
+// soundCloudAPIBase is the public (undocumented but widely used) API the
+// SoundCloud web player itself talks to. It requires a client_id, which
+// SoundCloud issues to web clients rather than third-party apps - operators
+// typically extract one from the web player's bundled JS and configure it
+// via SOUNDCLOUD_CLIENT_ID
+const soundCloudAPIBase = "https://api-v2.soundcloud.com"
+
+// SoundCloudProvider resolves, streams, and searches SoundCloud tracks via
+// its public API instead of shelling out to yt-dlp
+type SoundCloudProvider struct {
+	clientID string
+	autoID   *soundcloud.ClientIDResolver
+	client   *http.Client
+}
+
+// NewSoundCloudProvider creates a SoundCloud Provider. clientID may be
+// empty, in which case resolveClientID falls back to scraping one from
+// soundcloud.com via autoID, so the provider still works for operators who
+// haven't registered their own client_id.
+func NewSoundCloudProvider(clientID string) *SoundCloudProvider {
+	client := &http.Client{Timeout: 15 * time.Second}
+	return &SoundCloudProvider{
+		clientID: clientID,
+		autoID:   soundcloud.NewClientIDResolver(client),
+		client:   client,
+	}
+}
+
+// resolveClientID returns the configured clientID, or a scraped one from
+// autoID if none was configured.
+func (p *SoundCloudProvider) resolveClientID(ctx context.Context) (string, error) {
+	if p.clientID != "" {
+		return p.clientID, nil
+	}
+	return p.autoID.Get(ctx)
+}
+
+// Name identifies this provider for logging
+func (p *SoundCloudProvider) Name() string {
+	return "soundcloud"
+}
+
+// Match reports whether url is a SoundCloud URL
+func (p *SoundCloudProvider) Match(url string) bool {
+	return soundcloud.IsSoundCloudURL(url)
+}
+
+// Extract resolves a SoundCloud track or set URL into MediaInfo via the
+// public /resolve endpoint
+func (p *SoundCloudProvider) Extract(ctx context.Context, rawURL string) (*MediaInfo, error) {
+	clientID, err := p.resolveClientID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("soundcloud client_id unavailable: %w", err)
+	}
+
+	var resolved scTrack
+	resolveURL := fmt.Sprintf("%s/resolve?url=%s&client_id=%s", soundCloudAPIBase, url.QueryEscape(rawURL), clientID)
+	if err := p.getJSON(ctx, resolveURL, &resolved); err != nil {
+		return nil, fmt.Errorf("failed to resolve soundcloud URL: %w", err)
+	}
+
+	if resolved.Kind == "playlist" {
+		entries := make([]MediaInfo, 0, len(resolved.Tracks))
+		for _, t := range resolved.Tracks {
+			entries = append(entries, fromSoundCloudTrack(t))
+		}
+		return &MediaInfo{IsPlaylist: true, Entries: entries}, nil
+	}
+
+	media := fromSoundCloudTrack(resolved)
+	return &media, nil
+}
+
+// Stream returns a playable stream URL for a SoundCloud track ID, picking
+// the progressive transcoding when available (HLS otherwise)
+func (p *SoundCloudProvider) Stream(id string) (string, error) {
+	ctx := context.Background()
+
+	clientID, err := p.resolveClientID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("soundcloud client_id unavailable: %w", err)
+	}
+
+	var track scTrack
+	trackURL := fmt.Sprintf("%s/tracks/%s?client_id=%s", soundCloudAPIBase, id, clientID)
+	if err := p.getJSON(ctx, trackURL, &track); err != nil {
+		return "", fmt.Errorf("failed to get soundcloud track: %w", err)
+	}
+
+	transcoding := bestTranscoding(track.Media.Transcodings)
+	if transcoding == nil {
+		// No transcoding metadata (unusual) - fall back to the track's own
+		// page, which yt-dlp can still resolve directly
+		if track.PermalinkURL != "" {
+			return track.PermalinkURL, nil
+		}
+		return "", fmt.Errorf("soundcloud track %s has no playable transcoding", id)
+	}
+
+	var stream struct {
+		URL string `json:"url"`
+	}
+	streamURL := fmt.Sprintf("%s&client_id=%s", transcoding.URL, clientID)
+	if err := p.getJSON(ctx, streamURL, &stream); err != nil {
+		return "", fmt.Errorf("failed to resolve soundcloud stream: %w", err)
+	}
+
+	return stream.URL, nil
+}
+
+// Search returns up to limit SoundCloud tracks matching query via the
+// public /search/tracks endpoint, best match first
+func (p *SoundCloudProvider) Search(query string, limit int) ([]MediaInfo, error) {
+	ctx := context.Background()
+
+	clientID, err := p.resolveClientID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("soundcloud client_id unavailable: %w", err)
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+
+	var result struct {
+		Collection []scTrack `json:"collection"`
+	}
+	searchURL := fmt.Sprintf("%s/search/tracks?q=%s&limit=%d&client_id=%s", soundCloudAPIBase, url.QueryEscape(query), limit, clientID)
+	if err := p.getJSON(ctx, searchURL, &result); err != nil {
+		return nil, fmt.Errorf("soundcloud search failed: %w", err)
+	}
+
+	media := make([]MediaInfo, 0, len(result.Collection))
+	for _, t := range result.Collection {
+		media = append(media, fromSoundCloudTrack(t))
+	}
+	return media, nil
+}
+
+func (p *SoundCloudProvider) getJSON(ctx context.Context, reqURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("soundcloud API returned status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// scTrack mirrors the fields we need from a SoundCloud API track/playlist
+// object; SoundCloud returns far more than this
+type scTrack struct {
+	Kind         string    `json:"kind"`
+	ID           int64     `json:"id"`
+	Title        string    `json:"title"`
+	Duration     int       `json:"duration"` // milliseconds
+	ArtworkURL   string    `json:"artwork_url"`
+	PermalinkURL string    `json:"permalink_url"`
+	User         scUser    `json:"user"`
+	Media        scMedia   `json:"media"`
+	Tracks       []scTrack `json:"tracks"` // populated for a resolved playlist/set
+}
+
+type scUser struct {
+	Username string `json:"username"`
+}
+
+type scMedia struct {
+	Transcodings []scTranscoding `json:"transcodings"`
+}
+
+type scTranscoding struct {
+	URL    string `json:"url"`
+	Format struct {
+		Protocol string `json:"protocol"`
+	} `json:"format"`
+}
+
+// bestTranscoding prefers a progressive (plain HTTP) transcoding over HLS
+// since it's simpler for the downstream pipeline to consume
+func bestTranscoding(transcodings []scTranscoding) *scTranscoding {
+	for i, t := range transcodings {
+		if t.Format.Protocol == "progressive" {
+			return &transcodings[i]
+		}
+	}
+	if len(transcodings) > 0 {
+		return &transcodings[0]
+	}
+	return nil
+}
+
+func fromSoundCloudTrack(t scTrack) MediaInfo {
+	return MediaInfo{
+		ID:        strconv.FormatInt(t.ID, 10),
+		Title:     t.Title,
+		URL:       t.PermalinkURL,
+		Duration:  t.Duration / 1000,
+		Thumbnail: t.ArtworkURL,
+		Uploader:  t.User.Username,
+	}
+}
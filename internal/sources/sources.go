@@ -0,0 +1,77 @@
+// Package sources provides a pluggable abstraction over the audio
+// providers the bot can resolve, stream, and search (YouTube, SoundCloud,
+// ...), so a new provider can be added without touching command handlers
+// beyond registration. See externalplaylist for the analogous abstraction
+// over playlist import.
+package sources
+
+import (
+	"context"
+	"fmt"
+)
+
+// MediaInfo is a provider-agnostic resolved track, or the container for a
+// resolved playlist/set
+type MediaInfo struct {
+	ID         string
+	Title      string
+	URL        string
+	Duration   int // seconds
+	Thumbnail  string
+	Uploader   string
+	IsPlaylist bool
+	Entries    []MediaInfo // populated when IsPlaylist is true
+}
+
+// Provider resolves URLs and search queries from a single audio source
+type Provider interface {
+	// Name identifies the provider for logging
+	Name() string
+	// Match reports whether url belongs to this provider
+	Match(url string) bool
+	// Extract resolves a single track or playlist URL into MediaInfo
+	Extract(ctx context.Context, url string) (*MediaInfo, error)
+	// Stream returns a playable URL for the track ID previously returned
+	// in MediaInfo.ID
+	Stream(id string) (string, error)
+	// Search returns up to limit results matching query, best match first
+	Search(query string, limit int) ([]MediaInfo, error)
+}
+
+// Registry picks the right provider for a URL out of a fixed set
+type Registry struct {
+	providers []Provider
+}
+
+// NewRegistry builds a registry from the available providers, skipping any
+// nil entries (e.g. a provider disabled because it has no credentials)
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{}
+	for _, p := range providers {
+		if p != nil {
+			r.providers = append(r.providers, p)
+		}
+	}
+	return r
+}
+
+// For returns the provider that matches the given URL
+func (r *Registry) For(url string) (Provider, error) {
+	for _, p := range r.providers {
+		if p.Match(url) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no provider available for URL: %s", url)
+}
+
+// ByName returns the registered provider with the given Name(), or nil if
+// none is registered (e.g. SoundCloud with no client_id configured)
+func (r *Registry) ByName(name string) Provider {
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
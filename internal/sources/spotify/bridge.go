@@ -0,0 +1,154 @@
+// Package spotify bridges Spotify tracks to playable YouTube videos. It
+// prefers an ISRC lookup - which identifies the exact recording - over a
+// fuzzy text search, and exposes the bridge so both /play and playlist
+// import can share one resolution path instead of /play alone getting the
+// accurate match.
+package spotify
+
+import (
+	"context"
+	"fmt"
+
+	svcspotify "github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+	"github.com/vuongmanhnghia/discord-music-bot/pkg/logger"
+)
+
+// isrcDurationToleranceSeconds is how far a YouTube result's duration may
+// drift from the Spotify track's and still count as an ISRC match
+const isrcDurationToleranceSeconds = 5
+
+// searchResultCount is how many YouTube results each text-search fallback
+// strategy considers before giving up
+const searchResultCount = 3
+
+// Bridge resolves Spotify tracks and URLs to playable YouTube videos.
+// Callers that need the result cached across requests should wrap
+// ResolveTrack themselves (see commands.Handler.resolveSpotifyTrackToYouTube,
+// which layers the persistent cache.Manager.SpotifyToYouTube store on top)
+// rather than this type caching internally, so there's a single cache to
+// invalidate instead of two.
+type Bridge struct {
+	spotify *svcspotify.Service
+	youtube *youtube.Service
+	logger  *logger.Logger
+}
+
+// NewBridge creates a Spotify->YouTube resolution bridge
+func NewBridge(sp *svcspotify.Service, yt *youtube.Service, log *logger.Logger) *Bridge {
+	return &Bridge{spotify: sp, youtube: yt, logger: log}
+}
+
+// ResolveTrack finds the best matching YouTube video ID for a Spotify
+// track, trying (1) SearchByISRC, accepting it only within
+// isrcDurationToleranceSeconds of the Spotify duration, then (2) a detailed
+// artist/title/album search, then (3) a plain artist/title search - both
+// scored by BestYouTubeCandidate. Returns "" if nothing cleared the score
+// threshold.
+func (b *Bridge) ResolveTrack(track svcspotify.Track) string {
+	spotifyDuration := track.GetDurationSeconds()
+
+	if isrc := track.GetISRC(); isrc != "" {
+		if info, err := b.youtube.SearchByISRC(isrc); err == nil {
+			if absInt(info.Duration-spotifyDuration) <= isrcDurationToleranceSeconds {
+				b.logger.WithField("track", track.Name).Info("✅ Found by ISRC with duration match")
+				return info.ID
+			}
+			b.logger.WithFields(map[string]interface{}{
+				"track":            track.Name,
+				"spotify_duration": spotifyDuration,
+				"youtube_duration": info.Duration,
+			}).Debug("ISRC match but duration mismatch, trying other methods")
+		}
+	}
+
+	if results, err := b.youtube.Search(track.ToDetailedSearchQuery(), searchResultCount); err == nil {
+		if best := BestYouTubeCandidate(track, results); best != nil {
+			b.logger.WithField("track", track.Name).Info("✅ Found by detailed search")
+			return best.ID
+		}
+	}
+
+	results, err := b.youtube.Search(track.ToSearchQuery(), searchResultCount)
+	if err != nil {
+		b.logger.WithError(err).WithField("track", track.Name).Warn("All search methods failed")
+		return ""
+	}
+	if len(results) == 0 {
+		b.logger.WithField("track", track.Name).Warn("No YouTube results found")
+		return ""
+	}
+	if best := BestYouTubeCandidate(track, results); best != nil {
+		b.logger.WithField("track", track.Name).Info("✅ Found by simple search")
+		return best.ID
+	}
+
+	// Last resort: use the first result rather than giving up entirely
+	b.logger.WithField("track", track.Name).Warn("⚠️ Using first result (no good match)")
+	return results[0].ID
+}
+
+// ResolvedTrack pairs a Spotify track with the YouTube video ID
+// ResolveTrack found for it
+type ResolvedTrack struct {
+	Track   svcspotify.Track
+	VideoID string
+}
+
+// ResolveURL fetches every track behind a Spotify track/playlist/album/
+// artist/show/episode URL and resolves each to a YouTube video ID. Tracks
+// that don't clear ResolveTrack's score threshold are silently skipped,
+// same as the existing /play path.
+func (b *Bridge) ResolveURL(urlStr string) ([]ResolvedTrack, error) {
+	urlType, id, err := svcspotify.ParseSpotifyURL(urlStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Spotify URL: %w", err)
+	}
+
+	var tracks []svcspotify.Track
+	switch urlType {
+	case "track":
+		track, err := b.spotify.GetTrack(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Spotify track: %w", err)
+		}
+		tracks = []svcspotify.Track{*track}
+	case "playlist":
+		tracks, err = b.spotify.GetPlaylistTracks(context.Background(), id)
+	case "album":
+		tracks, err = b.spotify.GetAlbumTracks(context.Background(), id)
+	case "artist":
+		tracks, err = b.spotify.GetArtistTopTracks(id, "")
+	case "show":
+		tracks, err = b.spotify.GetShowEpisodes(id)
+	case "episode":
+		var track *svcspotify.Track
+		track, err = b.spotify.GetEpisode(id)
+		if track != nil {
+			tracks = []svcspotify.Track{*track}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Spotify URL type: %s", urlType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Spotify content: %w", err)
+	}
+
+	resolved := make([]ResolvedTrack, 0, len(tracks))
+	for _, track := range tracks {
+		videoID := b.ResolveTrack(track)
+		if videoID == "" {
+			continue
+		}
+		resolved = append(resolved, ResolvedTrack{Track: track, VideoID: videoID})
+	}
+
+	return resolved, nil
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
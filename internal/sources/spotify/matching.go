@@ -0,0 +1,158 @@
+package spotify
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	svcspotify "github.com/vuongmanhnghia/discord-music-bot/internal/services/spotify"
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+)
+
+// candidateScoreThreshold is the minimum ScoredCandidate.Score
+// BestYouTubeCandidate requires before trusting a match; below it, a caller
+// should fall back to a different search strategy rather than risk playing
+// the wrong song.
+const candidateScoreThreshold = 0.55
+
+// junkTitleKeywords flag a YouTube upload as probably not the canonical
+// studio track, unless the Spotify track's own title already contains one
+// (e.g. a song actually named "Live From Abbey Road").
+var junkTitleKeywords = []string{"cover", "remix", "live", "karaoke", "sped up", "nightcore"}
+
+// parentheticalTagPattern strips bracketed/parenthesized upload qualifiers
+// like "(Official Video)" or "[Lyrics]" before comparing titles, since
+// those describe the upload rather than the song.
+var parentheticalTagPattern = regexp.MustCompile(`[(\[][^)\]]*[)\]]`)
+
+// punctuationPattern strips whatever punctuation remains once parenthetical
+// tags are gone, so e.g. "Don't Stop" and "Dont Stop" compare as closely as
+// two differently-spaced copies of the same title.
+var punctuationPattern = regexp.MustCompile(`[^a-z0-9 ]+`)
+
+// ScoredCandidate pairs a YouTube search result with the score
+// RankYouTubeCandidates gave it against a Spotify track.
+type ScoredCandidate struct {
+	Info  youtube.YouTubeInfo
+	Score float64
+}
+
+// RankYouTubeCandidates scores each of results against track on a 0-1 scale
+// combining title similarity, duration closeness, and an uploader/artist
+// match, sorted highest score first. Duration-nearest alone happily picks a
+// remix or live version that just happens to run the right length;
+// weighting in title similarity and penalizing junk keywords catches those
+// before they get queued.
+func RankYouTubeCandidates(track svcspotify.Track, results []youtube.YouTubeInfo) []ScoredCandidate {
+	targetTitle := track.Name
+	if len(track.Artists) > 0 {
+		targetTitle = track.Name + " " + track.Artists[0].Name
+	}
+	targetDuration := float64(track.GetDurationSeconds())
+	spotifyTitleHasJunkWord := containsJunkKeyword(track.Name)
+
+	scored := make([]ScoredCandidate, 0, len(results))
+	for _, r := range results {
+		titleScore := trigramSimilarity(targetTitle, r.Title)
+		durationScore := math.Max(0, 1-absFloat(float64(r.Duration)-targetDuration)/15)
+
+		artistBonus := 0.0
+		for _, artist := range track.Artists {
+			if artist.Name != "" && strings.Contains(strings.ToLower(r.Uploader), strings.ToLower(artist.Name)) {
+				artistBonus = 1.0
+				break
+			}
+		}
+
+		penalty := 0.0
+		if !spotifyTitleHasJunkWord && containsJunkKeyword(r.Title) {
+			penalty = 0.3
+		}
+
+		score := 0.5*titleScore + 0.35*durationScore + 0.15*artistBonus - penalty
+		scored = append(scored, ScoredCandidate{Info: r, Score: score})
+	}
+
+	sort.Slice(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	return scored
+}
+
+// BestYouTubeCandidate returns the highest-scoring result for track above
+// candidateScoreThreshold, or nil if none clears it.
+func BestYouTubeCandidate(track svcspotify.Track, results []youtube.YouTubeInfo) *youtube.YouTubeInfo {
+	scored := RankYouTubeCandidates(track, results)
+	if len(scored) == 0 || scored[0].Score < candidateScoreThreshold {
+		return nil
+	}
+	return &scored[0].Info
+}
+
+func containsJunkKeyword(title string) bool {
+	lower := strings.ToLower(title)
+	for _, junk := range junkTitleKeywords {
+		if strings.Contains(lower, junk) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeForTrigram lowercases s, strips parenthetical upload tags and
+// remaining punctuation, and collapses whitespace, so trigramSimilarity
+// compares only the words that actually name the song.
+func normalizeForTrigram(s string) string {
+	s = strings.ToLower(s)
+	s = parentheticalTagPattern.ReplaceAllString(s, "")
+	s = punctuationPattern.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// trigramSet returns s's overlapping 3-character sequences. Strings shorter
+// than 3 characters are treated as a single "trigram" of their own so short
+// titles (and the empty string) still compare sensibly.
+func trigramSet(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	if len(s) < 3 {
+		if s != "" {
+			set[s] = struct{}{}
+		}
+		return set
+	}
+	for i := 0; i+3 <= len(s); i++ {
+		set[s[i:i+3]] = struct{}{}
+	}
+	return set
+}
+
+// trigramSimilarity is the Jaccard similarity |A∩B| / |A∪B| of a and b's
+// trigram sets, after normalizeForTrigram - a cheap, dependency-free stand-
+// in for full fuzzy string matching that's tolerant of word reordering and
+// minor spelling differences between a Spotify title and a YouTube upload.
+func trigramSimilarity(a, b string) float64 {
+	na, nb := normalizeForTrigram(a), normalizeForTrigram(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+
+	setA, setB := trigramSet(na), trigramSet(nb)
+	intersection := 0
+	for g := range setA {
+		if _, ok := setB[g]; ok {
+			intersection++
+		}
+	}
+
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+func absFloat(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,79 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/vuongmanhnghia/discord-music-bot/internal/services/youtube"
+)
+
+// YouTubeProvider adapts youtube.Service (the yt-dlp wrapper) to Provider
+type YouTubeProvider struct {
+	yt *youtube.Service
+}
+
+// NewYouTubeProvider creates a YouTube Provider backed by an existing
+// youtube.Service
+func NewYouTubeProvider(yt *youtube.Service) *YouTubeProvider {
+	return &YouTubeProvider{yt: yt}
+}
+
+// Name identifies this provider for logging
+func (p *YouTubeProvider) Name() string {
+	return "youtube"
+}
+
+// Match reports whether url is a YouTube URL
+func (p *YouTubeProvider) Match(url string) bool {
+	return youtube.IsYouTubeURL(url)
+}
+
+// Extract resolves a YouTube video or playlist URL into MediaInfo
+func (p *YouTubeProvider) Extract(ctx context.Context, url string) (*MediaInfo, error) {
+	if youtube.IsPlaylistURL(url) {
+		videos, err := p.yt.ExtractPlaylist(url)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]MediaInfo, 0, len(videos))
+		for _, v := range videos {
+			entries = append(entries, fromYouTubeInfo(v))
+		}
+		return &MediaInfo{IsPlaylist: true, Entries: entries}, nil
+	}
+
+	info, err := p.yt.ExtractInfo(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	media := fromYouTubeInfo(*info)
+	return &media, nil
+}
+
+// Stream returns the best audio stream URL for a YouTube video ID
+func (p *YouTubeProvider) Stream(id string) (string, error) {
+	return p.yt.GetStreamURL(id)
+}
+
+// Search returns up to limit YouTube results matching query
+func (p *YouTubeProvider) Search(query string, limit int) ([]MediaInfo, error) {
+	results, err := p.yt.Search(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	media := make([]MediaInfo, 0, len(results))
+	for _, r := range results {
+		media = append(media, fromYouTubeInfo(r))
+	}
+	return media, nil
+}
+
+func fromYouTubeInfo(info youtube.YouTubeInfo) MediaInfo {
+	return MediaInfo{
+		ID:        info.ID,
+		Title:     info.Title,
+		URL:       info.WebpageURL,
+		Duration:  info.Duration,
+		Thumbnail: info.Thumbnail,
+		Uploader:  info.Uploader,
+	}
+}
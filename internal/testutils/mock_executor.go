@@ -0,0 +1,77 @@
+// Package testutils holds test doubles shared across the services packages'
+// unit tests.
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MockResponse is a canned result MockCommandExecutor returns for a matched
+// command invocation.
+type MockResponse struct {
+	Stdout []byte
+	Stderr []byte
+	Err    error
+}
+
+// MockCommandExecutor is an exec.CommandExecutor test double that returns
+// pre-programmed responses instead of spawning a real yt-dlp/ffmpeg process,
+// keyed by the full command line (binary plus args).
+type MockCommandExecutor struct {
+	mu        sync.Mutex
+	responses map[string]MockResponse
+	calls     []string
+
+	// Default, if set, is returned for any command line with no matching
+	// SetResponse entry, instead of an error.
+	Default *MockResponse
+}
+
+// NewMockCommandExecutor creates an empty MockCommandExecutor. Program
+// responses with SetResponse before use.
+func NewMockCommandExecutor() *MockCommandExecutor {
+	return &MockCommandExecutor{responses: make(map[string]MockResponse)}
+}
+
+// commandKey joins bin and args the same way SetResponse and RunWithTimeout
+// look them up, so a programmed response matches an exact invocation.
+func commandKey(bin string, args ...string) string {
+	return bin + " " + strings.Join(args, " ")
+}
+
+// SetResponse programs resp to be returned when bin is invoked with exactly
+// args.
+func (m *MockCommandExecutor) SetResponse(resp MockResponse, bin string, args ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[commandKey(bin, args...)] = resp
+}
+
+// Calls returns every command line RunWithTimeout has seen so far, in call
+// order, for assertions like "did it actually invoke yt-dlp with --get-url".
+func (m *MockCommandExecutor) Calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.calls...)
+}
+
+// RunWithTimeout implements exec.CommandExecutor by returning the response
+// programmed via SetResponse for this exact bin/args, or Default if set.
+func (m *MockCommandExecutor) RunWithTimeout(_ context.Context, bin string, args ...string) ([]byte, []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := commandKey(bin, args...)
+	m.calls = append(m.calls, key)
+
+	if resp, ok := m.responses[key]; ok {
+		return resp.Stdout, resp.Stderr, resp.Err
+	}
+	if m.Default != nil {
+		return m.Default.Stdout, m.Default.Stderr, m.Default.Err
+	}
+	return nil, nil, fmt.Errorf("testutils: no mock response programmed for %q", key)
+}
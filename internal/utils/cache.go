@@ -2,147 +2,298 @@ package utils
 
 import (
 	"container/list"
+	"fmt"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
-// CacheEntry represents an entry in the cache with TTL
-type CacheEntry struct {
-	Key        string
-	Value      interface{}
-	ExpiresAt  time.Time
+// RemoteBackend is an optional tier behind a Cache's in-process LRU, e.g.
+// Redis or BadgerDB, so a miss on one instance can still be satisfied
+// without invoking the loader. Get's bool return is whether key was found;
+// a "not found" is not an error. Implementations are expected to apply ttl
+// themselves (0 means no expiry).
+type RemoteBackend[K comparable, V any] interface {
+	Get(key K) (V, bool, error)
+	Set(key K, value V, ttl time.Duration) error
+	Delete(key K) error
+}
+
+// noopBackend is the default RemoteBackend: every Get misses, every Set and
+// Delete is a no-op. Used when a Cache has no remote tier configured.
+type noopBackend[K comparable, V any] struct{}
+
+func (noopBackend[K, V]) Get(key K) (V, bool, error) {
+	var zero V
+	return zero, false, nil
+}
+
+func (noopBackend[K, V]) Set(key K, value V, ttl time.Duration) error { return nil }
+
+func (noopBackend[K, V]) Delete(key K) error { return nil }
+
+// MetricsReporter receives cache events for exporting, e.g. as Prometheus
+// counters and a histogram. Called synchronously from the hot path, so
+// implementations must be cheap and non-blocking.
+type MetricsReporter interface {
+	ObserveHit()
+	ObserveMiss()
+	ObserveEviction()
+	ObserveLoadLatency(d time.Duration)
+}
+
+// cacheEntry is an entry in the in-process tier with TTL support. err caches
+// a failed GetOrLoad call (negative-result caching) alongside a zero value.
+type cacheEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	err        error
+	expiresAt  time.Time
 	accessTime time.Time
 }
 
-// IsExpired returns true if the entry has expired
-func (e *CacheEntry) IsExpired() bool {
-	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+func (e *cacheEntry[K, V]) isExpired() bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
 }
 
-// SmartCache is an LRU cache with TTL support
-type SmartCache struct {
-	maxSize   int
-	ttl       time.Duration
-	items     map[string]*list.Element
-	lruList   *list.List
-	mu        sync.RWMutex
+// Cache is a two-tier, generic LRU+TTL cache: an in-process tier (the
+// original SmartCache implementation) in front of an optional RemoteBackend.
+// GetOrLoad collapses concurrent misses for the same key into a single
+// loader call via singleflight, which matters when the same key (e.g. a
+// YouTube URL) is looked up by many guilds at once.
+type Cache[K comparable, V any] struct {
+	maxSize     int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	remote      RemoteBackend[K, V]
+	metrics     MetricsReporter
+
+	items   map[K]*list.Element
+	lruList *list.List
+	mu      sync.RWMutex
+	group   singleflight.Group
+
 	hits      int64
 	misses    int64
 	evictions int64
 }
 
-// NewSmartCache creates a new cache with LRU eviction and TTL
-func NewSmartCache(maxSize int, ttl time.Duration) *SmartCache {
-	return &SmartCache{
+// NewCache creates a two-tier cache. maxSize bounds the in-process LRU tier;
+// ttl is the default entry lifetime (0 disables expiry). Configure the rest
+// with WithRemoteBackend, WithNegativeTTL, and WithMetricsReporter.
+func NewCache[K comparable, V any](maxSize int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
 		maxSize: maxSize,
 		ttl:     ttl,
-		items:   make(map[string]*list.Element),
+		remote:  noopBackend[K, V]{},
+		items:   make(map[K]*list.Element),
 		lruList: list.New(),
 	}
 }
 
-// Get retrieves a value from the cache
-func (c *SmartCache) Get(key string) (interface{}, bool) {
+// SmartCache is a string-keyed, interface{}-valued Cache, kept as the name
+// existing callers use; see NewCache for the generic constructor.
+type SmartCache = Cache[string, interface{}]
+
+// NewSmartCache creates a new SmartCache with LRU eviction and TTL.
+func NewSmartCache(maxSize int, ttl time.Duration) *SmartCache {
+	return NewCache[string, interface{}](maxSize, ttl)
+}
+
+// WithRemoteBackend sets the optional tier behind the in-process LRU
+func (c *Cache[K, V]) WithRemoteBackend(backend RemoteBackend[K, V]) *Cache[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remote = backend
+	return c
+}
+
+// WithNegativeTTL sets how long a GetOrLoad loader error is cached before
+// the loader is retried. 0 (the default) disables negative caching, so a
+// failing loader is retried on every call.
+func (c *Cache[K, V]) WithNegativeTTL(ttl time.Duration) *Cache[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.negativeTTL = ttl
+	return c
+}
+
+// WithMetricsReporter sets the hook notified of every hit, miss, eviction,
+// and GetOrLoad loader call
+func (c *Cache[K, V]) WithMetricsReporter(reporter MetricsReporter) *Cache[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = reporter
+	return c
+}
+
+// Get retrieves a value from the in-process tier, falling back to the
+// remote backend (and repopulating the in-process tier) on a miss. A
+// negative cache entry (see GetOrLoad) is treated as a miss.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	if entry, ok := c.getEntryLocked(key); ok && entry.err == nil {
+		return entry.value, true
+	}
+
+	if value, found, err := c.remote.Get(key); err == nil && found {
+		c.Set(key, value)
+		return value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// getEntryLocked looks up key in the in-process tier, evicting it if
+// expired, and records the hit/miss
+func (c *Cache[K, V]) getEntryLocked(key K) (*cacheEntry[K, V], bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	elem, exists := c.items[key]
 	if !exists {
 		c.misses++
+		c.reportMiss()
 		return nil, false
 	}
 
-	entry := elem.Value.(*CacheEntry)
-
-	// Check if expired
-	if entry.IsExpired() {
+	entry := elem.Value.(*cacheEntry[K, V])
+	if entry.isExpired() {
 		c.removeLocked(key)
 		c.misses++
+		c.reportMiss()
 		return nil, false
 	}
 
-	// Update access time and move to front (most recently used)
 	entry.accessTime = time.Now()
 	c.lruList.MoveToFront(elem)
 	c.hits++
+	c.reportHit()
+	return entry, true
+}
 
-	return entry.Value, true
+// Set adds or updates a value using the cache's default TTL
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
 }
 
-// Set adds or updates a value in the cache
-func (c *SmartCache) Set(key string, value interface{}) {
+// SetWithTTL adds or updates a value with a per-key TTL override instead of
+// the cache's default, and best-effort mirrors it to the remote backend
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.setEntry(key, value, nil, ttl)
+	_ = c.remote.Set(key, value, ttl)
+}
+
+// setEntry is the shared implementation behind SetWithTTL and GetOrLoad's
+// negative caching
+func (c *Cache[K, V]) setEntry(key K, value V, err error, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	now := time.Now()
 	expiresAt := time.Time{}
-	if c.ttl > 0 {
-		expiresAt = now.Add(c.ttl)
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
 	}
 
-	// Check if key already exists
 	if elem, exists := c.items[key]; exists {
-		entry := elem.Value.(*CacheEntry)
-		entry.Value = value
-		entry.ExpiresAt = expiresAt
+		entry := elem.Value.(*cacheEntry[K, V])
+		entry.value = value
+		entry.err = err
+		entry.expiresAt = expiresAt
 		entry.accessTime = now
 		c.lruList.MoveToFront(elem)
 		return
 	}
 
-	// Create new entry
-	entry := &CacheEntry{
-		Key:        key,
-		Value:      value,
-		ExpiresAt:  expiresAt,
-		accessTime: now,
-	}
-
-	// Add to front of LRU list
+	entry := &cacheEntry[K, V]{key: key, value: value, err: err, expiresAt: expiresAt, accessTime: now}
 	elem := c.lruList.PushFront(entry)
 	c.items[key] = elem
 
-	// Evict if over capacity
 	if c.lruList.Len() > c.maxSize {
 		c.evictOldestLocked()
 	}
 }
 
+// GetOrLoad returns the cached value for key, calling loader on a miss (and
+// caching its result) instead of requiring the caller to Get then Set.
+// Concurrent callers for the same key share a single in-flight loader call
+// via singleflight. A loader error is cached for NegativeTTL (see
+// WithNegativeTTL) before being retried, so a burst of lookups for a key
+// that doesn't exist upstream doesn't hammer the loader.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	if entry, ok := c.getEntryLocked(key); ok {
+		return entry.value, entry.err
+	}
+
+	result, err, _ := c.group.Do(fmt.Sprint(key), func() (interface{}, error) {
+		// Another goroutine may have populated the cache while we waited to
+		// enter the singleflight group
+		if entry, ok := c.getEntryLocked(key); ok {
+			return entry.value, entry.err
+		}
+
+		if value, found, rerr := c.remote.Get(key); rerr == nil && found {
+			c.setEntry(key, value, nil, c.ttl)
+			return value, nil
+		}
+
+		start := time.Now()
+		value, loadErr := loader()
+		c.reportLoadLatency(time.Since(start))
+
+		if loadErr != nil {
+			if c.negativeTTL > 0 {
+				c.setEntry(key, value, loadErr, c.negativeTTL)
+			}
+			return value, loadErr
+		}
+
+		c.setEntry(key, value, nil, c.ttl)
+		_ = c.remote.Set(key, value, c.ttl)
+		return value, nil
+	})
+
+	return result.(V), err
+}
+
 // Delete removes a value from the cache
-func (c *SmartCache) Delete(key string) {
+func (c *Cache[K, V]) Delete(key K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.removeLocked(key)
+	c.mu.Unlock()
+	_ = c.remote.Delete(key)
 }
 
-// Clear removes all entries from the cache
-func (c *SmartCache) Clear() {
+// Clear removes all entries from the in-process tier
+func (c *Cache[K, V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items = make(map[string]*list.Element)
+	c.items = make(map[K]*list.Element)
 	c.lruList.Init()
 	c.hits = 0
 	c.misses = 0
 	c.evictions = 0
 }
 
-// Size returns the current number of entries
-func (c *SmartCache) Size() int {
+// Size returns the current number of entries in the in-process tier
+func (c *Cache[K, V]) Size() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.lruList.Len()
 }
 
-// Stats returns cache statistics
-func (c *SmartCache) Stats() (hits, misses, evictions int64, size int) {
+// Stats returns cache statistics for the in-process tier
+func (c *Cache[K, V]) Stats() (hits, misses, evictions int64, size int) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.hits, c.misses, c.evictions, c.lruList.Len()
 }
 
 // HitRate returns the cache hit rate (0.0 to 1.0)
-func (c *SmartCache) HitRate() float64 {
+func (c *Cache[K, V]) HitRate() float64 {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -153,15 +304,15 @@ func (c *SmartCache) HitRate() float64 {
 	return float64(c.hits) / float64(total)
 }
 
-// CleanupExpired removes all expired entries
-func (c *SmartCache) CleanupExpired() int {
+// CleanupExpired removes all expired entries from the in-process tier
+func (c *Cache[K, V]) CleanupExpired() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	removed := 0
 	for key, elem := range c.items {
-		entry := elem.Value.(*CacheEntry)
-		if entry.IsExpired() {
+		entry := elem.Value.(*cacheEntry[K, V])
+		if entry.isExpired() {
 			c.removeLocked(key)
 			removed++
 		}
@@ -169,26 +320,29 @@ func (c *SmartCache) CleanupExpired() int {
 	return removed
 }
 
-// removeLocked removes an entry (must be called with lock held)
-func (c *SmartCache) removeLocked(key string) {
+// removeLocked removes an entry (must be called with mu held)
+func (c *Cache[K, V]) removeLocked(key K) {
 	if elem, exists := c.items[key]; exists {
 		c.lruList.Remove(elem)
 		delete(c.items, key)
 	}
 }
 
-// evictOldestLocked removes the least recently used entry (must be called with lock held)
-func (c *SmartCache) evictOldestLocked() {
+// evictOldestLocked removes the least recently used entry (must be called
+// with mu held)
+func (c *Cache[K, V]) evictOldestLocked() {
 	elem := c.lruList.Back()
 	if elem != nil {
-		entry := elem.Value.(*CacheEntry)
-		c.removeLocked(entry.Key)
+		entry := elem.Value.(*cacheEntry[K, V])
+		c.removeLocked(entry.key)
 		c.evictions++
+		c.reportEviction()
 	}
 }
 
-// StartCleanupWorker starts a background worker that periodically removes expired entries
-func (c *SmartCache) StartCleanupWorker(interval time.Duration, stop <-chan struct{}) {
+// StartCleanupWorker starts a background worker that periodically removes
+// expired entries
+func (c *Cache[K, V]) StartCleanupWorker(interval time.Duration, stop <-chan struct{}) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -201,3 +355,27 @@ func (c *SmartCache) StartCleanupWorker(interval time.Duration, stop <-chan stru
 		}
 	}
 }
+
+func (c *Cache[K, V]) reportHit() {
+	if c.metrics != nil {
+		c.metrics.ObserveHit()
+	}
+}
+
+func (c *Cache[K, V]) reportMiss() {
+	if c.metrics != nil {
+		c.metrics.ObserveMiss()
+	}
+}
+
+func (c *Cache[K, V]) reportEviction() {
+	if c.metrics != nil {
+		c.metrics.ObserveEviction()
+	}
+}
+
+func (c *Cache[K, V]) reportLoadLatency(d time.Duration) {
+	if c.metrics != nil {
+		c.metrics.ObserveLoadLatency(d)
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -246,3 +249,182 @@ func TestSmartCacheEvictionStats(t *testing.T) {
 		t.Errorf("Expected 1 eviction, got %d", evictions)
 	}
 }
+
+func TestCacheGetOrLoadCachesResult(t *testing.T) {
+	cache := NewCache[string, string](10, time.Minute)
+
+	var calls int32
+	loader := func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "loaded", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		val, err := cache.GetOrLoad("key1", loader)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if val != "loaded" {
+			t.Errorf("Expected 'loaded', got %v", val)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected loader to run once, ran %d times", calls)
+	}
+}
+
+func TestCacheGetOrLoadCoalescesConcurrentMisses(t *testing.T) {
+	cache := NewCache[string, string](10, time.Minute)
+
+	var calls int32
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, _ = cache.GetOrLoad("shared-key", func() (string, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				return "value", nil
+			})
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected a single coalesced loader call, got %d", calls)
+	}
+}
+
+func TestCacheGetOrLoadNegativeCaching(t *testing.T) {
+	cache := NewCache[string, string](10, time.Minute).WithNegativeTTL(50 * time.Millisecond)
+
+	var calls int32
+	loadErr := errors.New("not found")
+	loader := func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "", loadErr
+	}
+
+	if _, err := cache.GetOrLoad("missing", loader); !errors.Is(err, loadErr) {
+		t.Fatalf("expected loadErr, got %v", err)
+	}
+	if _, err := cache.GetOrLoad("missing", loader); !errors.Is(err, loadErr) {
+		t.Fatalf("expected cached loadErr, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected negative result to be cached, loader ran %d times", calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := cache.GetOrLoad("missing", loader); !errors.Is(err, loadErr) {
+		t.Fatalf("expected loadErr after negative TTL expiry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected loader to retry after negative TTL expiry, ran %d times", calls)
+	}
+}
+
+func TestCacheSetWithTTLOverridesDefault(t *testing.T) {
+	cache := NewCache[string, string](10, time.Hour)
+
+	cache.SetWithTTL("short-lived", "value", 20*time.Millisecond)
+	if _, ok := cache.Get("short-lived"); !ok {
+		t.Fatal("Expected value to be present immediately after Set")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("short-lived"); ok {
+		t.Error("Expected per-key TTL to expire ahead of the cache's default TTL")
+	}
+}
+
+// mapRemoteBackend is a minimal in-memory RemoteBackend for exercising the
+// Cache<->backend interaction without a real Redis/BadgerDB dependency.
+type mapRemoteBackend struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (b *mapRemoteBackend) Get(key string) (string, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	val, ok := b.values[key]
+	return val, ok, nil
+}
+
+func (b *mapRemoteBackend) Set(key string, value string, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.values[key] = value
+	return nil
+}
+
+func (b *mapRemoteBackend) Delete(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.values, key)
+	return nil
+}
+
+func TestCacheRemoteBackendFallback(t *testing.T) {
+	backend := &mapRemoteBackend{values: map[string]string{"key1": "from-remote"}}
+	cache := NewCache[string, string](10, time.Minute).WithRemoteBackend(backend)
+
+	val, ok := cache.Get("key1")
+	if !ok || val != "from-remote" {
+		t.Fatalf("Expected to fall back to remote backend, got %v, %v", val, ok)
+	}
+
+	// Repopulates the in-process tier so a second Get doesn't need the backend
+	backend.values["key1"] = "changed-in-remote"
+	val, ok = cache.Get("key1")
+	if !ok || val != "from-remote" {
+		t.Errorf("Expected in-process tier to serve the previously cached value, got %v", val)
+	}
+}
+
+type countingMetricsReporter struct {
+	hits, misses, evictions int32
+	loads                   int32
+}
+
+func (r *countingMetricsReporter) ObserveHit()      { atomic.AddInt32(&r.hits, 1) }
+func (r *countingMetricsReporter) ObserveMiss()     { atomic.AddInt32(&r.misses, 1) }
+func (r *countingMetricsReporter) ObserveEviction() { atomic.AddInt32(&r.evictions, 1) }
+func (r *countingMetricsReporter) ObserveLoadLatency(d time.Duration) {
+	atomic.AddInt32(&r.loads, 1)
+}
+
+func TestCacheMetricsReporter(t *testing.T) {
+	reporter := &countingMetricsReporter{}
+	cache := NewCache[string, string](1, 0).WithMetricsReporter(reporter)
+
+	cache.Set("key1", "value1")
+	cache.Get("key1")
+	cache.Get("missing")
+	cache.Set("key2", "value2") // Evicts key1
+
+	if reporter.hits != 1 {
+		t.Errorf("Expected 1 hit, got %d", reporter.hits)
+	}
+	if reporter.misses != 1 {
+		t.Errorf("Expected 1 miss, got %d", reporter.misses)
+	}
+	if reporter.evictions != 1 {
+		t.Errorf("Expected 1 eviction, got %d", reporter.evictions)
+	}
+
+	if _, err := cache.GetOrLoad("key3", func() (string, error) { return "value3", nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reporter.loads != 1 {
+		t.Errorf("Expected 1 load latency observation, got %d", reporter.loads)
+	}
+}
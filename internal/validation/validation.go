@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vuongmanhnghia/discord-music-bot/internal/errors"
 )
@@ -14,6 +16,11 @@ var (
 	youtubePattern    = regexp.MustCompile(`^(https?://)?(www\.)?(youtube\.com|youtu\.be)/.+$`)
 	soundcloudPattern = regexp.MustCompile(`^https?://(www\.)?soundcloud\.com/.+$`)
 	spotifyPattern    = regexp.MustCompile(`^https?://open\.spotify\.com/(track|album|playlist)/.+$`)
+
+	// timestampPattern matches YouTube's "Xh Ym Zs" start-offset format, e.g.
+	// "90", "90s", "1m30s", "1h2m3s" - every component is optional except at
+	// least one must be present
+	timestampPattern = regexp.MustCompile(`^(?:(\d+)h)?(?:(\d+)m)?(?:(\d+)s?)?$`)
 )
 
 // ValidateURL validates if a string is a valid URL
@@ -35,6 +42,15 @@ func IsYouTubeURL(input string) bool {
 	return youtubePattern.MatchString(input)
 }
 
+// IsYouTubePlaylistURL checks if a URL explicitly points at a playlist
+// (youtube.com/playlist?list=...), as opposed to a single video that merely
+// carries its enclosing playlist's list= parameter
+// (youtube.com/watch?v=X&list=...) or an auto-generated radio/mix
+// (list=RD...), either of which is still a single-video request.
+func IsYouTubePlaylistURL(input string) bool {
+	return IsYouTubeURL(input) && strings.Contains(input, "playlist?list=")
+}
+
 // IsSoundCloudURL checks if URL is a SoundCloud URL
 func IsSoundCloudURL(input string) bool {
 	return soundcloudPattern.MatchString(input)
@@ -98,6 +114,63 @@ func ValidatePlaylistName(name string) error {
 	return nil
 }
 
+// ParseStartOffset extracts a "share at current time" start offset from a
+// YouTube URL's t or start query parameter, supporting the "90", "90s",
+// "1m30s", and "1h2m3s" forms YouTube itself generates. Returns 0 if the URL
+// has no recognizable offset parameter.
+func ParseStartOffset(rawURL string) (time.Duration, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errors.ErrInvalidURL, err)
+	}
+
+	query := parsed.Query()
+	value := query.Get("t")
+	if value == "" {
+		value = query.Get("start")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	return parseTimestamp(value)
+}
+
+// parseTimestamp parses a YouTube-style timestamp ("90", "90s", "1m30s",
+// "1h2m3s") into a duration
+func parseTimestamp(value string) (time.Duration, error) {
+	match := timestampPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("%w: invalid timestamp %q", errors.ErrInvalidInput, value)
+	}
+
+	hours, minutes, seconds := match[1], match[2], match[3]
+	if hours == "" && minutes == "" && seconds == "" {
+		return 0, fmt.Errorf("%w: invalid timestamp %q", errors.ErrInvalidInput, value)
+	}
+
+	total := 0
+	for _, part := range []struct {
+		value string
+		unit  int
+	}{
+		{hours, 3600},
+		{minutes, 60},
+		{seconds, 1},
+	} {
+		if part.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part.value)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid timestamp %q", errors.ErrInvalidInput, value)
+		}
+		total += n * part.unit
+	}
+
+	return time.Duration(total) * time.Second, nil
+}
+
 // TruncateString safely truncates a string to max length
 func TruncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
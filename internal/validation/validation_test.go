@@ -1,6 +1,9 @@
 package validation
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestIsYouTubePlaylistURL(t *testing.T) {
 	tests := []struct {
@@ -59,3 +62,66 @@ func TestIsYouTubePlaylistURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStartOffset(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "no offset parameter",
+			url:      "https://www.youtube.com/watch?v=D8OCBS2UZOk",
+			expected: 0,
+		},
+		{
+			name:     "plain seconds",
+			url:      "https://www.youtube.com/watch?v=D8OCBS2UZOk&t=90",
+			expected: 90 * time.Second,
+		},
+		{
+			name:     "seconds with trailing s",
+			url:      "https://youtu.be/D8OCBS2UZOk?t=90s",
+			expected: 90 * time.Second,
+		},
+		{
+			name:     "minutes and seconds",
+			url:      "https://www.youtube.com/watch?v=D8OCBS2UZOk&t=1m30s",
+			expected: 90 * time.Second,
+		},
+		{
+			name:     "hours, minutes, and seconds",
+			url:      "https://www.youtube.com/watch?v=D8OCBS2UZOk&t=1h2m3s",
+			expected: time.Hour + 2*time.Minute + 3*time.Second,
+		},
+		{
+			name:     "start parameter instead of t",
+			url:      "https://www.youtube.com/watch?v=D8OCBS2UZOk&start=45",
+			expected: 45 * time.Second,
+		},
+		{
+			name:    "malformed timestamp",
+			url:     "https://www.youtube.com/watch?v=D8OCBS2UZOk&t=abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseStartOffset(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStartOffset(%s) expected an error, got nil", tt.url)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseStartOffset(%s) unexpected error: %v", tt.url, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseStartOffset(%s) = %v, expected %v", tt.url, result, tt.expected)
+			}
+		})
+	}
+}
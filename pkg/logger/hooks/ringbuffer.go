@@ -0,0 +1,94 @@
+// Package hooks provides logrus hooks shared across the bot, starting with
+// a ring buffer that backs the in-Discord /logs admin command.
+package hooks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a single captured log line, decoupled from logrus.Entry so
+// callers (e.g. /logs) don't need to import logrus just to read it back.
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Fields  logrus.Fields
+}
+
+// RingBuffer is a logrus.Hook that retains the most recent Size entries
+// fired through the logger it's attached to, discarding older ones, so
+// /logs can dump recent activity without a log aggregator.
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	size    int
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates a RingBuffer retaining up to size entries. size
+// must be positive.
+func NewRingBuffer(size int) *RingBuffer {
+	return &RingBuffer{
+		entries: make([]Entry, size),
+		size:    size,
+	}
+}
+
+// Levels reports that RingBuffer wants every level; filtering happens at
+// the logger's level, not the hook's.
+func (h *RingBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire records entry, overwriting the oldest retained entry once Size is
+// reached.
+func (h *RingBuffer) Fire(entry *logrus.Entry) error {
+	fields := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		fields[k] = v
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = Entry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		Fields:  fields,
+	}
+	h.next = (h.next + 1) % h.size
+	if h.next == 0 {
+		h.full = true
+	}
+	return nil
+}
+
+// Last returns up to n of the most recently fired entries, oldest first.
+func (h *RingBuffer) Last(n int) []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	count := h.next
+	if h.full {
+		count = h.size
+	}
+	if n > count {
+		n = count
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	result := make([]Entry, n)
+	start := h.next - n
+	for i := 0; i < n; i++ {
+		idx := (start + i + h.size) % h.size
+		result[i] = h.entries[idx]
+	}
+	return result
+}
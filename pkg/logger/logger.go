@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"os"
 
@@ -17,6 +18,10 @@ type Config struct {
 	Level  string // debug, info, warn, error
 	Format string // text or json
 	Output io.Writer
+
+	// Fields are static fields (version, git SHA, ...) stamped onto every
+	// log entry this logger emits, regardless of call site
+	Fields logrus.Fields
 }
 
 // New creates a new logger instance
@@ -33,7 +38,14 @@ func New(cfg Config) *Logger {
 	// Set formatter
 	if cfg.Format == "json" {
 		log.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat: "2006-01-02 15:04:05",
+			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
+			// Rename keys to match common log-aggregation schemas (Datadog,
+			// GCP Cloud Logging, etc.)
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime:  "ts",
+				logrus.FieldKeyLevel: "severity",
+				logrus.FieldKeyMsg:   "message",
+			},
 		})
 	} else {
 		log.SetFormatter(&logrus.TextFormatter{
@@ -50,9 +62,32 @@ func New(cfg Config) *Logger {
 		log.SetOutput(os.Stdout)
 	}
 
+	if len(cfg.Fields) > 0 {
+		log.AddHook(&staticFieldsHook{fields: cfg.Fields})
+	}
+
 	return &Logger{Logger: log}
 }
 
+// staticFieldsHook stamps a fixed set of fields (e.g. version, git SHA) onto
+// every log entry without every call site having to pass them
+type staticFieldsHook struct {
+	fields logrus.Fields
+}
+
+func (h *staticFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *staticFieldsHook) Fire(entry *logrus.Entry) error {
+	for k, v := range h.fields {
+		if _, exists := entry.Data[k]; !exists {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
+
 // WithField adds a single field to the log entry
 func (l *Logger) WithField(key string, value interface{}) *logrus.Entry {
 	return l.Logger.WithField(key, value)
@@ -67,3 +102,38 @@ func (l *Logger) WithFields(fields logrus.Fields) *logrus.Entry {
 func (l *Logger) WithError(err error) *logrus.Entry {
 	return l.Logger.WithError(err)
 }
+
+// contextKey is an unexported type so values this package stores in a
+// context.Context can't collide with keys set by other packages
+type contextKey string
+
+const logFieldsKey contextKey = "logger_fields"
+
+// WithContext returns a copy of ctx carrying fields that FromContext will
+// attach to any log entry created further down the call chain. Calling it
+// more than once on the same ctx accumulates fields rather than replacing
+// them, so e.g. a correlation ID attached at the top of a request survives
+// deeper calls adding their own fields.
+func WithContext(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := logrus.Fields{}
+	if existing, ok := ctx.Value(logFieldsKey).(logrus.Fields); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, logFieldsKey, merged)
+}
+
+// FromContext returns a log entry pre-populated with any fields attached via
+// WithContext (guild ID, user ID, command name, correlation ID, ...), so
+// deep call chains don't have to thread those fields through every call
+func (l *Logger) FromContext(ctx context.Context) *logrus.Entry {
+	fields, ok := ctx.Value(logFieldsKey).(logrus.Fields)
+	if !ok {
+		return logrus.NewEntry(l.Logger)
+	}
+	return l.Logger.WithFields(fields)
+}